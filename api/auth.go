@@ -0,0 +1,91 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// AuthMethod is a method a principal was authenticated with.
+type AuthMethod string
+
+// List of supported authentication methods.
+const (
+	AuthMethodAPIKey     AuthMethod = "api-key"
+	AuthMethodClientCert AuthMethod = "client-cert"
+)
+
+// Principal is an authenticated API client.
+type Principal struct {
+	Name   string
+	Method AuthMethod
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of the context with the principal.
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the authenticated principal if any.
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
+
+// Authenticate is an authentication middleware that accepts bearer tokens
+// matching any of the API keys and, if clientCerts is true, verified TLS
+// client certificates. The subject common name of a client certificate
+// becomes the name of the principal.
+func Authenticate(keys *APIKeys, clientCerts bool) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if clientCerts {
+				if principal, ok := clientCertPrincipal(r); ok {
+					next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), principal)))
+					return
+				}
+			}
+
+			header := r.Header.Get("Authorization")
+			if header == "" {
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+
+			fields := strings.Fields(header)
+			if len(fields) != 2 || !strings.EqualFold(fields[0], "Bearer") {
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+
+			name, ok := keys.Match(fields[1])
+			if !ok {
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+
+			principal := Principal{Name: name, Method: AuthMethodAPIKey}
+			next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), principal)))
+		})
+	}
+}
+
+// clientCertPrincipal maps a verified TLS client certificate to a principal.
+func clientCertPrincipal(r *http.Request) (Principal, bool) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return Principal{}, false
+	}
+
+	cert := r.TLS.VerifiedChains[0][0]
+	name := cert.Subject.CommonName
+	if name == "" && len(cert.DNSNames) > 0 {
+		name = cert.DNSNames[0]
+	}
+	if name == "" {
+		return Principal{}, false
+	}
+
+	return Principal{Name: name, Method: AuthMethodClientCert}, true
+}
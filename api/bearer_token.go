@@ -1,35 +1,62 @@
 package api
 
 import (
+	"crypto/sha256"
 	"crypto/subtle"
+	"encoding/hex"
 	"net/http"
-	"strings"
+	"sync/atomic"
 )
 
+// APIKeys is a set of API keys that can be replaced at runtime.
+type APIKeys struct {
+	keys atomic.Pointer[[]string]
+}
+
+// NewAPIKeys creates a new set of API keys.
+func NewAPIKeys(keys ...string) *APIKeys {
+	k := &APIKeys{}
+	k.Set(keys...)
+
+	return k
+}
+
+// Set replaces the API keys.
+func (k *APIKeys) Set(keys ...string) {
+	keys = append([]string(nil), keys...)
+	k.keys.Store(&keys)
+}
+
+// Valid checks if the token matches any of the API keys.
+func (k *APIKeys) Valid(token string) bool {
+	_, ok := k.Match(token)
+	return ok
+}
+
+// Match returns a non-secret identifier of the API key that matches the token.
+func (k *APIKeys) Match(token string) (string, bool) {
+	var matched string
+	for _, key := range *k.keys.Load() {
+		// Compare against all keys to not leak which one matched.
+		if len(key) == len(token) && subtle.ConstantTimeCompare([]byte(key), []byte(token)) == 1 {
+			matched = key
+		}
+	}
+	if matched == "" {
+		return "", false
+	}
+
+	sum := sha256.Sum256([]byte(matched))
+
+	return "key:" + hex.EncodeToString(sum[:4]), true
+}
+
 // BearerToken is a an authentication middleware.
 func BearerToken(key string) func(next http.Handler) http.Handler {
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			header := r.Header.Get("Authorization")
-			if header == "" {
-				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
-				return
-			}
-
-			fields := strings.Fields(header)
-			if len(fields) != 2 ||
-				!strings.EqualFold(fields[0], "Bearer") ||
-				len(fields[1]) != len(key) {
-				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
-				return
-			}
-
-			if subtle.ConstantTimeCompare([]byte(key), []byte(fields[1])) != 1 {
-				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
-				return
-			}
-
-			next.ServeHTTP(w, r)
-		})
-	}
+	return BearerTokens(NewAPIKeys(key))
+}
+
+// BearerTokens is an authentication middleware that accepts any of the API keys.
+func BearerTokens(keys *APIKeys) func(next http.Handler) http.Handler {
+	return Authenticate(keys, false)
 }
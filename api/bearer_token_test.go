@@ -76,3 +76,37 @@ func TestBearerToken(t *testing.T) {
 		})
 	}
 }
+
+func TestBearerTokens(t *testing.T) {
+	token1 := base64.RawURLEncoding.EncodeToString([]byte(uuid.NewString()))
+	token2 := base64.RawURLEncoding.EncodeToString([]byte(uuid.NewString()))
+	keys := NewAPIKeys(token1)
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	request := func(token string) int {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/foo", nil)
+		r.Header.Set("Authorization", "Bearer "+token)
+
+		BearerTokens(keys)(h).ServeHTTP(w, r)
+
+		return w.Code
+	}
+
+	if want, got := http.StatusOK, request(token1); want != got {
+		t.Errorf("Expected status code %d got %d", want, got)
+	}
+	if want, got := http.StatusUnauthorized, request(token2); want != got {
+		t.Errorf("Expected status code %d got %d", want, got)
+	}
+
+	// Rotate the keys.
+	keys.Set(token2)
+
+	if want, got := http.StatusUnauthorized, request(token1); want != got {
+		t.Errorf("Expected status code %d got %d", want, got)
+	}
+	if want, got := http.StatusOK, request(token2); want != got {
+		t.Errorf("Expected status code %d got %d", want, got)
+	}
+}
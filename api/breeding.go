@@ -0,0 +1,223 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+// GetLineage gets the ancestors and the descendants of a dinosaur.
+// GET /dinosaurs/:id/lineage[?generations=...]
+func (s *Server) GetLineage() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+		id := chi.URLParam(r, "id")
+
+		if err := app.ValidateID(id); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		generations, err := lineageGenerations(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		lineage, err := s.BreedingStore.Lineage(r.Context(), id, generations)
+		if err != nil {
+			if err == app.ErrNotFound {
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+				return
+			}
+
+			logger.Error("Error getting lineage", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		response := struct {
+			Data *app.Lineage `json:"data"`
+		}{
+			Data: lineage,
+		}
+
+		if err = json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			return
+		}
+	}
+}
+
+// SetParentsRequest is a request to set the parents of a dinosaur.
+type SetParentsRequest struct {
+	ParentIDs []string `json:"parentIds"`
+}
+
+// SetParents replaces the parents of a dinosaur.
+// PUT /dinosaurs/:id/parents
+func (s *Server) SetParents() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+		id := chi.URLParam(r, "id")
+
+		if err := app.ValidateID(id); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var req SetParentsRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.Error("Error decoding request body", "error", err)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		if err := app.ValidateParents(id, req.ParentIDs); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		lineage, err := s.BreedingStore.SetParents(r.Context(), id, req.ParentIDs)
+		if err != nil {
+			switch err {
+			case app.ErrNotFound:
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			case app.ErrSpeciesMismatch, app.ErrLineageCycle:
+				http.Error(w, err.Error(), http.StatusConflict)
+			default:
+				logger.Error("Error setting parents", "error", err)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		response := struct {
+			Data *app.Lineage `json:"data"`
+		}{
+			Data: lineage,
+		}
+
+		if err = json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			return
+		}
+	}
+}
+
+// ListBreedingPairs lists breeding pairs, the most recent first.
+// GET /breeding-pairs[?dinosaurId=...][&flagged=true]
+func (s *Server) ListBreedingPairs() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+
+		filter, err := breedingPairFilter(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		pairs, err := s.BreedingStore.ListPairs(r.Context(), filter)
+		if err != nil {
+			logger.Error("Error getting breeding pairs", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		if pairs == nil {
+			pairs = []app.BreedingPair{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		response := struct {
+			Data []app.BreedingPair `json:"data"`
+		}{
+			Data: pairs,
+		}
+
+		if err = json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			return
+		}
+	}
+}
+
+// AddBreedingPairRequest is a request to propose a breeding pair.
+type AddBreedingPairRequest struct {
+	SireID string `json:"sireId"`
+	DamID  string `json:"damId"`
+	Notes  string `json:"notes"`
+}
+
+// Pair returns the requested breeding pair.
+func (r AddBreedingPairRequest) Pair() app.BreedingPair {
+	return app.BreedingPair{
+		SireID: r.SireID,
+		DamID:  r.DamID,
+		Notes:  r.Notes,
+	}
+}
+
+// AddBreedingPair proposes a breeding pair.
+// The pair is flagged if the inbreeding coefficient of its offspring goes over the threshold.
+// POST /breeding-pairs
+func (s *Server) AddBreedingPair() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+
+		var req AddBreedingPairRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.Error("Error decoding request body", "error", err)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		pair := req.Pair()
+		if err := pair.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		added, err := s.BreedingStore.AddPair(r.Context(), &pair)
+		if err != nil {
+			switch err {
+			case app.ErrNotFound:
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			case app.ErrNotAdult, app.ErrSpeciesMismatch:
+				http.Error(w, err.Error(), http.StatusConflict)
+			default:
+				logger.Error("Error adding breeding pair", "error", err)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+
+		response := struct {
+			Data *app.BreedingPair `json:"data"`
+		}{
+			Data: added,
+		}
+
+		if err = json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			return
+		}
+	}
+}
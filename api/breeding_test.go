@@ -0,0 +1,308 @@
+//go:build unit
+// +build unit
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+type fakeBreedingStore struct {
+	pair        app.BreedingPair
+	parentIDs   []string
+	generations int
+	coefficient float64
+	threshold   float64
+	filter      app.BreedingPairFilter
+	err         error
+}
+
+func (s *fakeBreedingStore) SetParents(_ context.Context, id string, parentIDs []string) (*app.Lineage, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	s.parentIDs = parentIDs
+	lineage := app.Lineage{DinosaurID: id, ParentIDs: []string{}, Ancestors: []app.Relative{}, Descendants: []app.Relative{}}
+	for _, parentID := range parentIDs {
+		lineage.ParentIDs = append(lineage.ParentIDs, parentID)
+		lineage.Ancestors = append(lineage.Ancestors, app.Relative{
+			ID:         parentID,
+			Name:       "Bess",
+			Species:    app.DinosaurSpeciesTriceratops,
+			Stage:      app.LifecycleStageAdult,
+			ParentIDs:  []string{},
+			Generation: 1,
+		})
+	}
+
+	return &lineage, nil
+}
+
+func (s *fakeBreedingStore) Lineage(_ context.Context, id string, generations int) (*app.Lineage, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	s.generations = generations
+
+	return &app.Lineage{DinosaurID: id, ParentIDs: []string{}, Ancestors: []app.Relative{}, Descendants: []app.Relative{}}, nil
+}
+
+func (s *fakeBreedingStore) AddPair(_ context.Context, pair *app.BreedingPair) (*app.BreedingPair, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	s.pair = *pair
+	s.pair.ID = uuid.NewString()
+	s.pair.Species = app.DinosaurSpeciesTriceratops
+	s.pair.InbreedingCoefficient = s.coefficient
+	s.pair.Flagged = s.coefficient > s.threshold
+	s.pair.CreatedAt = time.Now()
+	added := s.pair
+
+	return &added, nil
+}
+
+func (s *fakeBreedingStore) ListPairs(_ context.Context, filter app.BreedingPairFilter) ([]app.BreedingPair, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	s.filter = filter
+	if s.pair.ID == "" {
+		return nil, nil
+	}
+
+	return []app.BreedingPair{s.pair}, nil
+}
+
+func TestGetLineage(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	id := uuid.NewString()
+	tests := []struct {
+		name        string
+		id          string
+		query       string
+		err         error
+		generations int
+		status      int
+	}{
+		{"default", id, "", nil, app.DefaultLineageGenerations, http.StatusOK},
+		{"generations", id, "?generations=5", nil, 5, http.StatusOK},
+		{"too few generations", id, "?generations=0", nil, 0, http.StatusBadRequest},
+		{"too many generations", id, "?generations=11", nil, 0, http.StatusBadRequest},
+		{"invalid generations", id, "?generations=all", nil, 0, http.StatusBadRequest},
+		{"invalid id", "foo", "", nil, 0, http.StatusBadRequest},
+		{"not found", id, "", app.ErrNotFound, 0, http.StatusNotFound},
+		{"store error", id, "", errors.New("store error"), 0, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeBreedingStore{err: tt.err}
+			svc := &Server{
+				Logger:        logger,
+				BreedingStore: store,
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/dinosaurs/"+tt.id+"/lineage"+tt.query, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.id)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			validated(t, svc.GetLineage()).ServeHTTP(w, r)
+
+			if want, got := tt.status, w.Code; want != got {
+				t.Fatalf("Expected %d got %d", want, got)
+			}
+			if want, got := tt.generations, store.generations; want != got {
+				t.Fatalf("Expected generations %d got %d", want, got)
+			}
+		})
+	}
+}
+
+func TestSetParents(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	id := uuid.NewString()
+	first, second := uuid.NewString(), uuid.NewString()
+	tests := []struct {
+		name    string
+		body    string
+		err     error
+		parents int
+		status  int
+	}{
+		{"two parents", `{"parentIds": ["` + first + `", "` + second + `"]}`, nil, 2, http.StatusOK},
+		{"no parents", `{"parentIds": []}`, nil, 0, http.StatusOK},
+		{"three parents", `{"parentIds": ["` + first + `", "` + second + `", "` + uuid.NewString() + `"]}`, nil, 0, http.StatusBadRequest},
+		{"self", `{"parentIds": ["` + id + `"]}`, nil, 0, http.StatusBadRequest},
+		{"duplicate", `{"parentIds": ["` + first + `", "` + first + `"]}`, nil, 0, http.StatusBadRequest},
+		{"invalid parent", `{"parentIds": ["foo"]}`, nil, 0, http.StatusBadRequest},
+		{"invalid body", `{"parentIds": [`, nil, 0, http.StatusBadRequest},
+		{"not found", `{"parentIds": ["` + first + `"]}`, app.ErrNotFound, 0, http.StatusNotFound},
+		{"cycle", `{"parentIds": ["` + first + `"]}`, app.ErrLineageCycle, 0, http.StatusConflict},
+		{"species mismatch", `{"parentIds": ["` + first + `"]}`, app.ErrSpeciesMismatch, 0, http.StatusConflict},
+		{"store error", `{"parentIds": ["` + first + `"]}`, errors.New("store error"), 0, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeBreedingStore{err: tt.err}
+			svc := &Server{
+				Logger:        logger,
+				BreedingStore: store,
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPut, "/dinosaurs/"+id+"/parents", strings.NewReader(tt.body))
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", id)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			validated(t, svc.SetParents()).ServeHTTP(w, r)
+
+			if want, got := tt.status, w.Code; want != got {
+				t.Fatalf("Expected %d got %d", want, got)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			response := struct {
+				Data app.Lineage `json:"data"`
+			}{}
+
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+
+			if want, got := tt.parents, len(response.Data.ParentIDs); want != got {
+				t.Fatalf("Expected ParentIDs %d got %d", want, got)
+			}
+		})
+	}
+}
+
+func TestAddBreedingPair(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	sireID, damID := uuid.NewString(), uuid.NewString()
+	pair := `{"sireId": "` + sireID + `", "damId": "` + damID + `"}`
+	tests := []struct {
+		name        string
+		body        string
+		coefficient float64
+		err         error
+		flagged     bool
+		status      int
+	}{
+		{"unrelated", pair, 0, nil, false, http.StatusCreated},
+		{"cousins", pair, app.DefaultInbreedingThreshold, nil, false, http.StatusCreated},
+		{"siblings", `{"sireId": "` + sireID + `", "damId": "` + damID + `", "notes": "Last of the line"}`, 0.25, nil, true, http.StatusCreated},
+		{"no dam", `{"sireId": "` + sireID + `"}`, 0, nil, false, http.StatusBadRequest},
+		{"same dinosaur", `{"sireId": "` + sireID + `", "damId": "` + sireID + `"}`, 0, nil, false, http.StatusBadRequest},
+		{"invalid sire", `{"sireId": "foo", "damId": "` + damID + `"}`, 0, nil, false, http.StatusBadRequest},
+		{"invalid body", `{"sireId": "`, 0, nil, false, http.StatusBadRequest},
+		{"not found", pair, 0, app.ErrNotFound, false, http.StatusNotFound},
+		{"not adult", pair, 0, app.ErrNotAdult, false, http.StatusConflict},
+		{"species mismatch", pair, 0, app.ErrSpeciesMismatch, false, http.StatusConflict},
+		{"store error", pair, 0, errors.New("store error"), false, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeBreedingStore{coefficient: tt.coefficient, threshold: app.DefaultInbreedingThreshold, err: tt.err}
+			svc := &Server{
+				Logger:        logger,
+				BreedingStore: store,
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/breeding-pairs", strings.NewReader(tt.body))
+
+			validated(t, svc.AddBreedingPair()).ServeHTTP(w, r)
+
+			if want, got := tt.status, w.Code; want != got {
+				t.Fatalf("Expected %d got %d", want, got)
+			}
+			if tt.status != http.StatusCreated {
+				return
+			}
+
+			response := struct {
+				Data app.BreedingPair `json:"data"`
+			}{}
+
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+
+			if want, got := tt.flagged, response.Data.Flagged; want != got {
+				t.Fatalf("Expected Flagged %t got %t", want, got)
+			}
+			if want, got := tt.coefficient, response.Data.InbreedingCoefficient; want != got {
+				t.Fatalf("Expected InbreedingCoefficient %v got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestListBreedingPairs(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	dinosaurID := uuid.NewString()
+	tests := []struct {
+		name   string
+		query  string
+		filter app.BreedingPairFilter
+		status int
+	}{
+		{"no filter", "", app.BreedingPairFilter{}, http.StatusOK},
+		{"dinosaur", "?dinosaurId=" + dinosaurID, app.BreedingPairFilter{DinosaurID: dinosaurID}, http.StatusOK},
+		{"flagged", "?flagged=true", app.BreedingPairFilter{FlaggedOnly: true}, http.StatusOK},
+		{"invalid dinosaur", "?dinosaurId=foo", app.BreedingPairFilter{}, http.StatusBadRequest},
+		{"invalid flagged", "?flagged=maybe", app.BreedingPairFilter{}, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeBreedingStore{}
+			svc := &Server{
+				Logger:        logger,
+				BreedingStore: store,
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/breeding-pairs"+tt.query, nil)
+
+			validated(t, svc.ListBreedingPairs()).ServeHTTP(w, r)
+
+			if want, got := tt.status, w.Code; want != got {
+				t.Fatalf("Expected %d got %d", want, got)
+			}
+			if want, got := tt.filter, store.filter; want != got {
+				t.Fatalf("Expected filter %+v got %+v", want, got)
+			}
+		})
+	}
+}
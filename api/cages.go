@@ -13,21 +13,30 @@ import (
 )
 
 // ListCages lists all cages.
-// GET /cages[&status=active|down]
+// GET /cages[?type=...][&status=active|down][&quarantine=true|false][&zoneId=...][&sectorId=...][&minFreeCapacity=N][&occupancy=empty|full][&sort=...][&fields=...][&expand=dinosaurs][&includeDeleted=true]
 func (s *Server) ListCages() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		requestID := middleware.GetReqID(r.Context())
 		logger := s.Logger.With("requestId", requestID)
 
-		status := app.CageStatus(r.URL.Query().Get("status"))
-		if !status.IsUnspecified() {
-			if err := status.Validate(); err != nil {
-				http.Error(w, err.Error(), http.StatusBadRequest)
-				return
-			}
+		filter, err := cageFilter(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		expanded, err := expand(r, "dinosaurs")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		fields := filter.Fields
+		if expanded["dinosaurs"] {
+			filter.Fields = withField(fields, "id")
 		}
 
-		cages, err := s.CageStore.List(r.Context(), status)
+		cages, err := s.CageStore.List(r.Context(), filter)
 		if err != nil {
 			logger.Error("Error getting cages", "error", err)
 			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -37,13 +46,26 @@ func (s *Server) ListCages() http.HandlerFunc {
 			cages = []app.Cage{}
 		}
 
-		w.Header().Set("Content-Type", "application/json")
-
 		response := struct {
-			Data []app.Cage `json:"data"`
+			Data any `json:"data"`
 		}{
 			Data: cages,
 		}
+		if expanded["dinosaurs"] {
+			if response.Data, err = s.expandDinosaurs(r.Context(), cages); err != nil {
+				logger.Error("Error getting dinosaurs", "error", err)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+		}
+		if response.Data, err = sparse(response.Data, fields, expanded); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
 		if err = json.NewEncoder(w).Encode(response); err != nil {
 			logger.Error("Error marshalling response", "error", err)
 			return
@@ -52,7 +74,7 @@ func (s *Server) ListCages() http.HandlerFunc {
 }
 
 // GetCage gets a cage by id.
-// GET /cages/:id
+// GET /cages/:id[?fields=...][&expand=dinosaurs][&includeDeleted=true]
 func (s *Server) GetCage() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		requestID := middleware.GetReqID(r.Context())
@@ -64,7 +86,24 @@ func (s *Server) GetCage() http.HandlerFunc {
 			return
 		}
 
-		cage, err := s.CageStore.Get(r.Context(), id)
+		opts, err := getOptions(r, app.CageFields)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		expanded, err := expand(r, "dinosaurs")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		fields := opts.Fields
+		if expanded["dinosaurs"] {
+			opts.Fields = withField(fields, "id")
+		}
+
+		cage, err := s.CageStore.Get(r.Context(), id, opts)
 		if err != nil {
 			if err == app.ErrNotFound {
 				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
@@ -76,13 +115,27 @@ func (s *Server) GetCage() http.HandlerFunc {
 			return
 		}
 
-		w.Header().Set("Content-Type", "application/json")
-
 		response := struct {
-			Data *app.Cage `json:"data"`
+			Data any `json:"data"`
 		}{
 			Data: cage,
 		}
+		if expanded["dinosaurs"] {
+			cages, err := s.expandDinosaurs(r.Context(), []app.Cage{*cage})
+			if err != nil {
+				logger.Error("Error getting dinosaurs", "error", err)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+			response.Data = cages[0]
+		}
+		if response.Data, err = sparse(response.Data, fields, expanded); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
 
 		if err := json.NewEncoder(w).Encode(response); err != nil {
 			logger.Error("Error marshalling response", "error", err)
@@ -93,17 +146,43 @@ func (s *Server) GetCage() http.HandlerFunc {
 
 // AddCageRequest is a request to add a new cage.
 type AddCageRequest struct {
-	Capacity int            `json:"capacity"`
-	Status   app.CageStatus `json:"status"`
+	Type         app.CageType     `json:"type"`
+	Capacity     int              `json:"capacity"`
+	CapacityUnit app.CapacityUnit `json:"capacityUnit"`
+	Status       app.CageStatus   `json:"status"`
+	SectorID     string           `json:"sectorId"`
+	Quarantine   bool             `json:"quarantine"`
 }
 
 // Validate validates the request.
+// The type and the capacity unit are optional,
+// cages are general purpose and count heads by default.
 func (r AddCageRequest) Validate() error {
-	if r.Capacity <= 0 {
-		return errors.New("invalid capacity")
+	if r.Type != app.CageTypeUnspecified {
+		if err := r.Type.Validate(); err != nil {
+			return err
+		}
 	}
 
-	return r.Status.Validate()
+	if err := app.ValidateCapacity(r.Capacity); err != nil {
+		return err
+	}
+
+	if r.CapacityUnit != app.CapacityUnitUnspecified {
+		if err := r.CapacityUnit.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if err := r.Status.Validate(); err != nil {
+		return err
+	}
+
+	if r.SectorID != "" {
+		return app.ValidateID(r.SectorID)
+	}
+
+	return nil
 }
 
 // AddCage adds a new cage.
@@ -127,10 +206,19 @@ func (s *Server) AddCage() http.HandlerFunc {
 		}
 
 		cage, err := s.CageStore.Add(r.Context(), &app.Cage{
-			Capacity: req.Capacity,
-			Status:   req.Status,
+			Type:         req.Type,
+			Capacity:     req.Capacity,
+			CapacityUnit: req.CapacityUnit,
+			Status:       req.Status,
+			SectorID:     req.SectorID,
+			Quarantine:   req.Quarantine,
 		})
 		if err != nil {
+			if err == app.ErrSectorNotFound {
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			}
+
 			logger.Error("Error adding cage", "error", err)
 			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 			return
@@ -217,7 +305,234 @@ func (s *Server) ChangeCageStatus() http.HandlerFunc {
 	}
 }
 
-// DeleteCage deletes a cage.
+// PatchCageRequest is a JSON merge patch of a cage.
+type PatchCageRequest struct {
+	Type         *app.CageType     `json:"type"`
+	Status       *app.CageStatus   `json:"status"`
+	Capacity     *int              `json:"capacity"`
+	CapacityUnit *app.CapacityUnit `json:"capacityUnit"`
+	SectorID     *string           `json:"sectorId"`
+	Quarantine   *bool             `json:"quarantine"`
+	// RemoveSectorID is set if the sector is removed with null.
+	RemoveSectorID bool `json:"-"`
+}
+
+func (r *PatchCageRequest) remove(name string) bool {
+	if name != "sectorId" {
+		return false
+	}
+	r.RemoveSectorID = true
+
+	return true
+}
+
+// Patch returns the cage patch.
+func (r PatchCageRequest) Patch() app.CagePatch {
+	patch := app.CagePatch{
+		Type:         r.Type,
+		Status:       r.Status,
+		Capacity:     r.Capacity,
+		CapacityUnit: r.CapacityUnit,
+		SectorID:     r.SectorID,
+		Quarantine:   r.Quarantine,
+	}
+	if r.RemoveSectorID {
+		none := ""
+		patch.SectorID = &none
+	}
+
+	return patch
+}
+
+// PatchCage changes the type, the status, the capacity, the capacity unit, the sector
+// and/or the quarantine designation of a cage.
+// The type can't be changed to one the occupants can't live in,
+// the capacity can't go below the occupancy in the capacity unit
+// and the designation can't be changed unless the occupants comply with it.
+// A null sector removes the cage from its sector.
+// PATCH /cages/:id
+func (s *Server) PatchCage() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+		id := chi.URLParam(r, "id")
+
+		if err := app.ValidateID(id); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var req PatchCageRequest
+		if err := decodeMergePatch(r.Body, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		// The sector is removed with null, an empty one is not an ID.
+		if req.SectorID != nil {
+			if err := app.ValidateID(*req.SectorID); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		patch := req.Patch()
+		if err := patch.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var (
+			cage *app.Cage
+			err  error
+		)
+		if patch.IsEmpty() {
+			cage, err = s.CageStore.Get(r.Context(), id, app.GetOptions{})
+		} else {
+			cage, err = s.CageStore.Update(r.Context(), id, patch)
+		}
+		if err != nil {
+			switch {
+			case errors.Is(err, app.ErrNotFound):
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			case errors.Is(err, app.ErrConflict):
+				http.Error(w, http.StatusText(http.StatusConflict), http.StatusConflict)
+			case errors.Is(err, app.ErrCapacityBelowOccupancy), errors.Is(err, app.ErrSectorNotFound),
+				errors.Is(err, app.ErrHabitatMismatch), errors.Is(err, app.ErrQuarantineMismatch),
+				errors.Is(err, app.ErrSpeciesMismatch):
+				http.Error(w, err.Error(), http.StatusConflict)
+			default:
+				logger.Error("Error updating cage", "error", err)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		response := struct {
+			Data *app.Cage `json:"data"`
+		}{
+			Data: cage,
+		}
+
+		if err = json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			return
+		}
+	}
+}
+
+// ResizeCageRequest is a request to change the capacity of a cage.
+type ResizeCageRequest struct {
+	Capacity int `json:"capacity"`
+}
+
+// Validate validates the request.
+func (r ResizeCageRequest) Validate() error {
+	return app.ValidateCapacity(r.Capacity)
+}
+
+// ResizeCage changes the capacity of a cage.
+// The capacity can't go below the current occupancy.
+// PUT /cages/:id/capacity
+func (s *Server) ResizeCage() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+		id := chi.URLParam(r, "id")
+
+		if err := app.ValidateID(id); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var req ResizeCageRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.Error("Error decoding request body", "error", err)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		if err := req.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		cage, err := s.CageStore.Resize(r.Context(), id, req.Capacity)
+		if err != nil {
+			switch {
+			case errors.Is(err, app.ErrNotFound):
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			case errors.Is(err, app.ErrCapacityBelowOccupancy):
+				http.Error(w, err.Error(), http.StatusConflict)
+			default:
+				logger.Error("Error resizing cage", "error", err)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		response := struct {
+			Data *app.Cage `json:"data"`
+		}{
+			Data: cage,
+		}
+
+		if err = json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			return
+		}
+	}
+}
+
+// ListCageHistory lists the recorded changes of a cage.
+// GET /cages/:id/history
+func (s *Server) ListCageHistory() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+		id := chi.URLParam(r, "id")
+
+		if err := app.ValidateID(id); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		history, err := s.CageStore.History(r.Context(), id)
+		if err != nil {
+			if err == app.ErrNotFound {
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+				return
+			}
+
+			logger.Error("Error getting cage history", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		if history == nil {
+			history = []app.CageHistoryEntry{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		response := struct {
+			Data []app.CageHistoryEntry `json:"data"`
+		}{
+			Data: history,
+		}
+
+		if err = json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			return
+		}
+	}
+}
+
+// DeleteCage soft deletes a cage.
 // DELETE /cages/:id
 func (s *Server) DeleteCage() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -244,6 +559,46 @@ func (s *Server) DeleteCage() http.HandlerFunc {
 			return
 		}
 
-		w.WriteHeader(http.StatusOK)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// RestoreCage restores a soft deleted cage.
+// POST /cages/:id/restore
+func (s *Server) RestoreCage() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+		id := chi.URLParam(r, "id")
+
+		if err := app.ValidateID(id); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		cage, err := s.CageStore.Restore(r.Context(), id)
+		if err != nil {
+			if err == app.ErrNotFound {
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+				return
+			}
+
+			logger.Error("Error restoring cage", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		response := struct {
+			Data *app.Cage `json:"data"`
+		}{
+			Data: cage,
+		}
+
+		if err = json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			return
+		}
 	}
 }
@@ -12,6 +12,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -23,10 +24,14 @@ import (
 )
 
 type fakeCageStore struct {
-	cage   app.Cage
-	id     string
-	status app.CageStatus
-	err    error
+	cage    app.Cage
+	id      string
+	status  app.CageStatus
+	patch   app.CagePatch
+	history []app.CageHistoryEntry
+	filter  app.CageFilter
+	opts    app.GetOptions
+	err     error
 }
 
 func (s *fakeCageStore) Add(_ context.Context, cage *app.Cage) (*app.Cage, error) {
@@ -37,6 +42,12 @@ func (s *fakeCageStore) Add(_ context.Context, cage *app.Cage) (*app.Cage, error
 	now := time.Now()
 	s.cage = *cage
 	s.cage.ID = uuid.NewString()
+	if s.cage.Type == app.CageTypeUnspecified {
+		s.cage.Type = app.CageTypeGeneral
+	}
+	if s.cage.CapacityUnit == app.CapacityUnitUnspecified {
+		s.cage.CapacityUnit = app.CapacityUnitHeads
+	}
 	s.cage.CreatedAt = now
 	s.cage.UpdatedAt = now
 	c := s.cage
@@ -44,23 +55,25 @@ func (s *fakeCageStore) Add(_ context.Context, cage *app.Cage) (*app.Cage, error
 	return &c, nil
 }
 
-func (s *fakeCageStore) Get(_ context.Context, id string) (*app.Cage, error) {
+func (s *fakeCageStore) Get(_ context.Context, id string, opts app.GetOptions) (*app.Cage, error) {
 	if s.err != nil {
 		return nil, s.err
 	}
 
 	c := s.cage
 	s.id = id
+	s.opts = opts
 
 	return &c, nil
 }
 
-func (s *fakeCageStore) List(_ context.Context, status app.CageStatus) ([]app.Cage, error) {
+func (s *fakeCageStore) List(_ context.Context, filter app.CageFilter) ([]app.Cage, error) {
 	if s.err != nil {
 		return nil, s.err
 	}
 
-	s.status = status
+	s.status = filter.Status
+	s.filter = filter
 
 	if s.cage.ID == "" {
 		return nil, nil
@@ -82,6 +95,67 @@ func (s *fakeCageStore) ChangeStatus(_ context.Context, id string, status app.Ca
 	return &c, nil
 }
 
+func (s *fakeCageStore) Update(_ context.Context, id string, patch app.CagePatch) (*app.Cage, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	if patch.Status != nil {
+		s.cage.Status = *patch.Status
+	}
+	if patch.Capacity != nil {
+		s.cage.Capacity = *patch.Capacity
+	}
+	if patch.CapacityUnit != nil {
+		s.cage.CapacityUnit = *patch.CapacityUnit
+	}
+	if patch.Quarantine != nil {
+		s.cage.Quarantine = *patch.Quarantine
+	}
+	if patch.SectorID != nil {
+		s.cage.SectorID = *patch.SectorID
+	}
+	s.id = id
+	s.patch = patch
+	c := s.cage
+
+	return &c, nil
+}
+
+func (s *fakeCageStore) Resize(_ context.Context, id string, capacity int) (*app.Cage, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	s.id = id
+	s.cage.Capacity = capacity
+	c := s.cage
+
+	return &c, nil
+}
+
+func (s *fakeCageStore) History(_ context.Context, id string) ([]app.CageHistoryEntry, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	s.id = id
+
+	return s.history, nil
+}
+
+func (s *fakeCageStore) Restore(_ context.Context, id string) (*app.Cage, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	s.id = id
+	s.cage.DeletedAt = nil
+	c := s.cage
+
+	return &c, nil
+}
+
 func (s *fakeCageStore) Delete(_ context.Context, id string) error {
 	if s.err != nil {
 		return s.err
@@ -98,12 +172,14 @@ func TestListCages(t *testing.T) {
 	now := time.Now()
 	store := &fakeCageStore{
 		cage: app.Cage{
-			ID:        uuid.NewString(),
-			Capacity:  1,
-			Status:    app.CageStatusActive,
-			Occupancy: 1,
-			CreatedAt: now,
-			UpdatedAt: now,
+			ID:           uuid.NewString(),
+			Type:         app.CageTypePaddock,
+			Capacity:     1,
+			CapacityUnit: app.CapacityUnitHeads,
+			Status:       app.CageStatusActive,
+			Occupancy:    1,
+			CreatedAt:    now,
+			UpdatedAt:    now,
 		},
 	}
 
@@ -115,7 +191,7 @@ func TestListCages(t *testing.T) {
 	w := httptest.NewRecorder()
 	r := httptest.NewRequest(http.MethodGet, "/cages", nil)
 
-	svc.ListCages().ServeHTTP(w, r)
+	validated(t, svc.ListCages()).ServeHTTP(w, r)
 
 	if want, got := http.StatusOK, w.Code; want != got {
 		t.Fatalf("Expected %d got %d", want, got)
@@ -157,7 +233,7 @@ func TestListCages(t *testing.T) {
 	}
 }
 
-func TestListCagesInvalidFilter(t *testing.T) {
+func TestListCagesFilter(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
 
 	store := &fakeCageStore{}
@@ -167,13 +243,69 @@ func TestListCagesInvalidFilter(t *testing.T) {
 	}
 
 	w := httptest.NewRecorder()
-	r := httptest.NewRequest(http.MethodGet, "/cages?status=foo", nil)
+	r := httptest.NewRequest(http.MethodGet, "/cages?type=paddock&status=active&quarantine=false&minFreeCapacity=2&occupancy=empty"+
+		"&createdAfter=2023-01-02T03:04:05Z&updatedBefore=2023-02-03T04:05:06Z&sort=-occupancy,createdAt", nil)
 
-	svc.ListCages().ServeHTTP(w, r)
+	validated(t, svc.ListCages()).ServeHTTP(w, r)
 
-	if want, got := http.StatusBadRequest, w.Code; want != got {
+	if want, got := http.StatusOK, w.Code; want != got {
 		t.Fatalf("Expected %d got %d", want, got)
 	}
+
+	quarantine := false
+	want := app.CageFilter{
+		Type:            app.CageTypePaddock,
+		Status:          app.CageStatusActive,
+		Quarantine:      &quarantine,
+		MinFreeCapacity: 2,
+		Occupancy:       app.CageOccupancyEmpty,
+		Created:         app.TimeRange{After: time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)},
+		Updated:         app.TimeRange{Before: time.Date(2023, 2, 3, 4, 5, 6, 0, time.UTC)},
+		Sort:            []app.Sort{{Field: "occupancy", Desc: true}, {Field: "createdAt"}},
+	}
+	if got := store.filter; !reflect.DeepEqual(want, got) {
+		t.Fatalf("Expected filter %+v got %+v", want, got)
+	}
+}
+
+func TestListCagesInvalidFilter(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"type", "type=moat"},
+		{"status", "status=foo"},
+		{"min free capacity", "minFreeCapacity=0"},
+		{"min free capacity not a number", "minFreeCapacity=foo"},
+		{"occupancy", "occupancy=half"},
+		{"created after", "createdAfter=yesterday"},
+		{"updated before", "updatedBefore=2023-01-02"},
+		{"sort field", "sort=name"},
+		{"sort direction", "sort=%2Bcapacity"},
+		{"zone", "zoneId=foo"},
+		{"sector", "sectorId=foo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeCageStore{}
+			svc := &Server{
+				Logger:    logger,
+				CageStore: store,
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/cages?"+tt.query, nil)
+
+			validated(t, svc.ListCages()).ServeHTTP(w, r)
+
+			if want, got := http.StatusBadRequest, w.Code; want != got {
+				t.Fatalf("Expected %d got %d", want, got)
+			}
+		})
+	}
 }
 
 func TestListCagesEmptyListIsRenderedCorrectly(t *testing.T) {
@@ -187,7 +319,7 @@ func TestListCagesEmptyListIsRenderedCorrectly(t *testing.T) {
 	w := httptest.NewRecorder()
 	r := httptest.NewRequest(http.MethodGet, "/cages", nil)
 
-	svc.ListCages().ServeHTTP(w, r)
+	validated(t, svc.ListCages()).ServeHTTP(w, r)
 
 	if want, got := http.StatusOK, w.Code; want != got {
 		t.Fatalf("Expected %d got %d", want, got)
@@ -213,7 +345,7 @@ func TestListCagesInternalError(t *testing.T) {
 	w := httptest.NewRecorder()
 	r := httptest.NewRequest(http.MethodGet, "/cages", nil)
 
-	svc.ListCages().ServeHTTP(w, r)
+	validated(t, svc.ListCages()).ServeHTTP(w, r)
 
 	if want, got := http.StatusInternalServerError, w.Code; want != got {
 		t.Fatalf("Expected %d got %d", want, got)
@@ -230,12 +362,12 @@ func TestAddCage(t *testing.T) {
 		CageStore: store,
 	}
 
-	body := `{"capacity": 1, "status": "active"}`
+	body := `{"type": "aquatic", "capacity": 1, "capacityUnit": "space", "status": "active"}`
 
 	w := httptest.NewRecorder()
 	r := httptest.NewRequest(http.MethodPost, "/cages", strings.NewReader(body))
 
-	svc.AddCage().ServeHTTP(w, r)
+	validated(t, svc.AddCage()).ServeHTTP(w, r)
 
 	if want, got := http.StatusCreated, w.Code; want != got {
 		t.Fatalf("Expected %d got %d", want, got)
@@ -256,12 +388,18 @@ func TestAddCage(t *testing.T) {
 	if response.Data.ID == "" {
 		t.Fatalf("Expected ID got empty")
 	}
+	if want, got := app.CageTypeAquatic, response.Data.Type; want != got {
+		t.Fatalf("Expected Type %s got %s", want, got)
+	}
 	if want, got := app.CageStatusActive, response.Data.Status; want != got {
 		t.Fatalf("Expected Status %s got %s", want, got)
 	}
 	if want, got := 1, response.Data.Capacity; want != got {
 		t.Fatalf("Expected Capacity %d got %d", want, got)
 	}
+	if want, got := app.CapacityUnitSpace, response.Data.CapacityUnit; want != got {
+		t.Fatalf("Expected CapacityUnit %s got %s", want, got)
+	}
 	if want, got := 0, response.Data.Occupancy; want != got {
 		t.Fatalf("Expected ID %d got %d", want, got)
 	}
@@ -306,6 +444,18 @@ func TestAddCageBadRequest(t *testing.T) {
 			desc: "invalid request body",
 			body: `{"capacity": 1, "status": "foo"`,
 		},
+		{
+			desc: "invalid sector",
+			body: `{"capacity": 1, "status": "active", "sectorId": "foo"}`,
+		},
+		{
+			desc: "invalid type",
+			body: `{"type": "moat", "capacity": 1, "status": "active"}`,
+		},
+		{
+			desc: "invalid capacity unit",
+			body: `{"capacity": 1, "capacityUnit": "tons", "status": "active"}`,
+		},
 	}
 
 	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
@@ -325,7 +475,7 @@ func TestAddCageBadRequest(t *testing.T) {
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest(http.MethodPost, "/cages", bodyReader)
 
-			svc.AddCage().ServeHTTP(w, r)
+			validated(t, svc.AddCage()).ServeHTTP(w, r)
 
 			if want, got := http.StatusBadRequest, w.Code; want != got {
 				t.Fatalf("Expected %d got %d", want, got)
@@ -346,12 +496,12 @@ func TestAddCageInternalError(t *testing.T) {
 		CageStore: store,
 	}
 
-	body := `{"capacity": 1, "status": "active"}`
+	body := `{"type": "aquatic", "capacity": 1, "capacityUnit": "space", "status": "active"}`
 
 	w := httptest.NewRecorder()
 	r := httptest.NewRequest(http.MethodPost, "/cages", strings.NewReader(body))
 
-	svc.AddCage().ServeHTTP(w, r)
+	validated(t, svc.AddCage()).ServeHTTP(w, r)
 
 	if want, got := http.StatusInternalServerError, w.Code; want != got {
 		t.Fatalf("Expected %d got %d", want, got)
@@ -365,12 +515,14 @@ func TestGetCage(t *testing.T) {
 	now := time.Now()
 	store := &fakeCageStore{
 		cage: app.Cage{
-			ID:        uuid.NewString(),
-			Capacity:  1,
-			Status:    app.CageStatusActive,
-			Occupancy: 1,
-			CreatedAt: now,
-			UpdatedAt: now,
+			ID:           uuid.NewString(),
+			Type:         app.CageTypePaddock,
+			Capacity:     1,
+			CapacityUnit: app.CapacityUnitHeads,
+			Status:       app.CageStatusActive,
+			Occupancy:    1,
+			CreatedAt:    now,
+			UpdatedAt:    now,
 		},
 	}
 
@@ -386,7 +538,7 @@ func TestGetCage(t *testing.T) {
 	rctx.URLParams.Add("id", id)
 	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
 
-	svc.GetCage().ServeHTTP(w, r)
+	validated(t, svc.GetCage()).ServeHTTP(w, r)
 
 	if want, got := http.StatusOK, w.Code; want != got {
 		t.Fatalf("Expected %d got %d", want, got)
@@ -444,7 +596,7 @@ func TestGetCageNotFoundError(t *testing.T) {
 	rctx.URLParams.Add("id", id)
 	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
 
-	svc.GetCage().ServeHTTP(w, r)
+	validated(t, svc.GetCage()).ServeHTTP(w, r)
 
 	if want, got := http.StatusNotFound, w.Code; want != got {
 		t.Fatalf("Expected %d got %d", want, got)
@@ -458,12 +610,14 @@ func TestChangeCageStatus(t *testing.T) {
 	now := time.Now()
 	store := &fakeCageStore{
 		cage: app.Cage{
-			ID:        id,
-			Capacity:  1,
-			Status:    app.CageStatusActive,
-			Occupancy: 0,
-			CreatedAt: now,
-			UpdatedAt: now,
+			ID:           id,
+			Type:         app.CageTypePaddock,
+			Capacity:     1,
+			CapacityUnit: app.CapacityUnitHeads,
+			Status:       app.CageStatusActive,
+			Occupancy:    0,
+			CreatedAt:    now,
+			UpdatedAt:    now,
 		},
 	}
 
@@ -481,7 +635,7 @@ func TestChangeCageStatus(t *testing.T) {
 	rctx.URLParams.Add("id", id)
 	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
 
-	svc.ChangeCageStatus().ServeHTTP(w, r)
+	validated(t, svc.ChangeCageStatus()).ServeHTTP(w, r)
 
 	if want, got := http.StatusOK, w.Code; want != got {
 		t.Fatalf("Expected %d got %d", want, got)
@@ -533,7 +687,7 @@ func TestChangeCageStatusNotFoundError(t *testing.T) {
 	rctx.URLParams.Add("id", id)
 	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
 
-	svc.ChangeCageStatus().ServeHTTP(w, r)
+	validated(t, svc.ChangeCageStatus()).ServeHTTP(w, r)
 
 	if want, got := http.StatusNotFound, w.Code; want != got {
 		t.Fatalf("Expected %d got %d", want, got)
@@ -562,7 +716,7 @@ func TestChangeCageStatusInternalError(t *testing.T) {
 	rctx.URLParams.Add("id", id)
 	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
 
-	svc.ChangeCageStatus().ServeHTTP(w, r)
+	validated(t, svc.ChangeCageStatus()).ServeHTTP(w, r)
 
 	if want, got := http.StatusInternalServerError, w.Code; want != got {
 		t.Fatalf("Expected %d got %d", want, got)
@@ -591,7 +745,7 @@ func TestChangeCageStatusConflict(t *testing.T) {
 	rctx.URLParams.Add("id", id)
 	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
 
-	svc.ChangeCageStatus().ServeHTTP(w, r)
+	validated(t, svc.ChangeCageStatus()).ServeHTTP(w, r)
 
 	if want, got := http.StatusConflict, w.Code; want != got {
 		t.Fatalf("Expected %d got %d", want, got)
@@ -618,7 +772,7 @@ func TestChangeCageStatusBadRequest(t *testing.T) {
 	rctx.URLParams.Add("id", id)
 	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
 
-	svc.ChangeCageStatus().ServeHTTP(w, r)
+	validated(t, svc.ChangeCageStatus()).ServeHTTP(w, r)
 
 	if want, got := http.StatusBadRequest, w.Code; want != got {
 		t.Fatalf("Expected %d got %d", want, got)
@@ -643,12 +797,503 @@ func TestDeleteCage(t *testing.T) {
 	rctx.URLParams.Add("id", id)
 	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
 
-	svc.DeleteCage().ServeHTTP(w, r)
+	validated(t, svc.DeleteCage()).ServeHTTP(w, r)
 
-	if want, got := http.StatusOK, w.Code; want != got {
+	if want, got := http.StatusNoContent, w.Code; want != got {
 		t.Fatalf("Expected %d got %d", want, got)
 	}
 	if want, got := id, store.id; want != got {
 		t.Fatalf("Expected %s got %s", want, got)
 	}
 }
+
+func TestPatchCage(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	id := uuid.NewString()
+	now := time.Now()
+	store := &fakeCageStore{
+		cage: app.Cage{
+			ID:           id,
+			Type:         app.CageTypePaddock,
+			Capacity:     1,
+			CapacityUnit: app.CapacityUnitHeads,
+			Status:       app.CageStatusActive,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		},
+	}
+
+	svc := &Server{
+		Logger:    logger,
+		CageStore: store,
+	}
+
+	body := `{"status": "down", "capacity": 5, "capacityUnit": "space", "quarantine": true}`
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPatch, "/cages/"+id, strings.NewReader(body))
+	r.Header.Set("Content-Type", mergePatchContentType)
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", id)
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+	validated(t, svc.PatchCage()).ServeHTTP(w, r)
+
+	if want, got := http.StatusOK, w.Code; want != got {
+		t.Fatalf("Expected %d got %d", want, got)
+	}
+	if want, got := id, store.id; want != got {
+		t.Fatalf("Expected ID %s got %s", want, got)
+	}
+	if store.patch.Status == nil || store.patch.Capacity == nil || store.patch.CapacityUnit == nil || store.patch.Quarantine == nil {
+		t.Fatalf("Expected all fields to be patched got %+v", store.patch)
+	}
+
+	response := struct {
+		Data app.Cage `json:"data"`
+	}{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := app.CageStatusDown, response.Data.Status; want != got {
+		t.Fatalf("Expected Status %s got %s", want, got)
+	}
+	if want, got := 5, response.Data.Capacity; want != got {
+		t.Fatalf("Expected Capacity %d got %d", want, got)
+	}
+	if want, got := app.CapacityUnitSpace, response.Data.CapacityUnit; want != got {
+		t.Fatalf("Expected CapacityUnit %s got %s", want, got)
+	}
+	if !response.Data.Quarantine {
+		t.Fatal("Expected a quarantine cage")
+	}
+}
+
+func TestPatchCageRemoveSector(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	id := uuid.NewString()
+	now := time.Now()
+	store := &fakeCageStore{
+		cage: app.Cage{
+			ID:           id,
+			Type:         app.CageTypePaddock,
+			Capacity:     1,
+			CapacityUnit: app.CapacityUnitHeads,
+			Status:       app.CageStatusActive,
+			SectorID:     uuid.NewString(),
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		},
+	}
+
+	svc := &Server{
+		Logger:    logger,
+		CageStore: store,
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPatch, "/cages/"+id, strings.NewReader(`{"sectorId": null}`))
+	r.Header.Set("Content-Type", mergePatchContentType)
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", id)
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+	validated(t, svc.PatchCage()).ServeHTTP(w, r)
+
+	if want, got := http.StatusOK, w.Code; want != got {
+		t.Fatalf("Expected %d got %d", want, got)
+	}
+	if store.patch.SectorID == nil || *store.patch.SectorID != "" {
+		t.Fatalf("Expected the sector to be removed got %+v", store.patch)
+	}
+
+	response := struct {
+		Data app.Cage `json:"data"`
+	}{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := "", response.Data.SectorID; want != got {
+		t.Fatalf("Expected no SectorID got %s", got)
+	}
+}
+
+func TestPatchCageErrors(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	tests := []struct {
+		name   string
+		body   string
+		err    error
+		status int
+	}{
+		{"not an object", `[]`, nil, http.StatusBadRequest},
+		{"unknown field", `{"occupancy": 1}`, nil, http.StatusBadRequest},
+		{"null field", `{"status": null}`, nil, http.StatusBadRequest},
+		{"invalid status", `{"status": "foo"}`, nil, http.StatusBadRequest},
+		{"invalid capacity", `{"capacity": 0}`, nil, http.StatusBadRequest},
+		{"not found", `{"capacity": 1}`, app.ErrNotFound, http.StatusNotFound},
+		{"occupied", `{"status": "down"}`, app.ErrConflict, http.StatusConflict},
+		{"below occupancy", `{"capacity": 1}`, app.ErrCapacityBelowOccupancy, http.StatusConflict},
+		{"invalid sector", `{"sectorId": "foo"}`, nil, http.StatusBadRequest},
+		{"empty sector", `{"sectorId": ""}`, nil, http.StatusBadRequest},
+		{"sector not found", `{"sectorId": "6d3f5e8a-1b1c-4c9e-8c1d-2f9b1a7e4c3d"}`, app.ErrSectorNotFound, http.StatusConflict},
+		{"invalid type", `{"type": "moat"}`, nil, http.StatusBadRequest},
+		{"invalid capacity unit", `{"capacityUnit": "tons"}`, nil, http.StatusBadRequest},
+		{"unit below occupancy", `{"capacityUnit": "space"}`, &app.OccupancyError{Occupancy: 8}, http.StatusConflict},
+		{"habitat mismatch", `{"type": "aviary"}`, app.ErrHabitatMismatch, http.StatusConflict},
+		{"invalid quarantine", `{"quarantine": "yes"}`, nil, http.StatusBadRequest},
+		{"quarantine mismatch", `{"quarantine": true}`, app.ErrQuarantineMismatch, http.StatusConflict},
+		{"mixed species in quarantine", `{"quarantine": true}`, app.ErrSpeciesMismatch, http.StatusConflict},
+		{"store error", `{"capacity": 1}`, errors.New("store error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := uuid.NewString()
+			svc := &Server{
+				Logger:    logger,
+				CageStore: &fakeCageStore{err: tt.err},
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPatch, "/cages/"+id, strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", mergePatchContentType)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", id)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			validated(t, svc.PatchCage()).ServeHTTP(w, r)
+
+			if want, got := tt.status, w.Code; want != got {
+				t.Fatalf("Expected %d got %d", want, got)
+			}
+		})
+	}
+}
+
+func TestResizeCage(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	id := uuid.NewString()
+	now := time.Now()
+	store := &fakeCageStore{
+		cage: app.Cage{
+			ID:           id,
+			Type:         app.CageTypePaddock,
+			Capacity:     1,
+			CapacityUnit: app.CapacityUnitHeads,
+			Status:       app.CageStatusActive,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		},
+	}
+
+	svc := &Server{
+		Logger:    logger,
+		CageStore: store,
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPut, "/cages/"+id+"/capacity", strings.NewReader(`{"capacity": 4}`))
+	r.Header.Set("Content-Type", jsonContentType)
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", id)
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+	validated(t, svc.ResizeCage()).ServeHTTP(w, r)
+
+	if want, got := http.StatusOK, w.Code; want != got {
+		t.Fatalf("Expected %d got %d", want, got)
+	}
+	if want, got := id, store.id; want != got {
+		t.Fatalf("Expected ID %s got %s", want, got)
+	}
+
+	response := struct {
+		Data app.Cage `json:"data"`
+	}{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := 4, response.Data.Capacity; want != got {
+		t.Fatalf("Expected Capacity %d got %d", want, got)
+	}
+}
+
+func TestResizeCageErrors(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	tests := []struct {
+		name   string
+		body   string
+		err    error
+		status int
+		text   string
+	}{
+		{"missing capacity", `{}`, nil, http.StatusBadRequest, ""},
+		{"invalid capacity", `{"capacity": 0}`, nil, http.StatusBadRequest, ""},
+		{"not found", `{"capacity": 1}`, app.ErrNotFound, http.StatusNotFound, ""},
+		{"below occupancy", `{"capacity": 1}`, &app.OccupancyError{Occupancy: 3}, http.StatusConflict, "capacity below occupancy of 3"},
+		{"store error", `{"capacity": 1}`, errors.New("store error"), http.StatusInternalServerError, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := uuid.NewString()
+			svc := &Server{
+				Logger:    logger,
+				CageStore: &fakeCageStore{err: tt.err},
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPut, "/cages/"+id+"/capacity", strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", jsonContentType)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", id)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			validated(t, svc.ResizeCage()).ServeHTTP(w, r)
+
+			if want, got := tt.status, w.Code; want != got {
+				t.Fatalf("Expected %d got %d", want, got)
+			}
+			if tt.text != "" {
+				if want, got := tt.text, strings.TrimSpace(w.Body.String()); want != got {
+					t.Fatalf("Expected body %q got %q", want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestListCageHistory(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	id := uuid.NewString()
+	store := &fakeCageStore{
+		history: []app.CageHistoryEntry{
+			{ID: 1, CageID: id, Change: app.CageChangeCapacity, From: "1", To: "4", CreatedAt: time.Now()},
+		},
+	}
+
+	svc := &Server{
+		Logger:    logger,
+		CageStore: store,
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/cages/"+id+"/history", nil)
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", id)
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+	validated(t, svc.ListCageHistory()).ServeHTTP(w, r)
+
+	if want, got := http.StatusOK, w.Code; want != got {
+		t.Fatalf("Expected %d got %d", want, got)
+	}
+
+	response := struct {
+		Data []app.CageHistoryEntry `json:"data"`
+	}{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := 1, len(response.Data); want != got {
+		t.Fatalf("Expected entries %d got %d", want, got)
+	}
+	if want, got := app.CageChangeCapacity, response.Data[0].Change; want != got {
+		t.Fatalf("Expected Change %s got %s", want, got)
+	}
+
+	// An unknown cage.
+	svc.CageStore = &fakeCageStore{err: app.ErrNotFound}
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodGet, "/cages/"+id+"/history", nil)
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+	validated(t, svc.ListCageHistory()).ServeHTTP(w, r)
+
+	if want, got := http.StatusNotFound, w.Code; want != got {
+		t.Fatalf("Expected %d got %d", want, got)
+	}
+}
+
+func TestListCagesIncludeDeleted(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	deletedAt := time.Now()
+	store := &fakeCageStore{
+		cage: app.Cage{
+			ID:           uuid.NewString(),
+			Type:         app.CageTypePaddock,
+			Capacity:     1,
+			CapacityUnit: app.CapacityUnitHeads,
+			Status:       app.CageStatusActive,
+			CreatedAt:    deletedAt,
+			UpdatedAt:    deletedAt,
+			DeletedAt:    &deletedAt,
+		},
+	}
+	svc := &Server{
+		Logger:    logger,
+		CageStore: store,
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/cages?includeDeleted=true", nil)
+
+	validated(t, svc.ListCages()).ServeHTTP(w, r)
+
+	if want, got := http.StatusOK, w.Code; want != got {
+		t.Fatalf("Expected %d got %d", want, got)
+	}
+	if !store.filter.IncludeDeleted {
+		t.Fatal("Expected IncludeDeleted")
+	}
+
+	response := struct {
+		Data []app.Cage `json:"data"`
+	}{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	if response.Data[0].DeletedAt == nil {
+		t.Fatal("Expected DeletedAt got empty")
+	}
+
+	// An invalid value is rejected.
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodGet, "/cages?includeDeleted=maybe", nil)
+
+	validated(t, svc.ListCages()).ServeHTTP(w, r)
+
+	if want, got := http.StatusBadRequest, w.Code; want != got {
+		t.Fatalf("Expected %d got %d", want, got)
+	}
+}
+
+func TestGetCageIncludeDeleted(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	id := uuid.NewString()
+	store := &fakeCageStore{
+		cage: app.Cage{
+			ID:           id,
+			Type:         app.CageTypePaddock,
+			Capacity:     1,
+			CapacityUnit: app.CapacityUnitHeads,
+			Status:       app.CageStatusActive,
+		},
+	}
+	svc := &Server{
+		Logger:    logger,
+		CageStore: store,
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/cages/"+id+"?includeDeleted=true", nil)
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", id)
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+	validated(t, svc.GetCage()).ServeHTTP(w, r)
+
+	if want, got := http.StatusOK, w.Code; want != got {
+		t.Fatalf("Expected %d got %d", want, got)
+	}
+	if !store.opts.IncludeDeleted {
+		t.Fatal("Expected IncludeDeleted")
+	}
+}
+
+func TestRestoreCage(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"restored", nil, http.StatusOK},
+		{"not found", app.ErrNotFound, http.StatusNotFound},
+		{"store error", errors.New("store error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := uuid.NewString()
+			deletedAt := time.Now()
+			store := &fakeCageStore{
+				cage: app.Cage{
+					ID:           id,
+					Type:         app.CageTypePaddock,
+					Capacity:     1,
+					CapacityUnit: app.CapacityUnitHeads,
+					Status:       app.CageStatusActive,
+					CreatedAt:    deletedAt,
+					UpdatedAt:    deletedAt,
+					DeletedAt:    &deletedAt,
+				},
+				err: tt.err,
+			}
+			svc := &Server{
+				Logger:    logger,
+				CageStore: store,
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/cages/"+id+"/restore", nil)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", id)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			validated(t, svc.RestoreCage()).ServeHTTP(w, r)
+
+			if want, got := tt.status, w.Code; want != got {
+				t.Fatalf("Expected %d got %d", want, got)
+			}
+			if tt.err != nil {
+				return
+			}
+
+			if want, got := id, store.id; want != got {
+				t.Fatalf("Expected ID %s got %s", want, got)
+			}
+
+			response := struct {
+				Data app.Cage `json:"data"`
+			}{}
+
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+
+			if response.Data.DeletedAt != nil {
+				t.Fatalf("Expected DeletedAt to be empty got %v", response.Data.DeletedAt)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// CORSOptions defines the CORS policy.
+type CORSOptions struct {
+	AllowedOrigins []string
+	AllowedMethods []string
+	AllowedHeaders []string
+	MaxAge         time.Duration
+}
+
+// CORSPolicy is a CORS policy that can be replaced at runtime.
+type CORSPolicy struct {
+	options atomic.Pointer[CORSOptions]
+}
+
+// NewCORSPolicy creates a new CORS policy.
+func NewCORSPolicy(options CORSOptions) *CORSPolicy {
+	p := &CORSPolicy{}
+	p.Set(options)
+
+	return p
+}
+
+// Set replaces the CORS options.
+func (p *CORSPolicy) Set(options CORSOptions) {
+	p.options.Store(&options)
+}
+
+// allowedOrigin returns the value of Access-Control-Allow-Origin for the origin if it is allowed.
+func (o *CORSOptions) allowedOrigin(origin string) (string, bool) {
+	for _, allowed := range o.AllowedOrigins {
+		if allowed == "*" {
+			return "*", true
+		}
+		if strings.EqualFold(allowed, origin) {
+			return origin, true
+		}
+	}
+
+	return "", false
+}
+
+// CORS is a middleware that handles cross-origin requests
+// according to the policy. Preflight requests are answered directly.
+func CORS(policy *CORSPolicy) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			options := policy.options.Load()
+			w.Header().Add("Vary", "Origin")
+			allowed, ok := options.allowedOrigin(origin)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("Access-Control-Allow-Origin", allowed)
+
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+			if !preflight {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("Access-Control-Allow-Methods", strings.Join(options.AllowedMethods, ", "))
+			w.Header().Set("Access-Control-Allow-Headers", strings.Join(options.AllowedHeaders, ", "))
+			if options.MaxAge > 0 {
+				w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(options.MaxAge.Seconds())))
+			}
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+}
@@ -0,0 +1,107 @@
+//go:build unit
+// +build unit
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCORS(t *testing.T) {
+	policy := NewCORSPolicy(CORSOptions{
+		AllowedOrigins: []string{"https://example.com"},
+		AllowedMethods: []string{"GET", "POST"},
+		AllowedHeaders: []string{"Authorization"},
+		MaxAge:         time.Minute,
+	})
+
+	tests := []struct {
+		desc        string
+		method      string
+		origin      string
+		preflight   bool
+		code        int
+		allowOrigin string
+		maxAge      string
+	}{
+		{
+			desc:   "no origin",
+			method: http.MethodGet,
+			code:   http.StatusOK,
+		},
+		{
+			desc:   "not allowed origin",
+			method: http.MethodGet,
+			origin: "https://foo.com",
+			code:   http.StatusOK,
+		},
+		{
+			desc:        "allowed origin",
+			method:      http.MethodGet,
+			origin:      "https://example.com",
+			code:        http.StatusOK,
+			allowOrigin: "https://example.com",
+		},
+		{
+			desc:        "preflight",
+			method:      http.MethodOptions,
+			origin:      "https://example.com",
+			preflight:   true,
+			code:        http.StatusNoContent,
+			allowOrigin: "https://example.com",
+			maxAge:      "60",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(tt.method, "/foo", nil)
+			if tt.origin != "" {
+				r.Header.Set("Origin", tt.origin)
+			}
+			if tt.preflight {
+				r.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			}
+			h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+			CORS(policy)(h).ServeHTTP(w, r)
+
+			if want, got := tt.code, w.Code; want != got {
+				t.Errorf("Expected status code %d got %d", want, got)
+			}
+			if want, got := tt.allowOrigin, w.Header().Get("Access-Control-Allow-Origin"); want != got {
+				t.Errorf("Expected allow origin %s got %s", want, got)
+			}
+			if want, got := tt.maxAge, w.Header().Get("Access-Control-Max-Age"); want != got {
+				t.Errorf("Expected max age %s got %s", want, got)
+			}
+		})
+	}
+}
+
+func TestCORSPolicyReload(t *testing.T) {
+	policy := NewCORSPolicy(CORSOptions{})
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/foo", nil)
+	r.Header.Set("Origin", "https://example.com")
+	CORS(policy)(h).ServeHTTP(w, r)
+
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Fatalf("Expected no allow origin got %s", got)
+	}
+
+	policy.Set(CORSOptions{AllowedOrigins: []string{"*"}})
+
+	w = httptest.NewRecorder()
+	CORS(policy)(h).ServeHTTP(w, r)
+
+	if want, got := "*", w.Header().Get("Access-Control-Allow-Origin"); want != got {
+		t.Fatalf("Expected allow origin %s got %s", want, got)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -30,7 +31,7 @@ func (r AddDinosaurRequest) Validate() error {
 	return r.Species.Validate()
 }
 
-// AddDinosaur adds a dinosaur to a cage.
+// AddDinosaur adds an adult dinosaur to a cage.
 // POST /cages/:id/dinosaurs
 func (s *Server) AddDinosaur() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -64,7 +65,8 @@ func (s *Server) AddDinosaur() http.HandlerFunc {
 			switch err {
 			case app.ErrNotFound:
 				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
-			case app.ErrCagePoweredDown, app.ErrCapacityExceeded, app.ErrSpeciesMismatch:
+			case app.ErrCagePoweredDown, app.ErrCageLockedDown, app.ErrNotCertified, app.ErrCapacityExceeded, app.ErrSpeciesMismatch, app.ErrHabitatMismatch,
+				app.ErrQuarantineMismatch:
 				http.Error(w, err.Error(), http.StatusConflict)
 			default:
 				logger.Error("Error adding dinosaur", "error", err)
@@ -91,7 +93,7 @@ func (s *Server) AddDinosaur() http.HandlerFunc {
 }
 
 // ListCageDinosaurs lists dinosaurs in a cage.
-// GET /cages/:id/dinosaurs[?species=...]
+// GET /cages/:id/dinosaurs[?species=...][&diet=...][&stage=...][&quarantined=true|false][&namePrefix=...][&sort=...][&fields=...][&expand=cage][&includeDeleted=true]
 func (s *Server) ListCageDinosaurs() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		requestID := middleware.GetReqID(r.Context())
@@ -103,15 +105,25 @@ func (s *Server) ListCageDinosaurs() http.HandlerFunc {
 			return
 		}
 
-		species := app.DinosaurSpecies(r.URL.Query().Get("species"))
-		if !species.IsUnspecified() {
-			if err := species.Validate(); err != nil {
-				http.Error(w, err.Error(), http.StatusBadRequest)
-				return
-			}
+		filter, err := dinosaurFilter(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		filter.CageID = id
+
+		expanded, err := expand(r, "cage")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
 		}
 
-		dinosaurs, err := s.DinosaurStore.List(r.Context(), id, species)
+		fields := filter.Fields
+		if expanded["cage"] {
+			filter.Fields = withField(fields, "cageId")
+		}
+
+		dinosaurs, err := s.DinosaurStore.List(r.Context(), filter)
 		if err != nil {
 			if err == app.ErrNotFound {
 				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
@@ -126,13 +138,26 @@ func (s *Server) ListCageDinosaurs() http.HandlerFunc {
 			dinosaurs = []app.Dinosaur{}
 		}
 
-		w.Header().Set("Content-Type", "application/json")
-
 		response := struct {
-			Data []app.Dinosaur `json:"data"`
+			Data any `json:"data"`
 		}{
 			Data: dinosaurs,
 		}
+		if expanded["cage"] {
+			if response.Data, err = s.expandCages(r.Context(), dinosaurs, filter.IncludeDeleted); err != nil {
+				logger.Error("Error getting cages", "error", err)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+		}
+		if response.Data, err = sparse(response.Data, fields, expanded); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
 		if err = json.NewEncoder(w).Encode(response); err != nil {
 			logger.Error("Error marshalling response", "error", err)
 			return
@@ -141,21 +166,30 @@ func (s *Server) ListCageDinosaurs() http.HandlerFunc {
 }
 
 // ListAllDinosaurs lists all dinosaurs.
-// GET dinosaurs[&species=...]
+// GET /dinosaurs[?zoneId=...][&species=...][&diet=...][&stage=...][&quarantined=true|false][&namePrefix=...][&sort=...][&fields=...][&expand=cage][&includeDeleted=true]
 func (s *Server) ListAllDinosaurs() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		requestID := middleware.GetReqID(r.Context())
 		logger := s.Logger.With("requestId", requestID)
 
-		species := app.DinosaurSpecies(r.URL.Query().Get("species"))
-		if !species.IsUnspecified() {
-			if err := species.Validate(); err != nil {
-				http.Error(w, err.Error(), http.StatusBadRequest)
-				return
-			}
+		filter, err := dinosaurFilter(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		expanded, err := expand(r, "cage")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
 		}
 
-		dinosaurs, err := s.DinosaurStore.List(r.Context(), app.IDUnspecified, species)
+		fields := filter.Fields
+		if expanded["cage"] {
+			filter.Fields = withField(fields, "cageId")
+		}
+
+		dinosaurs, err := s.DinosaurStore.List(r.Context(), filter)
 		if err != nil {
 			if err == app.ErrNotFound {
 				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
@@ -170,13 +204,26 @@ func (s *Server) ListAllDinosaurs() http.HandlerFunc {
 			dinosaurs = []app.Dinosaur{}
 		}
 
-		w.Header().Set("Content-Type", "application/json")
-
 		response := struct {
-			Data []app.Dinosaur `json:"data"`
+			Data any `json:"data"`
 		}{
 			Data: dinosaurs,
 		}
+		if expanded["cage"] {
+			if response.Data, err = s.expandCages(r.Context(), dinosaurs, filter.IncludeDeleted); err != nil {
+				logger.Error("Error getting cages", "error", err)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+		}
+		if response.Data, err = sparse(response.Data, fields, expanded); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
 		if err = json.NewEncoder(w).Encode(response); err != nil {
 			logger.Error("Error marshalling response", "error", err)
 			return
@@ -185,7 +232,7 @@ func (s *Server) ListAllDinosaurs() http.HandlerFunc {
 }
 
 // GetDinosaur gets a dinosaur by id.
-// GET /dinosaurs/:id
+// GET /dinosaurs/:id[?fields=...][&expand=cage][&includeDeleted=true]
 func (s *Server) GetDinosaur() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		requestID := middleware.GetReqID(r.Context())
@@ -197,7 +244,24 @@ func (s *Server) GetDinosaur() http.HandlerFunc {
 			return
 		}
 
-		dinosaur, err := s.DinosaurStore.Get(r.Context(), id)
+		opts, err := getOptions(r, app.DinosaurFields)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		expanded, err := expand(r, "cage")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		fields := opts.Fields
+		if expanded["cage"] {
+			opts.Fields = withField(fields, "cageId")
+		}
+
+		dinosaur, err := s.DinosaurStore.Get(r.Context(), id, opts)
 		if err != nil {
 			if err == app.ErrNotFound {
 				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
@@ -209,13 +273,27 @@ func (s *Server) GetDinosaur() http.HandlerFunc {
 			return
 		}
 
-		w.Header().Set("Content-Type", "application/json")
-
 		response := struct {
-			Data *app.Dinosaur `json:"data"`
+			Data any `json:"data"`
 		}{
 			Data: dinosaur,
 		}
+		if expanded["cage"] {
+			dinosaurs, err := s.expandCages(r.Context(), []app.Dinosaur{*dinosaur}, opts.IncludeDeleted)
+			if err != nil {
+				logger.Error("Error getting cage", "error", err)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+			response.Data = dinosaurs[0]
+		}
+		if response.Data, err = sparse(response.Data, fields, expanded); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
 
 		if err := json.NewEncoder(w).Encode(response); err != nil {
 			logger.Error("Error marshalling response", "error", err)
@@ -270,7 +348,8 @@ func (s *Server) MoveDinosaur() http.HandlerFunc {
 				// NOTE: This can be improved by differentiating between
 				// a dinosaur or a cage being not found.
 				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
-			case app.ErrCagePoweredDown, app.ErrCapacityExceeded, app.ErrSpeciesMismatch:
+			case app.ErrCagePoweredDown, app.ErrCageLockedDown, app.ErrNotCertified, app.ErrCapacityExceeded, app.ErrSpeciesMismatch, app.ErrHabitatMismatch,
+				app.ErrQuarantineMismatch, app.ErrNotInCage:
 				http.Error(w, err.Error(), http.StatusConflict)
 			default:
 				logger.Error("Error moving dinosaur", "error", err)
@@ -295,8 +374,86 @@ func (s *Server) MoveDinosaur() http.HandlerFunc {
 	}
 }
 
-// DeleteDinosaur deletes a dinosaur.
-// DELETE /dinosaur/:id
+// PatchDinosaurRequest is a JSON merge patch of a dinosaur.
+type PatchDinosaurRequest struct {
+	Name   *string `json:"name"`
+	CageID *string `json:"cageId"`
+}
+
+// Patch returns the dinosaur patch.
+func (r PatchDinosaurRequest) Patch() app.DinosaurPatch {
+	return app.DinosaurPatch{
+		Name:   r.Name,
+		CageID: r.CageID,
+	}
+}
+
+// PatchDinosaur renames a dinosaur and/or moves it to a different cage.
+// PATCH /dinosaurs/:id
+func (s *Server) PatchDinosaur() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+		id := chi.URLParam(r, "id")
+
+		if err := app.ValidateID(id); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var req PatchDinosaurRequest
+		if err := decodeMergePatch(r.Body, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		patch := req.Patch()
+		if err := patch.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var (
+			dinosaur *app.Dinosaur
+			err      error
+		)
+		if patch.IsEmpty() {
+			dinosaur, err = s.DinosaurStore.Get(r.Context(), id, app.GetOptions{})
+		} else {
+			dinosaur, err = s.DinosaurStore.Update(r.Context(), id, patch)
+		}
+		if err != nil {
+			switch err {
+			case app.ErrNotFound:
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			case app.ErrCagePoweredDown, app.ErrCageLockedDown, app.ErrNotCertified, app.ErrCapacityExceeded, app.ErrSpeciesMismatch, app.ErrHabitatMismatch,
+				app.ErrQuarantineMismatch, app.ErrNotInCage:
+				http.Error(w, err.Error(), http.StatusConflict)
+			default:
+				logger.Error("Error updating dinosaur", "error", err)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		response := struct {
+			Data *app.Dinosaur `json:"data"`
+		}{
+			Data: dinosaur,
+		}
+
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			return
+		}
+	}
+}
+
+// DeleteDinosaur soft deletes a dinosaur.
+// DELETE /dinosaurs/:id
 func (s *Server) DeleteDinosaur() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		requestID := middleware.GetReqID(r.Context())
@@ -322,6 +479,434 @@ func (s *Server) DeleteDinosaur() http.HandlerFunc {
 			return
 		}
 
-		w.WriteHeader(http.StatusOK)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// RestoreDinosaur restores a soft deleted dinosaur into its cage.
+// POST /dinosaurs/:id/restore
+func (s *Server) RestoreDinosaur() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+		id := chi.URLParam(r, "id")
+
+		if err := app.ValidateID(id); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		dinosaur, err := s.DinosaurStore.Restore(r.Context(), id)
+		if err != nil {
+			switch err {
+			case app.ErrNotFound:
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			case app.ErrConflict:
+				http.Error(w, http.StatusText(http.StatusConflict), http.StatusConflict)
+			case app.ErrCagePoweredDown, app.ErrCageLockedDown, app.ErrNotCertified, app.ErrCapacityExceeded, app.ErrSpeciesMismatch, app.ErrHabitatMismatch,
+				app.ErrQuarantineMismatch:
+				http.Error(w, err.Error(), http.StatusConflict)
+			default:
+				logger.Error("Error restoring dinosaur", "error", err)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		response := struct {
+			Data *app.Dinosaur `json:"data"`
+		}{
+			Data: dinosaur,
+		}
+
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			return
+		}
+	}
+}
+
+// QuarantineDinosaurRequest is a request to quarantine a dinosaur.
+type QuarantineDinosaurRequest struct {
+	CageID string    `json:"cageId"`
+	Reason string    `json:"reason"`
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+}
+
+// Quarantine returns the requested quarantine.
+func (r QuarantineDinosaurRequest) Quarantine() app.Quarantine {
+	return app.Quarantine{
+		CageID: r.CageID,
+		Reason: r.Reason,
+		Start:  r.Start,
+		End:    r.End,
+	}
+}
+
+// QuarantineDinosaur moves a dinosaur to a quarantine cage and flags it as quarantined.
+// POST /dinosaurs/:id/quarantine
+func (s *Server) QuarantineDinosaur() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+		id := chi.URLParam(r, "id")
+
+		if err := app.ValidateID(id); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var req QuarantineDinosaurRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.Error("Error decoding request body", "error", err)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		quarantine := req.Quarantine()
+		if err := quarantine.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		dinosaur, err := s.DinosaurStore.Quarantine(r.Context(), id, quarantine)
+		if err != nil {
+			switch err {
+			case app.ErrNotFound:
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			case app.ErrConflict:
+				http.Error(w, http.StatusText(http.StatusConflict), http.StatusConflict)
+			case app.ErrCagePoweredDown, app.ErrCageLockedDown, app.ErrNotCertified, app.ErrCapacityExceeded, app.ErrSpeciesMismatch, app.ErrHabitatMismatch,
+				app.ErrQuarantineMismatch, app.ErrNotInCage:
+				http.Error(w, err.Error(), http.StatusConflict)
+			default:
+				logger.Error("Error quarantining dinosaur", "error", err)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		response := struct {
+			Data *app.Dinosaur `json:"data"`
+		}{
+			Data: dinosaur,
+		}
+
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			return
+		}
+	}
+}
+
+// ReleaseDinosaur ends the quarantine of a dinosaur and moves it to a regular cage.
+// POST /dinosaurs/:id/release
+func (s *Server) ReleaseDinosaur() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+		id := chi.URLParam(r, "id")
+
+		if err := app.ValidateID(id); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var req MoveDinosaurRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.Error("Error decoding request body", "error", err)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		if err := req.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		dinosaur, err := s.DinosaurStore.Release(r.Context(), id, req.CageID)
+		if err != nil {
+			switch err {
+			case app.ErrNotFound:
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			case app.ErrConflict:
+				http.Error(w, http.StatusText(http.StatusConflict), http.StatusConflict)
+			case app.ErrCagePoweredDown, app.ErrCageLockedDown, app.ErrNotCertified, app.ErrCapacityExceeded, app.ErrSpeciesMismatch, app.ErrHabitatMismatch,
+				app.ErrQuarantineMismatch:
+				http.Error(w, err.Error(), http.StatusConflict)
+			default:
+				logger.Error("Error releasing dinosaur", "error", err)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		response := struct {
+			Data *app.Dinosaur `json:"data"`
+		}{
+			Data: dinosaur,
+		}
+
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			return
+		}
+	}
+}
+
+// ListHealthRecords lists the health records of a dinosaur in chronological order.
+// GET /dinosaurs/:id/health[?kind=...][&recordedAfter=...][&recordedBefore=...]
+func (s *Server) ListHealthRecords() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+		id := chi.URLParam(r, "id")
+
+		if err := app.ValidateID(id); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		filter, err := healthRecordFilter(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		records, err := s.DinosaurStore.HealthRecords(r.Context(), id, filter)
+		if err != nil {
+			if err == app.ErrNotFound {
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+				return
+			}
+
+			logger.Error("Error getting health records", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		if records == nil {
+			records = []app.HealthRecord{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		response := struct {
+			Data []app.HealthRecord `json:"data"`
+		}{
+			Data: records,
+		}
+
+		if err = json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			return
+		}
+	}
+}
+
+// AddHealthRecordRequest is a request to add a health record of a dinosaur.
+type AddHealthRecordRequest struct {
+	Kind       app.HealthRecordKind `json:"kind"`
+	Author     string               `json:"author"`
+	WeightKg   float64              `json:"weightKg"`
+	Notes      string               `json:"notes"`
+	Status     app.HealthStatus     `json:"status"`
+	RecordedAt time.Time            `json:"recordedAt"`
+}
+
+// Record returns the requested health record of the dinosaur.
+func (r AddHealthRecordRequest) Record(dinosaurID string) app.HealthRecord {
+	return app.HealthRecord{
+		DinosaurID: dinosaurID,
+		Kind:       r.Kind,
+		Author:     r.Author,
+		WeightKg:   r.WeightKg,
+		Notes:      r.Notes,
+		Status:     r.Status,
+		RecordedAt: r.RecordedAt,
+	}
+}
+
+// AddHealthRecord adds a health record of a dinosaur.
+// The author defaults to the authenticated client.
+// POST /dinosaurs/:id/health
+func (s *Server) AddHealthRecord() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+		id := chi.URLParam(r, "id")
+
+		if err := app.ValidateID(id); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var req AddHealthRecordRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.Error("Error decoding request body", "error", err)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		record := req.Record(id)
+		if principal, ok := PrincipalFromContext(r.Context()); ok && record.Author == "" {
+			record.Author = principal.Name
+		}
+		if err := record.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		added, err := s.DinosaurStore.AddHealthRecord(r.Context(), &record)
+		if err != nil {
+			if err == app.ErrNotFound {
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+				return
+			}
+
+			logger.Error("Error adding health record", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+
+		response := struct {
+			Data *app.HealthRecord `json:"data"`
+		}{
+			Data: added,
+		}
+
+		if err = json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			return
+		}
+	}
+}
+
+// ListLifecycleEvents lists the lifecycle events of a dinosaur in chronological order.
+// GET /dinosaurs/:id/lifecycle
+func (s *Server) ListLifecycleEvents() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+		id := chi.URLParam(r, "id")
+
+		if err := app.ValidateID(id); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		events, err := s.DinosaurStore.LifecycleEvents(r.Context(), id)
+		if err != nil {
+			if err == app.ErrNotFound {
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+				return
+			}
+
+			logger.Error("Error getting lifecycle events", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		if events == nil {
+			events = []app.LifecycleEvent{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		response := struct {
+			Data []app.LifecycleEvent `json:"data"`
+		}{
+			Data: events,
+		}
+
+		if err = json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			return
+		}
+	}
+}
+
+// TransitionDinosaurRequest is a request to move a dinosaur to the next stage of its lifecycle.
+type TransitionDinosaurRequest struct {
+	Stage      app.LifecycleStage `json:"stage"`
+	Notes      string             `json:"notes"`
+	OccurredAt time.Time          `json:"occurredAt"`
+}
+
+// Transition returns the requested lifecycle transition.
+func (r TransitionDinosaurRequest) Transition() app.LifecycleTransition {
+	return app.LifecycleTransition{
+		Stage:      r.Stage,
+		Notes:      r.Notes,
+		OccurredAt: r.OccurredAt,
+	}
+}
+
+// TransitionDinosaur moves a dinosaur to the next stage of its lifecycle.
+// A deceased or transferred out dinosaur leaves its cage.
+// POST /dinosaurs/:id/lifecycle
+func (s *Server) TransitionDinosaur() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+		id := chi.URLParam(r, "id")
+
+		if err := app.ValidateID(id); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var req TransitionDinosaurRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.Error("Error decoding request body", "error", err)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		transition := req.Transition()
+		if err := transition.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		event, err := s.DinosaurStore.Transition(r.Context(), id, transition)
+		if err != nil {
+			switch err {
+			case app.ErrNotFound:
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			case app.ErrIllegalTransition:
+				http.Error(w, err.Error(), http.StatusConflict)
+			default:
+				logger.Error("Error transitioning dinosaur", "error", err)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+
+		response := struct {
+			Data *app.LifecycleEvent `json:"data"`
+		}{
+			Data: event,
+		}
+
+		if err = json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			return
+		}
 	}
 }
@@ -6,10 +6,12 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -21,11 +23,19 @@ import (
 )
 
 type fakeDinosaurStore struct {
-	dinosaur app.Dinosaur
-	id       string
-	species  app.DinosaurSpecies
-	cageID   string
-	err      error
+	dinosaur     app.Dinosaur
+	record       app.HealthRecord
+	id           string
+	cageID       string
+	patch        app.DinosaurPatch
+	filter       app.DinosaurFilter
+	healthFilter app.HealthRecordFilter
+	quarantine   app.Quarantine
+	egg          app.Egg
+	transition   app.LifecycleTransition
+	event        app.LifecycleEvent
+	opts         app.GetOptions
+	err          error
 }
 
 func (s *fakeDinosaurStore) Add(_ context.Context, dinosaur *app.Dinosaur) (*app.Dinosaur, error) {
@@ -36,6 +46,8 @@ func (s *fakeDinosaurStore) Add(_ context.Context, dinosaur *app.Dinosaur) (*app
 	now := time.Now()
 	s.dinosaur = *dinosaur
 	s.dinosaur.ID = uuid.NewString()
+	s.dinosaur.Stage = app.LifecycleStageAdult
+	s.dinosaur.HealthStatus = app.HealthStatusHealthy
 	s.dinosaur.CreatedAt = now
 	s.dinosaur.UpdatedAt = now
 	c := s.dinosaur
@@ -43,24 +55,25 @@ func (s *fakeDinosaurStore) Add(_ context.Context, dinosaur *app.Dinosaur) (*app
 	return &c, nil
 }
 
-func (s *fakeDinosaurStore) Get(_ context.Context, id string) (*app.Dinosaur, error) {
+func (s *fakeDinosaurStore) Get(_ context.Context, id string, opts app.GetOptions) (*app.Dinosaur, error) {
 	if s.err != nil {
 		return nil, s.err
 	}
 
 	d := s.dinosaur
 	s.id = id
+	s.opts = opts
 
 	return &d, nil
 }
 
-func (s *fakeDinosaurStore) List(_ context.Context, cageID string, species app.DinosaurSpecies) ([]app.Dinosaur, error) {
+func (s *fakeDinosaurStore) List(_ context.Context, filter app.DinosaurFilter) ([]app.Dinosaur, error) {
 	if s.err != nil {
 		return nil, s.err
 	}
 
-	s.cageID = cageID
-	s.species = species
+	s.cageID = filter.CageID
+	s.filter = filter
 
 	if s.dinosaur.ID == "" {
 		return nil, nil
@@ -82,6 +95,36 @@ func (s *fakeDinosaurStore) Move(_ context.Context, id string, cageID string) (*
 	return &d, nil
 }
 
+func (s *fakeDinosaurStore) Update(_ context.Context, id string, patch app.DinosaurPatch) (*app.Dinosaur, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	if patch.Name != nil {
+		s.dinosaur.Name = *patch.Name
+	}
+	if patch.CageID != nil {
+		s.dinosaur.CageID = *patch.CageID
+	}
+	s.id = id
+	s.patch = patch
+	d := s.dinosaur
+
+	return &d, nil
+}
+
+func (s *fakeDinosaurStore) Restore(_ context.Context, id string) (*app.Dinosaur, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	s.id = id
+	s.dinosaur.DeletedAt = nil
+	d := s.dinosaur
+
+	return &d, nil
+}
+
 func (s *fakeDinosaurStore) Delete(_ context.Context, id string) error {
 	if s.err != nil {
 		return s.err
@@ -92,6 +135,159 @@ func (s *fakeDinosaurStore) Delete(_ context.Context, id string) error {
 	return nil
 }
 
+func (s *fakeDinosaurStore) AddHealthRecord(_ context.Context, record *app.HealthRecord) (*app.HealthRecord, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	now := time.Now()
+	s.record = *record
+	s.record.ID = uuid.NewString()
+	if s.record.RecordedAt.IsZero() {
+		s.record.RecordedAt = now
+	}
+	s.record.CreatedAt = now
+	if !record.Status.IsUnspecified() {
+		s.dinosaur.HealthStatus = record.Status
+	}
+	r := s.record
+
+	return &r, nil
+}
+
+func (s *fakeDinosaurStore) HealthRecords(_ context.Context, id string, filter app.HealthRecordFilter) ([]app.HealthRecord, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	s.id = id
+	s.healthFilter = filter
+
+	if s.record.ID == "" {
+		return nil, nil
+	}
+
+	return []app.HealthRecord{s.record}, nil
+}
+
+func (s *fakeDinosaurStore) Quarantine(_ context.Context, id string, quarantine app.Quarantine) (*app.Dinosaur, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	start := quarantine.Start
+	if start.IsZero() {
+		start = time.Now()
+	}
+	s.id = id
+	s.quarantine = quarantine
+	s.dinosaur.CageID = quarantine.CageID
+	s.dinosaur.Quarantined = true
+	s.dinosaur.QuarantineReason = quarantine.Reason
+	s.dinosaur.QuarantineStart = &start
+	if !quarantine.End.IsZero() {
+		s.dinosaur.QuarantineEnd = &quarantine.End
+	}
+	d := s.dinosaur
+
+	return &d, nil
+}
+
+func (s *fakeDinosaurStore) Release(_ context.Context, id string, cageID string) (*app.Dinosaur, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	s.id = id
+	s.cageID = cageID
+	s.dinosaur.CageID = cageID
+	s.dinosaur.Quarantined = false
+	s.dinosaur.QuarantineReason = ""
+	s.dinosaur.QuarantineStart = nil
+	s.dinosaur.QuarantineEnd = nil
+	d := s.dinosaur
+
+	return &d, nil
+}
+
+func (s *fakeDinosaurStore) AddEgg(_ context.Context, egg app.Egg) (*app.Dinosaur, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	now := time.Now()
+	s.egg = egg
+	s.dinosaur = app.Dinosaur{
+		ID:           uuid.NewString(),
+		Name:         egg.Name,
+		Species:      egg.Species,
+		Stage:        app.LifecycleStageEgg,
+		HatchDate:    &egg.HatchDate,
+		HealthStatus: app.HealthStatusHealthy,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	d := s.dinosaur
+
+	return &d, nil
+}
+
+func (s *fakeDinosaurStore) Hatch(_ context.Context, id string, cageID string) (*app.Dinosaur, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	now := time.Now()
+	s.id = id
+	s.cageID = cageID
+	s.dinosaur.CageID = cageID
+	s.dinosaur.Stage = app.LifecycleStageJuvenile
+	s.dinosaur.HatchDate = &now
+	d := s.dinosaur
+
+	return &d, nil
+}
+
+func (s *fakeDinosaurStore) Transition(_ context.Context, id string, transition app.LifecycleTransition) (*app.LifecycleEvent, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	now := time.Now()
+	s.id = id
+	s.transition = transition
+	s.event = app.LifecycleEvent{
+		ID:         uuid.NewString(),
+		DinosaurID: id,
+		From:       s.dinosaur.Stage,
+		To:         transition.Stage,
+		CageID:     s.dinosaur.CageID,
+		Notes:      transition.Notes,
+		OccurredAt: transition.OccurredAt,
+		CreatedAt:  now,
+	}
+	if s.event.OccurredAt.IsZero() {
+		s.event.OccurredAt = now
+	}
+	e := s.event
+
+	return &e, nil
+}
+
+func (s *fakeDinosaurStore) LifecycleEvents(_ context.Context, id string) ([]app.LifecycleEvent, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	s.id = id
+
+	if s.event.ID == "" {
+		return nil, nil
+	}
+
+	return []app.LifecycleEvent{s.event}, nil
+}
+
 func TestAddDinosaur(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
 
@@ -112,7 +308,7 @@ func TestAddDinosaur(t *testing.T) {
 	rctx.URLParams.Add("id", id)
 	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
 
-	svc.AddDinosaur().ServeHTTP(w, r)
+	validated(t, svc.AddDinosaur()).ServeHTTP(w, r)
 
 	if want, got := http.StatusCreated, w.Code; want != got {
 		t.Fatalf("Expected %d got %d", want, got)
@@ -158,12 +354,14 @@ func TestGetDinosaur(t *testing.T) {
 	now := time.Now()
 	store := &fakeDinosaurStore{
 		dinosaur: app.Dinosaur{
-			ID:        id,
-			Name:      "Tyrannosaurus Rex",
-			Species:   app.DinosaurSpeciesTyrannosaurus,
-			CageID:    cageID,
-			CreatedAt: now,
-			UpdatedAt: now,
+			ID:           id,
+			Name:         "Tyrannosaurus Rex",
+			Species:      app.DinosaurSpeciesTyrannosaurus,
+			CageID:       cageID,
+			Stage:        app.LifecycleStageAdult,
+			HealthStatus: app.HealthStatusHealthy,
+			CreatedAt:    now,
+			UpdatedAt:    now,
 		},
 	}
 
@@ -179,7 +377,7 @@ func TestGetDinosaur(t *testing.T) {
 	rctx.URLParams.Add("id", id)
 	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
 
-	svc.GetDinosaur().ServeHTTP(w, r)
+	validated(t, svc.GetDinosaur()).ServeHTTP(w, r)
 
 	if want, got := http.StatusOK, w.Code; want != got {
 		t.Fatalf("Expected %d got %d", want, got)
@@ -225,12 +423,14 @@ func TestListCageDinosaurs(t *testing.T) {
 	now := time.Now()
 	store := &fakeDinosaurStore{
 		dinosaur: app.Dinosaur{
-			ID:        id,
-			Name:      "Tyrannosaurus Rex",
-			Species:   app.DinosaurSpeciesTyrannosaurus,
-			CageID:    cageID,
-			CreatedAt: now,
-			UpdatedAt: now,
+			ID:           id,
+			Name:         "Tyrannosaurus Rex",
+			Species:      app.DinosaurSpeciesTyrannosaurus,
+			CageID:       cageID,
+			Stage:        app.LifecycleStageAdult,
+			HealthStatus: app.HealthStatusHealthy,
+			CreatedAt:    now,
+			UpdatedAt:    now,
 		},
 	}
 
@@ -246,7 +446,7 @@ func TestListCageDinosaurs(t *testing.T) {
 	rctx.URLParams.Add("id", id)
 	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
 
-	svc.ListCageDinosaurs().ServeHTTP(w, r)
+	validated(t, svc.ListCageDinosaurs()).ServeHTTP(w, r)
 
 	if want, got := http.StatusOK, w.Code; want != got {
 		t.Fatalf("Expected %d got %d", want, got)
@@ -280,12 +480,14 @@ func TestListAllDinosaurs(t *testing.T) {
 	now := time.Now()
 	store := &fakeDinosaurStore{
 		dinosaur: app.Dinosaur{
-			ID:        id,
-			Name:      "Tyrannosaurus Rex",
-			Species:   app.DinosaurSpeciesTyrannosaurus,
-			CageID:    cageID,
-			CreatedAt: now,
-			UpdatedAt: now,
+			ID:           id,
+			Name:         "Tyrannosaurus Rex",
+			Species:      app.DinosaurSpeciesTyrannosaurus,
+			CageID:       cageID,
+			Stage:        app.LifecycleStageAdult,
+			HealthStatus: app.HealthStatusHealthy,
+			CreatedAt:    now,
+			UpdatedAt:    now,
 		},
 	}
 
@@ -297,7 +499,7 @@ func TestListAllDinosaurs(t *testing.T) {
 	w := httptest.NewRecorder()
 	r := httptest.NewRequest(http.MethodGet, "/dinosaurs", nil)
 
-	svc.ListAllDinosaurs().ServeHTTP(w, r)
+	validated(t, svc.ListAllDinosaurs()).ServeHTTP(w, r)
 
 	if want, got := http.StatusOK, w.Code; want != got {
 		t.Fatalf("Expected %d got %d", want, got)
@@ -332,12 +534,14 @@ func TestMoveDinosaur(t *testing.T) {
 	now := time.Now()
 	store := &fakeDinosaurStore{
 		dinosaur: app.Dinosaur{
-			ID:        id,
-			Name:      "Tyrannosaurus Rex",
-			Species:   app.DinosaurSpeciesTyrannosaurus,
-			CageID:    cage1ID,
-			CreatedAt: now,
-			UpdatedAt: now,
+			ID:           id,
+			Name:         "Tyrannosaurus Rex",
+			Species:      app.DinosaurSpeciesTyrannosaurus,
+			CageID:       cage1ID,
+			Stage:        app.LifecycleStageAdult,
+			HealthStatus: app.HealthStatusHealthy,
+			CreatedAt:    now,
+			UpdatedAt:    now,
 		},
 	}
 
@@ -355,7 +559,7 @@ func TestMoveDinosaur(t *testing.T) {
 	rctx.URLParams.Add("id", id)
 	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
 
-	svc.MoveDinosaur().ServeHTTP(w, r)
+	validated(t, svc.MoveDinosaur()).ServeHTTP(w, r)
 
 	if want, got := http.StatusOK, w.Code; want != got {
 		t.Fatalf("Expected %d got %d", want, got)
@@ -379,12 +583,14 @@ func TestDeleteDinosaur(t *testing.T) {
 	now := time.Now()
 	store := &fakeDinosaurStore{
 		dinosaur: app.Dinosaur{
-			ID:        id,
-			Name:      "Tyrannosaurus Rex",
-			Species:   app.DinosaurSpeciesTyrannosaurus,
-			CageID:    cageID,
-			CreatedAt: now,
-			UpdatedAt: now,
+			ID:           id,
+			Name:         "Tyrannosaurus Rex",
+			Species:      app.DinosaurSpeciesTyrannosaurus,
+			CageID:       cageID,
+			Stage:        app.LifecycleStageAdult,
+			HealthStatus: app.HealthStatusHealthy,
+			CreatedAt:    now,
+			UpdatedAt:    now,
 		},
 	}
 
@@ -400,7 +606,51 @@ func TestDeleteDinosaur(t *testing.T) {
 	rctx.URLParams.Add("id", id)
 	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
 
-	svc.DeleteDinosaur().ServeHTTP(w, r)
+	validated(t, svc.DeleteDinosaur()).ServeHTTP(w, r)
+
+	if want, got := http.StatusNoContent, w.Code; want != got {
+		t.Fatalf("Expected %d got %d", want, got)
+	}
+	if want, got := id, store.id; want != got {
+		t.Errorf("Expected id %s got %s", want, got)
+	}
+}
+
+func TestPatchDinosaur(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	id := uuid.NewString()
+	cageID := uuid.NewString()
+	now := time.Now()
+	store := &fakeDinosaurStore{
+		dinosaur: app.Dinosaur{
+			ID:           id,
+			Name:         "Blue",
+			Species:      app.DinosaurSpeciesVelociraptor,
+			CageID:       uuid.NewString(),
+			Stage:        app.LifecycleStageAdult,
+			HealthStatus: app.HealthStatusHealthy,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		},
+	}
+
+	svc := &Server{
+		Logger:        logger,
+		DinosaurStore: store,
+	}
+
+	body := `{"name": "Charlie", "cageId": "` + cageID + `"}`
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPatch, "/dinosaurs/"+id, strings.NewReader(body))
+	r.Header.Set("Content-Type", mergePatchContentType)
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", id)
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+	validated(t, svc.PatchDinosaur()).ServeHTTP(w, r)
 
 	if want, got := http.StatusOK, w.Code; want != got {
 		t.Fatalf("Expected %d got %d", want, got)
@@ -408,4 +658,728 @@ func TestDeleteDinosaur(t *testing.T) {
 	if want, got := id, store.id; want != got {
 		t.Errorf("Expected id %s got %s", want, got)
 	}
+
+	response := struct {
+		Data app.Dinosaur `json:"data"`
+	}{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := "Charlie", response.Data.Name; want != got {
+		t.Errorf("Expected name %s got %s", want, got)
+	}
+	if want, got := cageID, response.Data.CageID; want != got {
+		t.Errorf("Expected cageId %s got %s", want, got)
+	}
+}
+
+func TestPatchDinosaurErrors(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	cageID := uuid.NewString()
+
+	tests := []struct {
+		name   string
+		body   string
+		err    error
+		status int
+	}{
+		{"species is immutable", `{"species": "tyrannosaurus"}`, nil, http.StatusBadRequest},
+		{"null field", `{"name": null}`, nil, http.StatusBadRequest},
+		{"empty name", `{"name": ""}`, nil, http.StatusBadRequest},
+		{"invalid cage id", `{"cageId": "foo"}`, nil, http.StatusBadRequest},
+		{"not found", `{"cageId": "` + cageID + `"}`, app.ErrNotFound, http.StatusNotFound},
+		{"powered down", `{"cageId": "` + cageID + `"}`, app.ErrCagePoweredDown, http.StatusConflict},
+		{"locked down", `{"cageId": "` + cageID + `"}`, app.ErrCageLockedDown, http.StatusConflict},
+		{"capacity exceeded", `{"cageId": "` + cageID + `"}`, app.ErrCapacityExceeded, http.StatusConflict},
+		{"species mismatch", `{"cageId": "` + cageID + `"}`, app.ErrSpeciesMismatch, http.StatusConflict},
+		{"habitat mismatch", `{"cageId": "` + cageID + `"}`, app.ErrHabitatMismatch, http.StatusConflict},
+		{"not in cage", `{"cageId": "` + cageID + `"}`, app.ErrNotInCage, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := uuid.NewString()
+			svc := &Server{
+				Logger:        logger,
+				DinosaurStore: &fakeDinosaurStore{err: tt.err},
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPatch, "/dinosaurs/"+id, strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", mergePatchContentType)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", id)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			validated(t, svc.PatchDinosaur()).ServeHTTP(w, r)
+
+			if want, got := tt.status, w.Code; want != got {
+				t.Fatalf("Expected %d got %d", want, got)
+			}
+		})
+	}
+}
+
+func TestListDinosaursFilter(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	store := &fakeDinosaurStore{}
+	svc := &Server{
+		Logger:        logger,
+		DinosaurStore: store,
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/dinosaurs?species=triceratops,stegosaurus&species=ankylosaurus"+
+		"&zoneId=6d3f5e8a-1b1c-4c9e-8c1d-2f9b1a7e4c3d&diet=herbivore&namePrefix=Bl&createdBefore=2023-01-02T03:04:05Z&updatedAfter=2023-02-03T04:05:06Z&sort=species,-name", nil)
+
+	validated(t, svc.ListAllDinosaurs()).ServeHTTP(w, r)
+
+	if want, got := http.StatusOK, w.Code; want != got {
+		t.Fatalf("Expected %d got %d", want, got)
+	}
+
+	want := app.DinosaurFilter{
+		Species: []app.DinosaurSpecies{
+			app.DinosaurSpeciesTriceratops,
+			app.DinosaurSpeciesStegosaurus,
+			app.DinosaurSpeciesAnkylosaurus,
+		},
+		ZoneID:     "6d3f5e8a-1b1c-4c9e-8c1d-2f9b1a7e4c3d",
+		Diet:       app.DinosaurTypeHerbivore,
+		NamePrefix: "Bl",
+		Created:    app.TimeRange{Before: time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)},
+		Updated:    app.TimeRange{After: time.Date(2023, 2, 3, 4, 5, 6, 0, time.UTC)},
+		Sort:       []app.Sort{{Field: "species"}, {Field: "name", Desc: true}},
+	}
+	if got := store.filter; !reflect.DeepEqual(want, got) {
+		t.Fatalf("Expected filter %+v got %+v", want, got)
+	}
+
+	cageID := uuid.NewString()
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodGet, "/cages/"+cageID+"/dinosaurs?diet=carnivore&stage=juvenile,adult&healthStatus=under-observation&quarantined=true&sort=-createdAt", nil)
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", cageID)
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+	validated(t, svc.ListCageDinosaurs()).ServeHTTP(w, r)
+
+	if want, got := http.StatusOK, w.Code; want != got {
+		t.Fatalf("Expected %d got %d", want, got)
+	}
+
+	quarantined := true
+	want = app.DinosaurFilter{
+		CageID:       cageID,
+		Diet:         app.DinosaurTypeCarnivore,
+		Stages:       []app.LifecycleStage{app.LifecycleStageJuvenile, app.LifecycleStageAdult},
+		HealthStatus: app.HealthStatusUnderObservation,
+		Quarantined:  &quarantined,
+		Sort:         []app.Sort{{Field: "createdAt", Desc: true}},
+	}
+	if got := store.filter; !reflect.DeepEqual(want, got) {
+		t.Fatalf("Expected filter %+v got %+v", want, got)
+	}
+}
+
+func TestListDinosaursInvalidFilter(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"species", "species=foo"},
+		{"one of species", "species=triceratops,foo"},
+		{"diet", "diet=omnivore"},
+		{"stage", "stage=hatchling"},
+		{"health status", "healthStatus=dead"},
+		{"quarantined", "quarantined=maybe"},
+		{"hatch before", "hatchBefore=tomorrow"},
+		{"created after", "createdAfter=yesterday"},
+		{"sort field", "sort=capacity"},
+		{"empty sort field", "sort=name,"},
+		{"zone", "zoneId=foo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeDinosaurStore{}
+			svc := &Server{
+				Logger:        logger,
+				DinosaurStore: store,
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/dinosaurs?"+tt.query, nil)
+
+			validated(t, svc.ListAllDinosaurs()).ServeHTTP(w, r)
+
+			if want, got := http.StatusBadRequest, w.Code; want != got {
+				t.Fatalf("Expected %d got %d", want, got)
+			}
+		})
+	}
+}
+
+func TestListDinosaursIncludeDeleted(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	store := &fakeDinosaurStore{}
+	svc := &Server{
+		Logger:        logger,
+		DinosaurStore: store,
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/dinosaurs?includeDeleted=true", nil)
+
+	validated(t, svc.ListAllDinosaurs()).ServeHTTP(w, r)
+
+	if want, got := http.StatusOK, w.Code; want != got {
+		t.Fatalf("Expected %d got %d", want, got)
+	}
+	if !store.filter.IncludeDeleted {
+		t.Fatal("Expected IncludeDeleted")
+	}
+
+	id := uuid.NewString()
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodGet, "/dinosaurs/"+id+"?includeDeleted=1", nil)
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", id)
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+	store.dinosaur = app.Dinosaur{ID: id, Name: "Blue", Species: app.DinosaurSpeciesVelociraptor, CageID: uuid.NewString(), Stage: app.LifecycleStageAdult, HealthStatus: app.HealthStatusHealthy}
+	validated(t, svc.GetDinosaur()).ServeHTTP(w, r)
+
+	if want, got := http.StatusOK, w.Code; want != got {
+		t.Fatalf("Expected %d got %d", want, got)
+	}
+	if !store.opts.IncludeDeleted {
+		t.Fatal("Expected IncludeDeleted")
+	}
+}
+
+func TestRestoreDinosaur(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"restored", nil, http.StatusOK},
+		{"not found", app.ErrNotFound, http.StatusNotFound},
+		{"cage deleted", app.ErrConflict, http.StatusConflict},
+		{"capacity exceeded", app.ErrCapacityExceeded, http.StatusConflict},
+		{"species mismatch", app.ErrSpeciesMismatch, http.StatusConflict},
+		{"store error", errors.New("store error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := uuid.NewString()
+			deletedAt := time.Now()
+			store := &fakeDinosaurStore{
+				dinosaur: app.Dinosaur{
+					ID:           id,
+					Name:         "Blue",
+					Species:      app.DinosaurSpeciesVelociraptor,
+					CageID:       uuid.NewString(),
+					Stage:        app.LifecycleStageAdult,
+					HealthStatus: app.HealthStatusHealthy,
+					CreatedAt:    deletedAt,
+					UpdatedAt:    deletedAt,
+					DeletedAt:    &deletedAt,
+				},
+				err: tt.err,
+			}
+			svc := &Server{
+				Logger:        logger,
+				DinosaurStore: store,
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/dinosaurs/"+id+"/restore", nil)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", id)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			validated(t, svc.RestoreDinosaur()).ServeHTTP(w, r)
+
+			if want, got := tt.status, w.Code; want != got {
+				t.Fatalf("Expected %d got %d", want, got)
+			}
+			if tt.err == nil && store.dinosaur.DeletedAt != nil {
+				t.Fatal("Expected the dinosaur to be restored")
+			}
+		})
+	}
+}
+
+func TestAddHealthRecord(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	tests := []struct {
+		name      string
+		body      string
+		principal string
+		author    string
+		err       error
+		status    int
+	}{
+		{"checkup", `{"kind": "checkup", "author": "Dr. Harding", "notes": "Limping", "status": "under-observation"}`, "", "Dr. Harding", nil, http.StatusCreated},
+		{"weight", `{"kind": "weight", "author": "Dr. Harding", "weightKg": 7200.5, "recordedAt": "2023-01-02T08:30:00Z"}`, "", "Dr. Harding", nil, http.StatusCreated},
+		{"principal author", `{"kind": "note", "notes": "Eats well"}`, "gate-1", "gate-1", nil, http.StatusCreated},
+		{"no author", `{"kind": "note", "notes": "Eats well"}`, "", "", nil, http.StatusBadRequest},
+		{"invalid kind", `{"kind": "surgery", "author": "Dr. Harding"}`, "", "", nil, http.StatusBadRequest},
+		{"weight without weight", `{"kind": "weight", "author": "Dr. Harding"}`, "", "", nil, http.StatusBadRequest},
+		{"weight on checkup", `{"kind": "checkup", "author": "Dr. Harding", "weightKg": 7200}`, "", "", nil, http.StatusBadRequest},
+		{"invalid status", `{"kind": "diagnosis", "author": "Dr. Harding", "status": "dead"}`, "", "", nil, http.StatusBadRequest},
+		{"invalid body", `{"kind": "note"`, "", "", nil, http.StatusBadRequest},
+		{"not found", `{"kind": "note", "author": "Dr. Harding"}`, "", "", app.ErrNotFound, http.StatusNotFound},
+		{"store error", `{"kind": "note", "author": "Dr. Harding"}`, "", "", errors.New("store error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeDinosaurStore{err: tt.err}
+			svc := &Server{
+				Logger:        logger,
+				DinosaurStore: store,
+			}
+
+			id := uuid.NewString()
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/dinosaurs/"+id+"/health", strings.NewReader(tt.body))
+
+			ctx := r.Context()
+			if tt.principal != "" {
+				ctx = WithPrincipal(ctx, Principal{Name: tt.principal, Method: AuthMethodAPIKey})
+			}
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", id)
+			r = r.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+
+			validated(t, svc.AddHealthRecord()).ServeHTTP(w, r)
+
+			if want, got := tt.status, w.Code; want != got {
+				t.Fatalf("Expected %d got %d", want, got)
+			}
+			if tt.status != http.StatusCreated {
+				return
+			}
+
+			response := struct {
+				Data app.HealthRecord `json:"data"`
+			}{}
+
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+
+			if want, got := id, response.Data.DinosaurID; want != got {
+				t.Fatalf("Expected DinosaurID %s got %s", want, got)
+			}
+			if want, got := tt.author, response.Data.Author; want != got {
+				t.Fatalf("Expected Author %s got %s", want, got)
+			}
+		})
+	}
+}
+
+func TestListHealthRecords(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	now := time.Now()
+	tests := []struct {
+		name   string
+		query  string
+		filter app.HealthRecordFilter
+		err    error
+		status int
+	}{
+		{"all", "", app.HealthRecordFilter{}, nil, http.StatusOK},
+		{"filtered", "?kind=weight&recordedAfter=2023-01-02T03:04:05Z",
+			app.HealthRecordFilter{Kind: app.HealthRecordKindWeight, Recorded: app.TimeRange{After: time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)}}, nil, http.StatusOK},
+		{"invalid kind", "?kind=surgery", app.HealthRecordFilter{}, nil, http.StatusBadRequest},
+		{"invalid time", "?recordedBefore=yesterday", app.HealthRecordFilter{}, nil, http.StatusBadRequest},
+		{"not found", "", app.HealthRecordFilter{}, app.ErrNotFound, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := uuid.NewString()
+			store := &fakeDinosaurStore{
+				record: app.HealthRecord{
+					ID:         uuid.NewString(),
+					DinosaurID: id,
+					Kind:       app.HealthRecordKindWeight,
+					Author:     "Dr. Harding",
+					WeightKg:   7200.5,
+					RecordedAt: now,
+					CreatedAt:  now,
+				},
+				err: tt.err,
+			}
+			svc := &Server{
+				Logger:        logger,
+				DinosaurStore: store,
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/dinosaurs/"+id+"/health"+tt.query, nil)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", id)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			validated(t, svc.ListHealthRecords()).ServeHTTP(w, r)
+
+			if want, got := tt.status, w.Code; want != got {
+				t.Fatalf("Expected %d got %d", want, got)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+			if want, got := tt.filter, store.healthFilter; want != got {
+				t.Fatalf("Expected filter %+v got %+v", want, got)
+			}
+
+			response := struct {
+				Data []app.HealthRecord `json:"data"`
+			}{}
+
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+
+			if want, got := 1, len(response.Data); want != got {
+				t.Fatalf("Expected records %d got %d", want, got)
+			}
+		})
+	}
+}
+
+func TestQuarantineDinosaur(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	cageID := uuid.NewString()
+	tests := []struct {
+		name   string
+		body   string
+		err    error
+		status int
+	}{
+		{"open ended", `{"cageId": "` + cageID + `", "reason": "Limping"}`, nil, http.StatusOK},
+		{"period", `{"cageId": "` + cageID + `", "reason": "Limping", "start": "2023-01-02T08:30:00Z", "end": "2023-01-16T08:30:00Z"}`, nil, http.StatusOK},
+		{"no cage", `{"reason": "Limping"}`, nil, http.StatusBadRequest},
+		{"invalid cage", `{"cageId": "foo", "reason": "Limping"}`, nil, http.StatusBadRequest},
+		{"no reason", `{"cageId": "` + cageID + `"}`, nil, http.StatusBadRequest},
+		{"end before start", `{"cageId": "` + cageID + `", "reason": "Limping", "start": "2023-01-16T08:30:00Z", "end": "2023-01-02T08:30:00Z"}`, nil, http.StatusBadRequest},
+		{"invalid body", `{"cageId": "`, nil, http.StatusBadRequest},
+		{"not found", `{"cageId": "` + cageID + `", "reason": "Limping"}`, app.ErrNotFound, http.StatusNotFound},
+		{"quarantined already", `{"cageId": "` + cageID + `", "reason": "Limping"}`, app.ErrConflict, http.StatusConflict},
+		{"regular cage", `{"cageId": "` + cageID + `", "reason": "Limping"}`, app.ErrQuarantineMismatch, http.StatusConflict},
+		{"other species", `{"cageId": "` + cageID + `", "reason": "Limping"}`, app.ErrSpeciesMismatch, http.StatusConflict},
+		{"store error", `{"cageId": "` + cageID + `", "reason": "Limping"}`, errors.New("store error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := uuid.NewString()
+			now := time.Now()
+			store := &fakeDinosaurStore{
+				dinosaur: app.Dinosaur{
+					ID:           id,
+					Name:         "Blue",
+					Species:      app.DinosaurSpeciesVelociraptor,
+					CageID:       uuid.NewString(),
+					Stage:        app.LifecycleStageAdult,
+					HealthStatus: app.HealthStatusSick,
+					CreatedAt:    now,
+					UpdatedAt:    now,
+				},
+				err: tt.err,
+			}
+			svc := &Server{
+				Logger:        logger,
+				DinosaurStore: store,
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/dinosaurs/"+id+"/quarantine", strings.NewReader(tt.body))
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", id)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			validated(t, svc.QuarantineDinosaur()).ServeHTTP(w, r)
+
+			if want, got := tt.status, w.Code; want != got {
+				t.Fatalf("Expected %d got %d", want, got)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+			if want, got := id, store.id; want != got {
+				t.Fatalf("Expected id %s got %s", want, got)
+			}
+
+			response := struct {
+				Data app.Dinosaur `json:"data"`
+			}{}
+
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+
+			if !response.Data.Quarantined {
+				t.Fatal("Expected the dinosaur to be quarantined")
+			}
+			if want, got := cageID, response.Data.CageID; want != got {
+				t.Fatalf("Expected CageID %s got %s", want, got)
+			}
+			if want, got := "Limping", response.Data.QuarantineReason; want != got {
+				t.Fatalf("Expected QuarantineReason %s got %s", want, got)
+			}
+			if want, got := store.quarantine.End.IsZero(), response.Data.QuarantineEnd == nil; want != got {
+				t.Fatalf("Expected open ended %t got %t", want, got)
+			}
+		})
+	}
+}
+
+func TestReleaseDinosaur(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	cageID := uuid.NewString()
+	tests := []struct {
+		name   string
+		body   string
+		err    error
+		status int
+	}{
+		{"released", `{"cageId": "` + cageID + `"}`, nil, http.StatusOK},
+		{"no cage", `{}`, nil, http.StatusBadRequest},
+		{"invalid body", `{"cageId": "`, nil, http.StatusBadRequest},
+		{"not found", `{"cageId": "` + cageID + `"}`, app.ErrNotFound, http.StatusNotFound},
+		{"not quarantined", `{"cageId": "` + cageID + `"}`, app.ErrConflict, http.StatusConflict},
+		{"quarantine cage", `{"cageId": "` + cageID + `"}`, app.ErrQuarantineMismatch, http.StatusConflict},
+		{"store error", `{"cageId": "` + cageID + `"}`, errors.New("store error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := uuid.NewString()
+			now := time.Now()
+			store := &fakeDinosaurStore{
+				dinosaur: app.Dinosaur{
+					ID:               id,
+					Name:             "Blue",
+					Species:          app.DinosaurSpeciesVelociraptor,
+					CageID:           uuid.NewString(),
+					Stage:            app.LifecycleStageAdult,
+					HealthStatus:     app.HealthStatusHealthy,
+					Quarantined:      true,
+					QuarantineReason: "Limping",
+					QuarantineStart:  &now,
+					CreatedAt:        now,
+					UpdatedAt:        now,
+				},
+				err: tt.err,
+			}
+			svc := &Server{
+				Logger:        logger,
+				DinosaurStore: store,
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/dinosaurs/"+id+"/release", strings.NewReader(tt.body))
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", id)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			validated(t, svc.ReleaseDinosaur()).ServeHTTP(w, r)
+
+			if want, got := tt.status, w.Code; want != got {
+				t.Fatalf("Expected %d got %d", want, got)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			response := struct {
+				Data app.Dinosaur `json:"data"`
+			}{}
+
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+
+			if response.Data.Quarantined || response.Data.QuarantineStart != nil {
+				t.Fatalf("Expected the dinosaur to be released got %+v", response.Data)
+			}
+			if want, got := cageID, response.Data.CageID; want != got {
+				t.Fatalf("Expected CageID %s got %s", want, got)
+			}
+		})
+	}
+}
+
+func TestTransitionDinosaur(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	tests := []struct {
+		name   string
+		body   string
+		err    error
+		status int
+	}{
+		{"grown up", `{"stage": "adult"}`, nil, http.StatusCreated},
+		{"deceased", `{"stage": "deceased", "notes": "Old age", "occurredAt": "2023-01-02T08:30:00Z"}`, nil, http.StatusCreated},
+		{"no stage", `{"notes": "Old age"}`, nil, http.StatusBadRequest},
+		{"invalid stage", `{"stage": "hatchling"}`, nil, http.StatusBadRequest},
+		{"invalid body", `{"stage": "`, nil, http.StatusBadRequest},
+		{"not found", `{"stage": "adult"}`, app.ErrNotFound, http.StatusNotFound},
+		{"illegal", `{"stage": "juvenile"}`, app.ErrIllegalTransition, http.StatusConflict},
+		{"store error", `{"stage": "adult"}`, errors.New("store error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := uuid.NewString()
+			now := time.Now()
+			store := &fakeDinosaurStore{
+				dinosaur: app.Dinosaur{
+					ID:           id,
+					Name:         "Blue",
+					Species:      app.DinosaurSpeciesVelociraptor,
+					CageID:       uuid.NewString(),
+					Stage:        app.LifecycleStageJuvenile,
+					HealthStatus: app.HealthStatusHealthy,
+					CreatedAt:    now,
+					UpdatedAt:    now,
+				},
+				err: tt.err,
+			}
+			svc := &Server{
+				Logger:        logger,
+				DinosaurStore: store,
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/dinosaurs/"+id+"/lifecycle", strings.NewReader(tt.body))
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", id)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			validated(t, svc.TransitionDinosaur()).ServeHTTP(w, r)
+
+			if want, got := tt.status, w.Code; want != got {
+				t.Fatalf("Expected %d got %d", want, got)
+			}
+			if tt.status != http.StatusCreated {
+				return
+			}
+			if want, got := id, store.id; want != got {
+				t.Fatalf("Expected id %s got %s", want, got)
+			}
+
+			response := struct {
+				Data app.LifecycleEvent `json:"data"`
+			}{}
+
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+
+			if want, got := app.LifecycleStageJuvenile, response.Data.From; want != got {
+				t.Fatalf("Expected From %s got %s", want, got)
+			}
+			if want, got := store.transition.Stage, response.Data.To; want != got {
+				t.Fatalf("Expected To %s got %s", want, got)
+			}
+			if want, got := store.transition.Notes, response.Data.Notes; want != got {
+				t.Fatalf("Expected Notes %s got %s", want, got)
+			}
+		})
+	}
+}
+
+func TestListLifecycleEvents(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	tests := []struct {
+		name   string
+		events int
+		err    error
+		status int
+	}{
+		{"events", 1, nil, http.StatusOK},
+		{"no events", 0, nil, http.StatusOK},
+		{"not found", 0, app.ErrNotFound, http.StatusNotFound},
+		{"store error", 0, errors.New("store error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := uuid.NewString()
+			now := time.Now()
+			store := &fakeDinosaurStore{err: tt.err}
+			if tt.events > 0 {
+				store.event = app.LifecycleEvent{
+					ID:         uuid.NewString(),
+					DinosaurID: id,
+					From:       app.LifecycleStageEgg,
+					To:         app.LifecycleStageJuvenile,
+					CageID:     uuid.NewString(),
+					OccurredAt: now,
+					CreatedAt:  now,
+				}
+			}
+			svc := &Server{
+				Logger:        logger,
+				DinosaurStore: store,
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/dinosaurs/"+id+"/lifecycle", nil)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", id)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			validated(t, svc.ListLifecycleEvents()).ServeHTTP(w, r)
+
+			if want, got := tt.status, w.Code; want != got {
+				t.Fatalf("Expected %d got %d", want, got)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			response := struct {
+				Data []app.LifecycleEvent `json:"data"`
+			}{}
+
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+
+			if want, got := tt.events, len(response.Data); want != got {
+				t.Fatalf("Expected events %d got %d", want, got)
+			}
+		})
+	}
 }
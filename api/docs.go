@@ -0,0 +1,47 @@
+package api
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+)
+
+// ServeSpec serves the OpenAPI specification.
+// GET /openapi.yaml
+func (s *Server) ServeSpec() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/yaml")
+		w.Write(spec) // nolint:errcheck
+	}
+}
+
+// docsPage renders the specification with Swagger UI loaded from a CDN.
+const docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Jurassic Park API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = () => {
+      window.ui = SwaggerUIBundle({url: "%s", dom_id: "#swagger-ui"});
+    };
+  </script>
+</body>
+</html>
+`
+
+// ServeDocs serves the API documentation UI for the specification at specURL.
+// GET /docs
+func (s *Server) ServeDocs(specURL string) http.HandlerFunc {
+	page := fmt.Sprintf(docsPage, html.EscapeString(specURL))
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(page)) // nolint:errcheck
+	}
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+// cageWithDinosaurs is a cage with its occupants embedded.
+// GET /cages?expand=dinosaurs
+type cageWithDinosaurs struct {
+	app.Cage
+	Dinosaurs []app.Dinosaur `json:"dinosaurs"`
+}
+
+// dinosaurWithCage is a dinosaur with its cage embedded.
+// GET /dinosaurs?expand=cage
+type dinosaurWithCage struct {
+	app.Dinosaur
+	Cage *app.Cage `json:"cage,omitempty"`
+}
+
+// expand parses the expand query parameter.
+// Only the allowed related resources are accepted.
+func expand(r *http.Request, allowed ...string) (map[string]bool, error) {
+	expanded := make(map[string]bool)
+	for _, value := range r.URL.Query()["expand"] {
+		for _, name := range strings.Split(value, ",") {
+			if !slices.Contains(allowed, name) {
+				return nil, fmt.Errorf("invalid expand %q", name)
+			}
+			expanded[name] = true
+		}
+	}
+
+	return expanded, nil
+}
+
+// expandDinosaurs embeds the current occupants into the cages.
+// The occupants of all the cages are fetched with a single query.
+func (s *Server) expandDinosaurs(ctx context.Context, cages []app.Cage) ([]cageWithDinosaurs, error) {
+	expanded := make([]cageWithDinosaurs, len(cages))
+	if len(cages) == 0 {
+		return expanded, nil
+	}
+
+	ids := make([]string, len(cages))
+	for i, cage := range cages {
+		ids[i] = cage.ID
+	}
+
+	dinosaurs, err := s.DinosaurStore.List(ctx, app.DinosaurFilter{CageIDs: ids})
+	if err != nil {
+		return nil, err
+	}
+
+	occupants := make(map[string][]app.Dinosaur, len(cages))
+	for _, d := range dinosaurs {
+		occupants[d.CageID] = append(occupants[d.CageID], d)
+	}
+
+	for i, cage := range cages {
+		expanded[i] = cageWithDinosaurs{Cage: cage, Dinosaurs: occupants[cage.ID]}
+		if expanded[i].Dinosaurs == nil {
+			expanded[i].Dinosaurs = []app.Dinosaur{}
+		}
+	}
+
+	return expanded, nil
+}
+
+// expandCages embeds the cages into the dinosaurs.
+// The cages of all the dinosaurs are fetched with a single query.
+// Deleted cages are embedded only along with the deleted dinosaurs,
+// the dinosaurs that don't live in a cage have none.
+func (s *Server) expandCages(ctx context.Context, dinosaurs []app.Dinosaur, includeDeleted bool) ([]dinosaurWithCage, error) {
+	expanded := make([]dinosaurWithCage, len(dinosaurs))
+	if len(dinosaurs) == 0 {
+		return expanded, nil
+	}
+
+	var (
+		ids  []string
+		seen = make(map[string]bool)
+	)
+	for _, d := range dinosaurs {
+		if d.CageID != "" && !seen[d.CageID] {
+			seen[d.CageID] = true
+			ids = append(ids, d.CageID)
+		}
+	}
+
+	var cages []app.Cage
+	if len(ids) > 0 {
+		var err error
+		if cages, err = s.CageStore.List(ctx, app.CageFilter{IDs: ids, IncludeDeleted: includeDeleted}); err != nil {
+			return nil, err
+		}
+	}
+
+	byID := make(map[string]*app.Cage, len(cages))
+	for i := range cages {
+		byID[cages[i].ID] = &cages[i]
+	}
+
+	for i, d := range dinosaurs {
+		expanded[i] = dinosaurWithCage{Dinosaur: d, Cage: byID[d.CageID]}
+	}
+
+	return expanded, nil
+}
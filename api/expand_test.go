@@ -0,0 +1,195 @@
+//go:build unit
+// +build unit
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+func TestExpandCageDinosaurs(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	now := time.Now()
+	cage := app.Cage{ID: uuid.NewString(), Type: app.CageTypePaddock, Capacity: 2, CapacityUnit: app.CapacityUnitHeads, Status: app.CageStatusActive, Occupancy: 1, CreatedAt: now, UpdatedAt: now}
+	dinosaur := app.Dinosaur{ID: uuid.NewString(), Name: "Blue", Species: app.DinosaurSpeciesVelociraptor, CageID: cage.ID, Stage: app.LifecycleStageAdult, HealthStatus: app.HealthStatusHealthy, CreatedAt: now, UpdatedAt: now}
+
+	cageStore := &fakeCageStore{cage: cage}
+	dinosaurStore := &fakeDinosaurStore{dinosaur: dinosaur}
+	svc := &Server{
+		Logger:        logger,
+		CageStore:     cageStore,
+		DinosaurStore: dinosaurStore,
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/cages?expand=dinosaurs", nil)
+
+	validated(t, svc.ListCages()).ServeHTTP(w, r)
+
+	if want, got := http.StatusOK, w.Code; want != got {
+		t.Fatalf("Expected %d got %d", want, got)
+	}
+
+	response := struct {
+		Data []cageWithDinosaurs `json:"data"`
+	}{}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 1, len(response.Data); want != got {
+		t.Fatalf("Expected cages %d got %d", want, got)
+	}
+	if want, got := cage.ID, response.Data[0].ID; want != got {
+		t.Fatalf("Expected ID %s got %s", want, got)
+	}
+	if want, got := 1, len(response.Data[0].Dinosaurs); want != got {
+		t.Fatalf("Expected dinosaurs %d got %d", want, got)
+	}
+	if want, got := dinosaur.ID, response.Data[0].Dinosaurs[0].ID; want != got {
+		t.Fatalf("Expected dinosaur %s got %s", want, got)
+	}
+	if want, got := []string{cage.ID}, dinosaurStore.filter.CageIDs; !reflect.DeepEqual(want, got) {
+		t.Fatalf("Expected CageIDs %v got %v", want, got)
+	}
+
+	// An empty cage has an empty list of dinosaurs.
+	dinosaurStore.dinosaur = app.Dinosaur{}
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodGet, "/cages/"+cage.ID+"?expand=dinosaurs", nil)
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", cage.ID)
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+	validated(t, svc.GetCage()).ServeHTTP(w, r)
+
+	if want, got := http.StatusOK, w.Code; want != got {
+		t.Fatalf("Expected %d got %d", want, got)
+	}
+
+	var single struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &single); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "[]", string(single.Data["dinosaurs"]); want != got {
+		t.Fatalf("Expected dinosaurs %s got %s", want, got)
+	}
+}
+
+func TestExpandDinosaurCage(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	now := time.Now()
+	cage := app.Cage{ID: uuid.NewString(), Type: app.CageTypePaddock, Capacity: 2, CapacityUnit: app.CapacityUnitHeads, Status: app.CageStatusActive, Occupancy: 1, CreatedAt: now, UpdatedAt: now}
+	dinosaur := app.Dinosaur{ID: uuid.NewString(), Name: "Blue", Species: app.DinosaurSpeciesVelociraptor, CageID: cage.ID, Stage: app.LifecycleStageAdult, HealthStatus: app.HealthStatusHealthy, CreatedAt: now, UpdatedAt: now}
+
+	cageStore := &fakeCageStore{cage: cage}
+	dinosaurStore := &fakeDinosaurStore{dinosaur: dinosaur}
+	svc := &Server{
+		Logger:        logger,
+		CageStore:     cageStore,
+		DinosaurStore: dinosaurStore,
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/dinosaurs?expand=cage&includeDeleted=true", nil)
+
+	validated(t, svc.ListAllDinosaurs()).ServeHTTP(w, r)
+
+	if want, got := http.StatusOK, w.Code; want != got {
+		t.Fatalf("Expected %d got %d", want, got)
+	}
+
+	response := struct {
+		Data []dinosaurWithCage `json:"data"`
+	}{}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 1, len(response.Data); want != got {
+		t.Fatalf("Expected dinosaurs %d got %d", want, got)
+	}
+	if response.Data[0].Cage == nil {
+		t.Fatal("Expected cage got nil")
+	}
+	if want, got := cage.ID, response.Data[0].Cage.ID; want != got {
+		t.Fatalf("Expected cage %s got %s", want, got)
+	}
+	if want, got := []string{cage.ID}, cageStore.filter.IDs; !reflect.DeepEqual(want, got) {
+		t.Fatalf("Expected IDs %v got %v", want, got)
+	}
+	if !cageStore.filter.IncludeDeleted {
+		t.Fatal("Expected IncludeDeleted")
+	}
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodGet, "/dinosaurs/"+dinosaur.ID+"?expand=cage", nil)
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", dinosaur.ID)
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+	validated(t, svc.GetDinosaur()).ServeHTTP(w, r)
+
+	if want, got := http.StatusOK, w.Code; want != got {
+		t.Fatalf("Expected %d got %d", want, got)
+	}
+
+	single := struct {
+		Data dinosaurWithCage `json:"data"`
+	}{}
+	if err := json.Unmarshal(w.Body.Bytes(), &single); err != nil {
+		t.Fatal(err)
+	}
+	if single.Data.Cage == nil || single.Data.Cage.ID != cage.ID {
+		t.Fatalf("Expected cage %s got %+v", cage.ID, single.Data.Cage)
+	}
+}
+
+func TestExpandInvalid(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	svc := &Server{
+		Logger:        logger,
+		CageStore:     &fakeCageStore{},
+		DinosaurStore: &fakeDinosaurStore{},
+	}
+
+	tests := []struct {
+		name    string
+		handler http.Handler
+		path    string
+	}{
+		{"cages", svc.ListCages(), "/cages?expand=cage"},
+		{"dinosaurs", svc.ListAllDinosaurs(), "/dinosaurs?expand=dinosaurs"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, tt.path, nil)
+
+			validated(t, tt.handler).ServeHTTP(w, r)
+
+			if want, got := http.StatusBadRequest, w.Code; want != got {
+				t.Fatalf("Expected %d got %d", want, got)
+			}
+		})
+	}
+}
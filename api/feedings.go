@@ -0,0 +1,307 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+// ListFeedingSchedules lists feeding schedules.
+// GET /feeding-schedules[?cageId=...][&species=...]
+func (s *Server) ListFeedingSchedules() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+
+		filter, err := feedingScheduleFilter(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		schedules, err := s.FeedingStore.ListSchedules(r.Context(), filter)
+		if err != nil {
+			logger.Error("Error getting feeding schedules", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		if schedules == nil {
+			schedules = []app.FeedingSchedule{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		response := struct {
+			Data []app.FeedingSchedule `json:"data"`
+		}{
+			Data: schedules,
+		}
+
+		if err = json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			return
+		}
+	}
+}
+
+// AddFeedingScheduleRequest is a request to add a new feeding schedule.
+type AddFeedingScheduleRequest struct {
+	CageID        string              `json:"cageId"`
+	Species       app.DinosaurSpecies `json:"species"`
+	Diet          string              `json:"diet"`
+	Quantity      float64             `json:"quantity"`
+	IntervalHours int                 `json:"intervalHours"`
+	WindowStart   string              `json:"windowStart"`
+	WindowEnd     string              `json:"windowEnd"`
+}
+
+// Schedule returns the requested feeding schedule.
+func (r AddFeedingScheduleRequest) Schedule() app.FeedingSchedule {
+	return app.FeedingSchedule{
+		CageID:        r.CageID,
+		Species:       r.Species,
+		Diet:          r.Diet,
+		Quantity:      r.Quantity,
+		IntervalHours: r.IntervalHours,
+		WindowStart:   r.WindowStart,
+		WindowEnd:     r.WindowEnd,
+	}
+}
+
+// AddFeedingSchedule adds a new feeding schedule of a cage or of a species.
+// POST /feeding-schedules
+func (s *Server) AddFeedingSchedule() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+
+		var req AddFeedingScheduleRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.Error("Error decoding request body", "error", err)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		schedule := req.Schedule()
+		if err := schedule.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		added, err := s.FeedingStore.AddSchedule(r.Context(), &schedule)
+		if err != nil {
+			if err == app.ErrNotFound {
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+				return
+			}
+
+			logger.Error("Error adding feeding schedule", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+
+		response := struct {
+			Data *app.FeedingSchedule `json:"data"`
+		}{
+			Data: added,
+		}
+
+		if err = json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			return
+		}
+	}
+}
+
+// DeleteFeedingSchedule deletes a feeding schedule.
+// DELETE /feeding-schedules/:id
+func (s *Server) DeleteFeedingSchedule() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+		id := chi.URLParam(r, "id")
+
+		if err := app.ValidateID(id); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err := s.FeedingStore.DeleteSchedule(r.Context(), id); err != nil {
+			if err == app.ErrNotFound {
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+				return
+			}
+
+			logger.Error("Error deleting feeding schedule", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// ListFeedings lists the recorded feedings, the most recent first.
+// GET /feedings[?cageId=...][&dinosaurId=...][&unsafe=true][&fedAfter=...][&fedBefore=...]
+func (s *Server) ListFeedings() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+
+		filter, err := feedingFilter(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		feedings, err := s.FeedingStore.List(r.Context(), filter)
+		if err != nil {
+			logger.Error("Error getting feedings", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		if feedings == nil {
+			feedings = []app.Feeding{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		response := struct {
+			Data []app.Feeding `json:"data"`
+		}{
+			Data: feedings,
+		}
+
+		if err = json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			return
+		}
+	}
+}
+
+// RecordFeedingRequest is a request to record a feeding.
+type RecordFeedingRequest struct {
+	CageID     string    `json:"cageId"`
+	DinosaurID string    `json:"dinosaurId"`
+	Diet       string    `json:"diet"`
+	Quantity   float64   `json:"quantity"`
+	FedAt      time.Time `json:"fedAt"`
+}
+
+// Feeding returns the requested feeding.
+func (r RecordFeedingRequest) Feeding() app.Feeding {
+	return app.Feeding{
+		CageID:     r.CageID,
+		DinosaurID: r.DinosaurID,
+		Diet:       r.Diet,
+		Quantity:   r.Quantity,
+		FedAt:      r.FedAt,
+	}
+}
+
+// RecordFeeding records a feeding of a dinosaur or of all occupants of a cage.
+// The feeding time defaults to now. Feedings of carnivores in a powered down
+// cage are recorded but flagged as unsafe.
+// POST /feedings
+func (s *Server) RecordFeeding() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+
+		var req RecordFeedingRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.Error("Error decoding request body", "error", err)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		feeding := req.Feeding()
+		if err := feeding.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		recorded, err := s.FeedingStore.Record(r.Context(), &feeding)
+		if err != nil {
+			switch err {
+			case app.ErrNotFound:
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			case app.ErrNotInCage:
+				http.Error(w, err.Error(), http.StatusConflict)
+			default:
+				logger.Error("Error recording feeding", "error", err)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+
+			return
+		}
+		if recorded.Unsafe {
+			logger.Warn("Unsafe feeding of carnivores in a powered down cage", "feedingId", recorded.ID, "cageId", recorded.CageID)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+
+		response := struct {
+			Data *app.Feeding `json:"data"`
+		}{
+			Data: recorded,
+		}
+
+		if err = json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			return
+		}
+	}
+}
+
+// ListOverdueFeedings reports the dinosaurs that are overdue for a feeding
+// according to the schedules at the given time, now by default,
+// the most overdue first.
+// GET /feedings/overdue[?at=...]
+func (s *Server) ListOverdueFeedings() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+
+		at, err := timestamp(r.URL.Query(), "at")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if at.IsZero() {
+			at = time.Now()
+		}
+
+		overdue, err := s.FeedingStore.Overdue(r.Context(), at)
+		if err != nil {
+			logger.Error("Error getting overdue feedings", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		if overdue == nil {
+			overdue = []app.OverdueFeeding{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		response := struct {
+			Data []app.OverdueFeeding `json:"data"`
+		}{
+			Data: overdue,
+		}
+
+		if err = json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			return
+		}
+	}
+}
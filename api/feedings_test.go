@@ -0,0 +1,439 @@
+//go:build unit
+// +build unit
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+type fakeFeedingStore struct {
+	schedule       app.FeedingSchedule
+	feeding        app.Feeding
+	overdue        []app.OverdueFeeding
+	scheduleFilter app.FeedingScheduleFilter
+	filter         app.FeedingFilter
+	id             string
+	at             time.Time
+	unsafe         bool
+	err            error
+}
+
+func (s *fakeFeedingStore) AddSchedule(_ context.Context, schedule *app.FeedingSchedule) (*app.FeedingSchedule, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	now := time.Now()
+	s.schedule = *schedule
+	s.schedule.ID = uuid.NewString()
+	s.schedule.CreatedAt = now
+	s.schedule.UpdatedAt = now
+	added := s.schedule
+
+	return &added, nil
+}
+
+func (s *fakeFeedingStore) ListSchedules(_ context.Context, filter app.FeedingScheduleFilter) ([]app.FeedingSchedule, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	s.scheduleFilter = filter
+	if s.schedule.ID == "" {
+		return nil, nil
+	}
+
+	return []app.FeedingSchedule{s.schedule}, nil
+}
+
+func (s *fakeFeedingStore) DeleteSchedule(_ context.Context, id string) error {
+	s.id = id
+
+	return s.err
+}
+
+func (s *fakeFeedingStore) Record(_ context.Context, feeding *app.Feeding) (*app.Feeding, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	now := time.Now()
+	s.feeding = *feeding
+	s.feeding.ID = uuid.NewString()
+	if s.feeding.CageID == "" {
+		s.feeding.CageID = uuid.NewString()
+	}
+	s.feeding.Unsafe = s.unsafe
+	if s.feeding.FedAt.IsZero() {
+		s.feeding.FedAt = now
+	}
+	s.feeding.CreatedAt = now
+	recorded := s.feeding
+
+	return &recorded, nil
+}
+
+func (s *fakeFeedingStore) List(_ context.Context, filter app.FeedingFilter) ([]app.Feeding, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	s.filter = filter
+	if s.feeding.ID == "" {
+		return nil, nil
+	}
+
+	return []app.Feeding{s.feeding}, nil
+}
+
+func (s *fakeFeedingStore) Overdue(_ context.Context, at time.Time) ([]app.OverdueFeeding, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	s.at = at
+
+	return s.overdue, nil
+}
+
+func TestAddFeedingSchedule(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	cageID := uuid.NewString()
+	tests := []struct {
+		name   string
+		body   string
+		err    error
+		status int
+	}{
+		{"cage", `{"cageId": "` + cageID + `", "diet": "goats", "quantity": 50, "intervalHours": 24, "windowStart": "08:00", "windowEnd": "10:00"}`, nil, http.StatusCreated},
+		{"species", `{"species": "triceratops", "diet": "ferns", "quantity": 120.5, "intervalHours": 8}`, nil, http.StatusCreated},
+		{"no cage or species", `{"diet": "goats", "quantity": 50, "intervalHours": 24}`, nil, http.StatusBadRequest},
+		{"cage and species", `{"cageId": "` + cageID + `", "species": "triceratops", "diet": "ferns", "quantity": 1, "intervalHours": 8}`, nil, http.StatusBadRequest},
+		{"invalid species", `{"species": "dodo", "diet": "ferns", "quantity": 1, "intervalHours": 8}`, nil, http.StatusBadRequest},
+		{"no diet", `{"species": "triceratops", "quantity": 1, "intervalHours": 8}`, nil, http.StatusBadRequest},
+		{"invalid quantity", `{"species": "triceratops", "diet": "ferns", "quantity": 0, "intervalHours": 8}`, nil, http.StatusBadRequest},
+		{"invalid interval", `{"species": "triceratops", "diet": "ferns", "quantity": 1, "intervalHours": 0}`, nil, http.StatusBadRequest},
+		{"half a window", `{"species": "triceratops", "diet": "ferns", "quantity": 1, "intervalHours": 8, "windowStart": "08:00"}`, nil, http.StatusBadRequest},
+		{"inverted window", `{"species": "triceratops", "diet": "ferns", "quantity": 1, "intervalHours": 8, "windowStart": "10:00", "windowEnd": "08:00"}`, nil, http.StatusBadRequest},
+		{"invalid body", `{"species": "triceratops"`, nil, http.StatusBadRequest},
+		{"cage not found", `{"cageId": "` + cageID + `", "diet": "goats", "quantity": 50, "intervalHours": 24}`, app.ErrNotFound, http.StatusNotFound},
+		{"store error", `{"cageId": "` + cageID + `", "diet": "goats", "quantity": 50, "intervalHours": 24}`, errors.New("store error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeFeedingStore{err: tt.err}
+			svc := &Server{
+				Logger:       logger,
+				FeedingStore: store,
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/feeding-schedules", strings.NewReader(tt.body))
+
+			validated(t, svc.AddFeedingSchedule()).ServeHTTP(w, r)
+
+			if want, got := tt.status, w.Code; want != got {
+				t.Fatalf("Expected %d got %d", want, got)
+			}
+			if tt.status != http.StatusCreated {
+				return
+			}
+
+			response := struct {
+				Data app.FeedingSchedule `json:"data"`
+			}{}
+
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+
+			if want, got := store.schedule.ID, response.Data.ID; want != got {
+				t.Fatalf("Expected ID %s got %s", want, got)
+			}
+			if want, got := store.schedule.Diet, response.Data.Diet; want != got {
+				t.Fatalf("Expected Diet %s got %s", want, got)
+			}
+		})
+	}
+}
+
+func TestListFeedingSchedules(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	cageID := uuid.NewString()
+	tests := []struct {
+		name   string
+		query  string
+		filter app.FeedingScheduleFilter
+		status int
+	}{
+		{"no filter", "", app.FeedingScheduleFilter{}, http.StatusOK},
+		{"cage", "?cageId=" + cageID, app.FeedingScheduleFilter{CageID: cageID}, http.StatusOK},
+		{"species", "?species=stegosaurus", app.FeedingScheduleFilter{Species: app.DinosaurSpeciesStegosaurus}, http.StatusOK},
+		{"invalid cage", "?cageId=foo", app.FeedingScheduleFilter{}, http.StatusBadRequest},
+		{"invalid species", "?species=dodo", app.FeedingScheduleFilter{}, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeFeedingStore{}
+			svc := &Server{
+				Logger:       logger,
+				FeedingStore: store,
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/feeding-schedules"+tt.query, nil)
+
+			validated(t, svc.ListFeedingSchedules()).ServeHTTP(w, r)
+
+			if want, got := tt.status, w.Code; want != got {
+				t.Fatalf("Expected %d got %d", want, got)
+			}
+			if want, got := tt.filter, store.scheduleFilter; want != got {
+				t.Fatalf("Expected filter %+v got %+v", want, got)
+			}
+			if tt.status == http.StatusOK {
+				if want, got := `{"data":[]}`, strings.TrimSpace(w.Body.String()); want != got {
+					t.Fatalf("Expected body %s got %s", want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestDeleteFeedingSchedule(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	tests := []struct {
+		name   string
+		id     string
+		err    error
+		status int
+	}{
+		{"deleted", uuid.NewString(), nil, http.StatusNoContent},
+		{"invalid id", "foo", nil, http.StatusBadRequest},
+		{"not found", uuid.NewString(), app.ErrNotFound, http.StatusNotFound},
+		{"store error", uuid.NewString(), errors.New("store error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeFeedingStore{err: tt.err}
+			svc := &Server{
+				Logger:       logger,
+				FeedingStore: store,
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodDelete, "/feeding-schedules/"+tt.id, nil)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.id)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			validated(t, svc.DeleteFeedingSchedule()).ServeHTTP(w, r)
+
+			if want, got := tt.status, w.Code; want != got {
+				t.Fatalf("Expected %d got %d", want, got)
+			}
+			if tt.status == http.StatusNoContent {
+				if want, got := tt.id, store.id; want != got {
+					t.Fatalf("Expected ID %s got %s", want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestRecordFeeding(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	cageID := uuid.NewString()
+	dinosaurID := uuid.NewString()
+	tests := []struct {
+		name   string
+		body   string
+		unsafe bool
+		err    error
+		status int
+	}{
+		{"cage", `{"cageId": "` + cageID + `", "diet": "goats", "quantity": 50}`, false, nil, http.StatusCreated},
+		{"dinosaur", `{"dinosaurId": "` + dinosaurID + `", "diet": "goats", "quantity": 50, "fedAt": "2023-01-02T08:30:00Z"}`, false, nil, http.StatusCreated},
+		{"unsafe", `{"cageId": "` + cageID + `", "diet": "goats", "quantity": 50}`, true, nil, http.StatusCreated},
+		{"no cage or dinosaur", `{"diet": "goats", "quantity": 50}`, false, nil, http.StatusBadRequest},
+		{"cage and dinosaur", `{"cageId": "` + cageID + `", "dinosaurId": "` + dinosaurID + `", "diet": "goats", "quantity": 50}`, false, nil, http.StatusBadRequest},
+		{"invalid dinosaur", `{"dinosaurId": "foo", "diet": "goats", "quantity": 50}`, false, nil, http.StatusBadRequest},
+		{"invalid quantity", `{"cageId": "` + cageID + `", "diet": "goats", "quantity": -1}`, false, nil, http.StatusBadRequest},
+		{"not found", `{"dinosaurId": "` + dinosaurID + `", "diet": "goats", "quantity": 50}`, false, app.ErrNotFound, http.StatusNotFound},
+		{"store error", `{"cageId": "` + cageID + `", "diet": "goats", "quantity": 50}`, false, errors.New("store error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeFeedingStore{unsafe: tt.unsafe, err: tt.err}
+			svc := &Server{
+				Logger:       logger,
+				FeedingStore: store,
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/feedings", strings.NewReader(tt.body))
+
+			validated(t, svc.RecordFeeding()).ServeHTTP(w, r)
+
+			if want, got := tt.status, w.Code; want != got {
+				t.Fatalf("Expected %d got %d", want, got)
+			}
+			if tt.status != http.StatusCreated {
+				return
+			}
+
+			response := struct {
+				Data app.Feeding `json:"data"`
+			}{}
+
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+
+			if want, got := tt.unsafe, response.Data.Unsafe; want != got {
+				t.Fatalf("Expected Unsafe %t got %t", want, got)
+			}
+			if want, got := store.feeding.DinosaurID, response.Data.DinosaurID; want != got {
+				t.Fatalf("Expected DinosaurID %s got %s", want, got)
+			}
+		})
+	}
+}
+
+func TestListFeedings(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	cageID := uuid.NewString()
+	after := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name   string
+		query  string
+		filter app.FeedingFilter
+		status int
+	}{
+		{"no filter", "", app.FeedingFilter{}, http.StatusOK},
+		{"cage", "?cageId=" + cageID, app.FeedingFilter{CageID: cageID}, http.StatusOK},
+		{"unsafe", "?unsafe=true&fedAfter=2023-01-02T03:04:05Z", app.FeedingFilter{UnsafeOnly: true, Fed: app.TimeRange{After: after}}, http.StatusOK},
+		{"invalid dinosaur", "?dinosaurId=foo", app.FeedingFilter{}, http.StatusBadRequest},
+		{"invalid unsafe", "?unsafe=maybe", app.FeedingFilter{}, http.StatusBadRequest},
+		{"invalid time", "?fedBefore=yesterday", app.FeedingFilter{}, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeFeedingStore{}
+			svc := &Server{
+				Logger:       logger,
+				FeedingStore: store,
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/feedings"+tt.query, nil)
+
+			validated(t, svc.ListFeedings()).ServeHTTP(w, r)
+
+			if want, got := tt.status, w.Code; want != got {
+				t.Fatalf("Expected %d got %d", want, got)
+			}
+			if want, got := tt.filter, store.filter; want != got {
+				t.Fatalf("Expected filter %+v got %+v", want, got)
+			}
+		})
+	}
+}
+
+func TestListOverdueFeedings(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	now := time.Now()
+	lastFedAt := now.Add(-30 * time.Hour)
+	store := &fakeFeedingStore{
+		overdue: []app.OverdueFeeding{
+			{
+				ScheduleID: uuid.NewString(),
+				DinosaurID: uuid.NewString(),
+				Name:       "Rexy",
+				Species:    app.DinosaurSpeciesTyrannosaurus,
+				CageID:     uuid.NewString(),
+				LastFedAt:  &lastFedAt,
+				DueAt:      lastFedAt.Add(24 * time.Hour),
+			},
+		},
+	}
+	svc := &Server{
+		Logger:       logger,
+		FeedingStore: store,
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/feedings/overdue", nil)
+
+	validated(t, svc.ListOverdueFeedings()).ServeHTTP(w, r)
+
+	if want, got := http.StatusOK, w.Code; want != got {
+		t.Fatalf("Expected %d got %d", want, got)
+	}
+	if store.at.Before(now) {
+		t.Fatalf("Expected overdue at now got %v", store.at)
+	}
+
+	response := struct {
+		Data []app.OverdueFeeding `json:"data"`
+	}{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := 1, len(response.Data); want != got {
+		t.Fatalf("Expected overdue %d got %d", want, got)
+	}
+	if want, got := "Rexy", response.Data[0].Name; want != got {
+		t.Fatalf("Expected Name %s got %s", want, got)
+	}
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodGet, "/feedings/overdue?at=2023-01-02T03:04:05Z", nil)
+
+	validated(t, svc.ListOverdueFeedings()).ServeHTTP(w, r)
+
+	if want, got := http.StatusOK, w.Code; want != got {
+		t.Fatalf("Expected %d got %d", want, got)
+	}
+	if want, got := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC), store.at; !want.Equal(got) {
+		t.Fatalf("Expected at %v got %v", want, got)
+	}
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodGet, "/feedings/overdue?at=tomorrow", nil)
+
+	validated(t, svc.ListOverdueFeedings()).ServeHTTP(w, r)
+
+	if want, got := http.StatusBadRequest, w.Code; want != got {
+		t.Fatalf("Expected %d got %d", want, got)
+	}
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+)
+
+// withField adds the field to a sparse fieldset unless it's there already.
+// An empty fieldset already has all the fields.
+func withField(fields []string, field string) []string {
+	if len(fields) == 0 || slices.Contains(fields, field) {
+		return fields
+	}
+
+	return append(slices.Clip(fields), field)
+}
+
+// sparse limits the JSON representation of a resource or a list of resources
+// to the fields and the expanded related resources.
+// All the fields are kept if the fieldset is empty.
+func sparse(v any, fields []string, expanded map[string]bool) (any, error) {
+	if len(fields) == 0 {
+		return v, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	keep := func(object map[string]json.RawMessage) {
+		for name := range object {
+			if !slices.Contains(fields, name) && !expanded[name] {
+				delete(object, name)
+			}
+		}
+	}
+
+	if bytes.HasPrefix(data, []byte("[")) {
+		var objects []map[string]json.RawMessage
+		if err := json.Unmarshal(data, &objects); err != nil {
+			return nil, err
+		}
+		for _, object := range objects {
+			keep(object)
+		}
+
+		return objects, nil
+	}
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+	keep(object)
+
+	return object, nil
+}
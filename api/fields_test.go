@@ -0,0 +1,173 @@
+//go:build unit
+// +build unit
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+// keys returns the sorted keys of the JSON objects.
+func keys(t *testing.T, data json.RawMessage) [][]string {
+	t.Helper()
+
+	var objects []map[string]json.RawMessage
+	if len(data) > 0 && data[0] == '{' {
+		data = append(append([]byte("["), data...), ']')
+	}
+	if err := json.Unmarshal(data, &objects); err != nil {
+		t.Fatal(err)
+	}
+
+	all := make([][]string, len(objects))
+	for i, object := range objects {
+		for key := range object {
+			all[i] = append(all[i], key)
+		}
+		sort.Strings(all[i])
+	}
+
+	return all
+}
+
+func TestListCagesFields(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	now := time.Now()
+	cage := app.Cage{ID: uuid.NewString(), Type: app.CageTypeGeneral, Capacity: 2, CapacityUnit: app.CapacityUnitHeads, Status: app.CageStatusActive, CreatedAt: now, UpdatedAt: now}
+	cageStore := &fakeCageStore{cage: cage}
+	dinosaurStore := &fakeDinosaurStore{}
+	svc := &Server{
+		Logger:        logger,
+		CageStore:     cageStore,
+		DinosaurStore: dinosaurStore,
+	}
+
+	tests := []struct {
+		name   string
+		query  string
+		fields []string // Fields passed to the store.
+		keys   []string // Keys in the response.
+	}{
+		{"all", "", nil, []string{"capacity", "capacityUnit", "createdAt", "headCount", "id", "occupancy", "quarantine", "spaceUsed", "status", "type", "updatedAt"}},
+		{"subset", "?fields=status,capacity", []string{"status", "capacity"}, []string{"capacity", "status"}},
+		{"expanded", "?fields=status&expand=dinosaurs", []string{"status", "id"}, []string{"dinosaurs", "status"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/cages"+tt.query, nil)
+
+			validated(t, svc.ListCages()).ServeHTTP(w, r)
+
+			if want, got := http.StatusOK, w.Code; want != got {
+				t.Fatalf("Expected %d got %d", want, got)
+			}
+			if want, got := tt.fields, cageStore.filter.Fields; !reflect.DeepEqual(want, got) {
+				t.Fatalf("Expected store fields %v got %v", want, got)
+			}
+
+			var response struct {
+				Data json.RawMessage `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+			if want, got := [][]string{tt.keys}, keys(t, response.Data); !reflect.DeepEqual(want, got) {
+				t.Fatalf("Expected keys %v got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestGetDinosaurFields(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	now := time.Now()
+	id := uuid.NewString()
+	store := &fakeDinosaurStore{
+		dinosaur: app.Dinosaur{ID: id, Name: "Blue", Species: app.DinosaurSpeciesVelociraptor, CageID: uuid.NewString(), HealthStatus: app.HealthStatusHealthy, CreatedAt: now, UpdatedAt: now},
+	}
+	svc := &Server{
+		Logger:        logger,
+		DinosaurStore: store,
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/dinosaurs/"+id+"?fields=id,name", nil)
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", id)
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+	validated(t, svc.GetDinosaur()).ServeHTTP(w, r)
+
+	if want, got := http.StatusOK, w.Code; want != got {
+		t.Fatalf("Expected %d got %d", want, got)
+	}
+	if want, got := []string{"id", "name"}, store.opts.Fields; !reflect.DeepEqual(want, got) {
+		t.Fatalf("Expected store fields %v got %v", want, got)
+	}
+
+	var response struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := [][]string{{"id", "name"}}, keys(t, response.Data); !reflect.DeepEqual(want, got) {
+		t.Fatalf("Expected keys %v got %v", want, got)
+	}
+}
+
+func TestInvalidFields(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	svc := &Server{
+		Logger:        logger,
+		CageStore:     &fakeCageStore{},
+		DinosaurStore: &fakeDinosaurStore{},
+	}
+
+	tests := []struct {
+		name    string
+		handler http.Handler
+		path    string
+	}{
+		{"cages", svc.ListCages(), "/cages?fields=id,name"},
+		{"dinosaurs", svc.ListAllDinosaurs(), "/dinosaurs?fields=capacity"},
+		{"dinosaur", svc.GetDinosaur(), "/dinosaurs/" + uuid.NewString() + "?fields=occupancy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, tt.path, nil)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", uuid.NewString())
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			validated(t, tt.handler).ServeHTTP(w, r)
+
+			if want, got := http.StatusBadRequest, w.Code; want != got {
+				t.Fatalf("Expected %d got %d", want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+// ListEggs lists the eggs in the hatchery.
+// Eggs are sorted by the hatch date unless the sort order is set.
+// GET /hatchery[?species=...][&diet=...][&namePrefix=...][&hatchAfter=...][&hatchBefore=...][&sort=...][&fields=...][&includeDeleted=true]
+func (s *Server) ListEggs() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+
+		filter, err := dinosaurFilter(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		filter.Stages = []app.LifecycleStage{app.LifecycleStageEgg}
+		if len(filter.Sort) == 0 {
+			filter.Sort = []app.Sort{{Field: "hatchDate"}}
+		}
+
+		eggs, err := s.DinosaurStore.List(r.Context(), filter)
+		if err != nil {
+			logger.Error("Error getting eggs", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		if eggs == nil {
+			eggs = []app.Dinosaur{}
+		}
+
+		response := struct {
+			Data any `json:"data"`
+		}{
+			Data: eggs,
+		}
+		if response.Data, err = sparse(response.Data, filter.Fields, nil); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if err = json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			return
+		}
+	}
+}
+
+// AddEggRequest is a request to lay an egg into the hatchery.
+type AddEggRequest struct {
+	Name      string              `json:"name"`
+	Species   app.DinosaurSpecies `json:"species"`
+	HatchDate time.Time           `json:"hatchDate"`
+}
+
+// Egg returns the requested egg.
+func (r AddEggRequest) Egg() app.Egg {
+	return app.Egg{
+		Name:      r.Name,
+		Species:   r.Species,
+		HatchDate: r.HatchDate,
+	}
+}
+
+// AddEgg lays an egg into the hatchery.
+// POST /hatchery
+func (s *Server) AddEgg() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+
+		var req AddEggRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.Error("Error decoding request body", "error", err)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		egg := req.Egg()
+		if err := egg.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		added, err := s.DinosaurStore.AddEgg(r.Context(), egg)
+		if err != nil {
+			logger.Error("Error adding egg", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+
+		response := struct {
+			Data *app.Dinosaur `json:"data"`
+		}{
+			Data: added,
+		}
+
+		if err = json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			return
+		}
+	}
+}
+
+// HatchEgg hatches an egg into a cage as a juvenile.
+// The cage has to admit the hatchling like any other dinosaur.
+// POST /hatchery/:id/hatch
+func (s *Server) HatchEgg() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+		id := chi.URLParam(r, "id")
+
+		if err := app.ValidateID(id); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var req MoveDinosaurRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.Error("Error decoding request body", "error", err)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		if err := req.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		dinosaur, err := s.DinosaurStore.Hatch(r.Context(), id, req.CageID)
+		if err != nil {
+			switch err {
+			case app.ErrNotFound:
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			case app.ErrCagePoweredDown, app.ErrCageLockedDown, app.ErrNotCertified, app.ErrCapacityExceeded, app.ErrSpeciesMismatch, app.ErrHabitatMismatch,
+				app.ErrQuarantineMismatch, app.ErrIllegalTransition:
+				http.Error(w, err.Error(), http.StatusConflict)
+			default:
+				logger.Error("Error hatching egg", "error", err)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		response := struct {
+			Data *app.Dinosaur `json:"data"`
+		}{
+			Data: dinosaur,
+		}
+
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			return
+		}
+	}
+}
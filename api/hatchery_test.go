@@ -0,0 +1,232 @@
+//go:build unit
+// +build unit
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+func TestAddEgg(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	tests := []struct {
+		name   string
+		body   string
+		err    error
+		status int
+	}{
+		{"laid", `{"name": "Blue", "species": "velociraptor", "hatchDate": "2023-03-01T00:00:00Z"}`, nil, http.StatusCreated},
+		{"no name", `{"species": "velociraptor", "hatchDate": "2023-03-01T00:00:00Z"}`, nil, http.StatusBadRequest},
+		{"invalid species", `{"name": "Blue", "species": "dodo", "hatchDate": "2023-03-01T00:00:00Z"}`, nil, http.StatusBadRequest},
+		{"no hatch date", `{"name": "Blue", "species": "velociraptor"}`, nil, http.StatusBadRequest},
+		{"invalid body", `{"name": "`, nil, http.StatusBadRequest},
+		{"store error", `{"name": "Blue", "species": "velociraptor", "hatchDate": "2023-03-01T00:00:00Z"}`, errors.New("store error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeDinosaurStore{err: tt.err}
+			svc := &Server{
+				Logger:        logger,
+				DinosaurStore: store,
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/hatchery", strings.NewReader(tt.body))
+
+			validated(t, svc.AddEgg()).ServeHTTP(w, r)
+
+			if want, got := tt.status, w.Code; want != got {
+				t.Fatalf("Expected %d got %d", want, got)
+			}
+			if tt.status != http.StatusCreated {
+				return
+			}
+
+			response := struct {
+				Data app.Dinosaur `json:"data"`
+			}{}
+
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+
+			if want, got := app.LifecycleStageEgg, response.Data.Stage; want != got {
+				t.Fatalf("Expected Stage %s got %s", want, got)
+			}
+			if want, got := "", response.Data.CageID; want != got {
+				t.Fatalf("Expected CageID %s got %s", want, got)
+			}
+			if response.Data.HatchDate == nil || !response.Data.HatchDate.Equal(time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)) {
+				t.Fatalf("Expected HatchDate 2023-03-01 got %v", response.Data.HatchDate)
+			}
+		})
+	}
+}
+
+func TestListEggs(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	tests := []struct {
+		name   string
+		query  string
+		filter app.DinosaurFilter
+		status int
+	}{
+		{"all", "", app.DinosaurFilter{
+			Stages: []app.LifecycleStage{app.LifecycleStageEgg},
+			Sort:   []app.Sort{{Field: "hatchDate"}},
+		}, http.StatusOK},
+		{"due", "?species=velociraptor&hatchBefore=2023-03-01T00:00:00Z&sort=-createdAt", app.DinosaurFilter{
+			Species:   []app.DinosaurSpecies{app.DinosaurSpeciesVelociraptor},
+			Stages:    []app.LifecycleStage{app.LifecycleStageEgg},
+			HatchDate: app.TimeRange{Before: time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)},
+			Sort:      []app.Sort{{Field: "createdAt", Desc: true}},
+		}, http.StatusOK},
+		{"invalid hatch date", "?hatchAfter=soon", app.DinosaurFilter{}, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hatchDate := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+			store := &fakeDinosaurStore{
+				dinosaur: app.Dinosaur{
+					ID:           uuid.NewString(),
+					Name:         "Blue",
+					Species:      app.DinosaurSpeciesVelociraptor,
+					Stage:        app.LifecycleStageEgg,
+					HatchDate:    &hatchDate,
+					HealthStatus: app.HealthStatusHealthy,
+					CreatedAt:    time.Now(),
+					UpdatedAt:    time.Now(),
+				},
+			}
+			svc := &Server{
+				Logger:        logger,
+				DinosaurStore: store,
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/hatchery"+tt.query, nil)
+
+			validated(t, svc.ListEggs()).ServeHTTP(w, r)
+
+			if want, got := tt.status, w.Code; want != got {
+				t.Fatalf("Expected %d got %d", want, got)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+			if got := store.filter; !reflect.DeepEqual(tt.filter, got) {
+				t.Fatalf("Expected filter %+v got %+v", tt.filter, got)
+			}
+
+			response := struct {
+				Data []app.Dinosaur `json:"data"`
+			}{}
+
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+
+			if want, got := 1, len(response.Data); want != got {
+				t.Fatalf("Expected eggs %d got %d", want, got)
+			}
+		})
+	}
+}
+
+func TestHatchEgg(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	cageID := uuid.NewString()
+	tests := []struct {
+		name   string
+		body   string
+		err    error
+		status int
+	}{
+		{"hatched", `{"cageId": "` + cageID + `"}`, nil, http.StatusOK},
+		{"no cage", `{}`, nil, http.StatusBadRequest},
+		{"invalid body", `{"cageId": "`, nil, http.StatusBadRequest},
+		{"not found", `{"cageId": "` + cageID + `"}`, app.ErrNotFound, http.StatusNotFound},
+		{"not an egg", `{"cageId": "` + cageID + `"}`, app.ErrIllegalTransition, http.StatusConflict},
+		{"capacity exceeded", `{"cageId": "` + cageID + `"}`, app.ErrCapacityExceeded, http.StatusConflict},
+		{"habitat mismatch", `{"cageId": "` + cageID + `"}`, app.ErrHabitatMismatch, http.StatusConflict},
+		{"store error", `{"cageId": "` + cageID + `"}`, errors.New("store error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := uuid.NewString()
+			now := time.Now()
+			store := &fakeDinosaurStore{
+				dinosaur: app.Dinosaur{
+					ID:           id,
+					Name:         "Blue",
+					Species:      app.DinosaurSpeciesVelociraptor,
+					Stage:        app.LifecycleStageEgg,
+					HatchDate:    &now,
+					HealthStatus: app.HealthStatusHealthy,
+					CreatedAt:    now,
+					UpdatedAt:    now,
+				},
+				err: tt.err,
+			}
+			svc := &Server{
+				Logger:        logger,
+				DinosaurStore: store,
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/hatchery/"+id+"/hatch", strings.NewReader(tt.body))
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", id)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			validated(t, svc.HatchEgg()).ServeHTTP(w, r)
+
+			if want, got := tt.status, w.Code; want != got {
+				t.Fatalf("Expected %d got %d", want, got)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+			if want, got := id, store.id; want != got {
+				t.Fatalf("Expected id %s got %s", want, got)
+			}
+
+			response := struct {
+				Data app.Dinosaur `json:"data"`
+			}{}
+
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+
+			if want, got := app.LifecycleStageJuvenile, response.Data.Stage; want != got {
+				t.Fatalf("Expected Stage %s got %s", want, got)
+			}
+			if want, got := cageID, response.Data.CageID; want != got {
+				t.Fatalf("Expected CageID %s got %s", want, got)
+			}
+		})
+	}
+}
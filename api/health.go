@@ -0,0 +1,95 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// HealthCheck is a named readiness check.
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// HealthResponse is a response of the health endpoints.
+type HealthResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+const (
+	healthStatusOK       = "ok"
+	healthStatusFailing  = "failing"
+	healthStatusDraining = "draining"
+)
+
+// Drain marks the server as shutting down
+// so that readiness checks start failing.
+func (s *Server) Drain() {
+	s.draining.Store(true)
+}
+
+// Liveness reports that the process is alive.
+// GET /healthz
+func (s *Server) Liveness() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeHealthResponse(w, r, s, http.StatusOK, HealthResponse{Status: healthStatusOK})
+	}
+}
+
+// Readiness reports whether the server is ready to accept traffic.
+// GET /readyz
+func (s *Server) Readiness() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.draining.Load() {
+			writeHealthResponse(w, r, s, http.StatusServiceUnavailable, HealthResponse{Status: healthStatusDraining})
+			return
+		}
+
+		timeout := s.ReadinessTimeout
+		if timeout <= 0 {
+			timeout = time.Second
+		}
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		code := http.StatusOK
+		response := HealthResponse{
+			Status: healthStatusOK,
+			Checks: make(map[string]string, len(s.ReadinessChecks)),
+		}
+		for _, check := range s.ReadinessChecks {
+			if err := check.Check(ctx); err != nil {
+				code = http.StatusServiceUnavailable
+				response.Status = healthStatusFailing
+				response.Checks[check.Name] = err.Error()
+				continue
+			}
+
+			response.Checks[check.Name] = healthStatusOK
+		}
+
+		writeHealthResponse(w, r, s, code, response)
+	}
+}
+
+func writeHealthResponse(w http.ResponseWriter, r *http.Request, s *Server, code int, health HealthResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+
+	response := struct {
+		Data HealthResponse `json:"data"`
+	}{
+		Data: health,
+	}
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		s.Logger.Error("Error marshalling response", "requestId", middleware.GetReqID(r.Context()), "error", err)
+		return
+	}
+}
@@ -0,0 +1,157 @@
+//go:build unit
+// +build unit
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestLiveness(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	svc := &Server{
+		Logger: logger,
+	}
+	svc.Drain() // Liveness should not be affected by draining.
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+
+	validated(t, svc.Liveness()).ServeHTTP(w, r)
+
+	if want, got := http.StatusOK, w.Code; want != got {
+		t.Fatalf("Expected %d got %d", want, got)
+	}
+}
+
+func TestReadiness(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	svc := &Server{
+		Logger: logger,
+		ReadinessChecks: []HealthCheck{
+			{Name: "foo", Check: func(context.Context) error { return nil }},
+		},
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+
+	validated(t, svc.Readiness()).ServeHTTP(w, r)
+
+	if want, got := http.StatusOK, w.Code; want != got {
+		t.Fatalf("Expected %d got %d", want, got)
+	}
+
+	response := struct {
+		Data HealthResponse `json:"data"`
+	}{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := "ok", response.Data.Status; want != got {
+		t.Fatalf("Expected status %s got %s", want, got)
+	}
+	if want, got := "ok", response.Data.Checks["foo"]; want != got {
+		t.Fatalf("Expected check status %s got %s", want, got)
+	}
+}
+
+func TestReadinessFailingCheck(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	svc := &Server{
+		Logger: logger,
+		ReadinessChecks: []HealthCheck{
+			{Name: "foo", Check: func(context.Context) error { return nil }},
+			{Name: "bar", Check: func(context.Context) error { return errors.New("something went wrong") }},
+		},
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+
+	validated(t, svc.Readiness()).ServeHTTP(w, r)
+
+	if want, got := http.StatusServiceUnavailable, w.Code; want != got {
+		t.Fatalf("Expected %d got %d", want, got)
+	}
+
+	response := struct {
+		Data HealthResponse `json:"data"`
+	}{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := "failing", response.Data.Status; want != got {
+		t.Fatalf("Expected status %s got %s", want, got)
+	}
+	if want, got := "something went wrong", response.Data.Checks["bar"]; want != got {
+		t.Fatalf("Expected check status %s got %s", want, got)
+	}
+}
+
+func TestReadinessTimeout(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	svc := &Server{
+		Logger: logger,
+		ReadinessChecks: []HealthCheck{
+			{Name: "slow", Check: func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			}},
+		},
+		ReadinessTimeout: 10 * time.Millisecond,
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+
+	validated(t, svc.Readiness()).ServeHTTP(w, r)
+
+	if want, got := http.StatusServiceUnavailable, w.Code; want != got {
+		t.Fatalf("Expected %d got %d", want, got)
+	}
+}
+
+func TestReadinessDraining(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	var checked bool
+	svc := &Server{
+		Logger: logger,
+		ReadinessChecks: []HealthCheck{
+			{Name: "foo", Check: func(context.Context) error {
+				checked = true
+				return nil
+			}},
+		},
+	}
+	svc.Drain()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+
+	validated(t, svc.Readiness()).ServeHTTP(w, r)
+
+	if want, got := http.StatusServiceUnavailable, w.Code; want != got {
+		t.Fatalf("Expected %d got %d", want, got)
+	}
+	if checked {
+		t.Fatal("Expected checks to be skipped while draining")
+	}
+}
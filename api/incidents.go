@@ -0,0 +1,290 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+// ListIncidents lists incidents, the most recently opened first.
+// GET /incidents[?cageId=...][&status=open|resolved][&severity=low|medium|high|critical]
+func (s *Server) ListIncidents() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+
+		filter, err := incidentFilter(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		list, err := s.IncidentStore.List(r.Context(), filter)
+		if err != nil {
+			logger.Error("Error getting incidents", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		if list == nil {
+			list = []app.Incident{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		response := struct {
+			Data []app.Incident `json:"data"`
+		}{
+			Data: list,
+		}
+
+		if err = json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			return
+		}
+	}
+}
+
+// OpenIncidentRequest is a request to open a new incident.
+type OpenIncidentRequest struct {
+	Type        app.IncidentType     `json:"type"`
+	Severity    app.IncidentSeverity `json:"severity"`
+	CageID      string               `json:"cageId"`
+	DinosaurIDs []string             `json:"dinosaurIds"`
+	Summary     string               `json:"summary"`
+}
+
+// Incident returns the requested incident.
+func (r OpenIncidentRequest) Incident() app.Incident {
+	return app.Incident{
+		Type:        r.Type,
+		Severity:    r.Severity,
+		CageID:      r.CageID,
+		DinosaurIDs: r.DinosaurIDs,
+		Summary:     r.Summary,
+	}
+}
+
+// OpenIncident opens a new incident in a cage.
+// An open critical containment incident locks the cage down until it's resolved.
+// POST /incidents
+func (s *Server) OpenIncident() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+
+		var req OpenIncidentRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.Error("Error decoding request body", "error", err)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		incident := req.Incident()
+		if err := incident.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		opened, err := s.IncidentStore.Open(r.Context(), &incident)
+		if err != nil {
+			if err == app.ErrNotFound {
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+				return
+			}
+
+			logger.Error("Error opening incident", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+
+		response := struct {
+			Data *app.Incident `json:"data"`
+		}{
+			Data: opened,
+		}
+
+		if err = json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			return
+		}
+	}
+}
+
+// GetIncident gets an incident by id along with its timeline.
+// GET /incidents/:id
+func (s *Server) GetIncident() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+		id := chi.URLParam(r, "id")
+
+		if err := app.ValidateID(id); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		incident, err := s.IncidentStore.Get(r.Context(), id)
+		if err != nil {
+			if err == app.ErrNotFound {
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+				return
+			}
+
+			logger.Error("Error getting incident", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		response := struct {
+			Data *app.Incident `json:"data"`
+		}{
+			Data: incident,
+		}
+
+		if err = json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			return
+		}
+	}
+}
+
+// AddIncidentEntryRequest is a request to add an entry to the timeline of an incident.
+type AddIncidentEntryRequest struct {
+	Note       string    `json:"note"`
+	OccurredAt time.Time `json:"occurredAt"`
+}
+
+// Entry returns the requested timeline entry.
+func (r AddIncidentEntryRequest) Entry() app.IncidentEntry {
+	return app.IncidentEntry{
+		Note:       r.Note,
+		OccurredAt: r.OccurredAt,
+	}
+}
+
+// AddIncidentEntry adds an entry to the timeline of an open incident.
+// POST /incidents/:id/timeline
+func (s *Server) AddIncidentEntry() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+		id := chi.URLParam(r, "id")
+
+		if err := app.ValidateID(id); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var req AddIncidentEntryRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.Error("Error decoding request body", "error", err)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		entry := req.Entry()
+		if err := entry.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		added, err := s.IncidentStore.AddEntry(r.Context(), id, &entry)
+		if err != nil {
+			switch err {
+			case app.ErrNotFound:
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			case app.ErrIncidentResolved:
+				http.Error(w, err.Error(), http.StatusConflict)
+			default:
+				logger.Error("Error adding incident entry", "error", err)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+
+		response := struct {
+			Data *app.IncidentEntry `json:"data"`
+		}{
+			Data: added,
+		}
+
+		if err = json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			return
+		}
+	}
+}
+
+// ResolveIncidentRequest is a request to resolve an incident.
+type ResolveIncidentRequest struct {
+	Resolution string `json:"resolution"`
+}
+
+// ResolveIncident resolves an open incident lifting the cage lockdown if any.
+// POST /incidents/:id/resolve
+func (s *Server) ResolveIncident() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+		id := chi.URLParam(r, "id")
+
+		if err := app.ValidateID(id); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var req ResolveIncidentRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.Error("Error decoding request body", "error", err)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		if req.Resolution == "" {
+			http.Error(w, "resolution is required", http.StatusBadRequest)
+			return
+		}
+
+		resolved, err := s.IncidentStore.Resolve(r.Context(), id, req.Resolution)
+		if err != nil {
+			switch err {
+			case app.ErrNotFound:
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			case app.ErrIncidentResolved:
+				http.Error(w, err.Error(), http.StatusConflict)
+			default:
+				logger.Error("Error resolving incident", "error", err)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		response := struct {
+			Data *app.Incident `json:"data"`
+		}{
+			Data: resolved,
+		}
+
+		if err = json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			return
+		}
+	}
+}
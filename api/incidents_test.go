@@ -0,0 +1,273 @@
+//go:build unit
+// +build unit
+
+package api
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+type fakeIncidentStore struct {
+	incident app.Incident
+	filter   app.IncidentFilter
+	id       string
+	err      error
+}
+
+func (s *fakeIncidentStore) Open(_ context.Context, incident *app.Incident) (*app.Incident, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	s.incident = *incident
+	s.incident.ID = uuid.NewString()
+	if s.incident.DinosaurIDs == nil {
+		s.incident.DinosaurIDs = []string{}
+	}
+	s.incident.Status = app.IncidentStatusOpen
+	s.incident.OpenedAt = time.Now()
+	s.incident.CreatedAt = s.incident.OpenedAt
+	s.incident.UpdatedAt = s.incident.OpenedAt
+	opened := s.incident
+
+	return &opened, nil
+}
+
+func (s *fakeIncidentStore) Get(_ context.Context, id string) (*app.Incident, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	s.id = id
+	incident := s.incident
+
+	return &incident, nil
+}
+
+func (s *fakeIncidentStore) List(_ context.Context, filter app.IncidentFilter) ([]app.Incident, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	s.filter = filter
+	if s.incident.ID == "" {
+		return nil, nil
+	}
+
+	return []app.Incident{s.incident}, nil
+}
+
+func (s *fakeIncidentStore) AddEntry(_ context.Context, id string, entry *app.IncidentEntry) (*app.IncidentEntry, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	s.id = id
+	added := *entry
+	added.ID = uuid.NewString()
+	added.IncidentID = id
+	added.CreatedAt = time.Now()
+	if added.OccurredAt.IsZero() {
+		added.OccurredAt = added.CreatedAt
+	}
+
+	return &added, nil
+}
+
+func (s *fakeIncidentStore) Resolve(_ context.Context, id, resolution string) (*app.Incident, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	s.id = id
+	now := time.Now()
+	resolved := s.incident
+	resolved.ID = id
+	resolved.Status = app.IncidentStatusResolved
+	resolved.Resolution = resolution
+	resolved.ResolvedAt = &now
+
+	return &resolved, nil
+}
+
+func TestOpenIncident(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	cageID, dinosaurID := uuid.NewString(), uuid.NewString()
+	escape := `{"type": "escape", "severity": "critical", "cageId": "` + cageID + `", "dinosaurIds": ["` + dinosaurID + `"], "summary": "Rexy is out"}`
+	tests := []struct {
+		name   string
+		body   string
+		err    error
+		status int
+	}{
+		{"escape", escape, nil, http.StatusCreated},
+		{"no dinosaurs", `{"type": "fence-failure", "severity": "high", "cageId": "` + cageID + `", "summary": "Fence flickering"}`, nil, http.StatusCreated},
+		{"invalid type", `{"type": "stampede", "severity": "high", "cageId": "` + cageID + `", "summary": "Run"}`, nil, http.StatusBadRequest},
+		{"invalid severity", `{"type": "escape", "severity": "dire", "cageId": "` + cageID + `", "summary": "Run"}`, nil, http.StatusBadRequest},
+		{"no summary", `{"type": "escape", "severity": "critical", "cageId": "` + cageID + `"}`, nil, http.StatusBadRequest},
+		{"duplicate dinosaurs", `{"type": "injury", "severity": "low", "cageId": "` + cageID + `", "dinosaurIds": ["` + dinosaurID + `", "` + dinosaurID + `"], "summary": "Scratch"}`, nil, http.StatusBadRequest},
+		{"invalid body", `{"type": `, nil, http.StatusBadRequest},
+		{"not found", escape, app.ErrNotFound, http.StatusNotFound},
+		{"store error", escape, errors.New("store error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &Server{
+				Logger:        logger,
+				IncidentStore: &fakeIncidentStore{err: tt.err},
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/incidents", strings.NewReader(tt.body))
+
+			validated(t, svc.OpenIncident()).ServeHTTP(w, r)
+
+			if want, got := tt.status, w.Code; want != got {
+				t.Fatalf("Expected %d got %d", want, got)
+			}
+		})
+	}
+}
+
+func TestListIncidents(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	cageID := uuid.NewString()
+	tests := []struct {
+		name   string
+		query  string
+		filter app.IncidentFilter
+		status int
+	}{
+		{"no filter", "", app.IncidentFilter{}, http.StatusOK},
+		{"open in cage", "?cageId=" + cageID + "&status=open", app.IncidentFilter{CageID: cageID, Status: app.IncidentStatusOpen}, http.StatusOK},
+		{"critical", "?severity=critical", app.IncidentFilter{Severity: app.IncidentSeverityCritical}, http.StatusOK},
+		{"invalid cage", "?cageId=foo", app.IncidentFilter{}, http.StatusBadRequest},
+		{"invalid status", "?status=closed", app.IncidentFilter{}, http.StatusBadRequest},
+		{"invalid severity", "?severity=dire", app.IncidentFilter{}, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeIncidentStore{}
+			svc := &Server{
+				Logger:        logger,
+				IncidentStore: store,
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/incidents"+tt.query, nil)
+
+			validated(t, svc.ListIncidents()).ServeHTTP(w, r)
+
+			if want, got := tt.status, w.Code; want != got {
+				t.Fatalf("Expected %d got %d", want, got)
+			}
+			if want, got := tt.filter, store.filter; want != got {
+				t.Fatalf("Expected filter %+v got %+v", want, got)
+			}
+		})
+	}
+}
+
+func TestAddIncidentEntry(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	id := uuid.NewString()
+	tests := []struct {
+		name   string
+		id     string
+		body   string
+		err    error
+		status int
+	}{
+		{"now", id, `{"note": "Rexy recaptured"}`, nil, http.StatusCreated},
+		{"occurred at", id, `{"note": "Fence down", "occurredAt": "2023-01-15T08:00:00Z"}`, nil, http.StatusCreated},
+		{"no note", id, `{"occurredAt": "2023-01-15T08:00:00Z"}`, nil, http.StatusBadRequest},
+		{"invalid id", "foo", `{"note": "Rexy recaptured"}`, nil, http.StatusBadRequest},
+		{"invalid body", id, `{"note": `, nil, http.StatusBadRequest},
+		{"not found", id, `{"note": "Rexy recaptured"}`, app.ErrNotFound, http.StatusNotFound},
+		{"resolved", id, `{"note": "Rexy recaptured"}`, app.ErrIncidentResolved, http.StatusConflict},
+		{"store error", id, `{"note": "Rexy recaptured"}`, errors.New("store error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &Server{
+				Logger:        logger,
+				IncidentStore: &fakeIncidentStore{err: tt.err},
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/incidents/"+tt.id+"/timeline", strings.NewReader(tt.body))
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.id)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			validated(t, svc.AddIncidentEntry()).ServeHTTP(w, r)
+
+			if want, got := tt.status, w.Code; want != got {
+				t.Fatalf("Expected %d got %d", want, got)
+			}
+		})
+	}
+}
+
+func TestResolveIncident(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	id := uuid.NewString()
+	tests := []struct {
+		name   string
+		id     string
+		body   string
+		err    error
+		status int
+	}{
+		{"resolved", id, `{"resolution": "Fence repaired"}`, nil, http.StatusOK},
+		{"no resolution", id, `{}`, nil, http.StatusBadRequest},
+		{"invalid id", "foo", `{"resolution": "Fence repaired"}`, nil, http.StatusBadRequest},
+		{"not found", id, `{"resolution": "Fence repaired"}`, app.ErrNotFound, http.StatusNotFound},
+		{"resolved already", id, `{"resolution": "Fence repaired"}`, app.ErrIncidentResolved, http.StatusConflict},
+		{"store error", id, `{"resolution": "Fence repaired"}`, errors.New("store error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &Server{
+				Logger: logger,
+				IncidentStore: &fakeIncidentStore{
+					incident: app.Incident{Type: app.IncidentTypeEscape, Severity: app.IncidentSeverityCritical, CageID: uuid.NewString(), DinosaurIDs: []string{}, Summary: "Rexy is out"},
+					err:      tt.err,
+				},
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/incidents/"+tt.id+"/resolve", strings.NewReader(tt.body))
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.id)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			validated(t, svc.ResolveIncident()).ServeHTTP(w, r)
+
+			if want, got := tt.status, w.Code; want != got {
+				t.Fatalf("Expected %d got %d", want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,493 @@
+package api
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed spec.yaml
+var spec []byte
+
+// Spec returns the OpenAPI specification of the API as YAML.
+func Spec() []byte {
+	return spec
+}
+
+// OpenAPI is the subset of an OpenAPI 3 document
+// needed to validate requests and responses.
+type OpenAPI struct {
+	Paths      map[string]map[string]*Operation `yaml:"paths"`
+	Components struct {
+		Schemas map[string]*Schema `yaml:"schemas"`
+	} `yaml:"components"`
+
+	templates []pathTemplate
+}
+
+// Operation is an API operation.
+type Operation struct {
+	Parameters  []Parameter          `yaml:"parameters"`
+	RequestBody *RequestBody         `yaml:"requestBody"`
+	Responses   map[string]*Response `yaml:"responses"`
+}
+
+// Parameter is a path or query parameter.
+type Parameter struct {
+	Name     string  `yaml:"name"`
+	In       string  `yaml:"in"`
+	Required bool    `yaml:"required"`
+	Schema   *Schema `yaml:"schema"`
+}
+
+// RequestBody is a request body.
+type RequestBody struct {
+	Required bool                  `yaml:"required"`
+	Content  map[string]*MediaType `yaml:"content"`
+}
+
+// Response is a response of an operation.
+type Response struct {
+	Content map[string]*MediaType `yaml:"content"`
+}
+
+// MediaType is the schema of a content type.
+type MediaType struct {
+	Schema *Schema `yaml:"schema"`
+}
+
+// Schema is a JSON schema.
+type Schema struct {
+	Ref                  string             `yaml:"$ref"`
+	Type                 string             `yaml:"type"`
+	Format               string             `yaml:"format"`
+	Enum                 []any              `yaml:"enum"`
+	Nullable             bool               `yaml:"nullable"`
+	Minimum              *float64           `yaml:"minimum"`
+	Maximum              *float64           `yaml:"maximum"`
+	MinLength            *int               `yaml:"minLength"`
+	MaxLength            *int               `yaml:"maxLength"`
+	MinItems             *int               `yaml:"minItems"`
+	Properties           map[string]*Schema `yaml:"properties"`
+	Required             []string           `yaml:"required"`
+	AdditionalProperties *Schema            `yaml:"additionalProperties"`
+	Items                *Schema            `yaml:"items"`
+}
+
+type pathTemplate struct {
+	path     string
+	segments []string
+	literals int
+}
+
+// LoadOpenAPI parses the embedded OpenAPI specification.
+func LoadOpenAPI() (*OpenAPI, error) {
+	return ParseOpenAPI(spec)
+}
+
+// ParseOpenAPI parses an OpenAPI specification.
+func ParseOpenAPI(b []byte) (*OpenAPI, error) {
+	var o OpenAPI
+	if err := yaml.Unmarshal(b, &o); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI spec: %w", err)
+	}
+
+	for path := range o.Paths {
+		t := pathTemplate{path: path, segments: strings.Split(strings.Trim(path, "/"), "/")}
+		for _, s := range t.segments {
+			if !isPathParam(s) {
+				t.literals++
+			}
+		}
+		o.templates = append(o.templates, t)
+	}
+	// Prefer the most specific template, e.g. /cages/restore over /cages/{id}.
+	sort.Slice(o.templates, func(i, j int) bool {
+		if o.templates[i].literals != o.templates[j].literals {
+			return o.templates[i].literals > o.templates[j].literals
+		}
+		return o.templates[i].path < o.templates[j].path
+	})
+
+	return &o, nil
+}
+
+// Operation finds the operation for the method and the request path
+// without the base URI. It returns the path parameters.
+func (o *OpenAPI) Operation(method, path string) (*Operation, map[string]string, bool) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for _, t := range o.templates {
+		params, ok := t.match(segments)
+		if !ok {
+			continue
+		}
+
+		op, ok := o.Paths[t.path][strings.ToLower(method)]
+		if !ok {
+			return nil, nil, false
+		}
+
+		return op, params, true
+	}
+
+	return nil, nil, false
+}
+
+func (t pathTemplate) match(segments []string) (map[string]string, bool) {
+	if len(segments) != len(t.segments) {
+		return nil, false
+	}
+
+	params := make(map[string]string)
+	for i, s := range t.segments {
+		if isPathParam(s) {
+			params[strings.Trim(s, "{}")] = segments[i]
+			continue
+		}
+		if s != segments[i] {
+			return nil, false
+		}
+	}
+
+	return params, true
+}
+
+func isPathParam(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+// ValidateRequest validates the parameters and the body of a request
+// against the operation. The body is the already read request body.
+func (o *OpenAPI) ValidateRequest(op *Operation, pathParams map[string]string, r *http.Request, body []byte) error {
+	query := r.URL.Query()
+	for _, p := range op.Parameters {
+		var values []string
+		switch p.In {
+		case "path":
+			if v, ok := pathParams[p.Name]; ok {
+				values = []string{v}
+			}
+		case "query":
+			values = query[p.Name]
+		default:
+			continue
+		}
+
+		if len(values) == 0 || (len(values) == 1 && values[0] == "") {
+			if p.Required {
+				return fmt.Errorf("%s parameter %s is required", p.In, p.Name)
+			}
+			continue
+		}
+
+		if err := o.validateParameter(p.Schema, values); err != nil {
+			return fmt.Errorf("%s parameter %s: %w", p.In, p.Name, err)
+		}
+	}
+
+	if op.RequestBody == nil {
+		return nil
+	}
+
+	if len(body) == 0 {
+		if op.RequestBody.Required {
+			return errors.New("request body is required")
+		}
+		return nil
+	}
+
+	media, err := mediaType(op.RequestBody.Content, r.Header.Get("Content-Type"))
+	if err != nil {
+		return err
+	}
+	if media.Schema == nil {
+		return nil
+	}
+
+	return o.validateJSON(media.Schema, body, "request body")
+}
+
+// ValidateResponse validates a response of the operation.
+func (o *OpenAPI) ValidateResponse(op *Operation, status int, contentType string, body []byte) error {
+	resp, ok := op.Responses[strconv.Itoa(status)]
+	if !ok {
+		resp, ok = op.Responses[strconv.Itoa(status/100)+"XX"]
+	}
+	if !ok {
+		resp, ok = op.Responses["default"]
+	}
+	if !ok {
+		return fmt.Errorf("undocumented response status %d", status)
+	}
+
+	if len(resp.Content) == 0 {
+		// Plain text error messages are not part of the spec.
+		if len(body) > 0 && isJSON(contentType) {
+			return fmt.Errorf("undocumented response body for status %d", status)
+		}
+		return nil
+	}
+
+	if len(body) == 0 {
+		return fmt.Errorf("missing response body for status %d", status)
+	}
+
+	media, err := mediaType(resp.Content, contentType)
+	if err != nil {
+		return err
+	}
+	if media.Schema == nil {
+		return nil
+	}
+
+	return o.validateJSON(media.Schema, body, "response body")
+}
+
+func mediaType(content map[string]*MediaType, contentType string) (*MediaType, error) {
+	mediaType := strings.TrimSpace(strings.Split(contentType, ";")[0])
+	if media, ok := content[mediaType]; ok {
+		return media, nil
+	}
+
+	return nil, fmt.Errorf("unsupported content type %q", contentType)
+}
+
+func isJSON(contentType string) bool {
+	mediaType := strings.TrimSpace(strings.Split(contentType, ";")[0])
+	return mediaType == jsonContentType || strings.HasSuffix(mediaType, "+json")
+}
+
+func (o *OpenAPI) validateJSON(schema *Schema, body []byte, name string) error {
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return fmt.Errorf("%s is not valid JSON", name)
+	}
+
+	if err := o.validate(schema, v, ""); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	return nil
+}
+
+// validateParameter validates parameter values converting them
+// to the type of the schema first.
+func (o *OpenAPI) validateParameter(schema *Schema, values []string) error {
+	schema, err := o.resolve(schema)
+	if err != nil {
+		return err
+	}
+	if schema == nil {
+		return nil
+	}
+
+	if schema.Type == "array" {
+		items := make([]any, 0, len(values))
+		for _, s := range values {
+			for _, item := range strings.Split(s, ",") {
+				v, err := o.parameterValue(schema.Items, item)
+				if err != nil {
+					return err
+				}
+				items = append(items, v)
+			}
+		}
+
+		return o.validate(schema, items, "")
+	}
+
+	if len(values) > 1 {
+		return errors.New("must not be repeated")
+	}
+
+	v, err := o.parameterValue(schema, values[0])
+	if err != nil {
+		return err
+	}
+
+	return o.validate(schema, v, "")
+}
+
+func (o *OpenAPI) parameterValue(schema *Schema, s string) (any, error) {
+	schema, err := o.resolve(schema)
+	if err != nil || schema == nil {
+		return s, err
+	}
+
+	switch schema.Type {
+	case "integer", "number":
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("must be a %s", schema.Type)
+		}
+		return f, nil
+	case "boolean":
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, errors.New("must be a boolean")
+		}
+		return b, nil
+	default:
+		return s, nil
+	}
+}
+
+func (o *OpenAPI) resolve(schema *Schema) (*Schema, error) {
+	for schema != nil && schema.Ref != "" {
+		name := strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+		resolved, ok := o.Components.Schemas[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown schema reference %q", schema.Ref)
+		}
+		schema = resolved
+	}
+
+	return schema, nil
+}
+
+// validate validates a decoded JSON value against the schema.
+// The path is the location of the value used in the error messages.
+func (o *OpenAPI) validate(schema *Schema, v any, path string) error {
+	schema, err := o.resolve(schema)
+	if err != nil {
+		return err
+	}
+	if schema == nil {
+		return nil
+	}
+
+	fail := func(format string, args ...any) error {
+		if path == "" {
+			return fmt.Errorf(format, args...)
+		}
+		return fmt.Errorf("%s: %s", path, fmt.Sprintf(format, args...))
+	}
+
+	if v == nil {
+		if schema.Nullable || schema.Type == "" {
+			return nil
+		}
+		return fail("must not be null")
+	}
+
+	if len(schema.Enum) > 0 && !enumContains(schema.Enum, v) {
+		return fail("must be one of %v", schema.Enum)
+	}
+
+	switch schema.Type {
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return fail("must be an object")
+		}
+		for _, name := range schema.Required {
+			if _, ok := obj[name]; !ok {
+				return fail("%s is required", name)
+			}
+		}
+
+		names := make([]string, 0, len(obj))
+		for name := range obj {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			prop, ok := schema.Properties[name]
+			if !ok {
+				prop = schema.AdditionalProperties
+			}
+			if err := o.validate(prop, obj[name], joinPath(path, name)); err != nil {
+				return err
+			}
+		}
+	case "array":
+		items, ok := v.([]any)
+		if !ok {
+			return fail("must be an array")
+		}
+		if schema.MinItems != nil && len(items) < *schema.MinItems {
+			return fail("must have at least %d items", *schema.MinItems)
+		}
+		for i, item := range items {
+			if err := o.validate(schema.Items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case "string":
+		s, ok := v.(string)
+		if !ok {
+			return fail("must be a string")
+		}
+		if schema.MinLength != nil && len([]rune(s)) < *schema.MinLength {
+			return fail("must be at least %d characters long", *schema.MinLength)
+		}
+		if schema.MaxLength != nil && len([]rune(s)) > *schema.MaxLength {
+			return fail("must be at most %d characters long", *schema.MaxLength)
+		}
+		switch schema.Format {
+		case "uuid":
+			if _, err := uuid.Parse(s); err != nil || len(s) != 36 {
+				return fail("must be a UUID")
+			}
+		case "date-time":
+			if _, err := time.Parse(time.RFC3339, s); err != nil {
+				return fail("must be an RFC 3339 date-time")
+			}
+		}
+	case "integer", "number":
+		f, ok := v.(float64)
+		if !ok {
+			return fail("must be a %s", schema.Type)
+		}
+		if schema.Type == "integer" && f != float64(int64(f)) {
+			return fail("must be an integer")
+		}
+		if schema.Minimum != nil && f < *schema.Minimum {
+			return fail("must be >= %v", *schema.Minimum)
+		}
+		if schema.Maximum != nil && f > *schema.Maximum {
+			return fail("must be <= %v", *schema.Maximum)
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return fail("must be a boolean")
+		}
+	}
+
+	return nil
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
+
+func enumContains(enum []any, v any) bool {
+	for _, e := range enum {
+		switch e := e.(type) {
+		case int:
+			if f, ok := v.(float64); ok && f == float64(e) {
+				return true
+			}
+		default:
+			if e == v {
+				return true
+			}
+		}
+	}
+
+	return false
+}
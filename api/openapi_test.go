@@ -0,0 +1,174 @@
+//go:build unit
+// +build unit
+
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+var testSpec = func() *OpenAPI {
+	spec, err := LoadOpenAPI()
+	if err != nil {
+		panic(err)
+	}
+
+	return spec
+}()
+
+// validated wraps a handler to check its responses against the OpenAPI spec.
+func validated(t *testing.T, h http.Handler) http.Handler {
+	t.Helper()
+
+	return ValidateResponses(testSpec, "", func(r *http.Request, err error) {
+		t.Errorf("%s %s: response doesn't match the spec: %s", r.Method, r.URL.Path, err)
+	})(h)
+}
+
+func TestRoutesMatchSpec(t *testing.T) {
+	svc := &Server{}
+	rtr := chi.NewRouter()
+	svc.Routes(rtr, "", nil)
+
+	routes := 0
+	err := chi.Walk(rtr, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		routes++
+		if _, ok := testSpec.Paths[route][strings.ToLower(method)]; !ok {
+			t.Errorf("%s %s is not in the spec", method, route)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	operations := 0
+	for path, ops := range testSpec.Paths {
+		if path == "/healthz" || path == "/readyz" || path == "/openapi.yaml" {
+			continue
+		}
+		operations += len(ops)
+	}
+	if want, got := operations, routes; want != got {
+		t.Errorf("Expected %d routes got %d", want, got)
+	}
+}
+
+func TestValidateRequests(t *testing.T) {
+	id := uuid.NewString()
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"valid", http.MethodPost, "/api/cages", `{"capacity": 10, "status": "active"}`, http.StatusOK},
+		{"missing body", http.MethodPost, "/api/cages", ``, http.StatusBadRequest},
+		{"invalid JSON", http.MethodPost, "/api/cages", `{`, http.StatusBadRequest},
+		{"missing property", http.MethodPost, "/api/cages", `{"capacity": 10}`, http.StatusBadRequest},
+		{"invalid enum", http.MethodPost, "/api/cages", `{"capacity": 10, "status": "foo"}`, http.StatusBadRequest},
+		{"out of range", http.MethodPost, "/api/cages", `{"capacity": 1000, "status": "active"}`, http.StatusBadRequest},
+		{"not an integer", http.MethodPost, "/api/cages", `{"capacity": 1.5, "status": "active"}`, http.StatusBadRequest},
+		{"wrong type", http.MethodPost, "/api/cages", `{"capacity": "10", "status": "active"}`, http.StatusBadRequest},
+		{"valid query", http.MethodGet, "/api/cages?status=down", ``, http.StatusOK},
+		{"invalid query", http.MethodGet, "/api/cages?status=foo", ``, http.StatusBadRequest},
+		{"valid array query", http.MethodGet, "/api/dinosaurs?species=triceratops,stegosaurus&sort=-createdAt,name", ``, http.StatusOK},
+		{"invalid array item", http.MethodGet, "/api/dinosaurs?species=triceratops,foo", ``, http.StatusBadRequest},
+		{"invalid sort field", http.MethodGet, "/api/cages?sort=-name", ``, http.StatusBadRequest},
+		{"invalid date-time", http.MethodGet, "/api/cages?createdAfter=yesterday", ``, http.StatusBadRequest},
+		{"valid path", http.MethodGet, "/api/cages/" + id, ``, http.StatusOK},
+		{"invalid path", http.MethodGet, "/api/cages/foo", ``, http.StatusBadRequest},
+		{"empty name", http.MethodPost, "/api/cages/" + id + "/dinosaurs", `{"name": "", "species": "triceratops"}`, http.StatusBadRequest},
+		{"invalid uuid", http.MethodPut, "/api/dinosaurs/" + id, `{"cageId": "foo"}`, http.StatusBadRequest},
+		{"not in spec", http.MethodGet, "/api/foo", ``, http.StatusOK},
+	}
+
+	h := ValidateRequests(testSpec, "/api")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", "application/json")
+
+			h.ServeHTTP(w, r)
+
+			if want, got := tt.status, w.Code; want != got {
+				t.Fatalf("Expected %d got %d: %s", want, got, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestValidateResponses(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		body        string
+		valid       bool
+	}{
+		{"valid", http.StatusOK, "application/json", `{"data": []}`, true},
+		{"plain text error", http.StatusBadRequest, "text/plain", "invalid status", true},
+		{"undocumented status", http.StatusTeapot, "text/plain", "", false},
+		{"missing body", http.StatusOK, "application/json", ``, false},
+		{"missing data", http.StatusOK, "application/json", `{}`, false},
+		{"invalid item", http.StatusOK, "application/json", `{"data": [{"id": "foo"}]}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reported error
+			h := ValidateResponses(testSpec, "", func(r *http.Request, err error) {
+				reported = err
+			})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body)) // nolint:errcheck
+			}))
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/cages", nil))
+
+			if want, got := tt.valid, reported == nil; want != got {
+				t.Fatalf("Expected valid %t got %v", want, reported)
+			}
+			if want, got := tt.body, w.Body.String(); want != got {
+				t.Fatalf("Expected body %q got %q", want, got)
+			}
+		})
+	}
+}
+
+func TestServeSpec(t *testing.T) {
+	svc := &Server{Logger: slog.New(slog.NewTextHandler(os.Stderr, nil))}
+
+	w := httptest.NewRecorder()
+	validated(t, svc.ServeSpec()).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.yaml", nil))
+
+	if want, got := http.StatusOK, w.Code; want != got {
+		t.Fatalf("Expected %d got %d", want, got)
+	}
+	if _, err := ParseOpenAPI(w.Body.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+
+	w = httptest.NewRecorder()
+	svc.ServeDocs("/openapi.yaml").ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+
+	if want, got := http.StatusOK, w.Code; want != got {
+		t.Fatalf("Expected %d got %d", want, got)
+	}
+	if !strings.Contains(w.Body.String(), `url: "/openapi.yaml"`) {
+		t.Fatalf("Expected the docs to load the spec got %s", w.Body.String())
+	}
+}
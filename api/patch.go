@@ -0,0 +1,56 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+const mergePatchContentType = "application/merge-patch+json"
+
+// mergePatchRemover is implemented by the patches with optional fields.
+type mergePatchRemover interface {
+	// remove marks the field as removed and returns false if it can't be removed.
+	remove(name string) bool
+}
+
+// decodeMergePatch decodes a JSON merge patch (RFC 7386) into v.
+// Only the optional fields, if v implements mergePatchRemover, can be
+// removed with null, removing the required ones is rejected as are unknown fields.
+func decodeMergePatch(r io.Reader, v any) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return fmt.Errorf("patch must be a JSON object")
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	remover, _ := v.(mergePatchRemover)
+	for _, name := range names {
+		if !bytes.Equal(bytes.TrimSpace(fields[name]), []byte("null")) {
+			continue
+		}
+		if remover == nil || !remover.remove(name) {
+			return fmt.Errorf("%s can't be removed", name)
+		}
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid patch: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,449 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+// includeDeleted parses the includeDeleted query parameter.
+func includeDeleted(r *http.Request) (bool, error) {
+	value := r.URL.Query().Get("includeDeleted")
+	if value == "" {
+		return false, nil
+	}
+
+	include, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errors.New("invalid includeDeleted")
+	}
+
+	return include, nil
+}
+
+// getOptions parses the query parameters of a single resource request.
+// The fields are limited to the allowed ones.
+func getOptions(r *http.Request, allowed []string) (app.GetOptions, error) {
+	var (
+		opts app.GetOptions
+		err  error
+	)
+	if opts.IncludeDeleted, err = includeDeleted(r); err != nil {
+		return opts, err
+	}
+	if opts.Fields, err = app.ParseFields(r.URL.Query().Get("fields"), allowed); err != nil {
+		return opts, err
+	}
+
+	return opts, nil
+}
+
+// cageFilter parses the cage list query parameters.
+// Sorting is limited to app.CageFields, e.g. sort=-occupancy,createdAt.
+func cageFilter(r *http.Request) (app.CageFilter, error) {
+	var (
+		filter app.CageFilter
+		err    error
+	)
+	query := r.URL.Query()
+
+	if filter.IDs, err = ids(query, "id"); err != nil {
+		return filter, err
+	}
+
+	filter.Type = app.CageType(query.Get("type"))
+	if filter.Type != app.CageTypeUnspecified {
+		if err := filter.Type.Validate(); err != nil {
+			return filter, err
+		}
+	}
+
+	filter.Status = app.CageStatus(query.Get("status"))
+	if !filter.Status.IsUnspecified() {
+		if err := filter.Status.Validate(); err != nil {
+			return filter, err
+		}
+	}
+
+	if filter.Quarantine, err = boolean(query, "quarantine"); err != nil {
+		return filter, err
+	}
+
+	if filter.ZoneID, err = id(query, "zoneId"); err != nil {
+		return filter, err
+	}
+	if filter.SectorID, err = id(query, "sectorId"); err != nil {
+		return filter, err
+	}
+
+	if value := query.Get("minFreeCapacity"); value != "" {
+		filter.MinFreeCapacity, err = strconv.Atoi(value)
+		if err != nil || filter.MinFreeCapacity < 1 {
+			return filter, errors.New("invalid minFreeCapacity")
+		}
+	}
+
+	filter.Occupancy = app.CageOccupancy(query.Get("occupancy"))
+	if filter.Occupancy != app.CageOccupancyUnspecified {
+		if err := filter.Occupancy.Validate(); err != nil {
+			return filter, err
+		}
+	}
+
+	if filter.Created, err = timeRange(query, "created"); err != nil {
+		return filter, err
+	}
+	if filter.Updated, err = timeRange(query, "updated"); err != nil {
+		return filter, err
+	}
+
+	if filter.Sort, err = app.ParseSort(query.Get("sort"), app.CageFields); err != nil {
+		return filter, err
+	}
+
+	if filter.Fields, err = app.ParseFields(query.Get("fields"), app.CageFields); err != nil {
+		return filter, err
+	}
+
+	if filter.IncludeDeleted, err = includeDeleted(r); err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
+
+// dinosaurFilter parses the dinosaur list query parameters.
+// Species can be passed as a comma separated list or repeated.
+// Sorting is limited to app.DinosaurFields, e.g. sort=species,-createdAt.
+func dinosaurFilter(r *http.Request) (app.DinosaurFilter, error) {
+	var (
+		filter app.DinosaurFilter
+		err    error
+	)
+	query := r.URL.Query()
+
+	if filter.CageIDs, err = ids(query, "cageId"); err != nil {
+		return filter, err
+	}
+
+	if filter.ZoneID, err = id(query, "zoneId"); err != nil {
+		return filter, err
+	}
+
+	for _, value := range query["species"] {
+		for _, s := range strings.Split(value, ",") {
+			species := app.DinosaurSpecies(s)
+			if err := species.Validate(); err != nil {
+				return filter, err
+			}
+			filter.Species = append(filter.Species, species)
+		}
+	}
+
+	if value := query.Get("diet"); value != "" {
+		filter.Diet = app.DinosaurType(value)
+		if err := filter.Diet.Validate(); err != nil {
+			return filter, err
+		}
+	}
+
+	for _, value := range query["stage"] {
+		for _, s := range strings.Split(value, ",") {
+			stage := app.LifecycleStage(s)
+			if err := stage.Validate(); err != nil {
+				return filter, err
+			}
+			filter.Stages = append(filter.Stages, stage)
+		}
+	}
+
+	if value := query.Get("healthStatus"); value != "" {
+		filter.HealthStatus = app.HealthStatus(value)
+		if err := filter.HealthStatus.Validate(); err != nil {
+			return filter, err
+		}
+	}
+
+	if filter.Quarantined, err = boolean(query, "quarantined"); err != nil {
+		return filter, err
+	}
+
+	filter.NamePrefix = query.Get("namePrefix")
+
+	if filter.HatchDate, err = timeRange(query, "hatch"); err != nil {
+		return filter, err
+	}
+	if filter.Created, err = timeRange(query, "created"); err != nil {
+		return filter, err
+	}
+	if filter.Updated, err = timeRange(query, "updated"); err != nil {
+		return filter, err
+	}
+
+	if filter.Sort, err = app.ParseSort(query.Get("sort"), app.DinosaurFields); err != nil {
+		return filter, err
+	}
+
+	if filter.Fields, err = app.ParseFields(query.Get("fields"), app.DinosaurFields); err != nil {
+		return filter, err
+	}
+
+	if filter.IncludeDeleted, err = includeDeleted(r); err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
+
+// feedingScheduleFilter parses the feeding schedule list query parameters.
+func feedingScheduleFilter(r *http.Request) (app.FeedingScheduleFilter, error) {
+	var (
+		filter app.FeedingScheduleFilter
+		err    error
+	)
+	query := r.URL.Query()
+
+	if filter.CageID, err = id(query, "cageId"); err != nil {
+		return filter, err
+	}
+
+	filter.Species = app.DinosaurSpecies(query.Get("species"))
+	if !filter.Species.IsUnspecified() {
+		if err := filter.Species.Validate(); err != nil {
+			return filter, err
+		}
+	}
+
+	return filter, nil
+}
+
+// feedingFilter parses the feeding list query parameters.
+func feedingFilter(r *http.Request) (app.FeedingFilter, error) {
+	var (
+		filter app.FeedingFilter
+		err    error
+	)
+	query := r.URL.Query()
+
+	if filter.CageID, err = id(query, "cageId"); err != nil {
+		return filter, err
+	}
+	if filter.DinosaurID, err = id(query, "dinosaurId"); err != nil {
+		return filter, err
+	}
+
+	if value := query.Get("unsafe"); value != "" {
+		if filter.UnsafeOnly, err = strconv.ParseBool(value); err != nil {
+			return filter, errors.New("invalid unsafe")
+		}
+	}
+
+	if filter.Fed, err = timeRange(query, "fed"); err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
+
+// breedingPairFilter parses the breeding pair list query parameters.
+func breedingPairFilter(r *http.Request) (app.BreedingPairFilter, error) {
+	var (
+		filter app.BreedingPairFilter
+		err    error
+	)
+	query := r.URL.Query()
+
+	if filter.DinosaurID, err = id(query, "dinosaurId"); err != nil {
+		return filter, err
+	}
+
+	if value := query.Get("flagged"); value != "" {
+		if filter.FlaggedOnly, err = strconv.ParseBool(value); err != nil {
+			return filter, errors.New("invalid flagged")
+		}
+	}
+
+	return filter, nil
+}
+
+// staffFilter parses the staff list query parameters.
+func staffFilter(r *http.Request) (app.StaffFilter, error) {
+	var filter app.StaffFilter
+
+	filter.Role = app.StaffRole(r.URL.Query().Get("role"))
+	if !filter.Role.IsUnspecified() {
+		if err := filter.Role.Validate(); err != nil {
+			return filter, err
+		}
+	}
+
+	return filter, nil
+}
+
+// assignmentFilter parses the assignment list query parameters.
+func assignmentFilter(r *http.Request) (app.AssignmentFilter, error) {
+	var (
+		filter app.AssignmentFilter
+		err    error
+	)
+	query := r.URL.Query()
+
+	if filter.StaffID, err = id(query, "staffId"); err != nil {
+		return filter, err
+	}
+	if filter.CageID, err = id(query, "cageId"); err != nil {
+		return filter, err
+	}
+
+	if filter.At, err = timestamp(query, "at"); err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
+
+// incidentFilter parses the incident list query parameters.
+func incidentFilter(r *http.Request) (app.IncidentFilter, error) {
+	var (
+		filter app.IncidentFilter
+		err    error
+	)
+	query := r.URL.Query()
+
+	if filter.CageID, err = id(query, "cageId"); err != nil {
+		return filter, err
+	}
+
+	filter.Status = app.IncidentStatus(query.Get("status"))
+	if !filter.Status.IsUnspecified() {
+		if err := filter.Status.Validate(); err != nil {
+			return filter, err
+		}
+	}
+
+	filter.Severity = app.IncidentSeverity(query.Get("severity"))
+	if !filter.Severity.IsUnspecified() {
+		if err := filter.Severity.Validate(); err != nil {
+			return filter, err
+		}
+	}
+
+	return filter, nil
+}
+
+// lineageGenerations parses the number of generations of a lineage.
+func lineageGenerations(r *http.Request) (int, error) {
+	value := r.URL.Query().Get("generations")
+	if value == "" {
+		return app.DefaultLineageGenerations, nil
+	}
+
+	generations, err := strconv.Atoi(value)
+	if err != nil || generations < 1 || generations > app.MaxLineageGenerations {
+		return 0, fmt.Errorf("generations must be between 1 and %d", app.MaxLineageGenerations)
+	}
+
+	return generations, nil
+}
+
+// healthRecordFilter parses the health record list query parameters.
+func healthRecordFilter(r *http.Request) (app.HealthRecordFilter, error) {
+	var (
+		filter app.HealthRecordFilter
+		err    error
+	)
+	query := r.URL.Query()
+
+	if value := query.Get("kind"); value != "" {
+		filter.Kind = app.HealthRecordKind(value)
+		if err := filter.Kind.Validate(); err != nil {
+			return filter, err
+		}
+	}
+
+	if filter.Recorded, err = timeRange(query, "recorded"); err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
+
+// id parses an optional id.
+func id(query url.Values, name string) (string, error) {
+	value := query.Get(name)
+	if value == "" {
+		return "", nil
+	}
+
+	if err := app.ValidateID(value); err != nil {
+		return "", fmt.Errorf("invalid %s", name)
+	}
+
+	return value, nil
+}
+
+// ids parses a comma separated or repeated list of ids.
+func ids(query url.Values, name string) ([]string, error) {
+	var ids []string
+	for _, value := range query[name] {
+		for _, id := range strings.Split(value, ",") {
+			if err := app.ValidateID(id); err != nil {
+				return nil, fmt.Errorf("invalid %s", name)
+			}
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, nil
+}
+
+// boolean parses an optional boolean.
+func boolean(query url.Values, name string) (*bool, error) {
+	value := query.Get(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s", name)
+	}
+
+	return &b, nil
+}
+
+// timeRange parses the <prefix>After and <prefix>Before RFC 3339 query parameters.
+func timeRange(query url.Values, prefix string) (app.TimeRange, error) {
+	var (
+		tr  app.TimeRange
+		err error
+	)
+	if tr.After, err = timestamp(query, prefix+"After"); err != nil {
+		return tr, err
+	}
+	if tr.Before, err = timestamp(query, prefix+"Before"); err != nil {
+		return tr, err
+	}
+
+	return tr, nil
+}
+
+func timestamp(query url.Values, name string) (time.Time, error) {
+	value := query.Get(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s", name)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,196 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// RateLimiter is a rate limiting backend.
+//
+// Limits are token buckets of the given number of requests that refill
+// over the period. Allow registers a request for the key and reports
+// whether it's within the limit along with the time left until the bucket
+// is full again.
+type RateLimiter interface {
+	Allow(ctx context.Context, key string, requests int, period time.Duration) (bool, time.Duration, error)
+}
+
+// RateLimit is a number of requests allowed per period.
+type RateLimit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// DefaultRateLimitGroup is the route group with the limit
+// that applies to groups without their own limit.
+const DefaultRateLimitGroup = "default"
+
+// RateLimitPolicy holds the rate limits per route group
+// and can be replaced at runtime.
+type RateLimitPolicy struct {
+	limits atomic.Pointer[map[string]RateLimit]
+}
+
+// NewRateLimitPolicy creates a new rate limit policy.
+func NewRateLimitPolicy(limits map[string]RateLimit) *RateLimitPolicy {
+	p := &RateLimitPolicy{}
+	p.Set(limits)
+
+	return p
+}
+
+// Set replaces the rate limits.
+func (p *RateLimitPolicy) Set(limits map[string]RateLimit) {
+	copied := make(map[string]RateLimit, len(limits))
+	for group, limit := range limits {
+		copied[group] = limit
+	}
+	p.limits.Store(&copied)
+}
+
+// Limit returns the rate limit of the route group.
+func (p *RateLimitPolicy) Limit(group string) (RateLimit, bool) {
+	limits := *p.limits.Load()
+	if limit, ok := limits[group]; ok {
+		return limit, limit.Requests > 0
+	}
+
+	limit, ok := limits[DefaultRateLimitGroup]
+
+	return limit, ok && limit.Requests > 0
+}
+
+// RateLimitMiddleware limits the request rate of a route group per client.
+// Clients are identified by the authenticated principal or by IP address.
+// Requests are let through if the backend fails.
+func RateLimitMiddleware(
+	logger *slog.Logger,
+	limiter RateLimiter,
+	policy *RateLimitPolicy,
+	group string,
+) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limit, ok := policy.Limit(group)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			key := group + ":" + rateLimitClient(r)
+			allowed, reset, err := limiter.Allow(r.Context(), key, limit.Requests, limit.Period)
+			if err != nil {
+				logger.Error("Error checking rate limit", "requestId", middleware.GetReqID(r.Context()), "error", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// The bucket refills one request per interval.
+			interval := limit.Period / time.Duration(limit.Requests)
+			remaining := 0
+			if allowed {
+				remaining = int((limit.Period - reset) / interval)
+			}
+
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(limit.Requests))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(remaining))
+			w.Header().Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(reset)))
+
+			if !allowed {
+				w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(reset+interval-limit.Period)))
+				http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// rateLimitClient identifies the client of the request.
+func rateLimitClient(r *http.Request) string {
+	if principal, ok := PrincipalFromContext(r.Context()); ok {
+		return "principal:" + principal.Name
+	}
+
+	// middleware.RealIP replaces RemoteAddr with a bare IP address.
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		ip = host
+	}
+
+	return "ip:" + ip
+}
+
+func ceilSeconds(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+
+	return int(math.Ceil(d.Seconds()))
+}
+
+// MemoryRateLimiter is an in-process RateLimiter.
+// It's only suitable for a single instance deployment.
+type MemoryRateLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]time.Time // Key to the time the bucket is full again.
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryRateLimiter creates a new in-process rate limiter.
+func NewMemoryRateLimiter() *MemoryRateLimiter {
+	return &MemoryRateLimiter{
+		buckets: make(map[string]time.Time),
+		now:     time.Now,
+	}
+}
+
+// Allow implements RateLimiter using the generic cell rate algorithm,
+// an equivalent of a token bucket that only needs to track a single time value.
+func (l *MemoryRateLimiter) Allow(_ context.Context, key string, requests int, period time.Duration) (bool, time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	interval := period / time.Duration(requests)
+	full := l.buckets[key]
+	if full.Before(now) {
+		full = now
+	}
+
+	next := full.Add(interval)
+	if next.Sub(now) > period {
+		return false, full.Sub(now), nil
+	}
+
+	l.buckets[key] = next
+
+	return true, next.Sub(now), nil
+}
+
+// sweep removes full buckets at most once a minute.
+func (l *MemoryRateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < time.Minute {
+		return
+	}
+	l.lastSweep = now
+
+	for key, full := range l.buckets {
+		if !full.After(now) {
+			delete(l.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+//go:build unit
+// +build unit
+
+package api
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestMemoryRateLimiter(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := NewMemoryRateLimiter()
+	limiter.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	allow := func(key string) (bool, time.Duration) {
+		t.Helper()
+
+		allowed, reset, err := limiter.Allow(ctx, key, 3, 3*time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return allowed, reset
+	}
+
+	// A burst of up to the number of requests is allowed.
+	for i := 1; i <= 3; i++ {
+		allowed, reset := allow("a")
+		if !allowed {
+			t.Fatalf("Expected request %d to be allowed", i)
+		}
+		if want, got := time.Duration(i)*time.Second, reset; want != got {
+			t.Fatalf("Expected reset %s got %s", want, got)
+		}
+	}
+
+	if allowed, _ := allow("a"); allowed {
+		t.Fatal("Expected request to be rejected")
+	}
+
+	// Other keys have their own buckets.
+	if allowed, _ := allow("b"); !allowed {
+		t.Fatal("Expected request to be allowed")
+	}
+
+	// The bucket refills one request per interval.
+	now = now.Add(time.Second)
+	if allowed, _ := allow("a"); !allowed {
+		t.Fatal("Expected request to be allowed")
+	}
+	if allowed, _ := allow("a"); allowed {
+		t.Fatal("Expected request to be rejected")
+	}
+
+	// Full buckets are swept.
+	now = now.Add(time.Hour)
+	allow("c")
+	if want, got := 1, len(limiter.buckets); want != got {
+		t.Fatalf("Expected buckets %d got %d", want, got)
+	}
+}
+
+func TestRateLimitPolicy(t *testing.T) {
+	policy := NewRateLimitPolicy(map[string]RateLimit{
+		DefaultRateLimitGroup: {Requests: 10, Period: time.Minute},
+		"dinosaurs":           {Requests: 5, Period: time.Minute},
+		"cages":               {Requests: 0, Period: time.Minute},
+	})
+
+	tests := []struct {
+		group    string
+		requests int
+		ok       bool
+	}{
+		{"dinosaurs", 5, true},
+		{"cages", 0, false},
+		{"zones", 10, true},
+	}
+
+	for _, tt := range tests {
+		limit, ok := policy.Limit(tt.group)
+		if want, got := tt.ok, ok; want != got {
+			t.Errorf("%s: expected ok %t got %t", tt.group, want, got)
+		}
+		if ok && tt.requests != limit.Requests {
+			t.Errorf("%s: expected requests %d got %d", tt.group, tt.requests, limit.Requests)
+		}
+	}
+
+	policy.Set(nil)
+	if _, ok := policy.Limit("dinosaurs"); ok {
+		t.Error("Expected no limit")
+	}
+}
+
+type fakeRateLimiter struct {
+	keys []string
+	err  error
+}
+
+func (l *fakeRateLimiter) Allow(_ context.Context, key string, _ int, _ time.Duration) (bool, time.Duration, error) {
+	l.keys = append(l.keys, key)
+
+	return false, 3 * time.Second, l.err
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	policy := NewRateLimitPolicy(map[string]RateLimit{
+		DefaultRateLimitGroup: {Requests: 2, Period: 2 * time.Second},
+	})
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	limiter := NewMemoryRateLimiter()
+	h := RateLimitMiddleware(logger, limiter, policy, "dinosaurs")(ok)
+
+	do := func(remoteAddr string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/dinosaurs", nil)
+		r.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		return w
+	}
+
+	w := do("10.0.0.1:1234")
+	if want, got := http.StatusOK, w.Code; want != got {
+		t.Fatalf("Expected %d got %d", want, got)
+	}
+	if want, got := "2", w.Header().Get("RateLimit-Limit"); want != got {
+		t.Fatalf("Expected RateLimit-Limit %s got %s", want, got)
+	}
+	if want, got := "1", w.Header().Get("RateLimit-Remaining"); want != got {
+		t.Fatalf("Expected RateLimit-Remaining %s got %s", want, got)
+	}
+
+	do("10.0.0.1:1235")
+	w = do("10.0.0.1:1236")
+	if want, got := http.StatusTooManyRequests, w.Code; want != got {
+		t.Fatalf("Expected %d got %d", want, got)
+	}
+	if want, got := "0", w.Header().Get("RateLimit-Remaining"); want != got {
+		t.Fatalf("Expected RateLimit-Remaining %s got %s", want, got)
+	}
+	if want, got := "1", w.Header().Get("Retry-After"); want != got {
+		t.Fatalf("Expected Retry-After %s got %s", want, got)
+	}
+
+	// Another client is not affected.
+	if want, got := http.StatusOK, do("10.0.0.2:1234").Code; want != got {
+		t.Fatalf("Expected %d got %d", want, got)
+	}
+}
+
+func TestRateLimitMiddlewareClientKey(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	policy := NewRateLimitPolicy(map[string]RateLimit{
+		DefaultRateLimitGroup: {Requests: 1, Period: time.Second},
+	})
+	limiter := &fakeRateLimiter{}
+	h := RateLimitMiddleware(logger, limiter, policy, "cages")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	r := httptest.NewRequest(http.MethodGet, "/cages", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	r = httptest.NewRequest(http.MethodGet, "/cages", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+	r = r.WithContext(WithPrincipal(r.Context(), Principal{Name: "gate-controller", Method: AuthMethodClientCert}))
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	if want, got := "cages:ip:10.0.0.1", limiter.keys[0]; want != got {
+		t.Fatalf("Expected key %s got %s", want, got)
+	}
+	if want, got := "cages:principal:gate-controller", limiter.keys[1]; want != got {
+		t.Fatalf("Expected key %s got %s", want, got)
+	}
+
+	// Requests are let through if the backend fails.
+	limiter.err = errors.New("connection refused")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/cages", nil))
+	if want, got := http.StatusOK, w.Code; want != got {
+		t.Fatalf("Expected %d got %d", want, got)
+	}
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+const jsonContentType = "application/json"
+
+// Route groups.
+const (
+	RouteGroupCages     = "cages"
+	RouteGroupDinosaurs = "dinosaurs"
+	RouteGroupZones     = "zones"
+	RouteGroupFeedings  = "feedings"
+	RouteGroupBreeding  = "breeding"
+	RouteGroupStaff     = "staff"
+	RouteGroupIncidents = "incidents"
+)
+
+// Routes registers the cage, dinosaur, zone, feeding, breeding, staff and incident endpoints under the base URI.
+// The optional group function returns a middleware for each route group,
+// e.g. to rate limit the groups separately.
+func (s *Server) Routes(rtr chi.Router, baseURI string, group func(name string) func(http.Handler) http.Handler) {
+	use := func(rtr chi.Router, name string) {
+		if group != nil {
+			rtr.Use(group(name))
+		}
+	}
+
+	// Cage endpoints.
+	rtr.Group(func(rtr chi.Router) {
+		use(rtr, RouteGroupCages)
+		rtr.Get(baseURI+"/cages", s.ListCages())
+		rtr.With(middleware.AllowContentType(jsonContentType)).
+			Post(baseURI+"/cages", s.AddCage())
+		rtr.Get(baseURI+"/cages/{id}", s.GetCage())
+		rtr.With(middleware.AllowContentType(jsonContentType)).
+			Put(baseURI+"/cages/{id}", s.ChangeCageStatus())
+		rtr.With(middleware.AllowContentType(mergePatchContentType)).
+			Patch(baseURI+"/cages/{id}", s.PatchCage())
+		rtr.Delete(baseURI+"/cages/{id}", s.DeleteCage())
+		rtr.With(middleware.AllowContentType(jsonContentType)).
+			Put(baseURI+"/cages/{id}/capacity", s.ResizeCage())
+		rtr.Get(baseURI+"/cages/{id}/history", s.ListCageHistory())
+		rtr.Post(baseURI+"/cages/{id}/restore", s.RestoreCage())
+	})
+	// Dinosaur endpoints.
+	rtr.Group(func(rtr chi.Router) {
+		use(rtr, RouteGroupDinosaurs)
+		rtr.With(middleware.AllowContentType(jsonContentType)).
+			Post(baseURI+"/cages/{id}/dinosaurs", s.AddDinosaur())
+		rtr.Get(baseURI+"/cages/{id}/dinosaurs", s.ListCageDinosaurs())
+		rtr.Get(baseURI+"/dinosaurs", s.ListAllDinosaurs())
+		rtr.Get(baseURI+"/dinosaurs/{id}", s.GetDinosaur())
+		rtr.With(middleware.AllowContentType(jsonContentType)).
+			Put(baseURI+"/dinosaurs/{id}", s.MoveDinosaur())
+		rtr.With(middleware.AllowContentType(mergePatchContentType)).
+			Patch(baseURI+"/dinosaurs/{id}", s.PatchDinosaur())
+		rtr.Delete(baseURI+"/dinosaurs/{id}", s.DeleteDinosaur())
+		rtr.Post(baseURI+"/dinosaurs/{id}/restore", s.RestoreDinosaur())
+		rtr.Get(baseURI+"/dinosaurs/{id}/health", s.ListHealthRecords())
+		rtr.With(middleware.AllowContentType(jsonContentType)).
+			Post(baseURI+"/dinosaurs/{id}/health", s.AddHealthRecord())
+		rtr.With(middleware.AllowContentType(jsonContentType)).
+			Post(baseURI+"/dinosaurs/{id}/quarantine", s.QuarantineDinosaur())
+		rtr.With(middleware.AllowContentType(jsonContentType)).
+			Post(baseURI+"/dinosaurs/{id}/release", s.ReleaseDinosaur())
+		rtr.Get(baseURI+"/dinosaurs/{id}/lifecycle", s.ListLifecycleEvents())
+		rtr.With(middleware.AllowContentType(jsonContentType)).
+			Post(baseURI+"/dinosaurs/{id}/lifecycle", s.TransitionDinosaur())
+		rtr.Get(baseURI+"/hatchery", s.ListEggs())
+		rtr.With(middleware.AllowContentType(jsonContentType)).
+			Post(baseURI+"/hatchery", s.AddEgg())
+		rtr.With(middleware.AllowContentType(jsonContentType)).
+			Post(baseURI+"/hatchery/{id}/hatch", s.HatchEgg())
+	})
+	// Zone endpoints.
+	rtr.Group(func(rtr chi.Router) {
+		use(rtr, RouteGroupZones)
+		rtr.Get(baseURI+"/zones", s.ListZones())
+		rtr.With(middleware.AllowContentType(jsonContentType)).
+			Post(baseURI+"/zones", s.AddZone())
+		rtr.Get(baseURI+"/zones/{id}", s.GetZone())
+		rtr.Get(baseURI+"/zones/{id}/sectors", s.ListSectors())
+		rtr.With(middleware.AllowContentType(jsonContentType)).
+			Post(baseURI+"/zones/{id}/sectors", s.AddSector())
+		rtr.Get(baseURI+"/zones/{id}/cages", s.ListZoneCages())
+	})
+	// Feeding endpoints.
+	rtr.Group(func(rtr chi.Router) {
+		use(rtr, RouteGroupFeedings)
+		rtr.Get(baseURI+"/feeding-schedules", s.ListFeedingSchedules())
+		rtr.With(middleware.AllowContentType(jsonContentType)).
+			Post(baseURI+"/feeding-schedules", s.AddFeedingSchedule())
+		rtr.Delete(baseURI+"/feeding-schedules/{id}", s.DeleteFeedingSchedule())
+		rtr.Get(baseURI+"/feedings", s.ListFeedings())
+		rtr.With(middleware.AllowContentType(jsonContentType)).
+			Post(baseURI+"/feedings", s.RecordFeeding())
+		rtr.Get(baseURI+"/feedings/overdue", s.ListOverdueFeedings())
+	})
+	// Breeding endpoints.
+	rtr.Group(func(rtr chi.Router) {
+		use(rtr, RouteGroupBreeding)
+		rtr.Get(baseURI+"/dinosaurs/{id}/lineage", s.GetLineage())
+		rtr.With(middleware.AllowContentType(jsonContentType)).
+			Put(baseURI+"/dinosaurs/{id}/parents", s.SetParents())
+		rtr.Get(baseURI+"/breeding-pairs", s.ListBreedingPairs())
+		rtr.With(middleware.AllowContentType(jsonContentType)).
+			Post(baseURI+"/breeding-pairs", s.AddBreedingPair())
+	})
+	// Staff endpoints.
+	rtr.Group(func(rtr chi.Router) {
+		use(rtr, RouteGroupStaff)
+		rtr.Get(baseURI+"/staff", s.ListStaff())
+		rtr.With(middleware.AllowContentType(jsonContentType)).
+			Post(baseURI+"/staff", s.AddStaff())
+		rtr.Get(baseURI+"/staff/{id}", s.GetStaff())
+		rtr.Get(baseURI+"/staff/{id}/cages", s.ListStaffCages())
+		rtr.Get(baseURI+"/cages/{id}/staff", s.ListCageStaff())
+		rtr.Get(baseURI+"/assignments", s.ListAssignments())
+		rtr.With(middleware.AllowContentType(jsonContentType)).
+			Post(baseURI+"/assignments", s.AddAssignment())
+		rtr.Delete(baseURI+"/assignments/{id}", s.DeleteAssignment())
+	})
+	// Incident endpoints.
+	rtr.Group(func(rtr chi.Router) {
+		use(rtr, RouteGroupIncidents)
+		rtr.Get(baseURI+"/incidents", s.ListIncidents())
+		rtr.With(middleware.AllowContentType(jsonContentType)).
+			Post(baseURI+"/incidents", s.OpenIncident())
+		rtr.Get(baseURI+"/incidents/{id}", s.GetIncident())
+		rtr.With(middleware.AllowContentType(jsonContentType)).
+			Post(baseURI+"/incidents/{id}/timeline", s.AddIncidentEntry())
+		rtr.With(middleware.AllowContentType(jsonContentType)).
+			Post(baseURI+"/incidents/{id}/resolve", s.ResolveIncident())
+	})
+}
@@ -3,6 +3,8 @@ package api
 import (
 	"context"
 	"log/slog"
+	"sync/atomic"
+	"time"
 
 	"github.com/pmatseykanets/jurassic/app"
 )
@@ -10,19 +12,79 @@ import (
 // CageStore defines the interface for the Cage store.
 type CageStore interface {
 	Add(ctx context.Context, cage *app.Cage) (*app.Cage, error)
-	Get(ctx context.Context, id string) (*app.Cage, error)
-	List(ctx context.Context, status app.CageStatus) ([]app.Cage, error)
+	Get(ctx context.Context, id string, opts app.GetOptions) (*app.Cage, error)
+	List(ctx context.Context, filter app.CageFilter) ([]app.Cage, error)
 	ChangeStatus(ctx context.Context, id string, status app.CageStatus) (*app.Cage, error)
+	Update(ctx context.Context, id string, patch app.CagePatch) (*app.Cage, error)
+	Resize(ctx context.Context, id string, capacity int) (*app.Cage, error)
+	History(ctx context.Context, id string) ([]app.CageHistoryEntry, error)
 	Delete(ctx context.Context, id string) error
+	Restore(ctx context.Context, id string) (*app.Cage, error)
 }
 
 // DinosaurStore defines the interface for the Dinosaur store.
 type DinosaurStore interface {
 	Add(ctx context.Context, dinosaur *app.Dinosaur) (*app.Dinosaur, error)
-	List(ctx context.Context, cageID string, species app.DinosaurSpecies) ([]app.Dinosaur, error)
-	Get(ctx context.Context, id string) (*app.Dinosaur, error)
+	List(ctx context.Context, filter app.DinosaurFilter) ([]app.Dinosaur, error)
+	Get(ctx context.Context, id string, opts app.GetOptions) (*app.Dinosaur, error)
 	Move(ctx context.Context, id string, cageID string) (*app.Dinosaur, error)
+	Update(ctx context.Context, id string, patch app.DinosaurPatch) (*app.Dinosaur, error)
 	Delete(ctx context.Context, id string) error
+	Restore(ctx context.Context, id string) (*app.Dinosaur, error)
+	AddHealthRecord(ctx context.Context, record *app.HealthRecord) (*app.HealthRecord, error)
+	HealthRecords(ctx context.Context, id string, filter app.HealthRecordFilter) ([]app.HealthRecord, error)
+	Quarantine(ctx context.Context, id string, quarantine app.Quarantine) (*app.Dinosaur, error)
+	Release(ctx context.Context, id string, cageID string) (*app.Dinosaur, error)
+	AddEgg(ctx context.Context, egg app.Egg) (*app.Dinosaur, error)
+	Hatch(ctx context.Context, id string, cageID string) (*app.Dinosaur, error)
+	Transition(ctx context.Context, id string, transition app.LifecycleTransition) (*app.LifecycleEvent, error)
+	LifecycleEvents(ctx context.Context, id string) ([]app.LifecycleEvent, error)
+}
+
+// ZoneStore defines the interface for the Zone store.
+type ZoneStore interface {
+	Add(ctx context.Context, zone *app.Zone) (*app.Zone, error)
+	Get(ctx context.Context, id string) (*app.Zone, error)
+	List(ctx context.Context) ([]app.Zone, error)
+	AddSector(ctx context.Context, sector *app.Sector) (*app.Sector, error)
+	ListSectors(ctx context.Context, zoneID string) ([]app.Sector, error)
+}
+
+// FeedingStore defines the interface for the Feeding store.
+type FeedingStore interface {
+	AddSchedule(ctx context.Context, schedule *app.FeedingSchedule) (*app.FeedingSchedule, error)
+	ListSchedules(ctx context.Context, filter app.FeedingScheduleFilter) ([]app.FeedingSchedule, error)
+	DeleteSchedule(ctx context.Context, id string) error
+	Record(ctx context.Context, feeding *app.Feeding) (*app.Feeding, error)
+	List(ctx context.Context, filter app.FeedingFilter) ([]app.Feeding, error)
+	Overdue(ctx context.Context, at time.Time) ([]app.OverdueFeeding, error)
+}
+
+// BreedingStore defines the interface for the Breeding store.
+type BreedingStore interface {
+	SetParents(ctx context.Context, id string, parentIDs []string) (*app.Lineage, error)
+	Lineage(ctx context.Context, id string, generations int) (*app.Lineage, error)
+	AddPair(ctx context.Context, pair *app.BreedingPair) (*app.BreedingPair, error)
+	ListPairs(ctx context.Context, filter app.BreedingPairFilter) ([]app.BreedingPair, error)
+}
+
+// StaffStore defines the interface for the Staff store.
+type StaffStore interface {
+	Add(ctx context.Context, staff *app.Staff) (*app.Staff, error)
+	Get(ctx context.Context, id string) (*app.Staff, error)
+	List(ctx context.Context, filter app.StaffFilter) ([]app.Staff, error)
+	Assign(ctx context.Context, assignment *app.Assignment) (*app.Assignment, error)
+	ListAssignments(ctx context.Context, filter app.AssignmentFilter) ([]app.Assignment, error)
+	DeleteAssignment(ctx context.Context, id string) error
+}
+
+// IncidentStore defines the interface for the Incident store.
+type IncidentStore interface {
+	Open(ctx context.Context, incident *app.Incident) (*app.Incident, error)
+	Get(ctx context.Context, id string) (*app.Incident, error)
+	List(ctx context.Context, filter app.IncidentFilter) ([]app.Incident, error)
+	AddEntry(ctx context.Context, id string, entry *app.IncidentEntry) (*app.IncidentEntry, error)
+	Resolve(ctx context.Context, id, resolution string) (*app.Incident, error)
 }
 
 // Server defines the API server.
@@ -31,4 +93,15 @@ type Server struct {
 	Logger        *slog.Logger
 	CageStore     CageStore
 	DinosaurStore DinosaurStore
+	ZoneStore     ZoneStore
+	FeedingStore  FeedingStore
+	BreedingStore BreedingStore
+	StaffStore    StaffStore
+	IncidentStore IncidentStore
+	// ReadinessChecks are run by the readiness endpoint.
+	ReadinessChecks []HealthCheck
+	// ReadinessTimeout limits the time all readiness checks may take.
+	ReadinessTimeout time.Duration
+
+	draining atomic.Bool
 }
@@ -0,0 +1,419 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+// ListStaff lists staff members sorted by name.
+// GET /staff[?role=keeper|veterinarian|security]
+func (s *Server) ListStaff() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+
+		filter, err := staffFilter(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		list, err := s.StaffStore.List(r.Context(), filter)
+		if err != nil {
+			logger.Error("Error getting staff", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		if list == nil {
+			list = []app.Staff{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		response := struct {
+			Data []app.Staff `json:"data"`
+		}{
+			Data: list,
+		}
+
+		if err = json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			return
+		}
+	}
+}
+
+// AddStaffRequest is a request to add a new staff member.
+type AddStaffRequest struct {
+	Name           string              `json:"name"`
+	Role           app.StaffRole       `json:"role"`
+	Certifications []app.Certification `json:"certifications"`
+}
+
+// Staff returns the requested staff member.
+func (r AddStaffRequest) Staff() app.Staff {
+	return app.Staff{
+		Name:           r.Name,
+		Role:           r.Role,
+		Certifications: r.Certifications,
+	}
+}
+
+// AddStaff adds a new staff member.
+// POST /staff
+func (s *Server) AddStaff() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+
+		var req AddStaffRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.Error("Error decoding request body", "error", err)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		staff := req.Staff()
+		if err := staff.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		added, err := s.StaffStore.Add(r.Context(), &staff)
+		if err != nil {
+			logger.Error("Error adding staff", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+
+		response := struct {
+			Data *app.Staff `json:"data"`
+		}{
+			Data: added,
+		}
+
+		if err = json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			return
+		}
+	}
+}
+
+// GetStaff gets a staff member by id.
+// GET /staff/:id
+func (s *Server) GetStaff() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+		id := chi.URLParam(r, "id")
+
+		if err := app.ValidateID(id); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		staff, err := s.StaffStore.Get(r.Context(), id)
+		if err != nil {
+			if err == app.ErrNotFound {
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+				return
+			}
+
+			logger.Error("Error getting staff", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		response := struct {
+			Data *app.Staff `json:"data"`
+		}{
+			Data: staff,
+		}
+
+		if err = json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			return
+		}
+	}
+}
+
+// ListStaffCages lists the cages a staff member covers at the given time, now by default.
+// GET /staff/:id/cages[?at=...]
+func (s *Server) ListStaffCages() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+		id := chi.URLParam(r, "id")
+
+		if err := app.ValidateID(id); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		at, err := timestamp(r.URL.Query(), "at")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if at.IsZero() {
+			at = time.Now()
+		}
+
+		if _, err := s.StaffStore.Get(r.Context(), id); err != nil {
+			if err == app.ErrNotFound {
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+				return
+			}
+
+			logger.Error("Error getting staff", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		assignments, err := s.StaffStore.ListAssignments(r.Context(), app.AssignmentFilter{StaffID: id, At: at})
+		if err != nil {
+			logger.Error("Error getting assignments", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		var cageIDs []string
+		for _, assignment := range assignments {
+			if !slices.Contains(cageIDs, assignment.CageID) {
+				cageIDs = append(cageIDs, assignment.CageID)
+			}
+		}
+
+		cages := []app.Cage{}
+		// An empty list of ids would list all cages.
+		if len(cageIDs) > 0 {
+			if cages, err = s.CageStore.List(r.Context(), app.CageFilter{IDs: cageIDs}); err != nil {
+				logger.Error("Error getting cages", "error", err)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+			if cages == nil {
+				cages = []app.Cage{}
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		response := struct {
+			Data []app.Cage `json:"data"`
+		}{
+			Data: cages,
+		}
+
+		if err = json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			return
+		}
+	}
+}
+
+// ListCageStaff lists the staff on duty for a cage at the given time, now by default.
+// GET /cages/:id/staff[?at=...]
+func (s *Server) ListCageStaff() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+		id := chi.URLParam(r, "id")
+
+		if err := app.ValidateID(id); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		at, err := timestamp(r.URL.Query(), "at")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if at.IsZero() {
+			at = time.Now()
+		}
+
+		if _, err := s.CageStore.Get(r.Context(), id, app.GetOptions{Fields: []string{"id"}}); err != nil {
+			if err == app.ErrNotFound {
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+				return
+			}
+
+			logger.Error("Error getting cage", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		list, err := s.StaffStore.List(r.Context(), app.StaffFilter{CageID: id, OnDuty: at})
+		if err != nil {
+			logger.Error("Error getting staff", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		if list == nil {
+			list = []app.Staff{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		response := struct {
+			Data []app.Staff `json:"data"`
+		}{
+			Data: list,
+		}
+
+		if err = json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			return
+		}
+	}
+}
+
+// ListAssignments lists assignments in the order the shifts start.
+// GET /assignments[?staffId=...][&cageId=...][&at=...]
+func (s *Server) ListAssignments() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+
+		filter, err := assignmentFilter(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		assignments, err := s.StaffStore.ListAssignments(r.Context(), filter)
+		if err != nil {
+			logger.Error("Error getting assignments", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		if assignments == nil {
+			assignments = []app.Assignment{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		response := struct {
+			Data []app.Assignment `json:"data"`
+		}{
+			Data: assignments,
+		}
+
+		if err = json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			return
+		}
+	}
+}
+
+// AddAssignmentRequest is a request to assign a staff member to a shift covering a cage.
+type AddAssignmentRequest struct {
+	StaffID  string    `json:"staffId"`
+	CageID   string    `json:"cageId"`
+	StartsAt time.Time `json:"startsAt"`
+	EndsAt   time.Time `json:"endsAt"`
+}
+
+// Assignment returns the requested assignment.
+func (r AddAssignmentRequest) Assignment() app.Assignment {
+	return app.Assignment{
+		StaffID:  r.StaffID,
+		CageID:   r.CageID,
+		StartsAt: r.StartsAt,
+		EndsAt:   r.EndsAt,
+	}
+}
+
+// AddAssignment assigns a staff member to a shift covering a cage.
+// Staff without the carnivore handling certification can't cover a cage holding carnivores.
+// POST /assignments
+func (s *Server) AddAssignment() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+
+		var req AddAssignmentRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.Error("Error decoding request body", "error", err)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		assignment := req.Assignment()
+		if err := assignment.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		added, err := s.StaffStore.Assign(r.Context(), &assignment)
+		if err != nil {
+			switch err {
+			case app.ErrNotFound:
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			case app.ErrNotCertified:
+				http.Error(w, err.Error(), http.StatusConflict)
+			default:
+				logger.Error("Error adding assignment", "error", err)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+
+		response := struct {
+			Data *app.Assignment `json:"data"`
+		}{
+			Data: added,
+		}
+
+		if err = json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			return
+		}
+	}
+}
+
+// DeleteAssignment deletes an assignment.
+// DELETE /assignments/:id
+func (s *Server) DeleteAssignment() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+		id := chi.URLParam(r, "id")
+
+		if err := app.ValidateID(id); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err := s.StaffStore.DeleteAssignment(r.Context(), id); err != nil {
+			if err == app.ErrNotFound {
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+				return
+			}
+
+			logger.Error("Error deleting assignment", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
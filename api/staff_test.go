@@ -0,0 +1,393 @@
+//go:build unit
+// +build unit
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+type fakeStaffStore struct {
+	staff            app.Staff
+	assignments      []app.Assignment
+	filter           app.StaffFilter
+	assignmentFilter app.AssignmentFilter
+	id               string
+	err              error
+}
+
+func (s *fakeStaffStore) Add(_ context.Context, staff *app.Staff) (*app.Staff, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	s.staff = *staff
+	s.staff.ID = uuid.NewString()
+	if s.staff.Certifications == nil {
+		s.staff.Certifications = []app.Certification{}
+	}
+	s.staff.CreatedAt = time.Now()
+	s.staff.UpdatedAt = s.staff.CreatedAt
+	added := s.staff
+
+	return &added, nil
+}
+
+func (s *fakeStaffStore) Get(_ context.Context, id string) (*app.Staff, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	s.id = id
+	staff := s.staff
+
+	return &staff, nil
+}
+
+func (s *fakeStaffStore) List(_ context.Context, filter app.StaffFilter) ([]app.Staff, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	s.filter = filter
+	if s.staff.ID == "" {
+		return nil, nil
+	}
+
+	return []app.Staff{s.staff}, nil
+}
+
+func (s *fakeStaffStore) Assign(_ context.Context, assignment *app.Assignment) (*app.Assignment, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	added := *assignment
+	added.ID = uuid.NewString()
+	added.CreatedAt = time.Now()
+	s.assignments = append(s.assignments, added)
+
+	return &added, nil
+}
+
+func (s *fakeStaffStore) ListAssignments(_ context.Context, filter app.AssignmentFilter) ([]app.Assignment, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	s.assignmentFilter = filter
+
+	return s.assignments, nil
+}
+
+func (s *fakeStaffStore) DeleteAssignment(_ context.Context, id string) error {
+	s.id = id
+
+	return s.err
+}
+
+func TestAddStaff(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	tests := []struct {
+		name   string
+		body   string
+		err    error
+		status int
+	}{
+		{"keeper", `{"name": "Muldoon", "role": "keeper", "certifications": ["carnivore-handling", "tranquilizer"]}`, nil, http.StatusCreated},
+		{"no certifications", `{"name": "Sattler", "role": "veterinarian"}`, nil, http.StatusCreated},
+		{"no name", `{"role": "keeper"}`, nil, http.StatusBadRequest},
+		{"invalid role", `{"name": "Nedry", "role": "programmer"}`, nil, http.StatusBadRequest},
+		{"invalid certification", `{"name": "Muldoon", "role": "keeper", "certifications": ["juggling"]}`, nil, http.StatusBadRequest},
+		{"invalid body", `{"name": `, nil, http.StatusBadRequest},
+		{"store error", `{"name": "Muldoon", "role": "keeper"}`, errors.New("store error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &Server{
+				Logger:     logger,
+				StaffStore: &fakeStaffStore{err: tt.err},
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/staff", strings.NewReader(tt.body))
+
+			validated(t, svc.AddStaff()).ServeHTTP(w, r)
+
+			if want, got := tt.status, w.Code; want != got {
+				t.Fatalf("Expected %d got %d", want, got)
+			}
+		})
+	}
+}
+
+func TestListStaffCages(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	staffID, cageID := uuid.NewString(), uuid.NewString()
+	assignments := []app.Assignment{{ID: uuid.NewString(), StaffID: staffID, CageID: cageID}}
+	at := time.Date(2023, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		id          string
+		query       string
+		assignments []app.Assignment
+		err         error
+		cageIDs     []string
+		cages       int
+		status      int
+	}{
+		{"now", staffID, "", assignments, nil, []string{cageID}, 1, http.StatusOK},
+		{"at", staffID, "?at=" + at.Format(time.RFC3339), assignments, nil, []string{cageID}, 1, http.StatusOK},
+		{"off duty", staffID, "", nil, nil, nil, 0, http.StatusOK},
+		{"invalid at", staffID, "?at=noon", nil, nil, nil, 0, http.StatusBadRequest},
+		{"invalid id", "foo", "", nil, nil, nil, 0, http.StatusBadRequest},
+		{"not found", staffID, "", nil, app.ErrNotFound, nil, 0, http.StatusNotFound},
+		{"store error", staffID, "", nil, errors.New("store error"), nil, 0, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			staffStore := &fakeStaffStore{assignments: tt.assignments, err: tt.err}
+			cageStore := &fakeCageStore{cage: app.Cage{ID: cageID, Type: app.CageTypeGeneral, Status: app.CageStatusActive, Capacity: 1, CapacityUnit: app.CapacityUnitHeads}}
+			svc := &Server{
+				Logger:     logger,
+				StaffStore: staffStore,
+				CageStore:  cageStore,
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/staff/"+tt.id+"/cages"+tt.query, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.id)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			validated(t, svc.ListStaffCages()).ServeHTTP(w, r)
+
+			if want, got := tt.status, w.Code; want != got {
+				t.Fatalf("Expected %d got %d", want, got)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			if want, got := staffID, staffStore.assignmentFilter.StaffID; want != got {
+				t.Fatalf("Expected StaffID %s got %s", want, got)
+			}
+			if staffStore.assignmentFilter.At.IsZero() {
+				t.Fatal("Expected the shifts to be filtered by time")
+			}
+			if tt.query != "" && !staffStore.assignmentFilter.At.Equal(at) {
+				t.Fatalf("Expected At %s got %s", at, staffStore.assignmentFilter.At)
+			}
+			if want, got := strings.Join(tt.cageIDs, ","), strings.Join(cageStore.filter.IDs, ","); want != got {
+				t.Fatalf("Expected cage IDs %s got %s", want, got)
+			}
+
+			response := struct {
+				Data []app.Cage `json:"data"`
+			}{}
+
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+
+			if want, got := tt.cages, len(response.Data); want != got {
+				t.Fatalf("Expected cages %d got %d", want, got)
+			}
+		})
+	}
+}
+
+func TestListCageStaff(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	cageID := uuid.NewString()
+	tests := []struct {
+		name     string
+		id       string
+		query    string
+		cageErr  error
+		staffErr error
+		status   int
+	}{
+		{"now", cageID, "", nil, nil, http.StatusOK},
+		{"at", cageID, "?at=2023-01-15T12:00:00Z", nil, nil, http.StatusOK},
+		{"invalid at", cageID, "?at=noon", nil, nil, http.StatusBadRequest},
+		{"invalid id", "foo", "", nil, nil, http.StatusBadRequest},
+		{"cage not found", cageID, "", app.ErrNotFound, nil, http.StatusNotFound},
+		{"store error", cageID, "", nil, errors.New("store error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			staffStore := &fakeStaffStore{
+				staff: app.Staff{ID: uuid.NewString(), Name: "Muldoon", Role: app.StaffRoleKeeper, Certifications: []app.Certification{}},
+				err:   tt.staffErr,
+			}
+			svc := &Server{
+				Logger:     logger,
+				StaffStore: staffStore,
+				CageStore:  &fakeCageStore{err: tt.cageErr},
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/cages/"+tt.id+"/staff"+tt.query, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.id)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			validated(t, svc.ListCageStaff()).ServeHTTP(w, r)
+
+			if want, got := tt.status, w.Code; want != got {
+				t.Fatalf("Expected %d got %d", want, got)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			if want, got := cageID, staffStore.filter.CageID; want != got {
+				t.Fatalf("Expected CageID %s got %s", want, got)
+			}
+			if staffStore.filter.OnDuty.IsZero() {
+				t.Fatal("Expected the staff to be filtered by time")
+			}
+		})
+	}
+}
+
+func TestAddAssignment(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	staffID, cageID := uuid.NewString(), uuid.NewString()
+	shift := `{"staffId": "` + staffID + `", "cageId": "` + cageID + `", "startsAt": "2023-01-15T08:00:00Z", "endsAt": "2023-01-15T16:00:00Z"}`
+	tests := []struct {
+		name   string
+		body   string
+		err    error
+		status int
+	}{
+		{"shift", shift, nil, http.StatusCreated},
+		{"no end", `{"staffId": "` + staffID + `", "cageId": "` + cageID + `", "startsAt": "2023-01-15T08:00:00Z"}`, nil, http.StatusBadRequest},
+		{"empty shift", `{"staffId": "` + staffID + `", "cageId": "` + cageID + `", "startsAt": "2023-01-15T08:00:00Z", "endsAt": "2023-01-15T08:00:00Z"}`, nil, http.StatusBadRequest},
+		{"invalid staff", `{"staffId": "foo", "cageId": "` + cageID + `", "startsAt": "2023-01-15T08:00:00Z", "endsAt": "2023-01-15T16:00:00Z"}`, nil, http.StatusBadRequest},
+		{"invalid body", `{"staffId": `, nil, http.StatusBadRequest},
+		{"not found", shift, app.ErrNotFound, http.StatusNotFound},
+		{"not certified", shift, app.ErrNotCertified, http.StatusConflict},
+		{"store error", shift, errors.New("store error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &Server{
+				Logger:     logger,
+				StaffStore: &fakeStaffStore{err: tt.err},
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/assignments", strings.NewReader(tt.body))
+
+			validated(t, svc.AddAssignment()).ServeHTTP(w, r)
+
+			if want, got := tt.status, w.Code; want != got {
+				t.Fatalf("Expected %d got %d", want, got)
+			}
+		})
+	}
+}
+
+func TestListAssignments(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	staffID, cageID := uuid.NewString(), uuid.NewString()
+	at := time.Date(2023, 1, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		query  string
+		filter app.AssignmentFilter
+		status int
+	}{
+		{"no filter", "", app.AssignmentFilter{}, http.StatusOK},
+		{"staff", "?staffId=" + staffID, app.AssignmentFilter{StaffID: staffID}, http.StatusOK},
+		{"cage at", "?cageId=" + cageID + "&at=" + at.Format(time.RFC3339), app.AssignmentFilter{CageID: cageID, At: at}, http.StatusOK},
+		{"invalid staff", "?staffId=foo", app.AssignmentFilter{}, http.StatusBadRequest},
+		{"invalid at", "?at=noon", app.AssignmentFilter{}, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeStaffStore{}
+			svc := &Server{
+				Logger:     logger,
+				StaffStore: store,
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/assignments"+tt.query, nil)
+
+			validated(t, svc.ListAssignments()).ServeHTTP(w, r)
+
+			if want, got := tt.status, w.Code; want != got {
+				t.Fatalf("Expected %d got %d", want, got)
+			}
+			if want, got := tt.filter, store.assignmentFilter; want != got {
+				t.Fatalf("Expected filter %+v got %+v", want, got)
+			}
+		})
+	}
+}
+
+func TestDeleteAssignment(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	id := uuid.NewString()
+	tests := []struct {
+		name   string
+		id     string
+		err    error
+		status int
+	}{
+		{"deleted", id, nil, http.StatusNoContent},
+		{"invalid id", "foo", nil, http.StatusBadRequest},
+		{"not found", id, app.ErrNotFound, http.StatusNotFound},
+		{"store error", id, errors.New("store error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &Server{
+				Logger:     logger,
+				StaffStore: &fakeStaffStore{err: tt.err},
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodDelete, "/assignments/"+tt.id, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.id)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			validated(t, svc.DeleteAssignment()).ServeHTTP(w, r)
+
+			if want, got := tt.status, w.Code; want != got {
+				t.Fatalf("Expected %d got %d", want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// TLSReloader serves TLS certificates and the client CA bundle
+// from files on disk and reloads them when the files change.
+type TLSReloader struct {
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string
+	ClientAuth   tls.ClientAuthType
+	Logger       *slog.Logger
+
+	config   atomic.Pointer[tls.Config]
+	modTimes []time.Time
+}
+
+// NewTLSReloader creates a new TLS reloader and loads the files.
+func NewTLSReloader(
+	logger *slog.Logger,
+	certFile, keyFile, clientCAFile string,
+	clientAuth tls.ClientAuthType,
+) (*TLSReloader, error) {
+	r := &TLSReloader{
+		CertFile:     certFile,
+		KeyFile:      keyFile,
+		ClientCAFile: clientCAFile,
+		ClientAuth:   clientAuth,
+		Logger:       logger,
+	}
+
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// TLSConfig returns a TLS config for http.Server that always uses
+// the most recently loaded certificate and client CA bundle.
+func (r *TLSReloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return &r.config.Load().Certificates[0], nil
+		},
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return r.config.Load(), nil
+		},
+	}
+}
+
+// Reload loads the files unconditionally.
+func (r *TLSReloader) Reload() error {
+	cert, err := tls.LoadX509KeyPair(r.CertFile, r.KeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+		NextProtos:   []string{"h2", "http/1.1"},
+	}
+
+	if r.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read TLS client CA bundle: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return errors.New("no certificates found in TLS client CA bundle")
+		}
+
+		config.ClientCAs = pool
+		config.ClientAuth = r.ClientAuth
+	}
+
+	r.config.Store(config)
+	r.modTimes = r.stat()
+
+	return nil
+}
+
+// Watch checks the files for changes every interval
+// and reloads them until the context is canceled.
+func (r *TLSReloader) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if !r.changed() {
+			continue
+		}
+
+		// Keep serving the previous certificate if the new files
+		// are incomplete, e.g. the key was not written yet.
+		if err := r.Reload(); err != nil {
+			r.Logger.Error("Failed to reload TLS certificates", "error", err)
+			continue
+		}
+
+		r.Logger.Info("Reloaded TLS certificates")
+	}
+}
+
+func (r *TLSReloader) files() []string {
+	files := []string{r.CertFile, r.KeyFile}
+	if r.ClientCAFile != "" {
+		files = append(files, r.ClientCAFile)
+	}
+
+	return files
+}
+
+func (r *TLSReloader) stat() []time.Time {
+	files := r.files()
+	modTimes := make([]time.Time, len(files))
+	for i, file := range files {
+		if info, err := os.Stat(file); err == nil {
+			modTimes[i] = info.ModTime()
+		}
+	}
+
+	return modTimes
+}
+
+func (r *TLSReloader) changed() bool {
+	modTimes := r.stat()
+	for i := range modTimes {
+		if !modTimes[i].Equal(r.modTimes[i]) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,244 @@
+//go:build unit
+// +build unit
+
+package api
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCert(t *testing.T, cn string, parent *testCert, isCA bool) *testCert {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		IsCA:         isCA,
+
+		BasicConstraintsValid: true,
+	}
+
+	parentCert, parentKey := template, key
+	if parent != nil {
+		parentCert, parentKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parentCert, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testCert{cert: cert, key: key}
+}
+
+func (c *testCert) write(t *testing.T, certFile, keyFile string) {
+	t.Helper()
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.cert.Raw})
+	if err := os.WriteFile(certFile, certPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if keyFile == "" {
+		return
+	}
+
+	der, err := x509.MarshalECPrivateKey(c.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+	if err := os.WriteFile(keyFile, keyPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (c *testCert) tlsCertificate() tls.Certificate {
+	return tls.Certificate{
+		Certificate: [][]byte{c.cert.Raw},
+		PrivateKey:  c.key,
+	}
+}
+
+func TestTLSReloaderMutualTLS(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	caFile := filepath.Join(dir, "ca.pem")
+
+	ca := newTestCert(t, "ca", nil, true)
+	ca.write(t, caFile, "")
+	newTestCert(t, "server", ca, false).write(t, certFile, keyFile)
+	client := newTestCert(t, "gate-controller", ca, false)
+
+	reloader, err := NewTLSReloader(logger, certFile, keyFile, caFile, tls.VerifyClientCertIfGiven)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var principal Principal
+	h := Authenticate(NewAPIKeys("secret"), true)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, _ = PrincipalFromContext(r.Context())
+	}))
+
+	srv := httptest.NewUnstartedServer(h)
+	srv.TLS = reloader.TLSConfig()
+	srv.StartTLS()
+	defer srv.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	newClient := func(certs ...tls.Certificate) *http.Client {
+		return &http.Client{Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: roots, Certificates: certs},
+		}}
+	}
+
+	// A client certificate authenticates the principal.
+	resp, err := newClient(client.tlsCertificate()).Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	if want, got := http.StatusOK, resp.StatusCode; want != got {
+		t.Fatalf("Expected %d got %d", want, got)
+	}
+	if want, got := "gate-controller", principal.Name; want != got {
+		t.Fatalf("Expected principal %s got %s", want, got)
+	}
+	if want, got := AuthMethodClientCert, principal.Method; want != got {
+		t.Fatalf("Expected method %s got %s", want, got)
+	}
+
+	// Without a client certificate a bearer token is required.
+	resp, err = newClient().Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if want, got := http.StatusUnauthorized, resp.StatusCode; want != got {
+		t.Fatalf("Expected %d got %d", want, got)
+	}
+
+	// A certificate signed by an unknown CA doesn't authenticate the principal.
+	other := newTestCert(t, "other", newTestCert(t, "other-ca", nil, true), false)
+	resp, err = newClient(other.tlsCertificate()).Get(srv.URL)
+	if err == nil {
+		resp.Body.Close()
+
+		if want, got := http.StatusUnauthorized, resp.StatusCode; want != got {
+			t.Fatalf("Expected %d got %d", want, got)
+		}
+	}
+}
+
+func TestTLSReloaderWatch(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+
+	ca := newTestCert(t, "ca", nil, true)
+	newTestCert(t, "server1", ca, false).write(t, certFile, keyFile)
+
+	reloader, err := NewTLSReloader(logger, certFile, keyFile, "", tls.NoClientCert)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go reloader.Watch(ctx, 10*time.Millisecond)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.TLS = reloader.TLSConfig()
+	srv.StartTLS()
+	defer srv.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	serverName := func() string {
+		conn, err := tls.Dial("tcp", srv.Listener.Addr().String(), &tls.Config{RootCAs: roots})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		return conn.ConnectionState().PeerCertificates[0].Subject.CommonName
+	}
+
+	if want, got := "server1", serverName(); want != got {
+		t.Fatalf("Expected certificate %s got %s", want, got)
+	}
+
+	// Replace the certificate on disk and make sure the modification time changes.
+	newTestCert(t, "server2", ca, false).write(t, certFile, keyFile)
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(certFile, future, future); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for serverName() != "server2" {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the certificate to be reloaded")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestNewTLSReloaderInvalidFiles(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	dir := t.TempDir()
+
+	_, err := NewTLSReloader(logger, filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem"), "", tls.NoClientCert)
+	if err == nil {
+		t.Fatal("Expected error got nil")
+	}
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// ValidateRequests is a middleware that rejects requests with parameters
+// or bodies that don't match the OpenAPI spec with 400 Bad Request.
+// Requests to paths that are not in the spec are passed through.
+func ValidateRequests(spec *OpenAPI, baseURI string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			op, params, ok := spec.Operation(r.Method, strings.TrimPrefix(r.URL.Path, baseURI))
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+			r.Body.Close()
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			if err := spec.ValidateRequest(op, params, r, body); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ValidateResponses is a middleware that checks the responses against
+// the OpenAPI spec and reports the mismatches. The responses are sent as is.
+// It's meant for tests and development.
+func ValidateResponses(spec *OpenAPI, baseURI string, report func(r *http.Request, err error)) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			op, _, ok := spec.Operation(r.Method, strings.TrimPrefix(r.URL.Path, baseURI))
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			var body bytes.Buffer
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			ww.Tee(&body)
+
+			next.ServeHTTP(ww, r)
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			if err := spec.ValidateResponse(op, status, ww.Header().Get("Content-Type"), body.Bytes()); err != nil {
+				report(r, err)
+			}
+		})
+	}
+}
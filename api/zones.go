@@ -0,0 +1,329 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+// ListZones lists all zones.
+// GET /zones
+func (s *Server) ListZones() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+
+		zones, err := s.ZoneStore.List(r.Context())
+		if err != nil {
+			logger.Error("Error getting zones", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		if zones == nil {
+			zones = []app.Zone{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		response := struct {
+			Data []app.Zone `json:"data"`
+		}{
+			Data: zones,
+		}
+
+		if err = json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			return
+		}
+	}
+}
+
+// AddZoneRequest is a request to add a new zone.
+type AddZoneRequest struct {
+	Name string `json:"name"`
+}
+
+// Validate validates the request.
+func (r AddZoneRequest) Validate() error {
+	return app.ValidateName(r.Name)
+}
+
+// AddZone adds a new zone.
+// POST /zones
+func (s *Server) AddZone() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+
+		var req AddZoneRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.Error("Error decoding request body", "error", err)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		if err := req.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		zone, err := s.ZoneStore.Add(r.Context(), &app.Zone{Name: req.Name})
+		if err != nil {
+			if err == app.ErrConflict {
+				http.Error(w, http.StatusText(http.StatusConflict), http.StatusConflict)
+				return
+			}
+
+			logger.Error("Error adding zone", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+
+		response := struct {
+			Data *app.Zone `json:"data"`
+		}{
+			Data: zone,
+		}
+
+		if err = json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			return
+		}
+	}
+}
+
+// GetZone gets a zone by id along with its sectors.
+// GET /zones/:id
+func (s *Server) GetZone() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+		id := chi.URLParam(r, "id")
+
+		if err := app.ValidateID(id); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		zone, err := s.ZoneStore.Get(r.Context(), id)
+		if err != nil {
+			if err == app.ErrNotFound {
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+				return
+			}
+
+			logger.Error("Error getting zone", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		response := struct {
+			Data *app.Zone `json:"data"`
+		}{
+			Data: zone,
+		}
+
+		if err = json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			return
+		}
+	}
+}
+
+// ListSectors lists the sectors of a zone.
+// GET /zones/:id/sectors
+func (s *Server) ListSectors() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+		id := chi.URLParam(r, "id")
+
+		if err := app.ValidateID(id); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		sectors, err := s.ZoneStore.ListSectors(r.Context(), id)
+		if err != nil {
+			if err == app.ErrNotFound {
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+				return
+			}
+
+			logger.Error("Error getting sectors", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		if sectors == nil {
+			sectors = []app.Sector{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		response := struct {
+			Data []app.Sector `json:"data"`
+		}{
+			Data: sectors,
+		}
+
+		if err = json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			return
+		}
+	}
+}
+
+// AddSectorRequest is a request to add a new sector to a zone.
+type AddSectorRequest struct {
+	Name string `json:"name"`
+}
+
+// Validate validates the request.
+func (r AddSectorRequest) Validate() error {
+	return app.ValidateName(r.Name)
+}
+
+// AddSector adds a new sector to a zone.
+// POST /zones/:id/sectors
+func (s *Server) AddSector() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+		id := chi.URLParam(r, "id")
+
+		if err := app.ValidateID(id); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var req AddSectorRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.Error("Error decoding request body", "error", err)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		if err := req.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		sector, err := s.ZoneStore.AddSector(r.Context(), &app.Sector{ZoneID: id, Name: req.Name})
+		if err != nil {
+			switch err {
+			case app.ErrNotFound:
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			case app.ErrConflict:
+				http.Error(w, http.StatusText(http.StatusConflict), http.StatusConflict)
+			default:
+				logger.Error("Error adding sector", "error", err)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+
+		response := struct {
+			Data *app.Sector `json:"data"`
+		}{
+			Data: sector,
+		}
+
+		if err = json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			return
+		}
+	}
+}
+
+// ListZoneCages lists the cages in all sectors of a zone.
+// It takes the same query parameters as GET /cages.
+// GET /zones/:id/cages[?sectorId=...][&status=active|down][&sort=...][&fields=...][&expand=dinosaurs]
+func (s *Server) ListZoneCages() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		logger := s.Logger.With("requestId", requestID)
+		id := chi.URLParam(r, "id")
+
+		if err := app.ValidateID(id); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		filter, err := cageFilter(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		filter.ZoneID = id
+
+		expanded, err := expand(r, "dinosaurs")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		fields := filter.Fields
+		if expanded["dinosaurs"] {
+			filter.Fields = withField(fields, "id")
+		}
+
+		if _, err := s.ZoneStore.Get(r.Context(), id); err != nil {
+			if err == app.ErrNotFound {
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+				return
+			}
+
+			logger.Error("Error getting zone", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		cages, err := s.CageStore.List(r.Context(), filter)
+		if err != nil {
+			logger.Error("Error getting cages", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		if cages == nil {
+			cages = []app.Cage{}
+		}
+
+		response := struct {
+			Data any `json:"data"`
+		}{
+			Data: cages,
+		}
+		if expanded["dinosaurs"] {
+			if response.Data, err = s.expandDinosaurs(r.Context(), cages); err != nil {
+				logger.Error("Error getting dinosaurs", "error", err)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+		}
+		if response.Data, err = sparse(response.Data, fields, expanded); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if err = json.NewEncoder(w).Encode(response); err != nil {
+			logger.Error("Error marshalling response", "error", err)
+			return
+		}
+	}
+}
@@ -0,0 +1,402 @@
+//go:build unit
+// +build unit
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+type fakeZoneStore struct {
+	zone   app.Zone
+	sector app.Sector
+	id     string
+	err    error
+}
+
+func (s *fakeZoneStore) Add(_ context.Context, zone *app.Zone) (*app.Zone, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	now := time.Now()
+	s.zone = *zone
+	s.zone.ID = uuid.NewString()
+	s.zone.CreatedAt = now
+	s.zone.UpdatedAt = now
+	z := s.zone
+
+	return &z, nil
+}
+
+func (s *fakeZoneStore) Get(_ context.Context, id string) (*app.Zone, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	s.id = id
+	z := s.zone
+
+	return &z, nil
+}
+
+func (s *fakeZoneStore) List(_ context.Context) ([]app.Zone, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	if s.zone.ID == "" {
+		return nil, nil
+	}
+
+	return []app.Zone{s.zone}, nil
+}
+
+func (s *fakeZoneStore) AddSector(_ context.Context, sector *app.Sector) (*app.Sector, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	now := time.Now()
+	s.sector = *sector
+	s.sector.ID = uuid.NewString()
+	s.sector.CreatedAt = now
+	s.sector.UpdatedAt = now
+	sec := s.sector
+
+	return &sec, nil
+}
+
+func (s *fakeZoneStore) ListSectors(_ context.Context, zoneID string) ([]app.Sector, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	s.id = zoneID
+
+	return s.zone.Sectors, nil
+}
+
+func TestAddZone(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	svc := &Server{
+		Logger:    logger,
+		ZoneStore: &fakeZoneStore{},
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/zones", strings.NewReader(`{"name": "Paddock North"}`))
+
+	validated(t, svc.AddZone()).ServeHTTP(w, r)
+
+	if want, got := http.StatusCreated, w.Code; want != got {
+		t.Fatalf("Expected %d got %d", want, got)
+	}
+
+	response := struct {
+		Data app.Zone `json:"data"`
+	}{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	if response.Data.ID == "" {
+		t.Fatal("Expected ID got empty")
+	}
+	if want, got := "Paddock North", response.Data.Name; want != got {
+		t.Fatalf("Expected Name %s got %s", want, got)
+	}
+}
+
+func TestAddZoneErrors(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	tests := []struct {
+		name   string
+		body   string
+		err    error
+		status int
+	}{
+		{"no name", `{}`, nil, http.StatusBadRequest},
+		{"invalid body", `{"name": "Aviary"`, nil, http.StatusBadRequest},
+		{"name taken", `{"name": "Aviary"}`, app.ErrConflict, http.StatusConflict},
+		{"store error", `{"name": "Aviary"}`, errors.New("store error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &Server{
+				Logger:    logger,
+				ZoneStore: &fakeZoneStore{err: tt.err},
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/zones", strings.NewReader(tt.body))
+
+			validated(t, svc.AddZone()).ServeHTTP(w, r)
+
+			if want, got := tt.status, w.Code; want != got {
+				t.Fatalf("Expected %d got %d", want, got)
+			}
+		})
+	}
+}
+
+func TestGetZone(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	id := uuid.NewString()
+	now := time.Now()
+	store := &fakeZoneStore{
+		zone: app.Zone{
+			ID:            id,
+			Name:          "Lagoon",
+			HeadCapacity:  10,
+			HeadCount:     3,
+			SpaceCapacity: 40,
+			SpaceUsed:     20,
+			Sectors: []app.Sector{
+				{ID: uuid.NewString(), ZoneID: id, Name: "East", HeadCapacity: 10, HeadCount: 3, SpaceCapacity: 40, SpaceUsed: 20, CreatedAt: now, UpdatedAt: now},
+			},
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+	}
+
+	svc := &Server{
+		Logger:    logger,
+		ZoneStore: store,
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/zones/"+id, nil)
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", id)
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+	validated(t, svc.GetZone()).ServeHTTP(w, r)
+
+	if want, got := http.StatusOK, w.Code; want != got {
+		t.Fatalf("Expected %d got %d", want, got)
+	}
+	if want, got := id, store.id; want != got {
+		t.Fatalf("Expected ID %s got %s", want, got)
+	}
+
+	response := struct {
+		Data app.Zone `json:"data"`
+	}{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := 10, response.Data.HeadCapacity; want != got {
+		t.Fatalf("Expected HeadCapacity %d got %d", want, got)
+	}
+	if want, got := 3, response.Data.HeadCount; want != got {
+		t.Fatalf("Expected HeadCount %d got %d", want, got)
+	}
+	if want, got := 20, response.Data.SpaceUsed; want != got {
+		t.Fatalf("Expected SpaceUsed %d got %d", want, got)
+	}
+	if want, got := 1, len(response.Data.Sectors); want != got {
+		t.Fatalf("Expected sectors %d got %d", want, got)
+	}
+}
+
+func TestGetZoneNotFoundError(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	svc := &Server{
+		Logger:    logger,
+		ZoneStore: &fakeZoneStore{err: app.ErrNotFound},
+	}
+
+	id := uuid.NewString()
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/zones/"+id, nil)
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", id)
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+	validated(t, svc.GetZone()).ServeHTTP(w, r)
+
+	if want, got := http.StatusNotFound, w.Code; want != got {
+		t.Fatalf("Expected %d got %d", want, got)
+	}
+}
+
+func TestListZonesEmptyListIsRenderedCorrectly(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	svc := &Server{
+		Logger:    logger,
+		ZoneStore: &fakeZoneStore{},
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/zones", nil)
+
+	validated(t, svc.ListZones()).ServeHTTP(w, r)
+
+	if want, got := http.StatusOK, w.Code; want != got {
+		t.Fatalf("Expected %d got %d", want, got)
+	}
+	if want, got := `{"data":[]}`, strings.TrimSpace(w.Body.String()); want != got {
+		t.Fatalf("Expected body %s got %s", want, got)
+	}
+}
+
+func TestAddSector(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	tests := []struct {
+		name   string
+		body   string
+		err    error
+		status int
+	}{
+		{"added", `{"name": "East"}`, nil, http.StatusCreated},
+		{"no name", `{"name": ""}`, nil, http.StatusBadRequest},
+		{"zone not found", `{"name": "East"}`, app.ErrNotFound, http.StatusNotFound},
+		{"name taken", `{"name": "East"}`, app.ErrConflict, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeZoneStore{err: tt.err}
+			svc := &Server{
+				Logger:    logger,
+				ZoneStore: store,
+			}
+
+			id := uuid.NewString()
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/zones/"+id+"/sectors", strings.NewReader(tt.body))
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", id)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			validated(t, svc.AddSector()).ServeHTTP(w, r)
+
+			if want, got := tt.status, w.Code; want != got {
+				t.Fatalf("Expected %d got %d", want, got)
+			}
+			if tt.status == http.StatusCreated {
+				if want, got := id, store.sector.ZoneID; want != got {
+					t.Fatalf("Expected ZoneID %s got %s", want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestListZoneCages(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	id := uuid.NewString()
+	sectorID := uuid.NewString()
+	now := time.Now()
+	cageStore := &fakeCageStore{
+		cage: app.Cage{
+			ID:           uuid.NewString(),
+			Type:         app.CageTypePaddock,
+			Capacity:     2,
+			CapacityUnit: app.CapacityUnitHeads,
+			Status:       app.CageStatusActive,
+			SectorID:     sectorID,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		},
+	}
+	svc := &Server{
+		Logger:    logger,
+		CageStore: cageStore,
+		ZoneStore: &fakeZoneStore{zone: app.Zone{ID: id}},
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/zones/"+id+"/cages?sectorId="+sectorID+"&status=active", nil)
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", id)
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+	validated(t, svc.ListZoneCages()).ServeHTTP(w, r)
+
+	if want, got := http.StatusOK, w.Code; want != got {
+		t.Fatalf("Expected %d got %d", want, got)
+	}
+	if want, got := id, cageStore.filter.ZoneID; want != got {
+		t.Fatalf("Expected ZoneID %s got %s", want, got)
+	}
+	if want, got := sectorID, cageStore.filter.SectorID; want != got {
+		t.Fatalf("Expected SectorID %s got %s", want, got)
+	}
+	if want, got := app.CageStatusActive, cageStore.filter.Status; want != got {
+		t.Fatalf("Expected Status %s got %s", want, got)
+	}
+
+	response := struct {
+		Data []app.Cage `json:"data"`
+	}{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := 1, len(response.Data); want != got {
+		t.Fatalf("Expected cages %d got %d", want, got)
+	}
+	if want, got := sectorID, response.Data[0].SectorID; want != got {
+		t.Fatalf("Expected SectorID %s got %s", want, got)
+	}
+}
+
+func TestListZoneCagesNotFoundError(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	cageStore := &fakeCageStore{}
+	svc := &Server{
+		Logger:    logger,
+		CageStore: cageStore,
+		ZoneStore: &fakeZoneStore{err: app.ErrNotFound},
+	}
+
+	id := uuid.NewString()
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/zones/"+id+"/cages", nil)
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", id)
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+	validated(t, svc.ListZoneCages()).ServeHTTP(w, r)
+
+	if want, got := http.StatusNotFound, w.Code; want != got {
+		t.Fatalf("Expected %d got %d", want, got)
+	}
+	if want, got := "", cageStore.filter.ZoneID; want != got {
+		t.Fatalf("Expected no cage list got ZoneID %s", got)
+	}
+}
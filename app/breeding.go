@@ -0,0 +1,175 @@
+package app
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// DefaultLineageGenerations is the number of generations in a lineage by default.
+	DefaultLineageGenerations = 3
+	// MaxLineageGenerations limits the generations walked up and down a lineage.
+	// The inbreeding coefficient of a pair is computed over as many generations of ancestors.
+	MaxLineageGenerations = 10
+	// DefaultInbreedingThreshold flags pairs related at least as closely as first cousins.
+	DefaultInbreedingThreshold = 0.0625
+)
+
+// ValidateParents validates the parents of a dinosaur.
+// A dinosaur has up to two distinct parents, none of them being the dinosaur itself.
+func ValidateParents(id string, parentIDs []string) error {
+	if len(parentIDs) > 2 {
+		return errors.New("at most two parents are allowed")
+	}
+
+	for i, parentID := range parentIDs {
+		if err := ValidateID(parentID); err != nil {
+			return err
+		}
+		if parentID == id {
+			return ErrLineageCycle
+		}
+		if i > 0 && parentID == parentIDs[0] {
+			return errors.New("parents must be distinct")
+		}
+	}
+
+	return nil
+}
+
+// Relative is an ancestor or a descendant of a dinosaur.
+type Relative struct {
+	ID        string          `json:"id"`
+	Name      string          `json:"name"`
+	Species   DinosaurSpecies `json:"species"`
+	Stage     LifecycleStage  `json:"stage"`
+	ParentIDs []string        `json:"parentIds"`
+	// Generation is the distance from the dinosaur, 1 for the parents and the children.
+	// A relative reachable along several paths is listed once at the closest generation.
+	Generation int `json:"generation"`
+}
+
+// Lineage is the family tree of a dinosaur.
+type Lineage struct {
+	DinosaurID  string     `json:"dinosaurId"`
+	ParentIDs   []string   `json:"parentIds"`
+	Ancestors   []Relative `json:"ancestors"`
+	Descendants []Relative `json:"descendants"`
+}
+
+// Pedigree maps the dinosaur IDs to the IDs of their known parents.
+// Dinosaurs with unknown parents are assumed to be unrelated founders.
+type Pedigree map[string][]string
+
+// Kinship returns the coefficient of kinship of two dinosaurs, i.e. the probability
+// that alleles picked at random from each of them are identical by descent.
+// The kinship of a pair is the inbreeding coefficient of their offspring.
+func (p Pedigree) Kinship(a, b string) float64 {
+	return p.kinship(a, b, make(map[[2]string]float64))
+}
+
+// Inbreeding returns the inbreeding coefficient of a dinosaur.
+func (p Pedigree) Inbreeding(id string) float64 {
+	parents := p[id]
+	if len(parents) < 2 {
+		return 0
+	}
+
+	return p.Kinship(parents[0], parents[1])
+}
+
+func (p Pedigree) kinship(a, b string, memo map[[2]string]float64) float64 {
+	if a > b {
+		a, b = b, a
+	}
+	key := [2]string{a, b}
+	if k, ok := memo[key]; ok {
+		return k
+	}
+
+	var k float64
+	if a == b {
+		var f float64
+		if parents := p[a]; len(parents) == 2 {
+			f = p.kinship(parents[0], parents[1], memo)
+		}
+		k = (1 + f) / 2
+	} else {
+		// Expand the one that isn't an ancestor of the other,
+		// the pedigree is acyclic so at least one of them isn't.
+		if p.isAncestor(b, a) {
+			a, b = b, a
+		}
+		for _, parent := range p[b] {
+			k += p.kinship(a, parent, memo) / 2
+		}
+	}
+	memo[key] = k
+
+	return k
+}
+
+// isAncestor returns true if the ancestor is found up the pedigree of the dinosaur.
+func (p Pedigree) isAncestor(ancestor, id string) bool {
+	seen := make(map[string]bool)
+	queue := append([]string{}, p[id]...)
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+		if next == ancestor {
+			return true
+		}
+		if seen[next] {
+			continue
+		}
+		seen[next] = true
+		queue = append(queue, p[next]...)
+	}
+
+	return false
+}
+
+// BreedingPair is a proposed pair of dinosaurs for the breeding program.
+type BreedingPair struct {
+	ID      string          `json:"id"`
+	SireID  string          `json:"sireId"`
+	DamID   string          `json:"damId"`
+	Species DinosaurSpecies `json:"species"`
+	// InbreedingCoefficient is the expected inbreeding coefficient of the offspring.
+	InbreedingCoefficient float64 `json:"inbreedingCoefficient"`
+	// Flagged is set for the pairs with the inbreeding coefficient over the threshold.
+	Flagged   bool      `json:"flagged"`
+	Notes     string    `json:"notes,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Validate the breeding pair values.
+func (p BreedingPair) Validate() error {
+	if p.SireID == "" {
+		return errors.New("sireId is required")
+	}
+	if err := ValidateID(p.SireID); err != nil {
+		return err
+	}
+
+	if p.DamID == "" {
+		return errors.New("damId is required")
+	}
+	if err := ValidateID(p.DamID); err != nil {
+		return err
+	}
+
+	if p.SireID == p.DamID {
+		return errors.New("sireId and damId must be distinct")
+	}
+
+	return nil
+}
+
+// BreedingPairFilter narrows down a list of breeding pairs.
+type BreedingPairFilter struct {
+	// DinosaurID lists the pairs the dinosaur is a sire or a dam of.
+	DinosaurID string
+	// FlaggedOnly lists only the pairs flagged as inbred.
+	FlaggedOnly bool
+}
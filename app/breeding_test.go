@@ -0,0 +1,84 @@
+//go:build unit
+// +build unit
+
+package app
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPedigreeKinship(t *testing.T) {
+	// Founders a, b, c and d. e and f are full siblings, g is their half sibling.
+	// h and i are first cousins, j is the offspring of the full siblings.
+	pedigree := Pedigree{
+		"e": {"a", "b"},
+		"f": {"a", "b"},
+		"g": {"a", "c"},
+		"h": {"e", "d"},
+		"i": {"f", "c"},
+		"j": {"e", "f"},
+	}
+
+	tests := []struct {
+		desc    string
+		a, b    string
+		kinship float64
+	}{
+		{"unrelated", "a", "b", 0},
+		{"self", "a", "a", 0.5},
+		{"parent and offspring", "a", "e", 0.25},
+		{"full siblings", "e", "f", 0.25},
+		{"half siblings", "e", "g", 0.125},
+		{"first cousins", "h", "i", 0.0625},
+		{"inbred self", "j", "j", 0.625},
+		{"unknown", "x", "y", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if want, got := tt.kinship, pedigree.Kinship(tt.a, tt.b); math.Abs(want-got) > 1e-9 {
+				t.Errorf("Expected %v got %v", want, got)
+			}
+			if want, got := tt.kinship, pedigree.Kinship(tt.b, tt.a); math.Abs(want-got) > 1e-9 {
+				t.Errorf("Expected symmetric %v got %v", want, got)
+			}
+		})
+	}
+
+	if want, got := 0.25, pedigree.Inbreeding("j"); math.Abs(want-got) > 1e-9 {
+		t.Errorf("Expected inbreeding %v got %v", want, got)
+	}
+	if want, got := 0.0, pedigree.Inbreeding("e"); want != got {
+		t.Errorf("Expected inbreeding %v got %v", want, got)
+	}
+}
+
+func TestValidateParents(t *testing.T) {
+	id := "6e1a2c6e-2c5f-4c1c-9d3b-111111111111"
+	first := "6e1a2c6e-2c5f-4c1c-9d3b-222222222222"
+	second := "6e1a2c6e-2c5f-4c1c-9d3b-333333333333"
+
+	tests := []struct {
+		desc    string
+		parents []string
+		valid   bool
+	}{
+		{"none", nil, true},
+		{"one", []string{first}, true},
+		{"two", []string{first, second}, true},
+		{"three", []string{first, second, id}, false},
+		{"self", []string{id}, false},
+		{"duplicate", []string{first, first}, false},
+		{"invalid", []string{"foo"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			err := ValidateParents(id, tt.parents)
+			if want, got := tt.valid, err == nil; want != got {
+				t.Errorf("Expected %t got %t: %v", want, got, err)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package app
 
 import (
 	"errors"
+	"slices"
 	"time"
 )
 
@@ -29,12 +30,221 @@ func (s CageStatus) IsUnspecified() bool {
 	return s == CageStatusUnspecified
 }
 
+// CageType represents a cage habitat type.
+type CageType string
+
+const (
+	CageTypeUnspecified CageType = ""
+	// CageTypeGeneral is a general purpose cage that accepts any species.
+	// Cages created before the cage types were introduced are general.
+	CageTypeGeneral      CageType = "general"
+	CageTypePaddock      CageType = "paddock"
+	CageTypeAquatic      CageType = "aquatic"
+	CageTypeAviary       CageType = "aviary"
+	CageTypeHighSecurity CageType = "high-security"
+)
+
+// Validate the cage type value.
+func (t CageType) Validate() error {
+	switch t {
+	case CageTypeGeneral, CageTypePaddock, CageTypeAquatic, CageTypeAviary, CageTypeHighSecurity:
+		return nil
+	default:
+		return errors.New("invalid type")
+	}
+}
+
+// Allows returns true if the species can live in a cage of the type.
+func (t CageType) Allows(species DinosaurSpecies) bool {
+	return t == CageTypeGeneral || slices.Contains(species.CageTypes(), t)
+}
+
+// CapacityUnit represents the unit a cage capacity is expressed in.
+type CapacityUnit string
+
+const (
+	CapacityUnitUnspecified CapacityUnit = ""
+	// CapacityUnitHeads counts every dinosaur as one.
+	// Cages created before the space units were introduced count heads.
+	CapacityUnitHeads CapacityUnit = "heads"
+	// CapacityUnitSpace counts the space weight of every dinosaur.
+	CapacityUnitSpace CapacityUnit = "space"
+)
+
+// Validate the capacity unit value.
+func (u CapacityUnit) Validate() error {
+	switch u {
+	case CapacityUnitHeads, CapacityUnitSpace:
+		return nil
+	default:
+		return errors.New("invalid capacity unit")
+	}
+}
+
+// Size returns how many units of the capacity a dinosaur of the species takes.
+func (u CapacityUnit) Size(species DinosaurSpecies) int {
+	if u == CapacityUnitSpace {
+		return species.Weight()
+	}
+
+	return 1
+}
+
+// Occupancy returns the occupancy in the unit given the head count and the space used.
+func (u CapacityUnit) Occupancy(headCount, spaceUsed int) int {
+	if u == CapacityUnitSpace {
+		return spaceUsed
+	}
+
+	return headCount
+}
+
 // Cage represents a cage.
+// Capacity and Occupancy are expressed in CapacityUnit,
+// HeadCount and SpaceUsed report both regardless of the unit.
+// Quarantine cages take only quarantined dinosaurs.
 type Cage struct {
-	ID        string     `json:"id"`
-	Status    CageStatus `json:"status"`
-	Capacity  int        `json:"capacity"`
-	Occupancy int        `json:"occupancy"`
+	ID           string       `json:"id"`
+	Type         CageType     `json:"type"`
+	Quarantine   bool         `json:"quarantine"`
+	Status       CageStatus   `json:"status"`
+	Capacity     int          `json:"capacity"`
+	CapacityUnit CapacityUnit `json:"capacityUnit"`
+	Occupancy    int          `json:"occupancy"`
+	HeadCount    int          `json:"headCount"`
+	SpaceUsed    int          `json:"spaceUsed"`
+	SectorID     string       `json:"sectorId,omitempty"`
+	CreatedAt    time.Time    `json:"createdAt"`
+	UpdatedAt    time.Time    `json:"updatedAt"`
+	DeletedAt    *time.Time   `json:"deletedAt,omitempty"`
+}
+
+// CageOccupancy represents an occupancy level of a cage.
+type CageOccupancy string
+
+const (
+	CageOccupancyUnspecified CageOccupancy = ""
+	CageOccupancyEmpty       CageOccupancy = "empty"
+	CageOccupancyFull        CageOccupancy = "full"
+)
+
+// Validate the cage occupancy value.
+func (o CageOccupancy) Validate() error {
+	switch o {
+	case CageOccupancyEmpty, CageOccupancyFull:
+		return nil
+	default:
+		return errors.New("invalid occupancy")
+	}
+}
+
+// CageFields is a list of cage fields that can be selected and sorted by.
+var CageFields = []string{
+	"id", "type", "quarantine", "status", "capacity", "capacityUnit", "occupancy", "headCount", "spaceUsed",
+	"sectorId", "createdAt", "updatedAt", "deletedAt",
+}
+
+// CageFilter narrows down a list of cages.
+type CageFilter struct {
+	// IDs lists only the cages with the ids.
+	IDs    []string
+	Type   CageType
+	Status CageStatus
+	// Quarantine lists only the quarantine cages if true and only the regular ones if false.
+	Quarantine *bool
+	// ZoneID lists cages in any sector of the zone.
+	ZoneID   string
+	SectorID string
+	// MinFreeCapacity lists cages that can take at least that many dinosaurs.
+	MinFreeCapacity int
+	Occupancy       CageOccupancy
+	Created         TimeRange
+	Updated         TimeRange
+	// Sort is a sort order on CageFields.
+	Sort []Sort
+	// Fields limits the cage fields to a subset of CageFields, all by default.
+	Fields []string
+	// IncludeDeleted includes the soft deleted cages.
+	IncludeDeleted bool
+}
+
+// CagePatch is a partial update of a cage.
+// Only the non-nil fields are changed, an empty SectorID removes the cage from its sector.
+type CagePatch struct {
+	Type         *CageType
+	Status       *CageStatus
+	Capacity     *int
+	CapacityUnit *CapacityUnit
+	SectorID     *string
+	Quarantine   *bool
+}
+
+// IsEmpty returns true if the patch doesn't change anything.
+func (p CagePatch) IsEmpty() bool {
+	return p.Type == nil && p.Status == nil && p.Capacity == nil && p.CapacityUnit == nil && p.SectorID == nil &&
+		p.Quarantine == nil
+}
+
+// Validate the cage patch values.
+func (p CagePatch) Validate() error {
+	if p.Type != nil {
+		if err := p.Type.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if p.Status != nil {
+		if err := p.Status.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if p.Capacity != nil {
+		if err := ValidateCapacity(*p.Capacity); err != nil {
+			return err
+		}
+	}
+
+	if p.CapacityUnit != nil {
+		if err := p.CapacityUnit.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if p.SectorID != nil && *p.SectorID != "" {
+		return ValidateID(*p.SectorID)
+	}
+
+	return nil
+}
+
+// ValidateCapacity validates a cage capacity value.
+func ValidateCapacity(capacity int) error {
+	if capacity <= 0 {
+		return errors.New("invalid capacity")
+	}
+
+	return nil
+}
+
+// CageChange is the kind of change recorded in the cage history.
+type CageChange string
+
+const (
+	CageChangeStatus       CageChange = "status"
+	CageChangeCapacity     CageChange = "capacity"
+	CageChangeSector       CageChange = "sector"
+	CageChangeType         CageChange = "type"
+	CageChangeCapacityUnit CageChange = "capacityUnit"
+	CageChangeQuarantine   CageChange = "quarantine"
+)
+
+// CageHistoryEntry is a recorded change of a cage.
+type CageHistoryEntry struct {
+	ID        int64      `json:"id"`
+	CageID    string     `json:"cageId"`
+	Change    CageChange `json:"change"`
+	From      string     `json:"from"`
+	To        string     `json:"to"`
 	CreatedAt time.Time  `json:"createdAt"`
-	UpdatedAt time.Time  `json:"updatedAt"`
 }
@@ -44,3 +44,97 @@ func TestCageStatusIsUnspecified(t *testing.T) {
 		})
 	}
 }
+
+func TestCageTypeValidate(t *testing.T) {
+	tests := []struct {
+		cageType CageType
+		valid    bool
+	}{
+		{CageTypeGeneral, true},
+		{CageTypePaddock, true},
+		{CageTypeAquatic, true},
+		{CageTypeAviary, true},
+		{CageTypeHighSecurity, true},
+		{CageTypeUnspecified, false},
+		{CageType("foo"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.cageType), func(t *testing.T) {
+			err := tt.cageType.Validate()
+			if want, got := tt.valid, err == nil; want != got {
+				t.Errorf("Expected %t got %t", want, got)
+			}
+		})
+	}
+}
+
+func TestCageTypeAllows(t *testing.T) {
+	tests := []struct {
+		cageType CageType
+		species  DinosaurSpecies
+		allowed  bool
+	}{
+		{CageTypeGeneral, DinosaurSpeciesTyrannosaurus, true},
+		{CageTypeGeneral, DinosaurSpeciesTriceratops, true},
+		{CageTypeHighSecurity, DinosaurSpeciesVelociraptor, true},
+		{CageTypeHighSecurity, DinosaurSpeciesSpinosaurus, true},
+		{CageTypeAquatic, DinosaurSpeciesSpinosaurus, true},
+		{CageTypeAquatic, DinosaurSpeciesTyrannosaurus, false},
+		{CageTypePaddock, DinosaurSpeciesStegosaurus, true},
+		{CageTypePaddock, DinosaurSpeciesMegalosaurus, false},
+		{CageTypeHighSecurity, DinosaurSpeciesBrachiosaurus, false},
+		{CageTypeAviary, DinosaurSpeciesAnkylosaurus, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.cageType)+"/"+string(tt.species), func(t *testing.T) {
+			if want, got := tt.allowed, tt.cageType.Allows(tt.species); want != got {
+				t.Errorf("Expected %t got %t", want, got)
+			}
+		})
+	}
+}
+
+func TestCapacityUnitValidate(t *testing.T) {
+	tests := []struct {
+		unit  CapacityUnit
+		valid bool
+	}{
+		{CapacityUnitHeads, true},
+		{CapacityUnitSpace, true},
+		{CapacityUnitUnspecified, false},
+		{CapacityUnit("tons"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.unit), func(t *testing.T) {
+			err := tt.unit.Validate()
+			if want, got := tt.valid, err == nil; want != got {
+				t.Errorf("Expected %t got %t", want, got)
+			}
+		})
+	}
+}
+
+func TestCapacityUnitSizeAndOccupancy(t *testing.T) {
+	tests := []struct {
+		unit      CapacityUnit
+		size      int
+		occupancy int
+	}{
+		{CapacityUnitHeads, 1, 2},
+		{CapacityUnitSpace, 4, 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.unit), func(t *testing.T) {
+			if want, got := tt.size, tt.unit.Size(DinosaurSpeciesTriceratops); want != got {
+				t.Errorf("Expected size %d got %d", want, got)
+			}
+			if want, got := tt.occupancy, tt.unit.Occupancy(2, 7); want != got {
+				t.Errorf("Expected occupancy %d got %d", want, got)
+			}
+		})
+	}
+}
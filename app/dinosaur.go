@@ -30,6 +30,40 @@ const (
 	DinosaurTypeHerbivore DinosaurType = "herbivore"
 )
 
+// Validate the dinosaur type value.
+func (t DinosaurType) Validate() error {
+	switch t {
+	case DinosaurTypeCarnivore, DinosaurTypeHerbivore:
+		return nil
+	default:
+		return errors.New("invalid diet")
+	}
+}
+
+// Species returns all species of the dinosaur type.
+func (t DinosaurType) Species() []DinosaurSpecies {
+	var species []DinosaurSpecies
+	for _, s := range AllDinosaurSpecies {
+		if s.Type() == t {
+			species = append(species, s)
+		}
+	}
+
+	return species
+}
+
+// AllDinosaurSpecies is a list of all supported dinosaur species.
+var AllDinosaurSpecies = []DinosaurSpecies{
+	DinosaurSpeciesTyrannosaurus,
+	DinosaurSpeciesVelociraptor,
+	DinosaurSpeciesSpinosaurus,
+	DinosaurSpeciesMegalosaurus,
+	DinosaurSpeciesBrachiosaurus,
+	DinosaurSpeciesStegosaurus,
+	DinosaurSpeciesAnkylosaurus,
+	DinosaurSpeciesTriceratops,
+}
+
 // Validate the dinosaur species value.
 func (s DinosaurSpecies) Validate() error {
 	switch s {
@@ -70,12 +104,128 @@ func (s DinosaurSpecies) Type() DinosaurType {
 	}
 }
 
+// Weight returns the space weight of the species,
+// i.e. how many space units it takes in a cage.
+func (s DinosaurSpecies) Weight() int {
+	switch s {
+	case DinosaurSpeciesVelociraptor:
+		return 1
+	case DinosaurSpeciesMegalosaurus,
+		DinosaurSpeciesStegosaurus,
+		DinosaurSpeciesAnkylosaurus:
+		return 3
+	case DinosaurSpeciesTriceratops:
+		return 4
+	case DinosaurSpeciesTyrannosaurus,
+		DinosaurSpeciesSpinosaurus:
+		return 5
+	case DinosaurSpeciesBrachiosaurus:
+		return 8
+	default:
+		panic("invalid species " + s)
+	}
+}
+
+// CageTypes returns the types of the cages the species can live in
+// besides the general purpose ones.
+func (s DinosaurSpecies) CageTypes() []CageType {
+	switch s {
+	case DinosaurSpeciesTyrannosaurus,
+		DinosaurSpeciesVelociraptor,
+		DinosaurSpeciesMegalosaurus:
+		return []CageType{CageTypeHighSecurity}
+	case DinosaurSpeciesSpinosaurus:
+		return []CageType{CageTypeAquatic, CageTypeHighSecurity}
+	case DinosaurSpeciesBrachiosaurus,
+		DinosaurSpeciesStegosaurus,
+		DinosaurSpeciesAnkylosaurus,
+		DinosaurSpeciesTriceratops:
+		return []CageType{CageTypePaddock}
+	default:
+		return nil
+	}
+}
+
 // Dinosaur represents a dinosaur.
 type Dinosaur struct {
-	ID        string          `json:"id"`
-	Name      string          `json:"name"`
-	Species   DinosaurSpecies `json:"species"`
-	CageID    string          `json:"cageId"`
-	CreatedAt time.Time       `json:"createdAt"`
-	UpdatedAt time.Time       `json:"updatedAt"`
+	ID      string          `json:"id"`
+	Name    string          `json:"name"`
+	Species DinosaurSpecies `json:"species"`
+	// CageID is empty unless the dinosaur lives in a cage at its lifecycle stage.
+	CageID string         `json:"cageId,omitempty"`
+	Stage  LifecycleStage `json:"stage"`
+	// HatchDate is the expected hatch date of an egg and the actual one once hatched.
+	HatchDate *time.Time `json:"hatchDate,omitempty"`
+	// HealthStatus is set by the health records, healthy by default.
+	HealthStatus HealthStatus `json:"healthStatus"`
+	// Quarantined dinosaurs live in quarantine cages, alone or with their own species.
+	// The reason and the period are set only while the dinosaur is quarantined.
+	Quarantined      bool       `json:"quarantined"`
+	QuarantineReason string     `json:"quarantineReason,omitempty"`
+	QuarantineStart  *time.Time `json:"quarantineStart,omitempty"`
+	QuarantineEnd    *time.Time `json:"quarantineEnd,omitempty"`
+	CreatedAt        time.Time  `json:"createdAt"`
+	UpdatedAt        time.Time  `json:"updatedAt"`
+	DeletedAt        *time.Time `json:"deletedAt,omitempty"`
+}
+
+// DinosaurFields is a list of dinosaur fields that can be selected and sorted by.
+var DinosaurFields = []string{
+	"id", "name", "species", "cageId", "stage", "hatchDate", "healthStatus",
+	"quarantined", "quarantineReason", "quarantineStart", "quarantineEnd",
+	"createdAt", "updatedAt", "deletedAt",
+}
+
+// DinosaurFilter narrows down a list of dinosaurs.
+type DinosaurFilter struct {
+	CageID string
+	// CageIDs lists dinosaurs in any of the cages.
+	CageIDs []string
+	// ZoneID lists dinosaurs in any cage of the zone.
+	ZoneID string
+	// Species lists dinosaurs of any of the species.
+	Species []DinosaurSpecies
+	// Diet lists dinosaurs of all the species of the type.
+	Diet DinosaurType
+	// Stages lists dinosaurs at any of the lifecycle stages.
+	Stages       []LifecycleStage
+	HealthStatus HealthStatus
+	// Quarantined lists only the quarantined dinosaurs if true and only the others if false.
+	Quarantined *bool
+	NamePrefix  string
+	// HatchDate lists dinosaurs with the hatch date in the range.
+	HatchDate TimeRange
+	Created   TimeRange
+	Updated   TimeRange
+	// Sort is a sort order on DinosaurFields.
+	Sort []Sort
+	// Fields limits the dinosaur fields to a subset of DinosaurFields, all by default.
+	Fields []string
+	// IncludeDeleted includes the soft deleted dinosaurs.
+	IncludeDeleted bool
+}
+
+// DinosaurPatch is a partial update of a dinosaur.
+// Only the non-nil fields are changed.
+type DinosaurPatch struct {
+	Name   *string
+	CageID *string
+}
+
+// IsEmpty returns true if the patch doesn't change anything.
+func (p DinosaurPatch) IsEmpty() bool {
+	return p.Name == nil && p.CageID == nil
+}
+
+// Validate the dinosaur patch values.
+func (p DinosaurPatch) Validate() error {
+	if p.Name != nil && *p.Name == "" {
+		return errors.New("name is required")
+	}
+
+	if p.CageID != nil {
+		return ValidateID(*p.CageID)
+	}
+
+	return nil
 }
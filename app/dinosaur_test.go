@@ -65,3 +65,111 @@ func TestDinosaurSpeciesTypePanicsOnInvalidSpecies(t *testing.T) {
 
 	_ = DinosaurSpecies("foo").Type()
 }
+
+func TestDinosaurTypeSpecies(t *testing.T) {
+	tests := []struct {
+		speciesType DinosaurType
+		species     []DinosaurSpecies
+	}{
+		{DinosaurTypeCarnivore, []DinosaurSpecies{
+			DinosaurSpeciesTyrannosaurus,
+			DinosaurSpeciesVelociraptor,
+			DinosaurSpeciesSpinosaurus,
+			DinosaurSpeciesMegalosaurus,
+		}},
+		{DinosaurTypeHerbivore, []DinosaurSpecies{
+			DinosaurSpeciesBrachiosaurus,
+			DinosaurSpeciesStegosaurus,
+			DinosaurSpeciesAnkylosaurus,
+			DinosaurSpeciesTriceratops,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.speciesType), func(t *testing.T) {
+			if err := tt.speciesType.Validate(); err != nil {
+				t.Fatal(err)
+			}
+
+			species := tt.speciesType.Species()
+			if want, got := len(tt.species), len(species); want != got {
+				t.Fatalf("Expected %d species got %d", want, got)
+			}
+			for i := range species {
+				if want, got := tt.species[i], species[i]; want != got {
+					t.Errorf("Expected %s got %s", want, got)
+				}
+			}
+		})
+	}
+
+	if err := DinosaurType("omnivore").Validate(); err == nil {
+		t.Error("Expected error")
+	}
+}
+
+func TestDinosaurSpeciesWeight(t *testing.T) {
+	for _, species := range AllDinosaurSpecies {
+		t.Run(string(species), func(t *testing.T) {
+			if got := species.Weight(); got < 1 {
+				t.Errorf("Expected a positive weight got %d", got)
+			}
+		})
+	}
+
+	if want, got := 8, DinosaurSpeciesBrachiosaurus.Weight(); want != got {
+		t.Errorf("Expected %d got %d", want, got)
+	}
+	if want, got := 1, DinosaurSpeciesVelociraptor.Weight(); want != got {
+		t.Errorf("Expected %d got %d", want, got)
+	}
+}
+
+func TestHealthStatusValidate(t *testing.T) {
+	tests := []struct {
+		status HealthStatus
+		valid  bool
+	}{
+		{HealthStatusHealthy, true},
+		{HealthStatusUnderObservation, true},
+		{HealthStatusSick, true},
+		{HealthStatusUnspecified, false},
+		{HealthStatus("dead"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			err := tt.status.Validate()
+			if want, got := tt.valid, err == nil; want != got {
+				t.Errorf("Expected %t got %t", want, got)
+			}
+		})
+	}
+}
+
+func TestHealthRecordValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		record HealthRecord
+		valid  bool
+	}{
+		{"checkup", HealthRecord{Kind: HealthRecordKindCheckup, Author: "Dr. Harding"}, true},
+		{"weight", HealthRecord{Kind: HealthRecordKindWeight, Author: "Dr. Harding", WeightKg: 7200.5}, true},
+		{"status", HealthRecord{Kind: HealthRecordKindDiagnosis, Author: "Dr. Harding", Status: HealthStatusSick}, true},
+		{"no kind", HealthRecord{Author: "Dr. Harding"}, false},
+		{"invalid kind", HealthRecord{Kind: HealthRecordKind("surgery"), Author: "Dr. Harding"}, false},
+		{"no author", HealthRecord{Kind: HealthRecordKindNote}, false},
+		{"no weight", HealthRecord{Kind: HealthRecordKindWeight, Author: "Dr. Harding"}, false},
+		{"weight on a checkup", HealthRecord{Kind: HealthRecordKindCheckup, Author: "Dr. Harding", WeightKg: 7200}, false},
+		{"invalid status", HealthRecord{Kind: HealthRecordKindDiagnosis, Author: "Dr. Harding", Status: HealthStatus("dead")}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.record.Validate()
+			if want, got := tt.valid, err == nil; want != got {
+				t.Errorf("Expected %t got %t: %v", want, got, err)
+			}
+		})
+	}
+}
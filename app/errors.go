@@ -1,6 +1,9 @@
 package app
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 // List of application errors.
 var (
@@ -9,4 +12,48 @@ var (
 	ErrCapacityExceeded = errors.New("capacity exceeded")
 	ErrCagePoweredDown  = errors.New("cage powered down")
 	ErrSpeciesMismatch  = errors.New("species mismatch")
+	// ErrCapacityBelowOccupancy is returned when a cage capacity
+	// is lowered below the number of dinosaurs in it.
+	ErrCapacityBelowOccupancy = errors.New("capacity below occupancy")
+	// ErrHabitatMismatch is returned when a dinosaur is admitted
+	// into a cage of a type its species can't live in.
+	ErrHabitatMismatch = errors.New("habitat mismatch")
+	// ErrSectorNotFound is returned when a cage is placed
+	// into a sector that doesn't exist.
+	ErrSectorNotFound = errors.New("sector not found")
+	// ErrQuarantineMismatch is returned when a quarantined dinosaur is admitted
+	// into a regular cage or a dinosaur that isn't quarantined into a quarantine cage.
+	ErrQuarantineMismatch = errors.New("quarantine mismatch")
+	// ErrIllegalTransition is returned when a dinosaur can't transition
+	// from its lifecycle stage to the requested one.
+	ErrIllegalTransition = errors.New("illegal lifecycle transition")
+	// ErrNotInCage is returned when an egg, a deceased or a transferred out
+	// dinosaur is moved around the cages.
+	ErrNotInCage = errors.New("dinosaur not in a cage")
+	// ErrLineageCycle is returned when a dinosaur would become its own ancestor.
+	ErrLineageCycle = errors.New("lineage cycle")
+	// ErrNotAdult is returned when a dinosaur that isn't an adult is paired for breeding.
+	ErrNotAdult = errors.New("dinosaur not an adult")
+	// ErrNotCertified is returned when a staff member without the carnivore handling
+	// certification is assigned to a cage holding carnivores.
+	ErrNotCertified = errors.New("carnivore handling certification required")
+	// ErrCageLockedDown is returned when a dinosaur is admitted
+	// into a cage with an open critical containment incident.
+	ErrCageLockedDown = errors.New("cage locked down")
+	// ErrIncidentResolved is returned when a resolved incident is changed.
+	ErrIncidentResolved = errors.New("incident resolved")
 )
+
+// OccupancyError is an ErrCapacityBelowOccupancy error
+// that carries the current occupancy of the cage.
+type OccupancyError struct {
+	Occupancy int
+}
+
+func (e *OccupancyError) Error() string {
+	return fmt.Sprintf("%s of %d", ErrCapacityBelowOccupancy, e.Occupancy)
+}
+
+func (e *OccupancyError) Unwrap() error {
+	return ErrCapacityBelowOccupancy
+}
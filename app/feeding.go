@@ -0,0 +1,143 @@
+package app
+
+import (
+	"errors"
+	"time"
+)
+
+// FeedingSchedule represents a feeding schedule of either a cage or a species.
+// A cage schedule applies to the current occupants of the cage,
+// a species one to all dinosaurs of the species wherever they are.
+type FeedingSchedule struct {
+	ID      string          `json:"id"`
+	CageID  string          `json:"cageId,omitempty"`
+	Species DinosaurSpecies `json:"species,omitempty"`
+	// Diet is what is fed, e.g. goats.
+	Diet string `json:"diet"`
+	// Quantity is the amount in kilograms per dinosaur per feeding.
+	Quantity      float64 `json:"quantity"`
+	IntervalHours int     `json:"intervalHours"`
+	// WindowStart and WindowEnd optionally limit the feedings to a time of day, e.g. 08:00-10:00 UTC.
+	WindowStart string    `json:"windowStart,omitempty"`
+	WindowEnd   string    `json:"windowEnd,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// Validate the feeding schedule values.
+func (s FeedingSchedule) Validate() error {
+	switch {
+	case s.CageID == "" && s.Species.IsUnspecified():
+		return errors.New("cageId or species is required")
+	case s.CageID != "" && !s.Species.IsUnspecified():
+		return errors.New("cageId and species are mutually exclusive")
+	case s.CageID != "":
+		if err := ValidateID(s.CageID); err != nil {
+			return err
+		}
+	default:
+		if err := s.Species.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if err := validateFood(s.Diet, s.Quantity); err != nil {
+		return err
+	}
+
+	if s.IntervalHours < 1 {
+		return errors.New("invalid intervalHours")
+	}
+
+	if s.WindowStart == "" && s.WindowEnd == "" {
+		return nil
+	}
+	start, err := time.Parse(timeOfDayLayout, s.WindowStart)
+	if err != nil {
+		return errors.New("invalid windowStart")
+	}
+	end, err := time.Parse(timeOfDayLayout, s.WindowEnd)
+	if err != nil {
+		return errors.New("invalid windowEnd")
+	}
+	if !start.Before(end) {
+		return errors.New("windowStart must be before windowEnd")
+	}
+
+	return nil
+}
+
+// timeOfDayLayout is the layout of the feeding window boundaries.
+const timeOfDayLayout = "15:04"
+
+// FeedingScheduleFilter narrows down a list of feeding schedules.
+type FeedingScheduleFilter struct {
+	CageID  string
+	Species DinosaurSpecies
+}
+
+// Feeding represents a recorded feeding of a dinosaur or of all occupants of a cage.
+type Feeding struct {
+	ID         string  `json:"id"`
+	CageID     string  `json:"cageId"`
+	DinosaurID string  `json:"dinosaurId,omitempty"`
+	Diet       string  `json:"diet"`
+	Quantity   float64 `json:"quantity"`
+	// Unsafe is set for the feedings of carnivores in a powered down cage.
+	Unsafe    bool      `json:"unsafe"`
+	FedAt     time.Time `json:"fedAt"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Validate the feeding values.
+// The cage of a dinosaur feeding is the current cage of the dinosaur.
+func (f Feeding) Validate() error {
+	switch {
+	case f.CageID == "" && f.DinosaurID == "":
+		return errors.New("cageId or dinosaurId is required")
+	case f.CageID != "" && f.DinosaurID != "":
+		return errors.New("cageId and dinosaurId are mutually exclusive")
+	case f.CageID != "":
+		if err := ValidateID(f.CageID); err != nil {
+			return err
+		}
+	default:
+		if err := ValidateID(f.DinosaurID); err != nil {
+			return err
+		}
+	}
+
+	return validateFood(f.Diet, f.Quantity)
+}
+
+// FeedingFilter narrows down a list of feedings.
+type FeedingFilter struct {
+	CageID     string
+	DinosaurID string
+	// UnsafeOnly lists only the feedings flagged as unsafe.
+	UnsafeOnly bool
+	Fed        TimeRange
+}
+
+// OverdueFeeding is a dinosaur that wasn't fed within the interval of a schedule.
+type OverdueFeeding struct {
+	ScheduleID string          `json:"scheduleId"`
+	DinosaurID string          `json:"dinosaurId"`
+	Name       string          `json:"name"`
+	Species    DinosaurSpecies `json:"species"`
+	CageID     string          `json:"cageId"`
+	LastFedAt  *time.Time      `json:"lastFedAt,omitempty"`
+	DueAt      time.Time       `json:"dueAt"`
+}
+
+func validateFood(diet string, quantity float64) error {
+	if diet == "" {
+		return errors.New("diet is required")
+	}
+
+	if quantity <= 0 {
+		return errors.New("invalid quantity")
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+//go:build unit
+// +build unit
+
+package app
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestFeedingScheduleValidate(t *testing.T) {
+	cageID := uuid.NewString()
+
+	tests := []struct {
+		name     string
+		schedule FeedingSchedule
+		valid    bool
+	}{
+		{"cage", FeedingSchedule{CageID: cageID, Diet: "goats", Quantity: 50, IntervalHours: 24}, true},
+		{"species", FeedingSchedule{Species: DinosaurSpeciesTriceratops, Diet: "ferns", Quantity: 0.5, IntervalHours: 1}, true},
+		{"window", FeedingSchedule{CageID: cageID, Diet: "goats", Quantity: 50, IntervalHours: 24, WindowStart: "08:00", WindowEnd: "10:30"}, true},
+		{"no cage or species", FeedingSchedule{Diet: "goats", Quantity: 50, IntervalHours: 24}, false},
+		{"cage and species", FeedingSchedule{CageID: cageID, Species: DinosaurSpeciesTriceratops, Diet: "goats", Quantity: 50, IntervalHours: 24}, false},
+		{"invalid cage", FeedingSchedule{CageID: "foo", Diet: "goats", Quantity: 50, IntervalHours: 24}, false},
+		{"invalid species", FeedingSchedule{Species: DinosaurSpecies("dodo"), Diet: "goats", Quantity: 50, IntervalHours: 24}, false},
+		{"no diet", FeedingSchedule{CageID: cageID, Quantity: 50, IntervalHours: 24}, false},
+		{"zero quantity", FeedingSchedule{CageID: cageID, Diet: "goats", IntervalHours: 24}, false},
+		{"zero interval", FeedingSchedule{CageID: cageID, Diet: "goats", Quantity: 50}, false},
+		{"window start only", FeedingSchedule{CageID: cageID, Diet: "goats", Quantity: 50, IntervalHours: 24, WindowStart: "08:00"}, false},
+		{"invalid window", FeedingSchedule{CageID: cageID, Diet: "goats", Quantity: 50, IntervalHours: 24, WindowStart: "8am", WindowEnd: "10:00"}, false},
+		{"empty window", FeedingSchedule{CageID: cageID, Diet: "goats", Quantity: 50, IntervalHours: 24, WindowStart: "10:00", WindowEnd: "10:00"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.schedule.Validate()
+			if want, got := tt.valid, err == nil; want != got {
+				t.Errorf("Expected %t got %t: %v", want, got, err)
+			}
+		})
+	}
+}
+
+func TestFeedingValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		feeding Feeding
+		valid   bool
+	}{
+		{"cage", Feeding{CageID: uuid.NewString(), Diet: "goats", Quantity: 50}, true},
+		{"dinosaur", Feeding{DinosaurID: uuid.NewString(), Diet: "goats", Quantity: 50}, true},
+		{"no cage or dinosaur", Feeding{Diet: "goats", Quantity: 50}, false},
+		{"cage and dinosaur", Feeding{CageID: uuid.NewString(), DinosaurID: uuid.NewString(), Diet: "goats", Quantity: 50}, false},
+		{"invalid dinosaur", Feeding{DinosaurID: "foo", Diet: "goats", Quantity: 50}, false},
+		{"no diet", Feeding{CageID: uuid.NewString(), Quantity: 50}, false},
+		{"negative quantity", Feeding{CageID: uuid.NewString(), Diet: "goats", Quantity: -1}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.feeding.Validate()
+			if want, got := tt.valid, err == nil; want != got {
+				t.Errorf("Expected %t got %t: %v", want, got, err)
+			}
+		})
+	}
+}
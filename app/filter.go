@@ -0,0 +1,77 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// TimeRange narrows down a list by a timestamp.
+// Zero bounds are not applied.
+type TimeRange struct {
+	After  time.Time
+	Before time.Time
+}
+
+// IsZero returns true if none of the bounds is set.
+func (r TimeRange) IsZero() bool {
+	return r.After.IsZero() && r.Before.IsZero()
+}
+
+// Sort represents a sort order on a field.
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// String returns the sort in the query format, i.e. field or -field.
+func (s Sort) String() string {
+	if s.Desc {
+		return "-" + s.Field
+	}
+
+	return s.Field
+}
+
+// ParseSort parses a comma separated list of fields into a sort order.
+// A field prefixed with - is sorted in descending order.
+// Only the allowed fields are accepted.
+func ParseSort(value string, allowed []string) ([]Sort, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var sort []Sort
+	for _, field := range strings.Split(value, ",") {
+		var s Sort
+		s.Field, s.Desc = strings.CutPrefix(field, "-")
+		if !slices.Contains(allowed, s.Field) {
+			return nil, fmt.Errorf("invalid sort field %q", s.Field)
+		}
+
+		sort = append(sort, s)
+	}
+
+	return sort, nil
+}
+
+// ParseFields parses a comma separated list of fields.
+// Only the allowed fields are accepted and duplicates are dropped.
+func ParseFields(value string, allowed []string) ([]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var fields []string
+	for _, field := range strings.Split(value, ",") {
+		if !slices.Contains(allowed, field) {
+			return nil, fmt.Errorf("invalid field %q", field)
+		}
+		if !slices.Contains(fields, field) {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,77 @@
+//go:build unit
+// +build unit
+
+package app
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSort(t *testing.T) {
+	allowed := []string{"name", "createdAt"}
+
+	tests := []struct {
+		value string
+		sort  []Sort
+		valid bool
+	}{
+		{"", nil, true},
+		{"name", []Sort{{Field: "name"}}, true},
+		{"-createdAt,name", []Sort{{Field: "createdAt", Desc: true}, {Field: "name"}}, true},
+		{"species", nil, false},
+		{"name,", nil, false},
+		{"--name", nil, false},
+		{"Name", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			sort, err := ParseSort(tt.value, allowed)
+			if want, got := tt.valid, err == nil; want != got {
+				t.Fatalf("Expected %t got %t: %v", want, got, err)
+			}
+			if want, got := tt.sort, sort; !reflect.DeepEqual(want, got) {
+				t.Errorf("Expected %v got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestSortString(t *testing.T) {
+	if want, got := "name", (Sort{Field: "name"}).String(); want != got {
+		t.Errorf("Expected %s got %s", want, got)
+	}
+	if want, got := "-name", (Sort{Field: "name", Desc: true}).String(); want != got {
+		t.Errorf("Expected %s got %s", want, got)
+	}
+}
+
+func TestParseFields(t *testing.T) {
+	allowed := []string{"id", "name", "createdAt"}
+
+	tests := []struct {
+		value  string
+		fields []string
+		valid  bool
+	}{
+		{"", nil, true},
+		{"id", []string{"id"}, true},
+		{"name,id,name", []string{"name", "id"}, true},
+		{"species", nil, false},
+		{"id,", nil, false},
+		{"-id", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			fields, err := ParseFields(tt.value, allowed)
+			if want, got := tt.valid, err == nil; want != got {
+				t.Fatalf("Expected %t got %t: %v", want, got, err)
+			}
+			if want, got := tt.fields, fields; !reflect.DeepEqual(want, got) {
+				t.Errorf("Expected %v got %v", want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package app
+
+import (
+	"errors"
+	"time"
+)
+
+// HealthStatus represents the health status of a dinosaur.
+type HealthStatus string
+
+const (
+	HealthStatusUnspecified HealthStatus = ""
+	// HealthStatusHealthy is the status of the dinosaurs
+	// without health records that set a status.
+	HealthStatusHealthy          HealthStatus = "healthy"
+	HealthStatusUnderObservation HealthStatus = "under-observation"
+	HealthStatusSick             HealthStatus = "sick"
+)
+
+// Validate the health status value.
+func (s HealthStatus) Validate() error {
+	switch s {
+	case HealthStatusHealthy, HealthStatusUnderObservation, HealthStatusSick:
+		return nil
+	default:
+		return errors.New("invalid health status")
+	}
+}
+
+// IsUnspecified returns true if the health status is empty.
+func (s HealthStatus) IsUnspecified() bool {
+	return s == HealthStatusUnspecified
+}
+
+// HealthRecordKind represents a kind of a health record.
+type HealthRecordKind string
+
+const (
+	HealthRecordKindUnspecified HealthRecordKind = ""
+	HealthRecordKindCheckup     HealthRecordKind = "checkup"
+	// HealthRecordKindWeight is a weight measurement, the only kind that carries a weight.
+	HealthRecordKindWeight    HealthRecordKind = "weight"
+	HealthRecordKindDiagnosis HealthRecordKind = "diagnosis"
+	HealthRecordKindTreatment HealthRecordKind = "treatment"
+	HealthRecordKindNote      HealthRecordKind = "note"
+)
+
+// Validate the health record kind value.
+func (k HealthRecordKind) Validate() error {
+	switch k {
+	case HealthRecordKindCheckup, HealthRecordKindWeight, HealthRecordKindDiagnosis, HealthRecordKindTreatment, HealthRecordKindNote:
+		return nil
+	default:
+		return errors.New("invalid kind")
+	}
+}
+
+// HealthRecord is an entry in the veterinary record of a dinosaur.
+type HealthRecord struct {
+	ID         string           `json:"id"`
+	DinosaurID string           `json:"dinosaurId"`
+	Kind       HealthRecordKind `json:"kind"`
+	// Author is the vet or the keeper who made the record.
+	Author string `json:"author"`
+	// WeightKg is the measured weight of a weight record.
+	WeightKg float64 `json:"weightKg,omitempty"`
+	Notes    string  `json:"notes,omitempty"`
+	// Status optionally changes the health status of the dinosaur.
+	// The status of the most recent record that sets one is the current one.
+	Status     HealthStatus `json:"status,omitempty"`
+	RecordedAt time.Time    `json:"recordedAt"`
+	CreatedAt  time.Time    `json:"createdAt"`
+}
+
+// Validate the health record values.
+func (r HealthRecord) Validate() error {
+	if err := r.Kind.Validate(); err != nil {
+		return err
+	}
+
+	if r.Author == "" {
+		return errors.New("author is required")
+	}
+
+	switch {
+	case r.Kind == HealthRecordKindWeight && r.WeightKg <= 0:
+		return errors.New("invalid weightKg")
+	case r.Kind != HealthRecordKindWeight && r.WeightKg != 0:
+		return errors.New("weightKg is only allowed on weight records")
+	}
+
+	if !r.Status.IsUnspecified() {
+		return r.Status.Validate()
+	}
+
+	return nil
+}
+
+// HealthRecordFilter narrows down a list of health records.
+type HealthRecordFilter struct {
+	Kind     HealthRecordKind
+	Recorded TimeRange
+}
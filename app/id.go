@@ -26,3 +26,11 @@ func ValidateID(id string) error {
 
 	return nil
 }
+
+// GetOptions controls how a single resource is fetched.
+type GetOptions struct {
+	// IncludeDeleted allows to get a soft deleted resource.
+	IncludeDeleted bool
+	// Fields limits the resource fields, all by default.
+	Fields []string
+}
@@ -0,0 +1,166 @@
+package app
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+// IncidentType represents the type of an incident.
+type IncidentType string
+
+const (
+	IncidentTypeUnspecified  IncidentType = ""
+	IncidentTypeEscape       IncidentType = "escape"
+	IncidentTypeFenceFailure IncidentType = "fence-failure"
+	IncidentTypeInjury       IncidentType = "injury"
+	IncidentTypeOther        IncidentType = "other"
+)
+
+// Validate the incident type value.
+func (t IncidentType) Validate() error {
+	switch t {
+	case IncidentTypeEscape, IncidentTypeFenceFailure, IncidentTypeInjury, IncidentTypeOther:
+		return nil
+	default:
+		return errors.New("invalid type")
+	}
+}
+
+// ContainmentIncidentTypes are the incident types that breach the cage containment.
+var ContainmentIncidentTypes = []IncidentType{IncidentTypeEscape, IncidentTypeFenceFailure}
+
+// Containment returns true if the incident type is a breach of the cage containment.
+func (t IncidentType) Containment() bool {
+	return slices.Contains(ContainmentIncidentTypes, t)
+}
+
+// IncidentSeverity represents the severity of an incident.
+type IncidentSeverity string
+
+const (
+	IncidentSeverityUnspecified IncidentSeverity = ""
+	IncidentSeverityLow         IncidentSeverity = "low"
+	IncidentSeverityMedium      IncidentSeverity = "medium"
+	IncidentSeverityHigh        IncidentSeverity = "high"
+	IncidentSeverityCritical    IncidentSeverity = "critical"
+)
+
+// Validate the incident severity value.
+func (s IncidentSeverity) Validate() error {
+	switch s {
+	case IncidentSeverityLow, IncidentSeverityMedium, IncidentSeverityHigh, IncidentSeverityCritical:
+		return nil
+	default:
+		return errors.New("invalid severity")
+	}
+}
+
+// IsUnspecified returns true if the incident severity is empty.
+func (s IncidentSeverity) IsUnspecified() bool {
+	return s == IncidentSeverityUnspecified
+}
+
+// IncidentStatus represents the status of an incident.
+type IncidentStatus string
+
+const (
+	IncidentStatusUnspecified IncidentStatus = ""
+	IncidentStatusOpen        IncidentStatus = "open"
+	IncidentStatusResolved    IncidentStatus = "resolved"
+)
+
+// Validate the incident status value.
+func (s IncidentStatus) Validate() error {
+	switch s {
+	case IncidentStatusOpen, IncidentStatusResolved:
+		return nil
+	default:
+		return errors.New("invalid status")
+	}
+}
+
+// IsUnspecified returns true if the incident status is empty.
+func (s IncidentStatus) IsUnspecified() bool {
+	return s == IncidentStatusUnspecified
+}
+
+// Incident represents an incident in a cage, e.g. a fence failure or an escape.
+// An open critical containment incident locks the cage down,
+// no dinosaurs are admitted into it until the incident is resolved.
+type Incident struct {
+	ID          string           `json:"id"`
+	Type        IncidentType     `json:"type"`
+	Severity    IncidentSeverity `json:"severity"`
+	CageID      string           `json:"cageId"`
+	DinosaurIDs []string         `json:"dinosaurIds"`
+	Summary     string           `json:"summary"`
+	Status      IncidentStatus   `json:"status"`
+	Resolution  string           `json:"resolution,omitempty"`
+	// Timeline is only populated for a single incident.
+	Timeline   []IncidentEntry `json:"timeline,omitempty"`
+	OpenedAt   time.Time       `json:"openedAt"`
+	ResolvedAt *time.Time      `json:"resolvedAt,omitempty"`
+	CreatedAt  time.Time       `json:"createdAt"`
+	UpdatedAt  time.Time       `json:"updatedAt"`
+}
+
+// Validate the values of a new incident.
+func (i Incident) Validate() error {
+	if err := i.Type.Validate(); err != nil {
+		return err
+	}
+
+	if err := i.Severity.Validate(); err != nil {
+		return err
+	}
+
+	if err := ValidateID(i.CageID); err != nil {
+		return errors.New("invalid cageId")
+	}
+
+	for j, id := range i.DinosaurIDs {
+		if err := ValidateID(id); err != nil {
+			return errors.New("invalid dinosaurIds")
+		}
+		if slices.Contains(i.DinosaurIDs[:j], id) {
+			return errors.New("dinosaurIds must be distinct")
+		}
+	}
+
+	if i.Summary == "" {
+		return errors.New("summary is required")
+	}
+
+	return nil
+}
+
+// LocksDown returns true if the incident blocks the admissions into its cage.
+func (i Incident) LocksDown() bool {
+	return i.Status == IncidentStatusOpen && i.Severity == IncidentSeverityCritical && i.Type.Containment()
+}
+
+// IncidentEntry is an entry in the timeline of an incident.
+type IncidentEntry struct {
+	ID         string    `json:"id"`
+	IncidentID string    `json:"incidentId"`
+	Note       string    `json:"note"`
+	OccurredAt time.Time `json:"occurredAt"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// Validate the timeline entry values.
+func (e IncidentEntry) Validate() error {
+	if e.Note == "" {
+		return errors.New("note is required")
+	}
+
+	return nil
+}
+
+// IncidentFilter narrows down a list of incidents.
+type IncidentFilter struct {
+	CageID   string
+	Status   IncidentStatus
+	Severity IncidentSeverity
+}
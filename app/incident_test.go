@@ -0,0 +1,60 @@
+//go:build unit
+// +build unit
+
+package app
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestIncidentValidate(t *testing.T) {
+	cageID, dinosaurID := uuid.NewString(), uuid.NewString()
+
+	tests := []struct {
+		name     string
+		incident Incident
+		valid    bool
+	}{
+		{"escape", Incident{Type: IncidentTypeEscape, Severity: IncidentSeverityCritical, CageID: cageID, DinosaurIDs: []string{dinosaurID}, Summary: "Raptor out"}, true},
+		{"no dinosaurs", Incident{Type: IncidentTypeFenceFailure, Severity: IncidentSeverityHigh, CageID: cageID, Summary: "Fence down"}, true},
+		{"invalid type", Incident{Type: IncidentType("flood"), Severity: IncidentSeverityLow, CageID: cageID, Summary: "Water"}, false},
+		{"no severity", Incident{Type: IncidentTypeInjury, CageID: cageID, Summary: "Bitten"}, false},
+		{"no cage", Incident{Type: IncidentTypeInjury, Severity: IncidentSeverityLow, Summary: "Bitten"}, false},
+		{"invalid dinosaur", Incident{Type: IncidentTypeEscape, Severity: IncidentSeverityCritical, CageID: cageID, DinosaurIDs: []string{"foo"}, Summary: "Raptor out"}, false},
+		{"duplicate dinosaur", Incident{Type: IncidentTypeEscape, Severity: IncidentSeverityCritical, CageID: cageID, DinosaurIDs: []string{dinosaurID, dinosaurID}, Summary: "Raptor out"}, false},
+		{"no summary", Incident{Type: IncidentTypeOther, Severity: IncidentSeverityLow, CageID: cageID}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.incident.Validate()
+			if want, got := tt.valid, err == nil; want != got {
+				t.Errorf("Expected %t got %t: %v", want, got, err)
+			}
+		})
+	}
+}
+
+func TestIncidentLocksDown(t *testing.T) {
+	tests := []struct {
+		name      string
+		incident  Incident
+		locksDown bool
+	}{
+		{"critical escape", Incident{Type: IncidentTypeEscape, Severity: IncidentSeverityCritical, Status: IncidentStatusOpen}, true},
+		{"critical fence failure", Incident{Type: IncidentTypeFenceFailure, Severity: IncidentSeverityCritical, Status: IncidentStatusOpen}, true},
+		{"resolved", Incident{Type: IncidentTypeEscape, Severity: IncidentSeverityCritical, Status: IncidentStatusResolved}, false},
+		{"high escape", Incident{Type: IncidentTypeEscape, Severity: IncidentSeverityHigh, Status: IncidentStatusOpen}, false},
+		{"critical injury", Incident{Type: IncidentTypeInjury, Severity: IncidentSeverityCritical, Status: IncidentStatusOpen}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if want, got := tt.locksDown, tt.incident.LocksDown(); want != got {
+				t.Errorf("Expected %t got %t", want, got)
+			}
+		})
+	}
+}
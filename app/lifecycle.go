@@ -0,0 +1,129 @@
+package app
+
+import (
+	"errors"
+	"time"
+)
+
+// LifecycleStage represents a stage in the life of a dinosaur.
+type LifecycleStage string
+
+const (
+	LifecycleStageUnspecified LifecycleStage = ""
+	// LifecycleStageEgg is an egg in the hatchery, outside of any cage.
+	LifecycleStageEgg      LifecycleStage = "egg"
+	LifecycleStageJuvenile LifecycleStage = "juvenile"
+	// LifecycleStageAdult is the stage of the dinosaurs admitted into the cages.
+	LifecycleStageAdult LifecycleStage = "adult"
+	// LifecycleStageDeceased and LifecycleStageTransferredOut are final,
+	// the dinosaurs leave their cages but stay on the records.
+	LifecycleStageDeceased       LifecycleStage = "deceased"
+	LifecycleStageTransferredOut LifecycleStage = "transferred-out"
+)
+
+// AllLifecycleStages is a list of all lifecycle stages in the order of the lifecycle.
+var AllLifecycleStages = []LifecycleStage{
+	LifecycleStageEgg,
+	LifecycleStageJuvenile,
+	LifecycleStageAdult,
+	LifecycleStageDeceased,
+	LifecycleStageTransferredOut,
+}
+
+// Validate the lifecycle stage value.
+func (s LifecycleStage) Validate() error {
+	switch s {
+	case LifecycleStageEgg,
+		LifecycleStageJuvenile,
+		LifecycleStageAdult,
+		LifecycleStageDeceased,
+		LifecycleStageTransferredOut:
+		return nil
+	default:
+		return errors.New("invalid stage")
+	}
+}
+
+// IsUnspecified returns true if the lifecycle stage is empty.
+func (s LifecycleStage) IsUnspecified() bool {
+	return s == LifecycleStageUnspecified
+}
+
+// InCage returns true if the dinosaurs at the stage live in a cage.
+func (s LifecycleStage) InCage() bool {
+	return s == LifecycleStageJuvenile || s == LifecycleStageAdult
+}
+
+// CanBecome returns true if the stage can transition to the next one.
+// An egg hatches into a juvenile that grows into an adult.
+// Any dinosaur, an egg included, can decease or be transferred out.
+func (s LifecycleStage) CanBecome(next LifecycleStage) bool {
+	switch next {
+	case LifecycleStageJuvenile:
+		return s == LifecycleStageEgg
+	case LifecycleStageAdult:
+		return s == LifecycleStageJuvenile
+	case LifecycleStageDeceased, LifecycleStageTransferredOut:
+		return s == LifecycleStageEgg || s.InCage()
+	default:
+		return false
+	}
+}
+
+// Egg is an egg laid into the hatchery.
+type Egg struct {
+	Name    string
+	Species DinosaurSpecies
+	// HatchDate is the expected hatch date.
+	HatchDate time.Time
+}
+
+// Validate the egg values.
+func (e Egg) Validate() error {
+	if e.Name == "" {
+		return errors.New("name is required")
+	}
+
+	if e.Species.IsUnspecified() {
+		return errors.New("species is required")
+	}
+	if err := e.Species.Validate(); err != nil {
+		return err
+	}
+
+	if e.HatchDate.IsZero() {
+		return errors.New("hatchDate is required")
+	}
+
+	return nil
+}
+
+// LifecycleEvent is a transition of a dinosaur from one lifecycle stage to another.
+type LifecycleEvent struct {
+	ID         string         `json:"id"`
+	DinosaurID string         `json:"dinosaurId"`
+	From       LifecycleStage `json:"from"`
+	To         LifecycleStage `json:"to"`
+	// CageID is the cage the dinosaur hatched into or left, if any.
+	CageID     string    `json:"cageId,omitempty"`
+	Notes      string    `json:"notes,omitempty"`
+	OccurredAt time.Time `json:"occurredAt"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// LifecycleTransition is a requested transition of a dinosaur to a lifecycle stage.
+type LifecycleTransition struct {
+	Stage LifecycleStage
+	Notes string
+	// OccurredAt is the time of the transition, now by default.
+	OccurredAt time.Time
+}
+
+// Validate the lifecycle transition values.
+func (t LifecycleTransition) Validate() error {
+	if t.Stage.IsUnspecified() {
+		return errors.New("stage is required")
+	}
+
+	return t.Stage.Validate()
+}
@@ -0,0 +1,80 @@
+//go:build unit
+// +build unit
+
+package app
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLifecycleStageCanBecome(t *testing.T) {
+	legal := map[LifecycleStage][]LifecycleStage{
+		LifecycleStageEgg:      {LifecycleStageJuvenile, LifecycleStageDeceased, LifecycleStageTransferredOut},
+		LifecycleStageJuvenile: {LifecycleStageAdult, LifecycleStageDeceased, LifecycleStageTransferredOut},
+		LifecycleStageAdult:    {LifecycleStageDeceased, LifecycleStageTransferredOut},
+	}
+
+	for _, from := range AllLifecycleStages {
+		for _, to := range AllLifecycleStages {
+			t.Run(string(from)+" to "+string(to), func(t *testing.T) {
+				var want bool
+				for _, stage := range legal[from] {
+					if stage == to {
+						want = true
+					}
+				}
+
+				if got := from.CanBecome(to); want != got {
+					t.Errorf("Expected %t got %t", want, got)
+				}
+			})
+		}
+	}
+}
+
+func TestLifecycleStageInCage(t *testing.T) {
+	tests := []struct {
+		stage  LifecycleStage
+		inCage bool
+	}{
+		{LifecycleStageEgg, false},
+		{LifecycleStageJuvenile, true},
+		{LifecycleStageAdult, true},
+		{LifecycleStageDeceased, false},
+		{LifecycleStageTransferredOut, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.stage), func(t *testing.T) {
+			if want, got := tt.inCage, tt.stage.InCage(); want != got {
+				t.Errorf("Expected %t got %t", want, got)
+			}
+		})
+	}
+}
+
+func TestEggValidate(t *testing.T) {
+	hatchDate := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		egg   Egg
+		valid bool
+	}{
+		{"valid", Egg{Name: "Blue", Species: DinosaurSpeciesVelociraptor, HatchDate: hatchDate}, true},
+		{"no name", Egg{Species: DinosaurSpeciesVelociraptor, HatchDate: hatchDate}, false},
+		{"no species", Egg{Name: "Blue", HatchDate: hatchDate}, false},
+		{"invalid species", Egg{Name: "Blue", Species: "foo", HatchDate: hatchDate}, false},
+		{"no hatch date", Egg{Name: "Blue", Species: DinosaurSpeciesVelociraptor}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.egg.Validate()
+			if want, got := tt.valid, err == nil; want != got {
+				t.Errorf("Expected %t got %t: %v", want, got, err)
+			}
+		})
+	}
+}
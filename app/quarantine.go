@@ -0,0 +1,38 @@
+package app
+
+import (
+	"errors"
+	"time"
+)
+
+// Quarantine isolates a dinosaur in a quarantine cage.
+type Quarantine struct {
+	// CageID is the quarantine cage the dinosaur is moved to.
+	CageID string
+	Reason string
+	// Start of the quarantine, now by default.
+	Start time.Time
+	// End is the planned end of the quarantine, open ended if zero.
+	// The quarantine lasts until the dinosaur is released regardless.
+	End time.Time
+}
+
+// Validate the quarantine values.
+func (q Quarantine) Validate() error {
+	if q.CageID == "" {
+		return errors.New("cageId is required")
+	}
+	if err := ValidateID(q.CageID); err != nil {
+		return err
+	}
+
+	if q.Reason == "" {
+		return errors.New("reason is required")
+	}
+
+	if !q.End.IsZero() && !q.Start.IsZero() && !q.End.After(q.Start) {
+		return errors.New("end must be after start")
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+//go:build unit
+// +build unit
+
+package app
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuarantineValidate(t *testing.T) {
+	cageID := "6e1a2c6e-2c5f-4c1c-9d3b-111111111111"
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		quarantine Quarantine
+		valid      bool
+	}{
+		{"open ended", Quarantine{CageID: cageID, Reason: "Fever"}, true},
+		{"period", Quarantine{CageID: cageID, Reason: "Fever", Start: start, End: start.Add(24 * time.Hour)}, true},
+		{"end only", Quarantine{CageID: cageID, Reason: "Fever", End: start}, true},
+		{"no cage", Quarantine{Reason: "Fever"}, false},
+		{"invalid cage", Quarantine{CageID: "foo", Reason: "Fever"}, false},
+		{"no reason", Quarantine{CageID: cageID}, false},
+		{"end before start", Quarantine{CageID: cageID, Reason: "Fever", Start: start, End: start.Add(-time.Hour)}, false},
+		{"empty period", Quarantine{CageID: cageID, Reason: "Fever", Start: start, End: start}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.quarantine.Validate()
+			if want, got := tt.valid, err == nil; want != got {
+				t.Errorf("Expected %t got %t: %v", want, got, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package app
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+// StaffRole represents the role of a staff member.
+type StaffRole string
+
+const (
+	StaffRoleUnspecified  StaffRole = ""
+	StaffRoleKeeper       StaffRole = "keeper"
+	StaffRoleVeterinarian StaffRole = "veterinarian"
+	StaffRoleSecurity     StaffRole = "security"
+)
+
+// Validate the staff role value.
+func (r StaffRole) Validate() error {
+	switch r {
+	case StaffRoleKeeper, StaffRoleVeterinarian, StaffRoleSecurity:
+		return nil
+	default:
+		return errors.New("invalid role")
+	}
+}
+
+// IsUnspecified returns true if the staff role is empty.
+func (r StaffRole) IsUnspecified() bool {
+	return r == StaffRoleUnspecified
+}
+
+// Certification represents a qualification of a staff member.
+type Certification string
+
+const (
+	// CertificationCarnivoreHandling is required to cover cages holding carnivores.
+	CertificationCarnivoreHandling Certification = "carnivore-handling"
+	CertificationAquaticHandling   Certification = "aquatic-handling"
+	CertificationTranquilizer      Certification = "tranquilizer"
+)
+
+// Validate the certification value.
+func (c Certification) Validate() error {
+	switch c {
+	case CertificationCarnivoreHandling, CertificationAquaticHandling, CertificationTranquilizer:
+		return nil
+	default:
+		return errors.New("invalid certification")
+	}
+}
+
+// Staff represents a park staff member, e.g. a keeper.
+type Staff struct {
+	ID             string          `json:"id"`
+	Name           string          `json:"name"`
+	Role           StaffRole       `json:"role"`
+	Certifications []Certification `json:"certifications"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	UpdatedAt      time.Time       `json:"updatedAt"`
+}
+
+// Validate the staff member values.
+func (s Staff) Validate() error {
+	if s.Name == "" {
+		return errors.New("name is required")
+	}
+
+	if err := s.Role.Validate(); err != nil {
+		return err
+	}
+
+	for i, c := range s.Certifications {
+		if err := c.Validate(); err != nil {
+			return err
+		}
+		if slices.Contains(s.Certifications[:i], c) {
+			return errors.New("certifications must be distinct")
+		}
+	}
+
+	return nil
+}
+
+// Certified returns true if the staff member holds the certification.
+func (s Staff) Certified(c Certification) bool {
+	return slices.Contains(s.Certifications, c)
+}
+
+// StaffFilter narrows down a list of staff members.
+type StaffFilter struct {
+	Role StaffRole
+	// CageID lists only the staff on duty for the cage at the OnDuty time, now by default.
+	CageID string
+	OnDuty time.Time
+}
+
+// Assignment represents a shift of a staff member covering a cage.
+// The shift starts at StartsAt inclusive and ends at EndsAt exclusive.
+type Assignment struct {
+	ID        string    `json:"id"`
+	StaffID   string    `json:"staffId"`
+	CageID    string    `json:"cageId"`
+	StartsAt  time.Time `json:"startsAt"`
+	EndsAt    time.Time `json:"endsAt"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Validate the assignment values.
+func (a Assignment) Validate() error {
+	if err := ValidateID(a.StaffID); err != nil {
+		return errors.New("invalid staffId")
+	}
+	if err := ValidateID(a.CageID); err != nil {
+		return errors.New("invalid cageId")
+	}
+
+	if a.StartsAt.IsZero() || a.EndsAt.IsZero() {
+		return errors.New("startsAt and endsAt are required")
+	}
+	if !a.StartsAt.Before(a.EndsAt) {
+		return errors.New("startsAt must be before endsAt")
+	}
+
+	return nil
+}
+
+// Covers returns true if the shift is on at the time.
+func (a Assignment) Covers(t time.Time) bool {
+	return !t.Before(a.StartsAt) && t.Before(a.EndsAt)
+}
+
+// AssignmentFilter narrows down a list of assignments.
+type AssignmentFilter struct {
+	StaffID string
+	CageID  string
+	// At lists only the shifts on at the time.
+	At time.Time
+}
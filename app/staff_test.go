@@ -0,0 +1,86 @@
+//go:build unit
+// +build unit
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestStaffValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		staff Staff
+		valid bool
+	}{
+		{"keeper", Staff{Name: "Muldoon", Role: StaffRoleKeeper}, true},
+		{"certified", Staff{Name: "Muldoon", Role: StaffRoleKeeper, Certifications: []Certification{CertificationCarnivoreHandling, CertificationTranquilizer}}, true},
+		{"no name", Staff{Role: StaffRoleKeeper}, false},
+		{"no role", Staff{Name: "Muldoon"}, false},
+		{"invalid role", Staff{Name: "Muldoon", Role: StaffRole("janitor")}, false},
+		{"invalid certification", Staff{Name: "Muldoon", Role: StaffRoleKeeper, Certifications: []Certification{"juggling"}}, false},
+		{"duplicate certification", Staff{Name: "Muldoon", Role: StaffRoleKeeper, Certifications: []Certification{CertificationTranquilizer, CertificationTranquilizer}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.staff.Validate()
+			if want, got := tt.valid, err == nil; want != got {
+				t.Errorf("Expected %t got %t: %v", want, got, err)
+			}
+		})
+	}
+}
+
+func TestAssignmentValidate(t *testing.T) {
+	start := time.Date(2023, 1, 15, 8, 0, 0, 0, time.UTC)
+	end := start.Add(8 * time.Hour)
+
+	tests := []struct {
+		name       string
+		assignment Assignment
+		valid      bool
+	}{
+		{"shift", Assignment{StaffID: uuid.NewString(), CageID: uuid.NewString(), StartsAt: start, EndsAt: end}, true},
+		{"invalid staff", Assignment{StaffID: "foo", CageID: uuid.NewString(), StartsAt: start, EndsAt: end}, false},
+		{"no cage", Assignment{StaffID: uuid.NewString(), StartsAt: start, EndsAt: end}, false},
+		{"no end", Assignment{StaffID: uuid.NewString(), CageID: uuid.NewString(), StartsAt: start}, false},
+		{"empty shift", Assignment{StaffID: uuid.NewString(), CageID: uuid.NewString(), StartsAt: start, EndsAt: start}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.assignment.Validate()
+			if want, got := tt.valid, err == nil; want != got {
+				t.Errorf("Expected %t got %t: %v", want, got, err)
+			}
+		})
+	}
+}
+
+func TestAssignmentCovers(t *testing.T) {
+	start := time.Date(2023, 1, 15, 8, 0, 0, 0, time.UTC)
+	shift := Assignment{StartsAt: start, EndsAt: start.Add(8 * time.Hour)}
+
+	tests := []struct {
+		name   string
+		at     time.Time
+		covers bool
+	}{
+		{"before", start.Add(-time.Minute), false},
+		{"start", start, true},
+		{"during", start.Add(4 * time.Hour), true},
+		{"end", start.Add(8 * time.Hour), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if want, got := tt.covers, shift.Covers(tt.at); want != got {
+				t.Errorf("Expected %t got %t", want, got)
+			}
+		})
+	}
+}
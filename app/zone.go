@@ -0,0 +1,45 @@
+package app
+
+import (
+	"errors"
+	"time"
+)
+
+// Zone represents a park zone, e.g. Paddock North.
+// Capacity and occupancy are rolled up from the cages in the zone sectors
+// separately per capacity unit, heads aren't added up with space.
+type Zone struct {
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	HeadCapacity  int       `json:"headCapacity"`
+	HeadCount     int       `json:"headCount"`
+	SpaceCapacity int       `json:"spaceCapacity"`
+	SpaceUsed     int       `json:"spaceUsed"`
+	Sectors       []Sector  `json:"sectors,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// Sector represents a part of a zone cages are located in.
+// Capacity and occupancy are rolled up from the cages in the sector
+// separately per capacity unit, heads aren't added up with space.
+type Sector struct {
+	ID            string    `json:"id"`
+	ZoneID        string    `json:"zoneId"`
+	Name          string    `json:"name"`
+	HeadCapacity  int       `json:"headCapacity"`
+	HeadCount     int       `json:"headCount"`
+	SpaceCapacity int       `json:"spaceCapacity"`
+	SpaceUsed     int       `json:"spaceUsed"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// ValidateName validates a zone or a sector name.
+func ValidateName(name string) error {
+	if name == "" {
+		return errors.New("name is required")
+	}
+
+	return nil
+}
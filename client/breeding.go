@@ -0,0 +1,87 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+// BreedingClient mirrors the breeding store operations over the API.
+type BreedingClient struct {
+	client *Client
+}
+
+// SetParents replaces the parents of a dinosaur and returns its lineage one generation deep.
+func (c *BreedingClient) SetParents(ctx context.Context, id string, parentIDs []string) (*app.Lineage, error) {
+	req := struct {
+		ParentIDs []string `json:"parentIds"`
+	}{
+		ParentIDs: parentIDs,
+	}
+	if req.ParentIDs == nil {
+		req.ParentIDs = []string{}
+	}
+
+	var lineage app.Lineage
+	if err := c.client.do(ctx, http.MethodPut, "/dinosaurs/"+url.PathEscape(id)+"/parents", nil, req, &lineage); err != nil {
+		return nil, err
+	}
+
+	return &lineage, nil
+}
+
+// Lineage returns the ancestors and the descendants of a dinosaur up to the number of generations.
+func (c *BreedingClient) Lineage(ctx context.Context, id string, generations int) (*app.Lineage, error) {
+	query := map[string]string{}
+	if generations > 0 {
+		query["generations"] = strconv.Itoa(generations)
+	}
+
+	var lineage app.Lineage
+	if err := c.client.do(ctx, http.MethodGet, "/dinosaurs/"+url.PathEscape(id)+"/lineage", query, nil, &lineage); err != nil {
+		return nil, err
+	}
+
+	return &lineage, nil
+}
+
+// AddPair proposes a breeding pair.
+// The pair is flagged if the inbreeding coefficient of its offspring goes over the threshold of the API.
+func (c *BreedingClient) AddPair(ctx context.Context, pair *app.BreedingPair) (*app.BreedingPair, error) {
+	req := struct {
+		SireID string `json:"sireId"`
+		DamID  string `json:"damId"`
+		Notes  string `json:"notes,omitempty"`
+	}{
+		SireID: pair.SireID,
+		DamID:  pair.DamID,
+		Notes:  pair.Notes,
+	}
+
+	var added app.BreedingPair
+	if err := c.client.do(ctx, http.MethodPost, "/breeding-pairs", nil, req, &added); err != nil {
+		return nil, err
+	}
+
+	return &added, nil
+}
+
+// ListPairs lists breeding pairs narrowed down by the filter, the most recent first.
+func (c *BreedingClient) ListPairs(ctx context.Context, filter app.BreedingPairFilter) ([]app.BreedingPair, error) {
+	query := map[string]string{
+		"dinosaurId": filter.DinosaurID,
+	}
+	if filter.FlaggedOnly {
+		query["flagged"] = "true"
+	}
+
+	var pairs []app.BreedingPair
+	if err := c.client.do(ctx, http.MethodGet, "/breeding-pairs", query, nil, &pairs); err != nil {
+		return nil, err
+	}
+
+	return pairs, nil
+}
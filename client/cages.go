@@ -0,0 +1,168 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+// CageClient mirrors the cage store operations over the API.
+type CageClient struct {
+	client *Client
+}
+
+// Add adds a new cage.
+func (c *CageClient) Add(ctx context.Context, cage *app.Cage) (*app.Cage, error) {
+	req := struct {
+		Type         app.CageType     `json:"type,omitempty"`
+		Capacity     int              `json:"capacity"`
+		CapacityUnit app.CapacityUnit `json:"capacityUnit,omitempty"`
+		Status       app.CageStatus   `json:"status"`
+		SectorID     string           `json:"sectorId,omitempty"`
+		Quarantine   bool             `json:"quarantine,omitempty"`
+	}{
+		Type:         cage.Type,
+		Capacity:     cage.Capacity,
+		CapacityUnit: cage.CapacityUnit,
+		Status:       cage.Status,
+		SectorID:     cage.SectorID,
+		Quarantine:   cage.Quarantine,
+	}
+
+	var added app.Cage
+	if err := c.client.do(ctx, http.MethodPost, "/cages", nil, req, &added); err != nil {
+		return nil, err
+	}
+
+	return &added, nil
+}
+
+// Get gets a cage by id.
+func (c *CageClient) Get(ctx context.Context, id string, opts app.GetOptions) (*app.Cage, error) {
+	var cage app.Cage
+	if err := c.client.do(ctx, http.MethodGet, "/cages/"+url.PathEscape(id), getQuery(opts), nil, &cage); err != nil {
+		return nil, err
+	}
+
+	return &cage, nil
+}
+
+// List lists cages narrowed down by the filter.
+func (c *CageClient) List(ctx context.Context, filter app.CageFilter) ([]app.Cage, error) {
+	query := map[string]string{
+		"id":         strings.Join(filter.IDs, ","),
+		"type":       string(filter.Type),
+		"status":     string(filter.Status),
+		"quarantine": boolQuery(filter.Quarantine),
+		"zoneId":     filter.ZoneID,
+		"sectorId":   filter.SectorID,
+		"occupancy":  string(filter.Occupancy),
+		"fields":     strings.Join(filter.Fields, ","),
+	}
+	if filter.MinFreeCapacity > 0 {
+		query["minFreeCapacity"] = strconv.Itoa(filter.MinFreeCapacity)
+	}
+	query = listQuery(query, filter.Created, filter.Updated, filter.Sort, filter.IncludeDeleted)
+
+	var cages []app.Cage
+	if err := c.client.do(ctx, http.MethodGet, "/cages", query, nil, &cages); err != nil {
+		return nil, err
+	}
+
+	return cages, nil
+}
+
+// ChangeStatus changes the status of a cage.
+func (c *CageClient) ChangeStatus(ctx context.Context, id string, status app.CageStatus) (*app.Cage, error) {
+	req := struct {
+		Status app.CageStatus `json:"status"`
+	}{
+		Status: status,
+	}
+
+	var cage app.Cage
+	if err := c.client.do(ctx, http.MethodPut, "/cages/"+url.PathEscape(id), nil, req, &cage); err != nil {
+		return nil, err
+	}
+
+	return &cage, nil
+}
+
+// Update applies a patch to a cage, an empty SectorID removes the cage from its sector.
+func (c *CageClient) Update(ctx context.Context, id string, patch app.CagePatch) (*app.Cage, error) {
+	req := struct {
+		Type         *app.CageType     `json:"type,omitempty"`
+		Status       *app.CageStatus   `json:"status,omitempty"`
+		Capacity     *int              `json:"capacity,omitempty"`
+		CapacityUnit *app.CapacityUnit `json:"capacityUnit,omitempty"`
+		SectorID     any               `json:"sectorId,omitempty"`
+		Quarantine   *bool             `json:"quarantine,omitempty"`
+	}{
+		Type:         patch.Type,
+		Status:       patch.Status,
+		Capacity:     patch.Capacity,
+		CapacityUnit: patch.CapacityUnit,
+		Quarantine:   patch.Quarantine,
+	}
+	// An empty sector removes the cage from its sector with null.
+	if patch.SectorID != nil {
+		req.SectorID = *patch.SectorID
+		if *patch.SectorID == "" {
+			req.SectorID = json.RawMessage("null")
+		}
+	}
+
+	var cage app.Cage
+	if err := c.client.do(ctx, http.MethodPatch, "/cages/"+url.PathEscape(id), nil, req, &cage); err != nil {
+		return nil, err
+	}
+
+	return &cage, nil
+}
+
+// Resize changes the capacity of a cage.
+// Lowering the capacity below the occupancy fails with an *app.OccupancyError.
+func (c *CageClient) Resize(ctx context.Context, id string, capacity int) (*app.Cage, error) {
+	req := struct {
+		Capacity int `json:"capacity"`
+	}{
+		Capacity: capacity,
+	}
+
+	var cage app.Cage
+	if err := c.client.do(ctx, http.MethodPut, "/cages/"+url.PathEscape(id)+"/capacity", nil, req, &cage); err != nil {
+		return nil, err
+	}
+
+	return &cage, nil
+}
+
+// History lists the recorded changes of a cage.
+func (c *CageClient) History(ctx context.Context, id string) ([]app.CageHistoryEntry, error) {
+	var history []app.CageHistoryEntry
+	if err := c.client.do(ctx, http.MethodGet, "/cages/"+url.PathEscape(id)+"/history", nil, nil, &history); err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// Delete deletes a cage.
+func (c *CageClient) Delete(ctx context.Context, id string) error {
+	return c.client.do(ctx, http.MethodDelete, "/cages/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// Restore restores a soft deleted cage.
+func (c *CageClient) Restore(ctx context.Context, id string) (*app.Cage, error) {
+	var cage app.Cage
+	if err := c.client.do(ctx, http.MethodPost, "/cages/"+url.PathEscape(id)+"/restore", nil, nil, &cage); err != nil {
+		return nil, err
+	}
+
+	return &cage, nil
+}
@@ -0,0 +1,334 @@
+// Package client is a Go client for the Jurassic Park API.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+// Default retry settings.
+const (
+	DefaultMaxRetries = 3
+	DefaultMinBackoff = 100 * time.Millisecond
+	DefaultMaxBackoff = 5 * time.Second
+)
+
+// Client is a Jurassic Park API client.
+type Client struct {
+	// BaseURL is the URL of the API including the base URI,
+	// e.g. https://jurassic.example.com/api/v1.
+	BaseURL string
+	// Token is an optional API key sent as a bearer token.
+	Token string
+	// HTTPClient is the HTTP client used to send requests.
+	HTTPClient *http.Client
+	// MaxRetries is the number of times idempotent requests are retried
+	// on network errors and on 429, 502, 503 and 504 responses.
+	MaxRetries int
+	// MinBackoff and MaxBackoff limit the exponential backoff between retries.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	Cages     *CageClient
+	Dinosaurs *DinosaurClient
+	Zones     *ZoneClient
+	Feedings  *FeedingClient
+	Breeding  *BreedingClient
+	Staff     *StaffClient
+	Incidents *IncidentClient
+}
+
+// New creates a new API client with the default settings.
+func New(baseURL string) *Client {
+	c := &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		HTTPClient: http.DefaultClient,
+		MaxRetries: DefaultMaxRetries,
+		MinBackoff: DefaultMinBackoff,
+		MaxBackoff: DefaultMaxBackoff,
+	}
+	c.Cages = &CageClient{client: c}
+	c.Dinosaurs = &DinosaurClient{client: c}
+	c.Zones = &ZoneClient{client: c}
+	c.Feedings = &FeedingClient{client: c}
+	c.Breeding = &BreedingClient{client: c}
+	c.Staff = &StaffClient{client: c}
+	c.Incidents = &IncidentClient{client: c}
+
+	return c
+}
+
+// Error is an error response of the API.
+type Error struct {
+	StatusCode int
+	Message    string
+	// Err is the application error the response maps to, if any.
+	Err error
+}
+
+// Error implements error.
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("jurassic: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+
+	return fmt.Sprintf("jurassic: %d %s", e.StatusCode, e.Message)
+}
+
+// Unwrap returns the application error so that errors.Is
+// can be used to check for app.ErrNotFound and alike.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// conflictErrors are the application errors returned as 409 Conflict
+// with the error text as the response body.
+var conflictErrors = []error{
+	app.ErrCapacityExceeded,
+	app.ErrCagePoweredDown,
+	app.ErrCageLockedDown,
+	app.ErrSpeciesMismatch,
+	app.ErrHabitatMismatch,
+	app.ErrCapacityBelowOccupancy,
+	app.ErrSectorNotFound,
+	app.ErrQuarantineMismatch,
+	app.ErrIllegalTransition,
+	app.ErrNotInCage,
+	app.ErrLineageCycle,
+	app.ErrNotAdult,
+	app.ErrNotCertified,
+	app.ErrIncidentResolved,
+}
+
+// newError maps an error response to the application errors.
+func newError(statusCode int, body []byte) *Error {
+	e := &Error{
+		StatusCode: statusCode,
+		Message:    strings.TrimSpace(string(body)),
+	}
+
+	switch statusCode {
+	case http.StatusNotFound:
+		e.Err = app.ErrNotFound
+	case http.StatusConflict:
+		e.Err = app.ErrConflict
+		for _, err := range conflictErrors {
+			if e.Message == err.Error() {
+				e.Err = err
+			}
+		}
+
+		// The current occupancy is reported along with the error.
+		var occupancy int
+		if _, err := fmt.Sscanf(e.Message, app.ErrCapacityBelowOccupancy.Error()+" of %d", &occupancy); err == nil {
+			e.Err = &app.OccupancyError{Occupancy: occupancy}
+		}
+	}
+
+	return e
+}
+
+// do sends a request and decodes the data of the response envelope into out.
+// Idempotent requests are retried.
+func (c *Client) do(ctx context.Context, method, path string, query map[string]string, in, out any) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return err
+		}
+	}
+
+	retries := 0
+	if idempotent(method) {
+		retries = c.MaxRetries
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, method, path, query, body)
+		if err != nil {
+			if ctx.Err() != nil || attempt >= retries {
+				return err
+			}
+			if err := c.wait(ctx, attempt, 0); err != nil {
+				return err
+			}
+			continue
+		}
+
+		b, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+
+		if retryable(resp.StatusCode) && attempt < retries {
+			if err := c.wait(ctx, attempt, retryAfter(resp)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return newError(resp.StatusCode, b)
+		}
+
+		if out == nil || len(b) == 0 {
+			return nil
+		}
+
+		envelope := struct {
+			Data any `json:"data"`
+		}{
+			Data: out,
+		}
+		if err := json.Unmarshal(b, &envelope); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+
+		return nil
+	}
+}
+
+func (c *Client) send(ctx context.Context, method, path string, query map[string]string, body []byte) (*http.Response, error) {
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, r)
+	if err != nil {
+		return nil, err
+	}
+
+	q := req.URL.Query()
+	for key, value := range query {
+		if value != "" {
+			q.Set(key, value)
+		}
+	}
+	req.URL.RawQuery = q.Encode()
+
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", contentType(method))
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	return c.HTTPClient.Do(req)
+}
+
+// getQuery returns the query parameters for the get options.
+func getQuery(opts app.GetOptions) map[string]string {
+	query := map[string]string{"fields": strings.Join(opts.Fields, ",")}
+	if opts.IncludeDeleted {
+		query["includeDeleted"] = "true"
+	}
+
+	return query
+}
+
+// listQuery sets the query parameters shared by the list operations.
+func listQuery(query map[string]string, created, updated app.TimeRange, sort []app.Sort, includeDeleted bool) map[string]string {
+	for prefix, tr := range map[string]app.TimeRange{"created": created, "updated": updated} {
+		if !tr.After.IsZero() {
+			query[prefix+"After"] = tr.After.Format(time.RFC3339Nano)
+		}
+		if !tr.Before.IsZero() {
+			query[prefix+"Before"] = tr.Before.Format(time.RFC3339Nano)
+		}
+	}
+
+	fields := make([]string, 0, len(sort))
+	for _, s := range sort {
+		fields = append(fields, s.String())
+	}
+	query["sort"] = strings.Join(fields, ",")
+
+	if includeDeleted {
+		query["includeDeleted"] = "true"
+	}
+
+	return query
+}
+
+// boolQuery formats an optional boolean query parameter, empty if unset.
+func boolQuery(b *bool) string {
+	if b == nil {
+		return ""
+	}
+
+	return strconv.FormatBool(*b)
+}
+
+// contentType returns the request body content type for the method.
+// Partial updates are sent as JSON merge patches.
+func contentType(method string) string {
+	if method == http.MethodPatch {
+		return "application/merge-patch+json"
+	}
+
+	return "application/json"
+}
+
+// wait sleeps before the next retry for the time the server asked for
+// or for an exponential backoff with jitter.
+func (c *Client) wait(ctx context.Context, attempt int, after time.Duration) error {
+	if after <= 0 {
+		backoff := c.MinBackoff << attempt
+		if backoff > c.MaxBackoff || backoff <= 0 {
+			backoff = c.MaxBackoff
+		}
+		after = backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+	}
+
+	timer := time.NewTimer(after)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+func retryable(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryAfter returns the delay from the Retry-After header in seconds.
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,1502 @@
+//go:build unit
+// +build unit
+
+package client
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/pmatseykanets/jurassic/api"
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+// The stores below are stubs that return canned responses and record the last call.
+// The rules behind the responses are covered by the store and api tests,
+// the client tests only check that the calls make it through the API and back.
+
+// calls records the last call of a stub store.
+type calls struct {
+	method string
+	args   []any
+}
+
+func (c *calls) record(method string, args ...any) {
+	c.method, c.args = method, args
+}
+
+// called fails the test unless the last call was the method with the args.
+func (c *calls) called(t *testing.T, method string, args ...any) {
+	t.Helper()
+
+	if want, got := method, c.method; want != got {
+		t.Fatalf("Expected call %s got %s", want, got)
+	}
+	if want, got := args, c.args; !reflect.DeepEqual(want, got) {
+		t.Fatalf("Expected %s args %+v got %+v", method, want, got)
+	}
+}
+
+type stubCageStore struct {
+	calls
+	cage    app.Cage
+	cages   []app.Cage
+	history []app.CageHistoryEntry
+	err     error
+}
+
+func (s *stubCageStore) result() (*app.Cage, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	cage := s.cage
+
+	return &cage, nil
+}
+
+func (s *stubCageStore) Add(_ context.Context, cage *app.Cage) (*app.Cage, error) {
+	s.record("Add", *cage)
+	return s.result()
+}
+
+func (s *stubCageStore) Get(_ context.Context, id string, opts app.GetOptions) (*app.Cage, error) {
+	s.record("Get", id, opts)
+	return s.result()
+}
+
+func (s *stubCageStore) List(_ context.Context, filter app.CageFilter) ([]app.Cage, error) {
+	s.record("List", filter)
+	return s.cages, s.err
+}
+
+func (s *stubCageStore) ChangeStatus(_ context.Context, id string, status app.CageStatus) (*app.Cage, error) {
+	s.record("ChangeStatus", id, status)
+	return s.result()
+}
+
+func (s *stubCageStore) Update(_ context.Context, id string, patch app.CagePatch) (*app.Cage, error) {
+	s.record("Update", id, patch)
+	return s.result()
+}
+
+func (s *stubCageStore) Resize(_ context.Context, id string, capacity int) (*app.Cage, error) {
+	s.record("Resize", id, capacity)
+	return s.result()
+}
+
+func (s *stubCageStore) History(_ context.Context, id string) ([]app.CageHistoryEntry, error) {
+	s.record("History", id)
+	return s.history, s.err
+}
+
+func (s *stubCageStore) Delete(_ context.Context, id string) error {
+	s.record("Delete", id)
+	return s.err
+}
+
+func (s *stubCageStore) Restore(_ context.Context, id string) (*app.Cage, error) {
+	s.record("Restore", id)
+	return s.result()
+}
+
+type stubDinosaurStore struct {
+	calls
+	dinosaur  app.Dinosaur
+	dinosaurs []app.Dinosaur
+	health    app.HealthRecord
+	records   []app.HealthRecord
+	event     app.LifecycleEvent
+	events    []app.LifecycleEvent
+	err       error
+}
+
+func (s *stubDinosaurStore) result() (*app.Dinosaur, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	dinosaur := s.dinosaur
+
+	return &dinosaur, nil
+}
+
+func (s *stubDinosaurStore) Add(_ context.Context, dinosaur *app.Dinosaur) (*app.Dinosaur, error) {
+	s.record("Add", *dinosaur)
+	return s.result()
+}
+
+func (s *stubDinosaurStore) List(_ context.Context, filter app.DinosaurFilter) ([]app.Dinosaur, error) {
+	s.record("List", filter)
+	return s.dinosaurs, s.err
+}
+
+func (s *stubDinosaurStore) Get(_ context.Context, id string, opts app.GetOptions) (*app.Dinosaur, error) {
+	s.record("Get", id, opts)
+	return s.result()
+}
+
+func (s *stubDinosaurStore) Move(_ context.Context, id string, cageID string) (*app.Dinosaur, error) {
+	s.record("Move", id, cageID)
+	return s.result()
+}
+
+func (s *stubDinosaurStore) Update(_ context.Context, id string, patch app.DinosaurPatch) (*app.Dinosaur, error) {
+	s.record("Update", id, patch)
+	return s.result()
+}
+
+func (s *stubDinosaurStore) Delete(_ context.Context, id string) error {
+	s.record("Delete", id)
+	return s.err
+}
+
+func (s *stubDinosaurStore) Restore(_ context.Context, id string) (*app.Dinosaur, error) {
+	s.record("Restore", id)
+	return s.result()
+}
+
+func (s *stubDinosaurStore) AddHealthRecord(_ context.Context, record *app.HealthRecord) (*app.HealthRecord, error) {
+	s.record("AddHealthRecord", *record)
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	added := s.health
+
+	return &added, nil
+}
+
+func (s *stubDinosaurStore) HealthRecords(_ context.Context, id string, filter app.HealthRecordFilter) ([]app.HealthRecord, error) {
+	s.record("HealthRecords", id, filter)
+	return s.records, s.err
+}
+
+func (s *stubDinosaurStore) Quarantine(_ context.Context, id string, quarantine app.Quarantine) (*app.Dinosaur, error) {
+	s.record("Quarantine", id, quarantine)
+	return s.result()
+}
+
+func (s *stubDinosaurStore) Release(_ context.Context, id string, cageID string) (*app.Dinosaur, error) {
+	s.record("Release", id, cageID)
+	return s.result()
+}
+
+func (s *stubDinosaurStore) AddEgg(_ context.Context, egg app.Egg) (*app.Dinosaur, error) {
+	s.record("AddEgg", egg)
+	return s.result()
+}
+
+func (s *stubDinosaurStore) Hatch(_ context.Context, id string, cageID string) (*app.Dinosaur, error) {
+	s.record("Hatch", id, cageID)
+	return s.result()
+}
+
+func (s *stubDinosaurStore) Transition(_ context.Context, id string, transition app.LifecycleTransition) (*app.LifecycleEvent, error) {
+	s.record("Transition", id, transition)
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	event := s.event
+
+	return &event, nil
+}
+
+func (s *stubDinosaurStore) LifecycleEvents(_ context.Context, id string) ([]app.LifecycleEvent, error) {
+	s.record("LifecycleEvents", id)
+	return s.events, s.err
+}
+
+type stubZoneStore struct {
+	calls
+	zone    app.Zone
+	zones   []app.Zone
+	sector  app.Sector
+	sectors []app.Sector
+	err     error
+}
+
+func (s *stubZoneStore) result() (*app.Zone, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	zone := s.zone
+
+	return &zone, nil
+}
+
+func (s *stubZoneStore) Add(_ context.Context, zone *app.Zone) (*app.Zone, error) {
+	s.record("Add", *zone)
+	return s.result()
+}
+
+func (s *stubZoneStore) Get(_ context.Context, id string) (*app.Zone, error) {
+	s.record("Get", id)
+	return s.result()
+}
+
+func (s *stubZoneStore) List(_ context.Context) ([]app.Zone, error) {
+	s.record("List")
+	return s.zones, s.err
+}
+
+func (s *stubZoneStore) AddSector(_ context.Context, sector *app.Sector) (*app.Sector, error) {
+	s.record("AddSector", *sector)
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	added := s.sector
+
+	return &added, nil
+}
+
+func (s *stubZoneStore) ListSectors(_ context.Context, zoneID string) ([]app.Sector, error) {
+	s.record("ListSectors", zoneID)
+	return s.sectors, s.err
+}
+
+type stubFeedingStore struct {
+	calls
+	schedule  app.FeedingSchedule
+	schedules []app.FeedingSchedule
+	feeding   app.Feeding
+	feedings  []app.Feeding
+	overdue   []app.OverdueFeeding
+	err       error
+}
+
+func (s *stubFeedingStore) AddSchedule(_ context.Context, schedule *app.FeedingSchedule) (*app.FeedingSchedule, error) {
+	s.record("AddSchedule", *schedule)
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	added := s.schedule
+
+	return &added, nil
+}
+
+func (s *stubFeedingStore) ListSchedules(_ context.Context, filter app.FeedingScheduleFilter) ([]app.FeedingSchedule, error) {
+	s.record("ListSchedules", filter)
+	return s.schedules, s.err
+}
+
+func (s *stubFeedingStore) DeleteSchedule(_ context.Context, id string) error {
+	s.record("DeleteSchedule", id)
+	return s.err
+}
+
+func (s *stubFeedingStore) Record(_ context.Context, feeding *app.Feeding) (*app.Feeding, error) {
+	s.record("Record", *feeding)
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	recorded := s.feeding
+
+	return &recorded, nil
+}
+
+func (s *stubFeedingStore) List(_ context.Context, filter app.FeedingFilter) ([]app.Feeding, error) {
+	s.record("List", filter)
+	return s.feedings, s.err
+}
+
+func (s *stubFeedingStore) Overdue(_ context.Context, at time.Time) ([]app.OverdueFeeding, error) {
+	s.record("Overdue", at)
+	return s.overdue, s.err
+}
+
+type stubBreedingStore struct {
+	calls
+	lineage app.Lineage
+	pair    app.BreedingPair
+	pairs   []app.BreedingPair
+	err     error
+}
+
+func (s *stubBreedingStore) result() (*app.Lineage, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	lineage := s.lineage
+
+	return &lineage, nil
+}
+
+func (s *stubBreedingStore) SetParents(_ context.Context, id string, parentIDs []string) (*app.Lineage, error) {
+	s.record("SetParents", id, parentIDs)
+	return s.result()
+}
+
+func (s *stubBreedingStore) Lineage(_ context.Context, id string, generations int) (*app.Lineage, error) {
+	s.record("Lineage", id, generations)
+	return s.result()
+}
+
+func (s *stubBreedingStore) AddPair(_ context.Context, pair *app.BreedingPair) (*app.BreedingPair, error) {
+	s.record("AddPair", *pair)
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	added := s.pair
+
+	return &added, nil
+}
+
+func (s *stubBreedingStore) ListPairs(_ context.Context, filter app.BreedingPairFilter) ([]app.BreedingPair, error) {
+	s.record("ListPairs", filter)
+	return s.pairs, s.err
+}
+
+type stubStaffStore struct {
+	calls
+	staff       app.Staff
+	list        []app.Staff
+	assignment  app.Assignment
+	assignments []app.Assignment
+	err         error
+}
+
+func (s *stubStaffStore) result() (*app.Staff, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	staff := s.staff
+
+	return &staff, nil
+}
+
+func (s *stubStaffStore) Add(_ context.Context, staff *app.Staff) (*app.Staff, error) {
+	s.record("Add", *staff)
+	return s.result()
+}
+
+func (s *stubStaffStore) Get(_ context.Context, id string) (*app.Staff, error) {
+	s.record("Get", id)
+	return s.result()
+}
+
+func (s *stubStaffStore) List(_ context.Context, filter app.StaffFilter) ([]app.Staff, error) {
+	s.record("List", filter)
+	return s.list, s.err
+}
+
+func (s *stubStaffStore) Assign(_ context.Context, assignment *app.Assignment) (*app.Assignment, error) {
+	s.record("Assign", *assignment)
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	added := s.assignment
+
+	return &added, nil
+}
+
+func (s *stubStaffStore) ListAssignments(_ context.Context, filter app.AssignmentFilter) ([]app.Assignment, error) {
+	s.record("ListAssignments", filter)
+	return s.assignments, s.err
+}
+
+func (s *stubStaffStore) DeleteAssignment(_ context.Context, id string) error {
+	s.record("DeleteAssignment", id)
+	return s.err
+}
+
+type stubIncidentStore struct {
+	calls
+	incident  app.Incident
+	incidents []app.Incident
+	entry     app.IncidentEntry
+	err       error
+}
+
+func (s *stubIncidentStore) result() (*app.Incident, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	incident := s.incident
+
+	return &incident, nil
+}
+
+func (s *stubIncidentStore) Open(_ context.Context, incident *app.Incident) (*app.Incident, error) {
+	s.record("Open", *incident)
+	return s.result()
+}
+
+func (s *stubIncidentStore) Get(_ context.Context, id string) (*app.Incident, error) {
+	s.record("Get", id)
+	return s.result()
+}
+
+func (s *stubIncidentStore) List(_ context.Context, filter app.IncidentFilter) ([]app.Incident, error) {
+	s.record("List", filter)
+	return s.incidents, s.err
+}
+
+func (s *stubIncidentStore) AddEntry(_ context.Context, id string, entry *app.IncidentEntry) (*app.IncidentEntry, error) {
+	s.record("AddEntry", id, *entry)
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	added := s.entry
+
+	return &added, nil
+}
+
+func (s *stubIncidentStore) Resolve(_ context.Context, id, resolution string) (*app.Incident, error) {
+	s.record("Resolve", id, resolution)
+	return s.result()
+}
+
+var (
+	_ api.CageStore     = (*CageClient)(nil)
+	_ api.DinosaurStore = (*DinosaurClient)(nil)
+	_ api.ZoneStore     = (*ZoneClient)(nil)
+	_ api.FeedingStore  = (*FeedingClient)(nil)
+	_ api.BreedingStore = (*BreedingClient)(nil)
+	_ api.StaffStore    = (*StaffClient)(nil)
+	_ api.IncidentStore = (*IncidentClient)(nil)
+)
+
+const (
+	testBaseURI = "/api/v1"
+	testToken   = "secret"
+)
+
+// newTestServer starts a server with the real handlers and routes backed by the stores of svc
+// and returns a client of the server.
+func newTestServer(t *testing.T, svc *api.Server, middlewares ...func(http.Handler) http.Handler) (*httptest.Server, *Client) {
+	t.Helper()
+
+	svc.Logger = slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	spec, err := api.LoadOpenAPI()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rtr := chi.NewRouter()
+	rtr.Use(middlewares...)
+	rtr.Use(api.ValidateResponses(spec, testBaseURI, func(r *http.Request, err error) {
+		t.Errorf("%s %s: response doesn't match the spec: %s", r.Method, r.URL.Path, err)
+	}))
+	rtr.Use(api.BearerToken(testToken))
+	rtr.Use(api.ValidateRequests(spec, testBaseURI))
+	svc.Routes(rtr, testBaseURI, nil)
+
+	srv := httptest.NewServer(rtr)
+	t.Cleanup(srv.Close)
+
+	c := New(srv.URL + testBaseURI + "/")
+	c.Token = testToken
+	c.MinBackoff = time.Millisecond
+	c.MaxBackoff = 10 * time.Millisecond
+
+	return srv, c
+}
+
+// now is a timestamp that survives a round trip through JSON.
+var now = time.Now().UTC().Truncate(time.Second)
+
+func testCage() app.Cage {
+	return app.Cage{
+		ID:           uuid.NewString(),
+		Type:         app.CageTypeGeneral,
+		Status:       app.CageStatusActive,
+		Capacity:     2,
+		CapacityUnit: app.CapacityUnitHeads,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+}
+
+func testDinosaur(cageID string) app.Dinosaur {
+	return app.Dinosaur{
+		ID:           uuid.NewString(),
+		Name:         "Rexy",
+		Species:      app.DinosaurSpeciesTyrannosaurus,
+		CageID:       cageID,
+		Stage:        app.LifecycleStageAdult,
+		HealthStatus: app.HealthStatusHealthy,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+}
+
+func TestClientCages(t *testing.T) {
+	store := &stubCageStore{cage: testCage()}
+	_, c := newTestServer(t, &api.Server{CageStore: store})
+	ctx := context.Background()
+
+	cage, err := c.Cages.Add(ctx, &app.Cage{Type: app.CageTypePaddock, Capacity: 10, CapacityUnit: app.CapacityUnitSpace, Status: app.CageStatusDown, Quarantine: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	added := store.args[0].(app.Cage)
+	if want, got := (app.Cage{Type: app.CageTypePaddock, Capacity: 10, CapacityUnit: app.CapacityUnitSpace, Status: app.CageStatusDown, Quarantine: true}), added; want != got {
+		t.Fatalf("Expected cage %+v got %+v", want, got)
+	}
+	if want, got := store.cage, *cage; want != got {
+		t.Fatalf("Expected cage %+v got %+v", want, got)
+	}
+
+	if _, err := c.Cages.Get(ctx, cage.ID, app.GetOptions{IncludeDeleted: true}); err != nil {
+		t.Fatal(err)
+	}
+	store.called(t, "Get", cage.ID, app.GetOptions{IncludeDeleted: true})
+
+	status, capacity, unit, sectorID := app.CageStatusActive, 3, app.CapacityUnitHeads, uuid.NewString()
+	patch := app.CagePatch{Status: &status, Capacity: &capacity, CapacityUnit: &unit, SectorID: &sectorID}
+	if _, err := c.Cages.Update(ctx, cage.ID, patch); err != nil {
+		t.Fatal(err)
+	}
+	store.called(t, "Update", cage.ID, patch)
+
+	// An empty sector is sent as null and removes the cage from its sector.
+	none := ""
+	if _, err := c.Cages.Update(ctx, cage.ID, app.CagePatch{SectorID: &none}); err != nil {
+		t.Fatal(err)
+	}
+	store.called(t, "Update", cage.ID, app.CagePatch{SectorID: &none})
+
+	if _, err := c.Cages.ChangeStatus(ctx, cage.ID, app.CageStatusDown); err != nil {
+		t.Fatal(err)
+	}
+	store.called(t, "ChangeStatus", cage.ID, app.CageStatusDown)
+
+	if _, err := c.Cages.Resize(ctx, cage.ID, 5); err != nil {
+		t.Fatal(err)
+	}
+	store.called(t, "Resize", cage.ID, 5)
+
+	store.history = []app.CageHistoryEntry{{ID: 1, CageID: cage.ID, Change: app.CageChangeCapacity, From: "2", To: "5", CreatedAt: now}}
+	history, err := c.Cages.History(ctx, cage.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.called(t, "History", cage.ID)
+	if want, got := store.history, history; !reflect.DeepEqual(want, got) {
+		t.Fatalf("Expected history %+v got %+v", want, got)
+	}
+
+	if err := c.Cages.Delete(ctx, cage.ID); err != nil {
+		t.Fatal(err)
+	}
+	store.called(t, "Delete", cage.ID)
+
+	if _, err := c.Cages.Restore(ctx, cage.ID); err != nil {
+		t.Fatal(err)
+	}
+	store.called(t, "Restore", cage.ID)
+}
+
+func TestClientDinosaurs(t *testing.T) {
+	cageID := uuid.NewString()
+	store := &stubDinosaurStore{dinosaur: testDinosaur(cageID)}
+	_, c := newTestServer(t, &api.Server{DinosaurStore: store})
+	ctx := context.Background()
+
+	rexy, err := c.Dinosaurs.Add(ctx, &app.Dinosaur{Name: "Rexy", Species: app.DinosaurSpeciesTyrannosaurus, CageID: cageID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	added := store.args[0].(app.Dinosaur)
+	if want, got := "Rexy", added.Name; want != got {
+		t.Fatalf("Expected Name %s got %s", want, got)
+	}
+	if want, got := cageID, added.CageID; want != got {
+		t.Fatalf("Expected CageID %s got %s", want, got)
+	}
+	if want, got := store.dinosaur, *rexy; want != got {
+		t.Fatalf("Expected dinosaur %+v got %+v", want, got)
+	}
+
+	if _, err := c.Dinosaurs.Get(ctx, rexy.ID, app.GetOptions{IncludeDeleted: true}); err != nil {
+		t.Fatal(err)
+	}
+	store.called(t, "Get", rexy.ID, app.GetOptions{IncludeDeleted: true})
+
+	// A cage in the filter lists the dinosaurs of the cage.
+	store.dinosaurs = []app.Dinosaur{store.dinosaur}
+	dinosaurs, err := c.Dinosaurs.List(ctx, app.DinosaurFilter{CageID: cageID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := cageID, store.args[0].(app.DinosaurFilter).CageID; want != got {
+		t.Fatalf("Expected CageID %s got %s", want, got)
+	}
+	if want, got := store.dinosaurs, dinosaurs; !reflect.DeepEqual(want, got) {
+		t.Fatalf("Expected dinosaurs %+v got %+v", want, got)
+	}
+
+	otherID := uuid.NewString()
+	if _, err := c.Dinosaurs.Move(ctx, rexy.ID, otherID); err != nil {
+		t.Fatal(err)
+	}
+	store.called(t, "Move", rexy.ID, otherID)
+
+	name := "Roberta"
+	if _, err := c.Dinosaurs.Update(ctx, rexy.ID, app.DinosaurPatch{Name: &name, CageID: &cageID}); err != nil {
+		t.Fatal(err)
+	}
+	store.called(t, "Update", rexy.ID, app.DinosaurPatch{Name: &name, CageID: &cageID})
+
+	if err := c.Dinosaurs.Delete(ctx, rexy.ID); err != nil {
+		t.Fatal(err)
+	}
+	store.called(t, "Delete", rexy.ID)
+
+	if _, err := c.Dinosaurs.Restore(ctx, rexy.ID); err != nil {
+		t.Fatal(err)
+	}
+	store.called(t, "Restore", rexy.ID)
+}
+
+func TestClientErrors(t *testing.T) {
+	cages := &stubCageStore{}
+	dinosaurs := &stubDinosaurStore{}
+	_, c := newTestServer(t, &api.Server{CageStore: cages, DinosaurStore: dinosaurs})
+	ctx := context.Background()
+
+	getCage := func(id string) error {
+		_, err := c.Cages.Get(ctx, id, app.GetOptions{})
+		return err
+	}
+	addDinosaur := func() error {
+		_, err := c.Dinosaurs.Add(ctx, &app.Dinosaur{Name: "Blue", Species: app.DinosaurSpeciesVelociraptor, CageID: uuid.NewString()})
+		return err
+	}
+	updateCage := func() error {
+		capacity := 1
+		_, err := c.Cages.Update(ctx, uuid.NewString(), app.CagePatch{Capacity: &capacity})
+		return err
+	}
+	deleteCage := func() error {
+		return c.Cages.Delete(ctx, uuid.NewString())
+	}
+
+	tests := []struct {
+		name   string
+		err    error
+		call   func() error
+		target error
+		status int
+	}{
+		{"not found", app.ErrNotFound, func() error { return getCage(uuid.NewString()) }, app.ErrNotFound, http.StatusNotFound},
+		{"capacity exceeded", app.ErrCapacityExceeded, addDinosaur, app.ErrCapacityExceeded, http.StatusConflict},
+		{"powered down", app.ErrCagePoweredDown, addDinosaur, app.ErrCagePoweredDown, http.StatusConflict},
+		{"locked down", app.ErrCageLockedDown, addDinosaur, app.ErrCageLockedDown, http.StatusConflict},
+		{"not certified", app.ErrNotCertified, addDinosaur, app.ErrNotCertified, http.StatusConflict},
+		{"species mismatch", app.ErrSpeciesMismatch, addDinosaur, app.ErrSpeciesMismatch, http.StatusConflict},
+		{"habitat mismatch", app.ErrHabitatMismatch, addDinosaur, app.ErrHabitatMismatch, http.StatusConflict},
+		{"quarantine mismatch", app.ErrQuarantineMismatch, addDinosaur, app.ErrQuarantineMismatch, http.StatusConflict},
+		{"capacity below occupancy", app.ErrCapacityBelowOccupancy, updateCage, app.ErrCapacityBelowOccupancy, http.StatusConflict},
+		{"sector not found", app.ErrSectorNotFound, updateCage, app.ErrSectorNotFound, http.StatusConflict},
+		{"conflict", app.ErrConflict, deleteCage, app.ErrConflict, http.StatusConflict},
+		{"bad request", nil, func() error { return getCage("foo") }, nil, http.StatusBadRequest},
+		{"internal error", errors.New("boom"), deleteCage, nil, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cages.err, dinosaurs.err = tt.err, tt.err
+
+			err := tt.call()
+
+			var apiErr *Error
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("Expected *Error got %v", err)
+			}
+			if want, got := tt.status, apiErr.StatusCode; want != got {
+				t.Fatalf("Expected status %d got %d", want, got)
+			}
+			if want, got := tt.target, apiErr.Err; want != got {
+				t.Fatalf("Expected error %v got %v", want, got)
+			}
+			if tt.target != nil && !errors.Is(err, tt.target) {
+				t.Fatalf("Expected errors.Is %v", tt.target)
+			}
+		})
+	}
+}
+
+func TestClientResizeBelowOccupancy(t *testing.T) {
+	store := &stubCageStore{err: &app.OccupancyError{Occupancy: 2}}
+	_, c := newTestServer(t, &api.Server{CageStore: store})
+
+	_, err := c.Cages.Resize(context.Background(), uuid.NewString(), 1)
+
+	var occupancyErr *app.OccupancyError
+	if !errors.As(err, &occupancyErr) {
+		t.Fatalf("Expected *app.OccupancyError got %v", err)
+	}
+	if want, got := 2, occupancyErr.Occupancy; want != got {
+		t.Fatalf("Expected Occupancy %d got %d", want, got)
+	}
+	if !errors.Is(err, app.ErrCapacityBelowOccupancy) {
+		t.Fatalf("Expected errors.Is %v", app.ErrCapacityBelowOccupancy)
+	}
+}
+
+func TestClientListFilters(t *testing.T) {
+	var query url.Values
+	cages := &stubCageStore{}
+	dinosaurs := &stubDinosaurStore{}
+	_, c := newTestServer(t, &api.Server{CageStore: cages, DinosaurStore: dinosaurs}, func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query = r.URL.Query()
+			next.ServeHTTP(w, r)
+		})
+	})
+	ctx := context.Background()
+
+	quarantine := true
+	cageFilter := app.CageFilter{
+		IDs:             []string{uuid.NewString(), uuid.NewString()},
+		Type:            app.CageTypePaddock,
+		Status:          app.CageStatusActive,
+		Quarantine:      &quarantine,
+		ZoneID:          uuid.NewString(),
+		MinFreeCapacity: 1,
+		Occupancy:       app.CageOccupancyEmpty,
+		Created:         app.TimeRange{After: time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)},
+		Sort:            []app.Sort{{Field: "occupancy", Desc: true}, {Field: "createdAt"}},
+		IncludeDeleted:  true,
+	}
+	if _, err := c.Cages.List(ctx, cageFilter); err != nil {
+		t.Fatal(err)
+	}
+	cages.called(t, "List", cageFilter)
+	if want, got := "-occupancy,createdAt", query.Get("sort"); want != got {
+		t.Fatalf("Expected sort %s got %s", want, got)
+	}
+	if want, got := "2023-01-02T03:04:05Z", query.Get("createdAfter"); want != got {
+		t.Fatalf("Expected createdAfter %s got %s", want, got)
+	}
+
+	dinosaurFilter := app.DinosaurFilter{
+		CageIDs:      []string{uuid.NewString()},
+		Species:      []app.DinosaurSpecies{app.DinosaurSpeciesVelociraptor, app.DinosaurSpeciesTyrannosaurus},
+		Diet:         app.DinosaurTypeCarnivore,
+		Stages:       []app.LifecycleStage{app.LifecycleStageJuvenile},
+		HealthStatus: app.HealthStatusSick,
+		Quarantined:  &quarantine,
+		NamePrefix:   "Bl",
+		HatchDate:    app.TimeRange{Before: now},
+		Sort:         []app.Sort{{Field: "name"}},
+	}
+	if _, err := c.Dinosaurs.List(ctx, dinosaurFilter); err != nil {
+		t.Fatal(err)
+	}
+	dinosaurs.called(t, "List", dinosaurFilter)
+	if want, got := "velociraptor,tyrannosaurus", query.Get("species"); want != got {
+		t.Fatalf("Expected species %s got %s", want, got)
+	}
+}
+
+func TestClientZones(t *testing.T) {
+	zones := &stubZoneStore{}
+	cages := &stubCageStore{}
+	_, c := newTestServer(t, &api.Server{ZoneStore: zones, CageStore: cages})
+	ctx := context.Background()
+
+	zones.zone = app.Zone{ID: uuid.NewString(), Name: "Paddock North", HeadCapacity: 3, HeadCount: 1, CreatedAt: now, UpdatedAt: now}
+	zone, err := c.Zones.Add(ctx, &app.Zone{Name: "Paddock North"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "Paddock North", zones.args[0].(app.Zone).Name; want != got {
+		t.Fatalf("Expected Name %s got %s", want, got)
+	}
+
+	zones.zone.Sectors = []app.Sector{{ID: uuid.NewString(), ZoneID: zone.ID, Name: "East", HeadCapacity: 3, HeadCount: 1, CreatedAt: now, UpdatedAt: now}}
+	got, err := c.Zones.Get(ctx, zone.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zones.called(t, "Get", zone.ID)
+	if want, got := zones.zone, *got; !reflect.DeepEqual(want, got) {
+		t.Fatalf("Expected zone %+v got %+v", want, got)
+	}
+
+	zones.zones = []app.Zone{zones.zone}
+	if _, err := c.Zones.List(ctx); err != nil {
+		t.Fatal(err)
+	}
+	zones.called(t, "List")
+
+	zones.sector = zones.zone.Sectors[0]
+	sector, err := c.Zones.AddSector(ctx, &app.Sector{ZoneID: zone.ID, Name: "East"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := zone.ID, zones.args[0].(app.Sector).ZoneID; want != got {
+		t.Fatalf("Expected ZoneID %s got %s", want, got)
+	}
+	if want, got := zones.sector, *sector; want != got {
+		t.Fatalf("Expected sector %+v got %+v", want, got)
+	}
+
+	if _, err := c.Zones.ListSectors(ctx, zone.ID); err != nil {
+		t.Fatal(err)
+	}
+	zones.called(t, "ListSectors", zone.ID)
+
+	if _, err := c.Cages.List(ctx, app.CageFilter{ZoneID: zone.ID, SectorID: sector.ID}); err != nil {
+		t.Fatal(err)
+	}
+	cages.called(t, "List", app.CageFilter{ZoneID: zone.ID, SectorID: sector.ID})
+
+	zones.err = app.ErrConflict
+	if _, err := c.Zones.Add(ctx, &app.Zone{Name: "Paddock North"}); !errors.Is(err, app.ErrConflict) {
+		t.Fatalf("Expected error %v got %v", app.ErrConflict, err)
+	}
+	zones.err = app.ErrNotFound
+	if _, err := c.Zones.Get(ctx, uuid.NewString()); !errors.Is(err, app.ErrNotFound) {
+		t.Fatalf("Expected error %v got %v", app.ErrNotFound, err)
+	}
+}
+
+func TestClientFeedings(t *testing.T) {
+	store := &stubFeedingStore{}
+	_, c := newTestServer(t, &api.Server{FeedingStore: store})
+	ctx := context.Background()
+
+	cageID, dinosaurID := uuid.NewString(), uuid.NewString()
+	store.schedule = app.FeedingSchedule{
+		ID:            uuid.NewString(),
+		CageID:        cageID,
+		Diet:          "goats",
+		Quantity:      50,
+		IntervalHours: 24,
+		WindowStart:   "08:00",
+		WindowEnd:     "10:00",
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	schedule, err := c.Feedings.AddSchedule(ctx, &app.FeedingSchedule{
+		CageID:        cageID,
+		Diet:          "goats",
+		Quantity:      50,
+		IntervalHours: 24,
+		WindowStart:   "08:00",
+		WindowEnd:     "10:00",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := (app.FeedingSchedule{CageID: cageID, Diet: "goats", Quantity: 50, IntervalHours: 24, WindowStart: "08:00", WindowEnd: "10:00"}), store.args[0].(app.FeedingSchedule); want != got {
+		t.Fatalf("Expected schedule %+v got %+v", want, got)
+	}
+	if want, got := store.schedule, *schedule; want != got {
+		t.Fatalf("Expected schedule %+v got %+v", want, got)
+	}
+
+	if _, err := c.Feedings.ListSchedules(ctx, app.FeedingScheduleFilter{Species: app.DinosaurSpeciesTyrannosaurus}); err != nil {
+		t.Fatal(err)
+	}
+	store.called(t, "ListSchedules", app.FeedingScheduleFilter{Species: app.DinosaurSpeciesTyrannosaurus})
+
+	store.feeding = app.Feeding{ID: uuid.NewString(), CageID: cageID, DinosaurID: dinosaurID, Diet: "goats", Quantity: 50, Unsafe: true, FedAt: now, CreatedAt: now}
+	feeding, err := c.Feedings.Record(ctx, &app.Feeding{DinosaurID: dinosaurID, Diet: "goats", Quantity: 50, FedAt: now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := (app.Feeding{DinosaurID: dinosaurID, Diet: "goats", Quantity: 50, FedAt: now}), store.args[0].(app.Feeding); want != got {
+		t.Fatalf("Expected feeding %+v got %+v", want, got)
+	}
+	if want, got := store.feeding, *feeding; want != got {
+		t.Fatalf("Expected feeding %+v got %+v", want, got)
+	}
+
+	filter := app.FeedingFilter{CageID: cageID, UnsafeOnly: true, Fed: app.TimeRange{After: now.Add(-time.Hour)}}
+	if _, err := c.Feedings.List(ctx, filter); err != nil {
+		t.Fatal(err)
+	}
+	store.called(t, "List", filter)
+
+	store.overdue = []app.OverdueFeeding{{ScheduleID: schedule.ID, DinosaurID: dinosaurID, Name: "Rexy", Species: app.DinosaurSpeciesTyrannosaurus, CageID: cageID, DueAt: now}}
+	overdue, err := c.Feedings.Overdue(ctx, now.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.called(t, "Overdue", now.Add(time.Hour))
+	if want, got := store.overdue, overdue; !reflect.DeepEqual(want, got) {
+		t.Fatalf("Expected overdue %+v got %+v", want, got)
+	}
+
+	if err := c.Feedings.DeleteSchedule(ctx, schedule.ID); err != nil {
+		t.Fatal(err)
+	}
+	store.called(t, "DeleteSchedule", schedule.ID)
+
+	store.err = app.ErrNotFound
+	if err := c.Feedings.DeleteSchedule(ctx, schedule.ID); !errors.Is(err, app.ErrNotFound) {
+		t.Fatalf("Expected error %v got %v", app.ErrNotFound, err)
+	}
+	store.err = app.ErrNotInCage
+	if _, err := c.Feedings.Record(ctx, &app.Feeding{DinosaurID: dinosaurID, Diet: "goats", Quantity: 50}); !errors.Is(err, app.ErrNotInCage) {
+		t.Fatalf("Expected error %v got %v", app.ErrNotInCage, err)
+	}
+}
+
+func TestClientHealthRecords(t *testing.T) {
+	store := &stubDinosaurStore{}
+	_, c := newTestServer(t, &api.Server{DinosaurStore: store})
+	ctx := context.Background()
+
+	dinosaurID := uuid.NewString()
+	store.health = app.HealthRecord{
+		ID:         uuid.NewString(),
+		DinosaurID: dinosaurID,
+		Kind:       app.HealthRecordKindDiagnosis,
+		Author:     "Dr. Harding",
+		Notes:      "Stomach ache",
+		Status:     app.HealthStatusSick,
+		RecordedAt: now.Add(-time.Hour),
+		CreatedAt:  now,
+	}
+	record, err := c.Dinosaurs.AddHealthRecord(ctx, &app.HealthRecord{
+		DinosaurID: dinosaurID,
+		Kind:       app.HealthRecordKindDiagnosis,
+		Author:     "Dr. Harding",
+		Notes:      "Stomach ache",
+		Status:     app.HealthStatusSick,
+		RecordedAt: now.Add(-time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	added := store.args[0].(app.HealthRecord)
+	added.ID, added.CreatedAt = store.health.ID, store.health.CreatedAt
+	if want, got := store.health, added; want != got {
+		t.Fatalf("Expected record %+v got %+v", want, got)
+	}
+	if want, got := store.health, *record; want != got {
+		t.Fatalf("Expected record %+v got %+v", want, got)
+	}
+
+	if _, err := c.Dinosaurs.AddHealthRecord(ctx, &app.HealthRecord{DinosaurID: dinosaurID, Kind: app.HealthRecordKindWeight, Author: "Dr. Harding", WeightKg: 7200.5}); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 7200.5, store.args[0].(app.HealthRecord).WeightKg; want != got {
+		t.Fatalf("Expected WeightKg %v got %v", want, got)
+	}
+
+	store.records = []app.HealthRecord{store.health}
+	filter := app.HealthRecordFilter{Kind: app.HealthRecordKindDiagnosis, Recorded: app.TimeRange{After: now.Add(-90 * time.Minute)}}
+	records, err := c.Dinosaurs.HealthRecords(ctx, dinosaurID, filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.called(t, "HealthRecords", dinosaurID, filter)
+	if want, got := store.records, records; !reflect.DeepEqual(want, got) {
+		t.Fatalf("Expected records %+v got %+v", want, got)
+	}
+
+	store.err = app.ErrNotFound
+	if _, err := c.Dinosaurs.AddHealthRecord(ctx, &app.HealthRecord{DinosaurID: uuid.NewString(), Kind: app.HealthRecordKindNote, Author: "Dr. Harding"}); !errors.Is(err, app.ErrNotFound) {
+		t.Fatalf("Expected error %v got %v", app.ErrNotFound, err)
+	}
+}
+
+func TestClientQuarantine(t *testing.T) {
+	wardID, paddockID := uuid.NewString(), uuid.NewString()
+	store := &stubDinosaurStore{dinosaur: testDinosaur(wardID)}
+	_, c := newTestServer(t, &api.Server{DinosaurStore: store})
+	ctx := context.Background()
+
+	start, end := now, now.Add(14*24*time.Hour)
+	store.dinosaur.Quarantined = true
+	store.dinosaur.QuarantineReason = "Fever"
+	store.dinosaur.QuarantineStart = &start
+	store.dinosaur.QuarantineEnd = &end
+	quarantine := app.Quarantine{CageID: wardID, Reason: "Fever", Start: start, End: end}
+	quarantined, err := c.Dinosaurs.Quarantine(ctx, store.dinosaur.ID, quarantine)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.called(t, "Quarantine", store.dinosaur.ID, quarantine)
+	if !quarantined.Quarantined || quarantined.QuarantineStart == nil || !quarantined.QuarantineStart.Equal(start) {
+		t.Fatalf("Expected the dinosaur to be quarantined got %+v", quarantined)
+	}
+	if quarantined.QuarantineEnd == nil || !quarantined.QuarantineEnd.Equal(end) {
+		t.Fatalf("Expected QuarantineEnd %v got %v", end, quarantined.QuarantineEnd)
+	}
+
+	store.dinosaur = testDinosaur(paddockID)
+	released, err := c.Dinosaurs.Release(ctx, store.dinosaur.ID, paddockID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.called(t, "Release", store.dinosaur.ID, paddockID)
+	if released.Quarantined || released.QuarantineStart != nil || released.QuarantineReason != "" {
+		t.Fatalf("Expected the dinosaur to be released got %+v", released)
+	}
+
+	store.err = app.ErrQuarantineMismatch
+	if _, err := c.Dinosaurs.Quarantine(ctx, store.dinosaur.ID, app.Quarantine{CageID: paddockID, Reason: "Fever"}); !errors.Is(err, app.ErrQuarantineMismatch) {
+		t.Fatalf("Expected error %v got %v", app.ErrQuarantineMismatch, err)
+	}
+	store.err = app.ErrConflict
+	if _, err := c.Dinosaurs.Release(ctx, store.dinosaur.ID, paddockID); !errors.Is(err, app.ErrConflict) {
+		t.Fatalf("Expected error %v got %v", app.ErrConflict, err)
+	}
+}
+
+func TestClientLifecycle(t *testing.T) {
+	store := &stubDinosaurStore{}
+	_, c := newTestServer(t, &api.Server{DinosaurStore: store})
+	ctx := context.Background()
+
+	hatchDate := now.Add(7 * 24 * time.Hour)
+	store.dinosaur = testDinosaur("")
+	store.dinosaur.Stage = app.LifecycleStageEgg
+	store.dinosaur.HatchDate = &hatchDate
+	egg, err := c.Dinosaurs.AddEgg(ctx, app.Egg{Name: "Blue", Species: app.DinosaurSpeciesVelociraptor, HatchDate: hatchDate})
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.called(t, "AddEgg", app.Egg{Name: "Blue", Species: app.DinosaurSpeciesVelociraptor, HatchDate: hatchDate})
+	if want, got := app.LifecycleStageEgg, egg.Stage; want != got {
+		t.Fatalf("Expected Stage %s got %s", want, got)
+	}
+	if egg.HatchDate == nil || !egg.HatchDate.Equal(hatchDate) {
+		t.Fatalf("Expected HatchDate %v got %v", hatchDate, egg.HatchDate)
+	}
+
+	cageID := uuid.NewString()
+	store.dinosaur = testDinosaur(cageID)
+	store.dinosaur.Stage = app.LifecycleStageJuvenile
+	hatched, err := c.Dinosaurs.Hatch(ctx, egg.ID, cageID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.called(t, "Hatch", egg.ID, cageID)
+	if want, got := cageID, hatched.CageID; want != got {
+		t.Fatalf("Expected CageID %s got %s", want, got)
+	}
+
+	store.event = app.LifecycleEvent{
+		ID:         uuid.NewString(),
+		DinosaurID: egg.ID,
+		From:       app.LifecycleStageJuvenile,
+		To:         app.LifecycleStageTransferredOut,
+		CageID:     cageID,
+		Notes:      "Isla Sorna",
+		OccurredAt: now,
+		CreatedAt:  now,
+	}
+	transition := app.LifecycleTransition{Stage: app.LifecycleStageTransferredOut, Notes: "Isla Sorna", OccurredAt: now}
+	event, err := c.Dinosaurs.Transition(ctx, egg.ID, transition)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.called(t, "Transition", egg.ID, transition)
+	if want, got := store.event, *event; want != got {
+		t.Fatalf("Expected event %+v got %+v", want, got)
+	}
+
+	store.events = []app.LifecycleEvent{store.event}
+	events, err := c.Dinosaurs.LifecycleEvents(ctx, egg.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.called(t, "LifecycleEvents", egg.ID)
+	if want, got := store.events, events; !reflect.DeepEqual(want, got) {
+		t.Fatalf("Expected events %+v got %+v", want, got)
+	}
+
+	store.err = app.ErrNotInCage
+	if _, err := c.Dinosaurs.Move(ctx, egg.ID, cageID); !errors.Is(err, app.ErrNotInCage) {
+		t.Fatalf("Expected error %v got %v", app.ErrNotInCage, err)
+	}
+	store.err = app.ErrIllegalTransition
+	if _, err := c.Dinosaurs.Transition(ctx, egg.ID, app.LifecycleTransition{Stage: app.LifecycleStageJuvenile}); !errors.Is(err, app.ErrIllegalTransition) {
+		t.Fatalf("Expected error %v got %v", app.ErrIllegalTransition, err)
+	}
+}
+
+func TestClientBreeding(t *testing.T) {
+	store := &stubBreedingStore{}
+	_, c := newTestServer(t, &api.Server{BreedingStore: store})
+	ctx := context.Background()
+
+	cera, bess, bull := uuid.NewString(), uuid.NewString(), uuid.NewString()
+	store.lineage = app.Lineage{
+		DinosaurID: cera,
+		ParentIDs:  []string{bess, bull},
+		Ancestors: []app.Relative{
+			{ID: bess, Name: "Bess", Species: app.DinosaurSpeciesTriceratops, Stage: app.LifecycleStageAdult, ParentIDs: []string{}, Generation: 1},
+			{ID: bull, Name: "Bull", Species: app.DinosaurSpeciesTriceratops, Stage: app.LifecycleStageAdult, ParentIDs: []string{}, Generation: 1},
+		},
+		Descendants: []app.Relative{},
+	}
+	lineage, err := c.Breeding.SetParents(ctx, cera, []string{bess, bull})
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.called(t, "SetParents", cera, []string{bess, bull})
+	if want, got := store.lineage, *lineage; !reflect.DeepEqual(want, got) {
+		t.Fatalf("Expected lineage %+v got %+v", want, got)
+	}
+
+	// Clearing the parents sends an empty list rather than null.
+	if _, err := c.Breeding.SetParents(ctx, cera, nil); err != nil {
+		t.Fatal(err)
+	}
+	store.called(t, "SetParents", cera, []string{})
+
+	if _, err := c.Breeding.Lineage(ctx, cera, 3); err != nil {
+		t.Fatal(err)
+	}
+	store.called(t, "Lineage", cera, 3)
+
+	store.pair = app.BreedingPair{
+		ID:                    uuid.NewString(),
+		SireID:                bull,
+		DamID:                 bess,
+		Species:               app.DinosaurSpeciesTriceratops,
+		InbreedingCoefficient: 0.25,
+		Flagged:               true,
+		Notes:                 "Siblings",
+		CreatedAt:             now,
+	}
+	pair, err := c.Breeding.AddPair(ctx, &app.BreedingPair{SireID: bull, DamID: bess, Notes: "Siblings"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.called(t, "AddPair", app.BreedingPair{SireID: bull, DamID: bess, Notes: "Siblings"})
+	if want, got := store.pair, *pair; want != got {
+		t.Fatalf("Expected pair %+v got %+v", want, got)
+	}
+
+	store.pairs = []app.BreedingPair{store.pair}
+	pairs, err := c.Breeding.ListPairs(ctx, app.BreedingPairFilter{DinosaurID: bull, FlaggedOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.called(t, "ListPairs", app.BreedingPairFilter{DinosaurID: bull, FlaggedOnly: true})
+	if want, got := store.pairs, pairs; !reflect.DeepEqual(want, got) {
+		t.Fatalf("Expected pairs %+v got %+v", want, got)
+	}
+
+	store.err = app.ErrLineageCycle
+	if _, err := c.Breeding.SetParents(ctx, bess, []string{cera}); !errors.Is(err, app.ErrLineageCycle) {
+		t.Fatalf("Expected error %v got %v", app.ErrLineageCycle, err)
+	}
+	store.err = app.ErrNotAdult
+	if _, err := c.Breeding.AddPair(ctx, &app.BreedingPair{SireID: bull, DamID: bess}); !errors.Is(err, app.ErrNotAdult) {
+		t.Fatalf("Expected error %v got %v", app.ErrNotAdult, err)
+	}
+}
+
+func TestClientStaff(t *testing.T) {
+	staff := &stubStaffStore{}
+	cages := &stubCageStore{cage: testCage()}
+	_, c := newTestServer(t, &api.Server{StaffStore: staff, CageStore: cages})
+	ctx := context.Background()
+
+	staff.staff = app.Staff{
+		ID:             uuid.NewString(),
+		Name:           "Muldoon",
+		Role:           app.StaffRoleKeeper,
+		Certifications: []app.Certification{app.CertificationCarnivoreHandling},
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	muldoon, err := c.Staff.Add(ctx, &app.Staff{Name: "Muldoon", Role: app.StaffRoleKeeper, Certifications: []app.Certification{app.CertificationCarnivoreHandling}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	added := staff.args[0].(app.Staff)
+	if want, got := staff.staff.Certifications, added.Certifications; !reflect.DeepEqual(want, got) {
+		t.Fatalf("Expected Certifications %v got %v", want, got)
+	}
+	if want, got := staff.staff, *muldoon; !reflect.DeepEqual(want, got) {
+		t.Fatalf("Expected staff %+v got %+v", want, got)
+	}
+
+	start := now.Add(-time.Hour)
+	end := start.Add(8 * time.Hour)
+	staff.assignment = app.Assignment{ID: uuid.NewString(), StaffID: muldoon.ID, CageID: cages.cage.ID, StartsAt: start, EndsAt: end, CreatedAt: now}
+	assignment, err := c.Staff.Assign(ctx, &app.Assignment{StaffID: muldoon.ID, CageID: cages.cage.ID, StartsAt: start, EndsAt: end})
+	if err != nil {
+		t.Fatal(err)
+	}
+	staff.called(t, "Assign", app.Assignment{StaffID: muldoon.ID, CageID: cages.cage.ID, StartsAt: start, EndsAt: end})
+	if want, got := staff.assignment, *assignment; want != got {
+		t.Fatalf("Expected assignment %+v got %+v", want, got)
+	}
+
+	// The cages covered by a staff member are looked up by the assignments.
+	staff.assignments = []app.Assignment{staff.assignment}
+	cages.cages = []app.Cage{cages.cage}
+	covered, err := c.Staff.Cages(ctx, muldoon.ID, end)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cages.called(t, "List", app.CageFilter{IDs: []string{cages.cage.ID}})
+	if want, got := cages.cages, covered; !reflect.DeepEqual(want, got) {
+		t.Fatalf("Expected cages %+v got %+v", want, got)
+	}
+
+	// A cage in the filter lists the staff on duty for the cage.
+	staff.list = []app.Staff{staff.staff}
+	onDuty, err := c.Staff.List(ctx, app.StaffFilter{CageID: cages.cage.ID, OnDuty: end})
+	if err != nil {
+		t.Fatal(err)
+	}
+	staff.called(t, "List", app.StaffFilter{CageID: cages.cage.ID, OnDuty: end})
+	if want, got := staff.list, onDuty; !reflect.DeepEqual(want, got) {
+		t.Fatalf("Expected staff %+v got %+v", want, got)
+	}
+	if _, err := c.Staff.List(ctx, app.StaffFilter{Role: app.StaffRoleVeterinarian}); err != nil {
+		t.Fatal(err)
+	}
+	staff.called(t, "List", app.StaffFilter{Role: app.StaffRoleVeterinarian})
+
+	if _, err := c.Staff.ListAssignments(ctx, app.AssignmentFilter{StaffID: muldoon.ID, At: end}); err != nil {
+		t.Fatal(err)
+	}
+	staff.called(t, "ListAssignments", app.AssignmentFilter{StaffID: muldoon.ID, At: end})
+
+	if err := c.Staff.DeleteAssignment(ctx, assignment.ID); err != nil {
+		t.Fatal(err)
+	}
+	staff.called(t, "DeleteAssignment", assignment.ID)
+
+	staff.err = app.ErrNotCertified
+	if _, err := c.Staff.Assign(ctx, &app.Assignment{StaffID: muldoon.ID, CageID: cages.cage.ID, StartsAt: start, EndsAt: end}); !errors.Is(err, app.ErrNotCertified) {
+		t.Fatalf("Expected error %v got %v", app.ErrNotCertified, err)
+	}
+	staff.err = app.ErrNotFound
+	if err := c.Staff.DeleteAssignment(ctx, assignment.ID); !errors.Is(err, app.ErrNotFound) {
+		t.Fatalf("Expected error %v got %v", app.ErrNotFound, err)
+	}
+}
+
+func TestClientIncidents(t *testing.T) {
+	store := &stubIncidentStore{}
+	_, c := newTestServer(t, &api.Server{IncidentStore: store})
+	ctx := context.Background()
+
+	cageID, dinosaurID := uuid.NewString(), uuid.NewString()
+	store.incident = app.Incident{
+		ID:          uuid.NewString(),
+		Type:        app.IncidentTypeEscape,
+		Severity:    app.IncidentSeverityCritical,
+		CageID:      cageID,
+		DinosaurIDs: []string{dinosaurID},
+		Summary:     "Rexy is out",
+		Status:      app.IncidentStatusOpen,
+		OpenedAt:    now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	escape, err := c.Incidents.Open(ctx, &app.Incident{
+		Type:        app.IncidentTypeEscape,
+		Severity:    app.IncidentSeverityCritical,
+		CageID:      cageID,
+		DinosaurIDs: []string{dinosaurID},
+		Summary:     "Rexy is out",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	opened := store.args[0].(app.Incident)
+	if want, got := []string{dinosaurID}, opened.DinosaurIDs; !reflect.DeepEqual(want, got) {
+		t.Fatalf("Expected DinosaurIDs %v got %v", want, got)
+	}
+	if want, got := store.incident, *escape; !reflect.DeepEqual(want, got) {
+		t.Fatalf("Expected incident %+v got %+v", want, got)
+	}
+
+	occurredAt := now.Add(-time.Hour)
+	store.entry = app.IncidentEntry{ID: uuid.NewString(), IncidentID: escape.ID, Note: "Fence down", OccurredAt: occurredAt, CreatedAt: now}
+	entry, err := c.Incidents.AddEntry(ctx, escape.ID, &app.IncidentEntry{Note: "Fence down", OccurredAt: occurredAt})
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.called(t, "AddEntry", escape.ID, app.IncidentEntry{Note: "Fence down", OccurredAt: occurredAt})
+	if want, got := store.entry, *entry; want != got {
+		t.Fatalf("Expected entry %+v got %+v", want, got)
+	}
+
+	store.incident.Timeline = []app.IncidentEntry{store.entry}
+	incident, err := c.Incidents.Get(ctx, escape.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.called(t, "Get", escape.ID)
+	if want, got := store.incident, *incident; !reflect.DeepEqual(want, got) {
+		t.Fatalf("Expected incident %+v got %+v", want, got)
+	}
+
+	filter := app.IncidentFilter{CageID: cageID, Status: app.IncidentStatusOpen, Severity: app.IncidentSeverityCritical}
+	if _, err := c.Incidents.List(ctx, filter); err != nil {
+		t.Fatal(err)
+	}
+	store.called(t, "List", filter)
+
+	store.incident.Timeline = nil
+	store.incident.Status = app.IncidentStatusResolved
+	store.incident.Resolution = "Rexy is back, fence repaired"
+	store.incident.ResolvedAt = &now
+	resolved, err := c.Incidents.Resolve(ctx, escape.ID, "Rexy is back, fence repaired")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.called(t, "Resolve", escape.ID, "Rexy is back, fence repaired")
+	if want, got := app.IncidentStatusResolved, resolved.Status; want != got {
+		t.Fatalf("Expected status %s got %s", want, got)
+	}
+
+	store.err = app.ErrIncidentResolved
+	if _, err := c.Incidents.Resolve(ctx, escape.ID, "Again"); !errors.Is(err, app.ErrIncidentResolved) {
+		t.Fatalf("Expected error %v got %v", app.ErrIncidentResolved, err)
+	}
+	if _, err := c.Incidents.AddEntry(ctx, escape.ID, &app.IncidentEntry{Note: "Late"}); !errors.Is(err, app.ErrIncidentResolved) {
+		t.Fatalf("Expected error %v got %v", app.ErrIncidentResolved, err)
+	}
+}
+
+func TestClientFields(t *testing.T) {
+	cages := &stubCageStore{}
+	dinosaurs := &stubDinosaurStore{dinosaur: testDinosaur(uuid.NewString())}
+	_, c := newTestServer(t, &api.Server{CageStore: cages, DinosaurStore: dinosaurs})
+	ctx := context.Background()
+
+	dinosaur, err := c.Dinosaurs.Get(ctx, dinosaurs.dinosaur.ID, app.GetOptions{Fields: []string{"id", "name"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := (app.Dinosaur{ID: dinosaurs.dinosaur.ID, Name: "Rexy"}), *dinosaur; want != got {
+		t.Fatalf("Expected %+v got %+v", want, got)
+	}
+
+	cages.cages = []app.Cage{testCage()}
+	cages.cages[0].Occupancy = 1
+	list, err := c.Cages.List(ctx, app.CageFilter{Fields: []string{"occupancy"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := []app.Cage{{Occupancy: 1}}, list; !reflect.DeepEqual(want, got) {
+		t.Fatalf("Expected %+v got %+v", want, got)
+	}
+
+	_, err = c.Cages.Get(ctx, uuid.NewString(), app.GetOptions{Fields: []string{"name"}})
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected *Error got %v", err)
+	}
+	if want, got := http.StatusBadRequest, apiErr.StatusCode; want != got {
+		t.Fatalf("Expected status %d got %d", want, got)
+	}
+}
+
+func TestClientUnauthorized(t *testing.T) {
+	_, c := newTestServer(t, &api.Server{CageStore: &stubCageStore{}})
+	c.Token = "wrong"
+
+	_, err := c.Cages.List(context.Background(), app.CageFilter{})
+
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected *Error got %v", err)
+	}
+	if want, got := http.StatusUnauthorized, apiErr.StatusCode; want != got {
+		t.Fatalf("Expected status %d got %d", want, got)
+	}
+}
+
+// flaky fails the first n requests of any method with 503.
+func flaky(n int32, requests *atomic.Int32) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if requests.Add(1) <= n {
+				w.Header().Set("Retry-After", "0")
+				http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestClientRetries(t *testing.T) {
+	var requests atomic.Int32
+	_, c := newTestServer(t, &api.Server{CageStore: &stubCageStore{cage: testCage()}}, flaky(2, &requests))
+	ctx := context.Background()
+
+	// Idempotent requests are retried.
+	if _, err := c.Cages.List(ctx, app.CageFilter{}); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := int32(3), requests.Load(); want != got {
+		t.Fatalf("Expected requests %d got %d", want, got)
+	}
+
+	// Giving up after MaxRetries.
+	requests.Store(0)
+	c.MaxRetries = 1
+	_, err := c.Cages.List(ctx, app.CageFilter{})
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("Expected 503 got %v", err)
+	}
+	if want, got := int32(2), requests.Load(); want != got {
+		t.Fatalf("Expected requests %d got %d", want, got)
+	}
+
+	// Non-idempotent requests are not retried.
+	requests.Store(0)
+	c.MaxRetries = DefaultMaxRetries
+	if _, err := c.Cages.Add(ctx, &app.Cage{Capacity: 1, Status: app.CageStatusActive}); err == nil {
+		t.Fatal("Expected error got nil")
+	}
+	if want, got := int32(1), requests.Load(); want != got {
+		t.Fatalf("Expected requests %d got %d", want, got)
+	}
+}
+
+func TestClientContextCancellation(t *testing.T) {
+	var requests atomic.Int32
+	_, c := newTestServer(t, &api.Server{DinosaurStore: &stubDinosaurStore{}}, flaky(1000, &requests))
+	c.MinBackoff = time.Hour
+	c.MaxBackoff = time.Hour
+
+	// The server asks to retry right away, make the client wait instead.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := c.Dinosaurs.Get(ctx, uuid.NewString(), app.GetOptions{})
+	if err == nil {
+		t.Fatal("Expected error got nil")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected %v got %v", context.DeadlineExceeded, err)
+	}
+}
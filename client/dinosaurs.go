@@ -0,0 +1,291 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+// DinosaurClient mirrors the dinosaur store operations over the API.
+type DinosaurClient struct {
+	client *Client
+}
+
+// Add adds a dinosaur to the cage set in CageID.
+func (c *DinosaurClient) Add(ctx context.Context, dinosaur *app.Dinosaur) (*app.Dinosaur, error) {
+	req := struct {
+		Name    string              `json:"name"`
+		Species app.DinosaurSpecies `json:"species"`
+	}{
+		Name:    dinosaur.Name,
+		Species: dinosaur.Species,
+	}
+
+	var added app.Dinosaur
+	path := "/cages/" + url.PathEscape(dinosaur.CageID) + "/dinosaurs"
+	if err := c.client.do(ctx, http.MethodPost, path, nil, req, &added); err != nil {
+		return nil, err
+	}
+
+	return &added, nil
+}
+
+// List lists dinosaurs narrowed down by the filter.
+func (c *DinosaurClient) List(ctx context.Context, filter app.DinosaurFilter) ([]app.Dinosaur, error) {
+	path := "/dinosaurs"
+	if filter.CageID != app.IDUnspecified {
+		path = "/cages/" + url.PathEscape(filter.CageID) + "/dinosaurs"
+	}
+	species := make([]string, 0, len(filter.Species))
+	for _, s := range filter.Species {
+		species = append(species, string(s))
+	}
+	stages := make([]string, 0, len(filter.Stages))
+	for _, s := range filter.Stages {
+		stages = append(stages, string(s))
+	}
+	query := map[string]string{
+		"cageId":       strings.Join(filter.CageIDs, ","),
+		"zoneId":       filter.ZoneID,
+		"species":      strings.Join(species, ","),
+		"diet":         string(filter.Diet),
+		"stage":        strings.Join(stages, ","),
+		"healthStatus": string(filter.HealthStatus),
+		"quarantined":  boolQuery(filter.Quarantined),
+		"namePrefix":   filter.NamePrefix,
+		"fields":       strings.Join(filter.Fields, ","),
+	}
+	if !filter.HatchDate.After.IsZero() {
+		query["hatchAfter"] = filter.HatchDate.After.Format(time.RFC3339Nano)
+	}
+	if !filter.HatchDate.Before.IsZero() {
+		query["hatchBefore"] = filter.HatchDate.Before.Format(time.RFC3339Nano)
+	}
+	query = listQuery(query, filter.Created, filter.Updated, filter.Sort, filter.IncludeDeleted)
+
+	var dinosaurs []app.Dinosaur
+	if err := c.client.do(ctx, http.MethodGet, path, query, nil, &dinosaurs); err != nil {
+		return nil, err
+	}
+
+	return dinosaurs, nil
+}
+
+// Get gets a dinosaur by id.
+func (c *DinosaurClient) Get(ctx context.Context, id string, opts app.GetOptions) (*app.Dinosaur, error) {
+	var dinosaur app.Dinosaur
+	if err := c.client.do(ctx, http.MethodGet, "/dinosaurs/"+url.PathEscape(id), getQuery(opts), nil, &dinosaur); err != nil {
+		return nil, err
+	}
+
+	return &dinosaur, nil
+}
+
+// Move moves a dinosaur to a different cage.
+func (c *DinosaurClient) Move(ctx context.Context, id string, cageID string) (*app.Dinosaur, error) {
+	req := struct {
+		CageID string `json:"cageId"`
+	}{
+		CageID: cageID,
+	}
+
+	var dinosaur app.Dinosaur
+	if err := c.client.do(ctx, http.MethodPut, "/dinosaurs/"+url.PathEscape(id), nil, req, &dinosaur); err != nil {
+		return nil, err
+	}
+
+	return &dinosaur, nil
+}
+
+// Update applies a patch to a dinosaur.
+func (c *DinosaurClient) Update(ctx context.Context, id string, patch app.DinosaurPatch) (*app.Dinosaur, error) {
+	req := struct {
+		Name   *string `json:"name,omitempty"`
+		CageID *string `json:"cageId,omitempty"`
+	}{
+		Name:   patch.Name,
+		CageID: patch.CageID,
+	}
+
+	var dinosaur app.Dinosaur
+	if err := c.client.do(ctx, http.MethodPatch, "/dinosaurs/"+url.PathEscape(id), nil, req, &dinosaur); err != nil {
+		return nil, err
+	}
+
+	return &dinosaur, nil
+}
+
+// Delete deletes a dinosaur.
+func (c *DinosaurClient) Delete(ctx context.Context, id string) error {
+	return c.client.do(ctx, http.MethodDelete, "/dinosaurs/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// Restore restores a soft deleted dinosaur into its cage.
+func (c *DinosaurClient) Restore(ctx context.Context, id string) (*app.Dinosaur, error) {
+	var dinosaur app.Dinosaur
+	if err := c.client.do(ctx, http.MethodPost, "/dinosaurs/"+url.PathEscape(id)+"/restore", nil, nil, &dinosaur); err != nil {
+		return nil, err
+	}
+
+	return &dinosaur, nil
+}
+
+// Quarantine moves a dinosaur to a quarantine cage and flags it as quarantined.
+func (c *DinosaurClient) Quarantine(ctx context.Context, id string, quarantine app.Quarantine) (*app.Dinosaur, error) {
+	req := struct {
+		CageID string     `json:"cageId"`
+		Reason string     `json:"reason"`
+		Start  *time.Time `json:"start,omitempty"`
+		End    *time.Time `json:"end,omitempty"`
+	}{
+		CageID: quarantine.CageID,
+		Reason: quarantine.Reason,
+	}
+	if !quarantine.Start.IsZero() {
+		req.Start = &quarantine.Start
+	}
+	if !quarantine.End.IsZero() {
+		req.End = &quarantine.End
+	}
+
+	var dinosaur app.Dinosaur
+	if err := c.client.do(ctx, http.MethodPost, "/dinosaurs/"+url.PathEscape(id)+"/quarantine", nil, req, &dinosaur); err != nil {
+		return nil, err
+	}
+
+	return &dinosaur, nil
+}
+
+// Release ends the quarantine of a dinosaur and moves it to a regular cage.
+func (c *DinosaurClient) Release(ctx context.Context, id string, cageID string) (*app.Dinosaur, error) {
+	req := struct {
+		CageID string `json:"cageId"`
+	}{
+		CageID: cageID,
+	}
+
+	var dinosaur app.Dinosaur
+	if err := c.client.do(ctx, http.MethodPost, "/dinosaurs/"+url.PathEscape(id)+"/release", nil, req, &dinosaur); err != nil {
+		return nil, err
+	}
+
+	return &dinosaur, nil
+}
+
+// AddEgg lays an egg into the hatchery.
+func (c *DinosaurClient) AddEgg(ctx context.Context, egg app.Egg) (*app.Dinosaur, error) {
+	req := struct {
+		Name      string              `json:"name"`
+		Species   app.DinosaurSpecies `json:"species"`
+		HatchDate time.Time           `json:"hatchDate"`
+	}{
+		Name:      egg.Name,
+		Species:   egg.Species,
+		HatchDate: egg.HatchDate,
+	}
+
+	var added app.Dinosaur
+	if err := c.client.do(ctx, http.MethodPost, "/hatchery", nil, req, &added); err != nil {
+		return nil, err
+	}
+
+	return &added, nil
+}
+
+// Hatch hatches an egg into a cage as a juvenile.
+func (c *DinosaurClient) Hatch(ctx context.Context, id string, cageID string) (*app.Dinosaur, error) {
+	req := struct {
+		CageID string `json:"cageId"`
+	}{
+		CageID: cageID,
+	}
+
+	var dinosaur app.Dinosaur
+	if err := c.client.do(ctx, http.MethodPost, "/hatchery/"+url.PathEscape(id)+"/hatch", nil, req, &dinosaur); err != nil {
+		return nil, err
+	}
+
+	return &dinosaur, nil
+}
+
+// Transition moves a dinosaur to the next stage of its lifecycle.
+func (c *DinosaurClient) Transition(ctx context.Context, id string, transition app.LifecycleTransition) (*app.LifecycleEvent, error) {
+	req := struct {
+		Stage      app.LifecycleStage `json:"stage"`
+		Notes      string             `json:"notes,omitempty"`
+		OccurredAt *time.Time         `json:"occurredAt,omitempty"`
+	}{
+		Stage: transition.Stage,
+		Notes: transition.Notes,
+	}
+	if !transition.OccurredAt.IsZero() {
+		req.OccurredAt = &transition.OccurredAt
+	}
+
+	var event app.LifecycleEvent
+	if err := c.client.do(ctx, http.MethodPost, "/dinosaurs/"+url.PathEscape(id)+"/lifecycle", nil, req, &event); err != nil {
+		return nil, err
+	}
+
+	return &event, nil
+}
+
+// LifecycleEvents lists the lifecycle events of a dinosaur.
+func (c *DinosaurClient) LifecycleEvents(ctx context.Context, id string) ([]app.LifecycleEvent, error) {
+	var events []app.LifecycleEvent
+	if err := c.client.do(ctx, http.MethodGet, "/dinosaurs/"+url.PathEscape(id)+"/lifecycle", nil, nil, &events); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// AddHealthRecord adds a health record of a dinosaur.
+func (c *DinosaurClient) AddHealthRecord(ctx context.Context, record *app.HealthRecord) (*app.HealthRecord, error) {
+	req := struct {
+		Kind       app.HealthRecordKind `json:"kind"`
+		Author     string               `json:"author,omitempty"`
+		WeightKg   float64              `json:"weightKg,omitempty"`
+		Notes      string               `json:"notes,omitempty"`
+		Status     app.HealthStatus     `json:"status,omitempty"`
+		RecordedAt *time.Time           `json:"recordedAt,omitempty"`
+	}{
+		Kind:     record.Kind,
+		Author:   record.Author,
+		WeightKg: record.WeightKg,
+		Notes:    record.Notes,
+		Status:   record.Status,
+	}
+	if !record.RecordedAt.IsZero() {
+		req.RecordedAt = &record.RecordedAt
+	}
+
+	var added app.HealthRecord
+	if err := c.client.do(ctx, http.MethodPost, "/dinosaurs/"+url.PathEscape(record.DinosaurID)+"/health", nil, req, &added); err != nil {
+		return nil, err
+	}
+
+	return &added, nil
+}
+
+// HealthRecords lists the health records of a dinosaur narrowed down by the filter.
+func (c *DinosaurClient) HealthRecords(ctx context.Context, id string, filter app.HealthRecordFilter) ([]app.HealthRecord, error) {
+	query := map[string]string{"kind": string(filter.Kind)}
+	if !filter.Recorded.After.IsZero() {
+		query["recordedAfter"] = filter.Recorded.After.Format(time.RFC3339Nano)
+	}
+	if !filter.Recorded.Before.IsZero() {
+		query["recordedBefore"] = filter.Recorded.Before.Format(time.RFC3339Nano)
+	}
+
+	var records []app.HealthRecord
+	if err := c.client.do(ctx, http.MethodGet, "/dinosaurs/"+url.PathEscape(id)+"/health", query, nil, &records); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
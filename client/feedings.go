@@ -0,0 +1,128 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+// FeedingClient mirrors the feeding store operations over the API.
+type FeedingClient struct {
+	client *Client
+}
+
+// AddSchedule adds a new feeding schedule of a cage or of a species.
+func (c *FeedingClient) AddSchedule(ctx context.Context, schedule *app.FeedingSchedule) (*app.FeedingSchedule, error) {
+	req := struct {
+		CageID        string              `json:"cageId,omitempty"`
+		Species       app.DinosaurSpecies `json:"species,omitempty"`
+		Diet          string              `json:"diet"`
+		Quantity      float64             `json:"quantity"`
+		IntervalHours int                 `json:"intervalHours"`
+		WindowStart   string              `json:"windowStart,omitempty"`
+		WindowEnd     string              `json:"windowEnd,omitempty"`
+	}{
+		CageID:        schedule.CageID,
+		Species:       schedule.Species,
+		Diet:          schedule.Diet,
+		Quantity:      schedule.Quantity,
+		IntervalHours: schedule.IntervalHours,
+		WindowStart:   schedule.WindowStart,
+		WindowEnd:     schedule.WindowEnd,
+	}
+
+	var added app.FeedingSchedule
+	if err := c.client.do(ctx, http.MethodPost, "/feeding-schedules", nil, req, &added); err != nil {
+		return nil, err
+	}
+
+	return &added, nil
+}
+
+// ListSchedules lists feeding schedules narrowed down by the filter.
+func (c *FeedingClient) ListSchedules(ctx context.Context, filter app.FeedingScheduleFilter) ([]app.FeedingSchedule, error) {
+	query := map[string]string{
+		"cageId":  filter.CageID,
+		"species": string(filter.Species),
+	}
+
+	var schedules []app.FeedingSchedule
+	if err := c.client.do(ctx, http.MethodGet, "/feeding-schedules", query, nil, &schedules); err != nil {
+		return nil, err
+	}
+
+	return schedules, nil
+}
+
+// DeleteSchedule deletes a feeding schedule.
+func (c *FeedingClient) DeleteSchedule(ctx context.Context, id string) error {
+	return c.client.do(ctx, http.MethodDelete, "/feeding-schedules/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// Record records a feeding of a dinosaur or of all occupants of a cage.
+func (c *FeedingClient) Record(ctx context.Context, feeding *app.Feeding) (*app.Feeding, error) {
+	req := struct {
+		CageID     string     `json:"cageId,omitempty"`
+		DinosaurID string     `json:"dinosaurId,omitempty"`
+		Diet       string     `json:"diet"`
+		Quantity   float64    `json:"quantity"`
+		FedAt      *time.Time `json:"fedAt,omitempty"`
+	}{
+		CageID:     feeding.CageID,
+		DinosaurID: feeding.DinosaurID,
+		Diet:       feeding.Diet,
+		Quantity:   feeding.Quantity,
+	}
+	if !feeding.FedAt.IsZero() {
+		req.FedAt = &feeding.FedAt
+	}
+
+	var recorded app.Feeding
+	if err := c.client.do(ctx, http.MethodPost, "/feedings", nil, req, &recorded); err != nil {
+		return nil, err
+	}
+
+	return &recorded, nil
+}
+
+// List lists recorded feedings narrowed down by the filter.
+func (c *FeedingClient) List(ctx context.Context, filter app.FeedingFilter) ([]app.Feeding, error) {
+	query := map[string]string{
+		"cageId":     filter.CageID,
+		"dinosaurId": filter.DinosaurID,
+	}
+	if filter.UnsafeOnly {
+		query["unsafe"] = "true"
+	}
+	if !filter.Fed.After.IsZero() {
+		query["fedAfter"] = filter.Fed.After.Format(time.RFC3339Nano)
+	}
+	if !filter.Fed.Before.IsZero() {
+		query["fedBefore"] = filter.Fed.Before.Format(time.RFC3339Nano)
+	}
+
+	var feedings []app.Feeding
+	if err := c.client.do(ctx, http.MethodGet, "/feedings", query, nil, &feedings); err != nil {
+		return nil, err
+	}
+
+	return feedings, nil
+}
+
+// Overdue reports the dinosaurs that are overdue for a feeding at the given time.
+func (c *FeedingClient) Overdue(ctx context.Context, at time.Time) ([]app.OverdueFeeding, error) {
+	query := map[string]string{}
+	if !at.IsZero() {
+		query["at"] = at.Format(time.RFC3339Nano)
+	}
+
+	var overdue []app.OverdueFeeding
+	if err := c.client.do(ctx, http.MethodGet, "/feedings/overdue", query, nil, &overdue); err != nil {
+		return nil, err
+	}
+
+	return overdue, nil
+}
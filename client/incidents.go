@@ -0,0 +1,102 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+// IncidentClient mirrors the incident store operations over the API.
+type IncidentClient struct {
+	client *Client
+}
+
+// Open a new incident in a cage.
+// An open critical containment incident locks the cage down until it's resolved.
+func (c *IncidentClient) Open(ctx context.Context, incident *app.Incident) (*app.Incident, error) {
+	req := struct {
+		Type        app.IncidentType     `json:"type"`
+		Severity    app.IncidentSeverity `json:"severity"`
+		CageID      string               `json:"cageId"`
+		DinosaurIDs []string             `json:"dinosaurIds,omitempty"`
+		Summary     string               `json:"summary"`
+	}{
+		Type:        incident.Type,
+		Severity:    incident.Severity,
+		CageID:      incident.CageID,
+		DinosaurIDs: incident.DinosaurIDs,
+		Summary:     incident.Summary,
+	}
+
+	var opened app.Incident
+	if err := c.client.do(ctx, http.MethodPost, "/incidents", nil, req, &opened); err != nil {
+		return nil, err
+	}
+
+	return &opened, nil
+}
+
+// Get an incident by id along with its timeline.
+func (c *IncidentClient) Get(ctx context.Context, id string) (*app.Incident, error) {
+	var incident app.Incident
+	if err := c.client.do(ctx, http.MethodGet, "/incidents/"+url.PathEscape(id), nil, nil, &incident); err != nil {
+		return nil, err
+	}
+
+	return &incident, nil
+}
+
+// List incidents narrowed down by the filter, the most recently opened first.
+func (c *IncidentClient) List(ctx context.Context, filter app.IncidentFilter) ([]app.Incident, error) {
+	query := map[string]string{
+		"cageId":   filter.CageID,
+		"status":   string(filter.Status),
+		"severity": string(filter.Severity),
+	}
+
+	var list []app.Incident
+	if err := c.client.do(ctx, http.MethodGet, "/incidents", query, nil, &list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// AddEntry adds an entry to the timeline of an open incident.
+func (c *IncidentClient) AddEntry(ctx context.Context, id string, entry *app.IncidentEntry) (*app.IncidentEntry, error) {
+	req := struct {
+		Note       string     `json:"note"`
+		OccurredAt *time.Time `json:"occurredAt,omitempty"`
+	}{
+		Note: entry.Note,
+	}
+	if !entry.OccurredAt.IsZero() {
+		req.OccurredAt = &entry.OccurredAt
+	}
+
+	var added app.IncidentEntry
+	if err := c.client.do(ctx, http.MethodPost, "/incidents/"+url.PathEscape(id)+"/timeline", nil, req, &added); err != nil {
+		return nil, err
+	}
+
+	return &added, nil
+}
+
+// Resolve an open incident lifting the cage lockdown if any.
+func (c *IncidentClient) Resolve(ctx context.Context, id, resolution string) (*app.Incident, error) {
+	req := struct {
+		Resolution string `json:"resolution"`
+	}{
+		Resolution: resolution,
+	}
+
+	var resolved app.Incident
+	if err := c.client.do(ctx, http.MethodPost, "/incidents/"+url.PathEscape(id)+"/resolve", nil, req, &resolved); err != nil {
+		return nil, err
+	}
+
+	return &resolved, nil
+}
@@ -0,0 +1,126 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+// StaffClient mirrors the staff store operations over the API.
+type StaffClient struct {
+	client *Client
+}
+
+// Add a new staff member.
+func (c *StaffClient) Add(ctx context.Context, staff *app.Staff) (*app.Staff, error) {
+	req := struct {
+		Name           string              `json:"name"`
+		Role           app.StaffRole       `json:"role"`
+		Certifications []app.Certification `json:"certifications,omitempty"`
+	}{
+		Name:           staff.Name,
+		Role:           staff.Role,
+		Certifications: staff.Certifications,
+	}
+
+	var added app.Staff
+	if err := c.client.do(ctx, http.MethodPost, "/staff", nil, req, &added); err != nil {
+		return nil, err
+	}
+
+	return &added, nil
+}
+
+// Get a staff member by id.
+func (c *StaffClient) Get(ctx context.Context, id string) (*app.Staff, error) {
+	var staff app.Staff
+	if err := c.client.do(ctx, http.MethodGet, "/staff/"+url.PathEscape(id), nil, nil, &staff); err != nil {
+		return nil, err
+	}
+
+	return &staff, nil
+}
+
+// List staff members narrowed down by the filter.
+// With a cage in the filter the staff on duty for the cage are listed.
+func (c *StaffClient) List(ctx context.Context, filter app.StaffFilter) ([]app.Staff, error) {
+	path, query := "/staff", map[string]string{
+		"role": string(filter.Role),
+	}
+	if filter.CageID != "" {
+		path, query = "/cages/"+url.PathEscape(filter.CageID)+"/staff", map[string]string{}
+		if !filter.OnDuty.IsZero() {
+			query["at"] = filter.OnDuty.Format(time.RFC3339Nano)
+		}
+	}
+
+	var list []app.Staff
+	if err := c.client.do(ctx, http.MethodGet, path, query, nil, &list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// Cages lists the cages a staff member covers at the time, now if the time is zero.
+func (c *StaffClient) Cages(ctx context.Context, id string, at time.Time) ([]app.Cage, error) {
+	query := map[string]string{}
+	if !at.IsZero() {
+		query["at"] = at.Format(time.RFC3339Nano)
+	}
+
+	var cages []app.Cage
+	if err := c.client.do(ctx, http.MethodGet, "/staff/"+url.PathEscape(id)+"/cages", query, nil, &cages); err != nil {
+		return nil, err
+	}
+
+	return cages, nil
+}
+
+// Assign a staff member to a shift covering a cage.
+func (c *StaffClient) Assign(ctx context.Context, assignment *app.Assignment) (*app.Assignment, error) {
+	req := struct {
+		StaffID  string    `json:"staffId"`
+		CageID   string    `json:"cageId"`
+		StartsAt time.Time `json:"startsAt"`
+		EndsAt   time.Time `json:"endsAt"`
+	}{
+		StaffID:  assignment.StaffID,
+		CageID:   assignment.CageID,
+		StartsAt: assignment.StartsAt,
+		EndsAt:   assignment.EndsAt,
+	}
+
+	var added app.Assignment
+	if err := c.client.do(ctx, http.MethodPost, "/assignments", nil, req, &added); err != nil {
+		return nil, err
+	}
+
+	return &added, nil
+}
+
+// ListAssignments lists assignments narrowed down by the filter in the order the shifts start.
+func (c *StaffClient) ListAssignments(ctx context.Context, filter app.AssignmentFilter) ([]app.Assignment, error) {
+	query := map[string]string{
+		"staffId": filter.StaffID,
+		"cageId":  filter.CageID,
+	}
+	if !filter.At.IsZero() {
+		query["at"] = filter.At.Format(time.RFC3339Nano)
+	}
+
+	var assignments []app.Assignment
+	if err := c.client.do(ctx, http.MethodGet, "/assignments", query, nil, &assignments); err != nil {
+		return nil, err
+	}
+
+	return assignments, nil
+}
+
+// DeleteAssignment deletes an assignment.
+func (c *StaffClient) DeleteAssignment(ctx context.Context, id string) error {
+	return c.client.do(ctx, http.MethodDelete, "/assignments/"+url.PathEscape(id), nil, nil, nil)
+}
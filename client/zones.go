@@ -0,0 +1,76 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+// ZoneClient mirrors the zone store operations over the API.
+type ZoneClient struct {
+	client *Client
+}
+
+// Add adds a new zone.
+func (c *ZoneClient) Add(ctx context.Context, zone *app.Zone) (*app.Zone, error) {
+	req := struct {
+		Name string `json:"name"`
+	}{
+		Name: zone.Name,
+	}
+
+	var added app.Zone
+	if err := c.client.do(ctx, http.MethodPost, "/zones", nil, req, &added); err != nil {
+		return nil, err
+	}
+
+	return &added, nil
+}
+
+// Get gets a zone by id along with its sectors.
+func (c *ZoneClient) Get(ctx context.Context, id string) (*app.Zone, error) {
+	var zone app.Zone
+	if err := c.client.do(ctx, http.MethodGet, "/zones/"+url.PathEscape(id), nil, nil, &zone); err != nil {
+		return nil, err
+	}
+
+	return &zone, nil
+}
+
+// List lists zones.
+func (c *ZoneClient) List(ctx context.Context) ([]app.Zone, error) {
+	var zones []app.Zone
+	if err := c.client.do(ctx, http.MethodGet, "/zones", nil, nil, &zones); err != nil {
+		return nil, err
+	}
+
+	return zones, nil
+}
+
+// AddSector adds a new sector to a zone.
+func (c *ZoneClient) AddSector(ctx context.Context, sector *app.Sector) (*app.Sector, error) {
+	req := struct {
+		Name string `json:"name"`
+	}{
+		Name: sector.Name,
+	}
+
+	var added app.Sector
+	if err := c.client.do(ctx, http.MethodPost, "/zones/"+url.PathEscape(sector.ZoneID)+"/sectors", nil, req, &added); err != nil {
+		return nil, err
+	}
+
+	return &added, nil
+}
+
+// ListSectors lists the sectors of a zone.
+func (c *ZoneClient) ListSectors(ctx context.Context, zoneID string) ([]app.Sector, error) {
+	var sectors []app.Sector
+	if err := c.client.do(ctx, http.MethodGet, "/zones/"+url.PathEscape(zoneID)+"/sectors", nil, nil, &sectors); err != nil {
+		return nil, err
+	}
+
+	return sectors, nil
+}
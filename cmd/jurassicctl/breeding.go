@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"strconv"
+	"strings"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+var breedingCommands = []command{
+	{name: "lineage", args: []string{"id"}, summary: "Show the ancestors and the descendants of a dinosaur", setup: breedingLineage},
+	{name: "set-parents", args: []string{"id"}, summary: "Replace the parents of a dinosaur", setup: breedingSetParents},
+	{name: "pairs", summary: "List breeding pairs, the most recent first", setup: breedingPairs},
+	{name: "propose", summary: "Propose a breeding pair, inbred pairs are flagged", setup: breedingPropose},
+}
+
+var relativeHeader = []string{"ID", "NAME", "SPECIES", "STAGE", "RELATION", "GENERATION", "PARENTS"}
+
+func relativeRow(r app.Relative, relation string) []string {
+	return []string{
+		r.ID,
+		r.Name,
+		string(r.Species),
+		string(r.Stage),
+		relation,
+		strconv.Itoa(r.Generation),
+		strings.Join(r.ParentIDs, ","),
+	}
+}
+
+func (e *env) printLineage(l *app.Lineage) error {
+	rows := make([][]string, 0, len(l.Ancestors)+len(l.Descendants))
+	for _, r := range l.Ancestors {
+		rows = append(rows, relativeRow(r, "ancestor"))
+	}
+	for _, r := range l.Descendants {
+		rows = append(rows, relativeRow(r, "descendant"))
+	}
+
+	return e.print(l, relativeHeader, rows)
+}
+
+func breedingLineage(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	generations := fs.Int("generations", app.DefaultLineageGenerations, "Number of generations up and down the lineage")
+
+	return func(ctx context.Context, e *env, args []string) error {
+		lineage, err := e.client.Breeding.Lineage(ctx, args[0], *generations)
+		if err != nil {
+			return err
+		}
+
+		return e.printLineage(lineage)
+	}
+}
+
+func breedingSetParents(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	var parentIDs []string
+	fs.Func("parents", "Comma separated parent IDs, up to two", func(value string) error {
+		parentIDs = strings.Split(value, ",")
+		return nil
+	})
+	none := fs.Bool("none", false, "Remove the parents")
+
+	return func(ctx context.Context, e *env, args []string) error {
+		if len(parentIDs) == 0 && !*none {
+			return errors.New("-parents or -none is required")
+		}
+
+		lineage, err := e.client.Breeding.SetParents(ctx, args[0], parentIDs)
+		if err != nil {
+			return err
+		}
+
+		return e.printLineage(lineage)
+	}
+}
+
+var breedingPairHeader = []string{"ID", "SIRE", "DAM", "SPECIES", "INBREEDING", "FLAGGED", "NOTES", "CREATED"}
+
+func breedingPairRow(p app.BreedingPair) []string {
+	return []string{
+		p.ID,
+		p.SireID,
+		p.DamID,
+		string(p.Species),
+		strconv.FormatFloat(p.InbreedingCoefficient, 'f', -1, 64),
+		strconv.FormatBool(p.Flagged),
+		p.Notes,
+		formatTime(p.CreatedAt),
+	}
+}
+
+func breedingPairs(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	dinosaurID := fs.String("dino", "", "Filter by the sire or the dam ID")
+	flagged := fs.Bool("flagged", false, "Only the pairs flagged as inbred")
+
+	return func(ctx context.Context, e *env, _ []string) error {
+		pairs, err := e.client.Breeding.ListPairs(ctx, app.BreedingPairFilter{
+			DinosaurID:  *dinosaurID,
+			FlaggedOnly: *flagged,
+		})
+		if err != nil {
+			return err
+		}
+		if pairs == nil {
+			pairs = []app.BreedingPair{}
+		}
+
+		rows := make([][]string, len(pairs))
+		for i, p := range pairs {
+			rows[i] = breedingPairRow(p)
+		}
+
+		return e.print(pairs, breedingPairHeader, rows)
+	}
+}
+
+func breedingPropose(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	sireID := fs.String("sire", "", "Sire ID (required)")
+	damID := fs.String("dam", "", "Dam ID (required)")
+	notes := fs.String("notes", "", "Notes")
+
+	return func(ctx context.Context, e *env, _ []string) error {
+		if *sireID == "" || *damID == "" {
+			return errors.New("-sire and -dam are required")
+		}
+
+		pair, err := e.client.Breeding.AddPair(ctx, &app.BreedingPair{
+			SireID: *sireID,
+			DamID:  *damID,
+			Notes:  *notes,
+		})
+		if err != nil {
+			return err
+		}
+		if pair.Flagged {
+			e.message("Warning: inbreeding coefficient over the threshold")
+		}
+
+		return e.print(pair, breedingPairHeader, [][]string{breedingPairRow(*pair)})
+	}
+}
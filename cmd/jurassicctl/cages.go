@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"strconv"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+var cageCommands = []command{
+	{name: "list", summary: "List cages", setup: cagesList},
+	{name: "get", args: []string{"id"}, summary: "Get a cage", setup: cagesGet},
+	{name: "add", summary: "Add a new cage", setup: cagesAdd},
+	{name: "power-down", args: []string{"id"}, summary: "Power down a cage that is empty or holds carnivores", setup: cagesChangeStatus(app.CageStatusDown)},
+	{name: "power-up", args: []string{"id"}, summary: "Power up a cage", setup: cagesChangeStatus(app.CageStatusActive)},
+	{name: "resize", args: []string{"id"}, summary: "Change the capacity of a cage", setup: cagesResize},
+	{name: "history", args: []string{"id"}, summary: "Show the type, status, capacity, capacity unit, sector and quarantine changes of a cage", setup: cagesHistory},
+	{name: "delete", args: []string{"id"}, summary: "Delete an empty cage", setup: cagesDelete},
+	{name: "restore", args: []string{"id"}, summary: "Restore a deleted cage", setup: cagesRestore},
+}
+
+var cageHeader = []string{"ID", "TYPE", "QUARANTINE", "STATUS", "CAPACITY", "UNIT", "OCCUPANCY", "HEADS", "CREATED", "DELETED"}
+
+func cageRow(c app.Cage) []string {
+	var quarantine string
+	if c.Quarantine {
+		quarantine = "yes"
+	}
+
+	return []string{
+		c.ID,
+		string(c.Type),
+		quarantine,
+		string(c.Status),
+		strconv.Itoa(c.Capacity),
+		string(c.CapacityUnit),
+		strconv.Itoa(c.Occupancy),
+		strconv.Itoa(c.HeadCount),
+		formatTime(c.CreatedAt),
+		formatDeleted(c.DeletedAt),
+	}
+}
+
+func (e *env) printCage(c *app.Cage) error {
+	return e.print(c, cageHeader, [][]string{cageRow(*c)})
+}
+
+func cagesList(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	cageType := fs.String("type", "", "Filter by type: general, paddock, aquatic, aviary or high-security")
+	status := fs.String("status", "", "Filter by status: active or down")
+	quarantine := boolFilterFlag(fs, "quarantine", "Only quarantine or only regular cages")
+	zoneID := fs.String("zone", "", "Filter by zone ID")
+	sectorID := fs.String("sector", "", "Filter by sector ID")
+	minFree := fs.Int("min-free-capacity", 0, "Only cages with at least that much free capacity in their capacity unit")
+	occupancy := fs.String("occupancy", "", "Filter by occupancy: empty or full")
+	created := timeRangeFlags(fs, "created")
+	sort := sortFlag(fs, app.CageFields)
+	deleted := fs.Bool("include-deleted", false, "Include deleted cages")
+
+	return func(ctx context.Context, e *env, _ []string) error {
+		cages, err := e.client.Cages.List(ctx, app.CageFilter{
+			Type:            app.CageType(*cageType),
+			Status:          app.CageStatus(*status),
+			Quarantine:      *quarantine,
+			ZoneID:          *zoneID,
+			SectorID:        *sectorID,
+			MinFreeCapacity: *minFree,
+			Occupancy:       app.CageOccupancy(*occupancy),
+			Created:         *created,
+			Sort:            *sort,
+			IncludeDeleted:  *deleted,
+		})
+		if err != nil {
+			return err
+		}
+		if cages == nil {
+			cages = []app.Cage{}
+		}
+
+		rows := make([][]string, len(cages))
+		for i, c := range cages {
+			rows[i] = cageRow(c)
+		}
+
+		return e.print(cages, cageHeader, rows)
+	}
+}
+
+func cagesGet(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	deleted := fs.Bool("include-deleted", false, "Get the cage even if it's deleted")
+
+	return func(ctx context.Context, e *env, args []string) error {
+		cage, err := e.client.Cages.Get(ctx, args[0], app.GetOptions{IncludeDeleted: *deleted})
+		if err != nil {
+			return err
+		}
+
+		return e.printCage(cage)
+	}
+}
+
+func cagesAdd(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	capacity := fs.Int("capacity", 0, "Cage capacity (required)")
+	cageType := fs.String("type", string(app.CageTypeGeneral), "Cage type: general, paddock, aquatic, aviary or high-security")
+	unit := fs.String("capacity-unit", string(app.CapacityUnitHeads), "Capacity unit: heads or space")
+	status := fs.String("status", string(app.CageStatusActive), "Cage status: active or down")
+	sectorID := fs.String("sector", "", "ID of the sector the cage is located in")
+	quarantine := fs.Bool("quarantine", false, "Designate a quarantine cage that takes only quarantined dinosaurs")
+
+	return func(ctx context.Context, e *env, _ []string) error {
+		cage, err := e.client.Cages.Add(ctx, &app.Cage{
+			Type:         app.CageType(*cageType),
+			Capacity:     *capacity,
+			CapacityUnit: app.CapacityUnit(*unit),
+			Status:       app.CageStatus(*status),
+			SectorID:     *sectorID,
+			Quarantine:   *quarantine,
+		})
+		if err != nil {
+			return err
+		}
+
+		return e.printCage(cage)
+	}
+}
+
+func cagesChangeStatus(status app.CageStatus) func(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	return func(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+		return func(ctx context.Context, e *env, args []string) error {
+			cage, err := e.client.Cages.ChangeStatus(ctx, args[0], status)
+			if err != nil {
+				return err
+			}
+
+			return e.printCage(cage)
+		}
+	}
+}
+
+func cagesResize(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	capacity := fs.Int("capacity", 0, "New cage capacity (required)")
+
+	return func(ctx context.Context, e *env, args []string) error {
+		cage, err := e.client.Cages.Resize(ctx, args[0], *capacity)
+		if err != nil {
+			return err
+		}
+
+		return e.printCage(cage)
+	}
+}
+
+var cageHistoryHeader = []string{"CHANGE", "FROM", "TO", "CHANGED"}
+
+func cagesHistory(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	return func(ctx context.Context, e *env, args []string) error {
+		history, err := e.client.Cages.History(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		if history == nil {
+			history = []app.CageHistoryEntry{}
+		}
+
+		rows := make([][]string, len(history))
+		for i, entry := range history {
+			rows[i] = []string{string(entry.Change), entry.From, entry.To, formatTime(entry.CreatedAt)}
+		}
+
+		return e.print(history, cageHistoryHeader, rows)
+	}
+}
+
+func cagesDelete(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	return func(ctx context.Context, e *env, args []string) error {
+		if err := e.client.Cages.Delete(ctx, args[0]); err != nil {
+			return err
+		}
+		e.message("Cage %s deleted", args[0])
+
+		return nil
+	}
+}
+
+func cagesRestore(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	return func(ctx context.Context, e *env, args []string) error {
+		cage, err := e.client.Cages.Restore(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		return e.printCage(cage)
+	}
+}
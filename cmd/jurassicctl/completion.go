@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+var completionCommands = []command{
+	{name: "bash", summary: "Print bash completion script", setup: completion(bashCompletion)},
+	{name: "zsh", summary: "Print zsh completion script", setup: completion(zshCompletion)},
+	{name: "fish", summary: "Print fish completion script", setup: completion(fishCompletion)},
+}
+
+// flagValues are the values completed for the flags.
+var flagValues = map[string][]string{
+	"o":         {formatTable, formatJSON, formatYAML},
+	"output":    {formatTable, formatJSON, formatYAML},
+	"status":    {string(app.CageStatusActive), string(app.CageStatusDown)},
+	"occupancy": {string(app.CageOccupancyEmpty), string(app.CageOccupancyFull)},
+	"diet":      {string(app.DinosaurTypeCarnivore), string(app.DinosaurTypeHerbivore)},
+	"species": {
+		string(app.DinosaurSpeciesTyrannosaurus),
+		string(app.DinosaurSpeciesVelociraptor),
+		string(app.DinosaurSpeciesSpinosaurus),
+		string(app.DinosaurSpeciesMegalosaurus),
+		string(app.DinosaurSpeciesBrachiosaurus),
+		string(app.DinosaurSpeciesStegosaurus),
+		string(app.DinosaurSpeciesAnkylosaurus),
+		string(app.DinosaurSpeciesTriceratops),
+	},
+	"type": {
+		string(app.CageTypeGeneral),
+		string(app.CageTypePaddock),
+		string(app.CageTypeAquatic),
+		string(app.CageTypeAviary),
+		string(app.CageTypeHighSecurity),
+	},
+	"capacity-unit": {string(app.CapacityUnitHeads), string(app.CapacityUnitSpace)},
+	"health":        {string(app.HealthStatusHealthy), string(app.HealthStatusUnderObservation), string(app.HealthStatusSick)},
+	"kind": {
+		string(app.HealthRecordKindCheckup),
+		string(app.HealthRecordKindWeight),
+		string(app.HealthRecordKindDiagnosis),
+		string(app.HealthRecordKindTreatment),
+		string(app.HealthRecordKindNote),
+	},
+	"stage": {
+		string(app.LifecycleStageEgg),
+		string(app.LifecycleStageJuvenile),
+		string(app.LifecycleStageAdult),
+		string(app.LifecycleStageDeceased),
+		string(app.LifecycleStageTransferredOut),
+	},
+	"role": {
+		string(app.StaffRoleKeeper),
+		string(app.StaffRoleVeterinarian),
+		string(app.StaffRoleSecurity),
+	},
+	"certifications": {
+		string(app.CertificationCarnivoreHandling),
+		string(app.CertificationAquaticHandling),
+		string(app.CertificationTranquilizer),
+	},
+	"incident": {
+		string(app.IncidentTypeEscape),
+		string(app.IncidentTypeFenceFailure),
+		string(app.IncidentTypeInjury),
+		string(app.IncidentTypeOther),
+	},
+	"severity": {
+		string(app.IncidentSeverityLow),
+		string(app.IncidentSeverityMedium),
+		string(app.IncidentSeverityHigh),
+		string(app.IncidentSeverityCritical),
+	},
+	"state":       {string(app.IncidentStatusOpen), string(app.IncidentStatusResolved)},
+	"quarantine":  {"true", "false"},
+	"quarantined": {"true", "false"},
+}
+
+func completion(script func(w io.Writer)) func(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	return func(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+		return func(_ context.Context, e *env, _ []string) error {
+			script(e.out)
+			return nil
+		}
+	}
+}
+
+// commandFlags returns the sorted flag names of a command.
+func commandFlags(cmd command) []string {
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	var opts options
+	opts.register(fs)
+	cmd.setup(fs)
+
+	var names []string
+	fs.VisitAll(func(f *flag.Flag) {
+		names = append(names, "--"+f.Name)
+	})
+	sort.Strings(names)
+
+	return names
+}
+
+func groupNames(g group) string {
+	return strings.Join(append([]string{g.name}, g.aliases...), "|")
+}
+
+func bashCompletion(w io.Writer) {
+	var names []string
+	for _, g := range groups {
+		names = append(names, g.name)
+	}
+
+	fmt.Fprintf(w, `# bash completion for jurassicctl
+# Add to ~/.bashrc: source <(jurassicctl completion bash)
+_jurassicctl() {
+    local cur="${COMP_WORDS[COMP_CWORD]}"
+    local prev="${COMP_WORDS[COMP_CWORD-1]}"
+
+    if [[ $COMP_CWORD -eq 1 ]]; then
+        COMPREPLY=($(compgen -W "%s" -- "$cur"))
+        return
+    fi
+
+    if [[ $COMP_CWORD -eq 2 ]]; then
+        case "${COMP_WORDS[1]}" in
+`, strings.Join(names, " "))
+	for _, g := range groups {
+		var subs []string
+		for _, cmd := range g.commands {
+			subs = append(subs, cmd.name)
+		}
+		fmt.Fprintf(w, "            %s) COMPREPLY=($(compgen -W \"%s\" -- \"$cur\")) ;;\n", groupNames(g), strings.Join(subs, " "))
+	}
+	fmt.Fprintf(w, `        esac
+        return
+    fi
+
+    case "$prev" in
+`)
+	keys := make([]string, 0, len(flagValues))
+	for name := range flagValues {
+		keys = append(keys, name)
+	}
+	sort.Strings(keys)
+	for _, name := range keys {
+		fmt.Fprintf(w, "        -%s|--%s) COMPREPLY=($(compgen -W \"%s\" -- \"$cur\")); return ;;\n", name, name, strings.Join(flagValues[name], " "))
+	}
+	fmt.Fprintf(w, `    esac
+
+    if [[ "$cur" == -* ]]; then
+        case "${COMP_WORDS[1]} ${COMP_WORDS[2]}" in
+`)
+	for _, g := range groups {
+		for _, cmd := range g.commands {
+			for _, name := range append([]string{g.name}, g.aliases...) {
+				fmt.Fprintf(w, "            \"%s %s\") COMPREPLY=($(compgen -W \"%s\" -- \"$cur\")) ;;\n", name, cmd.name, strings.Join(commandFlags(cmd), " "))
+			}
+		}
+	}
+	fmt.Fprintf(w, `        esac
+    fi
+}
+complete -F _jurassicctl jurassicctl
+`)
+}
+
+func zshCompletion(w io.Writer) {
+	fmt.Fprintf(w, "#compdef jurassicctl\n# Add to ~/.zshrc: source <(jurassicctl completion zsh)\nautoload -U +X bashcompinit && bashcompinit\n")
+	bashCompletion(w)
+}
+
+func fishCompletion(w io.Writer) {
+	fmt.Fprintf(w, "# fish completion for jurassicctl\n# Run: jurassicctl completion fish > ~/.config/fish/completions/jurassicctl.fish\n")
+	fmt.Fprintf(w, "complete -c jurassicctl -f\n")
+
+	var names []string
+	for _, g := range groups {
+		names = append(names, g.name)
+		fmt.Fprintf(w, "complete -c jurassicctl -n __fish_use_subcommand -a %s -d %q\n", g.name, g.summary)
+	}
+
+	for _, g := range groups {
+		for _, cmd := range g.commands {
+			fmt.Fprintf(w, "complete -c jurassicctl -n \"__fish_seen_subcommand_from %s; and not __fish_seen_subcommand_from %s\" -a %s -d %q\n",
+				g.name, cmd.name, cmd.name, cmd.summary)
+
+			for _, name := range commandFlags(cmd) {
+				name = strings.TrimPrefix(name, "--")
+				values := ""
+				if v, ok := flagValues[name]; ok {
+					values = fmt.Sprintf(" -x -a %q", strings.Join(v, " "))
+				}
+				fmt.Fprintf(w, "complete -c jurassicctl -n \"__fish_seen_subcommand_from %s; and __fish_seen_subcommand_from %s\" -l %s%s\n",
+					g.name, cmd.name, name, values)
+			}
+		}
+	}
+}
@@ -0,0 +1,344 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"strings"
+	"time"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+var dinoCommands = []command{
+	{name: "list", summary: "List dinosaurs", setup: dinosList},
+	{name: "get", args: []string{"id"}, summary: "Get a dinosaur", setup: dinosGet},
+	{name: "add", summary: "Add a dinosaur to a cage", setup: dinosAdd},
+	{name: "move", args: []string{"id"}, summary: "Move a dinosaur to a different cage", setup: dinosMove},
+	{name: "delete", args: []string{"id"}, summary: "Delete a dinosaur", setup: dinosDelete},
+	{name: "restore", args: []string{"id"}, summary: "Restore a deleted dinosaur into its cage", setup: dinosRestore},
+	{name: "quarantine", args: []string{"id"}, summary: "Quarantine a dinosaur in a quarantine cage", setup: dinosQuarantine},
+	{name: "release", args: []string{"id"}, summary: "Release a dinosaur from quarantine into a regular cage", setup: dinosRelease},
+	{name: "health", args: []string{"id"}, summary: "List the health records of a dinosaur", setup: dinosHealth},
+	{name: "add-health", args: []string{"id"}, summary: "Add a health record of a dinosaur", setup: dinosAddHealth},
+	{name: "lifecycle", args: []string{"id"}, summary: "List the lifecycle transitions of a dinosaur", setup: dinosLifecycle},
+	{name: "transition", args: []string{"id"}, summary: "Transition a dinosaur to a lifecycle stage", setup: dinosTransition},
+}
+
+var dinoHeader = []string{"ID", "NAME", "SPECIES", "CAGE", "STAGE", "HEALTH", "QUARANTINE", "CREATED", "DELETED"}
+
+func dinoRow(d app.Dinosaur) []string {
+	return []string{
+		d.ID,
+		d.Name,
+		string(d.Species),
+		d.CageID,
+		string(d.Stage),
+		string(d.HealthStatus),
+		d.QuarantineReason,
+		formatTime(d.CreatedAt),
+		formatDeleted(d.DeletedAt),
+	}
+}
+
+func (e *env) printDinosaur(d *app.Dinosaur) error {
+	return e.print(d, dinoHeader, [][]string{dinoRow(*d)})
+}
+
+func dinosList(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	var species []app.DinosaurSpecies
+	fs.Func("species", "Filter by species, comma separated", func(value string) error {
+		for _, s := range strings.Split(value, ",") {
+			species = append(species, app.DinosaurSpecies(s))
+		}
+		return nil
+	})
+	diet := fs.String("diet", "", "Filter by diet: carnivore or herbivore")
+	var stages []app.LifecycleStage
+	fs.Func("stage", "Filter by lifecycle stage, comma separated", func(value string) error {
+		for _, s := range strings.Split(value, ",") {
+			stages = append(stages, app.LifecycleStage(s))
+		}
+		return nil
+	})
+	health := fs.String("health", "", "Filter by health status: healthy, under-observation or sick")
+	quarantined := boolFilterFlag(fs, "quarantined", "Only quarantined or only not quarantined dinosaurs")
+	namePrefix := fs.String("name-prefix", "", "Filter by name prefix")
+	cageID := fs.String("cage", "", "Filter by cage ID")
+	zoneID := fs.String("zone", "", "Filter by zone ID")
+	hatch := timeRangeFlags(fs, "hatch")
+	created := timeRangeFlags(fs, "created")
+	sort := sortFlag(fs, app.DinosaurFields)
+	deleted := fs.Bool("include-deleted", false, "Include deleted dinosaurs")
+
+	return func(ctx context.Context, e *env, _ []string) error {
+		dinosaurs, err := e.client.Dinosaurs.List(ctx, app.DinosaurFilter{
+			CageID:         *cageID,
+			ZoneID:         *zoneID,
+			Species:        species,
+			Diet:           app.DinosaurType(*diet),
+			Stages:         stages,
+			HealthStatus:   app.HealthStatus(*health),
+			Quarantined:    *quarantined,
+			NamePrefix:     *namePrefix,
+			HatchDate:      *hatch,
+			Created:        *created,
+			Sort:           *sort,
+			IncludeDeleted: *deleted,
+		})
+		if err != nil {
+			return err
+		}
+		if dinosaurs == nil {
+			dinosaurs = []app.Dinosaur{}
+		}
+
+		rows := make([][]string, len(dinosaurs))
+		for i, d := range dinosaurs {
+			rows[i] = dinoRow(d)
+		}
+
+		return e.print(dinosaurs, dinoHeader, rows)
+	}
+}
+
+func dinosGet(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	deleted := fs.Bool("include-deleted", false, "Get the dinosaur even if it's deleted")
+
+	return func(ctx context.Context, e *env, args []string) error {
+		dinosaur, err := e.client.Dinosaurs.Get(ctx, args[0], app.GetOptions{IncludeDeleted: *deleted})
+		if err != nil {
+			return err
+		}
+
+		return e.printDinosaur(dinosaur)
+	}
+}
+
+func dinosAdd(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	cageID := fs.String("cage", "", "Cage ID (required)")
+	name := fs.String("name", "", "Dinosaur name (required)")
+	species := fs.String("species", "", "Dinosaur species (required)")
+
+	return func(ctx context.Context, e *env, _ []string) error {
+		if *cageID == "" {
+			return errors.New("-cage is required")
+		}
+
+		dinosaur, err := e.client.Dinosaurs.Add(ctx, &app.Dinosaur{
+			Name:    *name,
+			Species: app.DinosaurSpecies(*species),
+			CageID:  *cageID,
+		})
+		if err != nil {
+			return err
+		}
+
+		return e.printDinosaur(dinosaur)
+	}
+}
+
+func dinosMove(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	cageID := fs.String("to", "", "Destination cage ID (required)")
+
+	return func(ctx context.Context, e *env, args []string) error {
+		if *cageID == "" {
+			return errors.New("-to is required")
+		}
+
+		dinosaur, err := e.client.Dinosaurs.Move(ctx, args[0], *cageID)
+		if err != nil {
+			return err
+		}
+
+		return e.printDinosaur(dinosaur)
+	}
+}
+
+func dinosDelete(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	return func(ctx context.Context, e *env, args []string) error {
+		if err := e.client.Dinosaurs.Delete(ctx, args[0]); err != nil {
+			return err
+		}
+		e.message("Dinosaur %s deleted", args[0])
+
+		return nil
+	}
+}
+
+func dinosRestore(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	return func(ctx context.Context, e *env, args []string) error {
+		dinosaur, err := e.client.Dinosaurs.Restore(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		return e.printDinosaur(dinosaur)
+	}
+}
+
+func dinosQuarantine(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	cageID := fs.String("to", "", "Quarantine cage ID (required)")
+	reason := fs.String("reason", "", "Reason of the quarantine (required)")
+	var start, end time.Time
+	fs.Func("start", "RFC 3339 start of the quarantine, now by default", timeFlag(&start))
+	fs.Func("end", "RFC 3339 planned end of the quarantine", timeFlag(&end))
+
+	return func(ctx context.Context, e *env, args []string) error {
+		if *cageID == "" {
+			return errors.New("-to is required")
+		}
+
+		dinosaur, err := e.client.Dinosaurs.Quarantine(ctx, args[0], app.Quarantine{
+			CageID: *cageID,
+			Reason: *reason,
+			Start:  start,
+			End:    end,
+		})
+		if err != nil {
+			return err
+		}
+
+		return e.printDinosaur(dinosaur)
+	}
+}
+
+func dinosRelease(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	cageID := fs.String("to", "", "Destination cage ID (required)")
+
+	return func(ctx context.Context, e *env, args []string) error {
+		if *cageID == "" {
+			return errors.New("-to is required")
+		}
+
+		dinosaur, err := e.client.Dinosaurs.Release(ctx, args[0], *cageID)
+		if err != nil {
+			return err
+		}
+
+		return e.printDinosaur(dinosaur)
+	}
+}
+
+var healthRecordHeader = []string{"ID", "KIND", "AUTHOR", "WEIGHT", "STATUS", "NOTES", "RECORDED"}
+
+func healthRecordRow(r app.HealthRecord) []string {
+	var weight string
+	if r.WeightKg > 0 {
+		weight = formatQuantity(r.WeightKg)
+	}
+
+	return []string{
+		r.ID,
+		string(r.Kind),
+		r.Author,
+		weight,
+		string(r.Status),
+		r.Notes,
+		formatTime(r.RecordedAt),
+	}
+}
+
+func dinosHealth(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	kind := fs.String("kind", "", "Filter by kind: checkup, weight, diagnosis, treatment or note")
+	recorded := timeRangeFlags(fs, "recorded")
+
+	return func(ctx context.Context, e *env, args []string) error {
+		records, err := e.client.Dinosaurs.HealthRecords(ctx, args[0], app.HealthRecordFilter{
+			Kind:     app.HealthRecordKind(*kind),
+			Recorded: *recorded,
+		})
+		if err != nil {
+			return err
+		}
+		if records == nil {
+			records = []app.HealthRecord{}
+		}
+
+		rows := make([][]string, len(records))
+		for i, r := range records {
+			rows[i] = healthRecordRow(r)
+		}
+
+		return e.print(records, healthRecordHeader, rows)
+	}
+}
+
+func dinosAddHealth(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	kind := fs.String("kind", "", "Record kind: checkup, weight, diagnosis, treatment or note (required)")
+	author := fs.String("author", "", "Vet or keeper who made the record, the authenticated client by default")
+	weight := fs.Float64("weight", 0, "Measured weight in kilograms of a weight record")
+	notes := fs.String("notes", "", "Notes")
+	status := fs.String("health", "", "New health status: healthy, under-observation or sick")
+	var recordedAt time.Time
+	fs.Func("at", "RFC 3339 time of the record, now by default", timeFlag(&recordedAt))
+
+	return func(ctx context.Context, e *env, args []string) error {
+		record, err := e.client.Dinosaurs.AddHealthRecord(ctx, &app.HealthRecord{
+			DinosaurID: args[0],
+			Kind:       app.HealthRecordKind(*kind),
+			Author:     *author,
+			WeightKg:   *weight,
+			Notes:      *notes,
+			Status:     app.HealthStatus(*status),
+			RecordedAt: recordedAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		return e.print(record, healthRecordHeader, [][]string{healthRecordRow(*record)})
+	}
+}
+
+var lifecycleEventHeader = []string{"ID", "FROM", "TO", "CAGE", "NOTES", "OCCURRED"}
+
+func lifecycleEventRow(ev app.LifecycleEvent) []string {
+	return []string{
+		ev.ID,
+		string(ev.From),
+		string(ev.To),
+		ev.CageID,
+		ev.Notes,
+		formatTime(ev.OccurredAt),
+	}
+}
+
+func dinosLifecycle(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	return func(ctx context.Context, e *env, args []string) error {
+		events, err := e.client.Dinosaurs.LifecycleEvents(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		if events == nil {
+			events = []app.LifecycleEvent{}
+		}
+
+		rows := make([][]string, len(events))
+		for i, ev := range events {
+			rows[i] = lifecycleEventRow(ev)
+		}
+
+		return e.print(events, lifecycleEventHeader, rows)
+	}
+}
+
+func dinosTransition(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	stage := fs.String("stage", "", "New lifecycle stage: adult, deceased or transferred-out (required)")
+	notes := fs.String("notes", "", "Notes")
+	var occurredAt time.Time
+	fs.Func("at", "RFC 3339 time of the transition, now by default", timeFlag(&occurredAt))
+
+	return func(ctx context.Context, e *env, args []string) error {
+		event, err := e.client.Dinosaurs.Transition(ctx, args[0], app.LifecycleTransition{
+			Stage:      app.LifecycleStage(*stage),
+			Notes:      *notes,
+			OccurredAt: occurredAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		return e.print(event, lifecycleEventHeader, [][]string{lifecycleEventRow(*event)})
+	}
+}
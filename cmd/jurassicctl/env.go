@@ -0,0 +1,169 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/pmatseykanets/jurassic/client"
+)
+
+// Environment variables that override the profile settings.
+const (
+	envConfig  = "JURASSICCTL_CONFIG"
+	envProfile = "JURASSICCTL_PROFILE"
+	envBaseURL = "JURASSICCTL_BASE_URL"
+	envAPIKey  = "JURASSICCTL_API_KEY"
+)
+
+const (
+	defaultProfile = "default"
+	defaultBaseURL = "http://localhost:9001"
+)
+
+// options are the flags accepted by all commands.
+type options struct {
+	config  string
+	profile string
+	output  string
+	baseURL string
+	apiKey  string
+}
+
+func (o *options) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.config, "config", "", "Config file (default $HOME/.config/jurassicctl/config.yaml)")
+	fs.StringVar(&o.profile, "profile", "", "Profile to use (default current profile)")
+	fs.StringVar(&o.output, "o", "", "Output format: table, json or yaml")
+	fs.StringVar(&o.output, "output", "", "Output format: table, json or yaml")
+	fs.StringVar(&o.baseURL, "base-url", "", "API URL including the base URI")
+	fs.StringVar(&o.apiKey, "api-key", "", "API key")
+}
+
+// profile is a named set of connection settings.
+type profile struct {
+	BaseURL string `yaml:"base-url" json:"baseUrl"`
+	APIKey  string `yaml:"api-key,omitempty" json:"apiKey,omitempty"`
+	Output  string `yaml:"output,omitempty" json:"output,omitempty"`
+}
+
+// ctlConfig is the jurassicctl config file.
+type ctlConfig struct {
+	CurrentProfile string             `yaml:"current-profile,omitempty"`
+	Profiles       map[string]profile `yaml:"profiles,omitempty"`
+}
+
+func configPath(flagValue string) (string, error) {
+	if flagValue != "" {
+		return flagValue, nil
+	}
+	if s := os.Getenv(envConfig); s != "" {
+		return s, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "jurassicctl", "config.yaml"), nil
+}
+
+// loadConfig reads the config file. A missing file is an empty config.
+func loadConfig(path string) (*ctlConfig, error) {
+	cfg := &ctlConfig{}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	if err := yaml.Unmarshal(b, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+func saveConfig(path string, cfg *ctlConfig) error {
+	b, err := yaml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	// The file holds API keys.
+	return os.WriteFile(path, b, 0o600)
+}
+
+// env is the environment a command runs in.
+type env struct {
+	out         io.Writer
+	format      string
+	client      *client.Client
+	config      *ctlConfig
+	configPath  string
+	profileName string
+}
+
+// newEnv resolves the settings from the flags, the environment
+// and the profile, in the order of decreasing precedence.
+func newEnv(opts options, out io.Writer) (*env, error) {
+	path, err := configPath(opts.config)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := loadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+
+	name := firstNonEmpty(opts.profile, os.Getenv(envProfile))
+	p, ok := cfg.Profiles[name]
+	if name != "" && !ok {
+		return nil, fmt.Errorf("unknown profile %q", name)
+	}
+	if name == "" {
+		name = firstNonEmpty(cfg.CurrentProfile, defaultProfile)
+		p = cfg.Profiles[name]
+	}
+
+	format := firstNonEmpty(opts.output, p.Output, formatTable)
+	if err := validateFormat(format); err != nil {
+		return nil, err
+	}
+
+	c := client.New(firstNonEmpty(opts.baseURL, os.Getenv(envBaseURL), p.BaseURL, defaultBaseURL))
+	c.Token = firstNonEmpty(opts.apiKey, os.Getenv(envAPIKey), p.APIKey)
+
+	return &env{
+		out:         out,
+		format:      format,
+		client:      c,
+		config:      cfg,
+		configPath:  path,
+		profileName: name,
+	}, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"strconv"
+	"time"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+var feedingCommands = []command{
+	{name: "schedules", summary: "List feeding schedules", setup: feedingsSchedules},
+	{name: "add-schedule", summary: "Add a new feeding schedule of a cage or of a species", setup: feedingsAddSchedule},
+	{name: "delete-schedule", args: []string{"id"}, summary: "Delete a feeding schedule", setup: feedingsDeleteSchedule},
+	{name: "log", summary: "List the recorded feedings, the most recent first", setup: feedingsLog},
+	{name: "record", summary: "Record a feeding of a dinosaur or of all occupants of a cage", setup: feedingsRecord},
+	{name: "overdue", summary: "List the dinosaurs overdue for a feeding", setup: feedingsOverdue},
+}
+
+var scheduleHeader = []string{"ID", "CAGE", "SPECIES", "FOOD", "QUANTITY", "INTERVAL", "WINDOW", "CREATED"}
+
+func scheduleRow(s app.FeedingSchedule) []string {
+	var window string
+	if s.WindowStart != "" {
+		window = s.WindowStart + "-" + s.WindowEnd
+	}
+
+	return []string{
+		s.ID,
+		s.CageID,
+		string(s.Species),
+		s.Diet,
+		formatQuantity(s.Quantity),
+		strconv.Itoa(s.IntervalHours) + "h",
+		window,
+		formatTime(s.CreatedAt),
+	}
+}
+
+var feedingHeader = []string{"ID", "CAGE", "DINOSAUR", "FOOD", "QUANTITY", "UNSAFE", "FED"}
+
+func feedingRow(f app.Feeding) []string {
+	return []string{
+		f.ID,
+		f.CageID,
+		f.DinosaurID,
+		f.Diet,
+		formatQuantity(f.Quantity),
+		strconv.FormatBool(f.Unsafe),
+		formatTime(f.FedAt),
+	}
+}
+
+var overdueHeader = []string{"DINOSAUR", "NAME", "SPECIES", "CAGE", "SCHEDULE", "LAST FED", "DUE"}
+
+func overdueRow(o app.OverdueFeeding) []string {
+	return []string{
+		o.DinosaurID,
+		o.Name,
+		string(o.Species),
+		o.CageID,
+		o.ScheduleID,
+		formatDeleted(o.LastFedAt),
+		formatTime(o.DueAt),
+	}
+}
+
+// formatQuantity formats a quantity in kilograms.
+func formatQuantity(q float64) string {
+	return strconv.FormatFloat(q, 'f', -1, 64) + "kg"
+}
+
+func feedingsSchedules(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	cageID := fs.String("cage", "", "Filter by cage ID")
+	species := fs.String("species", "", "Filter by species")
+
+	return func(ctx context.Context, e *env, _ []string) error {
+		schedules, err := e.client.Feedings.ListSchedules(ctx, app.FeedingScheduleFilter{
+			CageID:  *cageID,
+			Species: app.DinosaurSpecies(*species),
+		})
+		if err != nil {
+			return err
+		}
+		if schedules == nil {
+			schedules = []app.FeedingSchedule{}
+		}
+
+		rows := make([][]string, len(schedules))
+		for i, s := range schedules {
+			rows[i] = scheduleRow(s)
+		}
+
+		return e.print(schedules, scheduleHeader, rows)
+	}
+}
+
+func feedingsAddSchedule(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	cageID := fs.String("cage", "", "ID of the cage whose occupants are fed")
+	species := fs.String("species", "", "Species fed wherever the dinosaurs are")
+	food := fs.String("food", "", "Food fed, e.g. goats (required)")
+	quantity := fs.Float64("quantity", 0, "Kilograms per dinosaur per feeding (required)")
+	interval := fs.Int("interval", 24, "Hours between feedings")
+	windowStart := fs.String("window-start", "", "Start of the feeding window, HH:MM UTC")
+	windowEnd := fs.String("window-end", "", "End of the feeding window, HH:MM UTC")
+
+	return func(ctx context.Context, e *env, _ []string) error {
+		schedule, err := e.client.Feedings.AddSchedule(ctx, &app.FeedingSchedule{
+			CageID:        *cageID,
+			Species:       app.DinosaurSpecies(*species),
+			Diet:          *food,
+			Quantity:      *quantity,
+			IntervalHours: *interval,
+			WindowStart:   *windowStart,
+			WindowEnd:     *windowEnd,
+		})
+		if err != nil {
+			return err
+		}
+
+		return e.print(schedule, scheduleHeader, [][]string{scheduleRow(*schedule)})
+	}
+}
+
+func feedingsDeleteSchedule(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	return func(ctx context.Context, e *env, args []string) error {
+		if err := e.client.Feedings.DeleteSchedule(ctx, args[0]); err != nil {
+			return err
+		}
+		e.message("Feeding schedule %s deleted", args[0])
+
+		return nil
+	}
+}
+
+func feedingsLog(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	cageID := fs.String("cage", "", "Filter by cage ID")
+	dinosaurID := fs.String("dino", "", "Filter by dinosaur ID")
+	unsafe := fs.Bool("unsafe", false, "Only the feedings flagged as unsafe")
+	fed := timeRangeFlags(fs, "fed")
+
+	return func(ctx context.Context, e *env, _ []string) error {
+		feedings, err := e.client.Feedings.List(ctx, app.FeedingFilter{
+			CageID:     *cageID,
+			DinosaurID: *dinosaurID,
+			UnsafeOnly: *unsafe,
+			Fed:        *fed,
+		})
+		if err != nil {
+			return err
+		}
+		if feedings == nil {
+			feedings = []app.Feeding{}
+		}
+
+		rows := make([][]string, len(feedings))
+		for i, f := range feedings {
+			rows[i] = feedingRow(f)
+		}
+
+		return e.print(feedings, feedingHeader, rows)
+	}
+}
+
+func feedingsRecord(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	cageID := fs.String("cage", "", "ID of the cage whose occupants were fed")
+	dinosaurID := fs.String("dino", "", "ID of the fed dinosaur")
+	food := fs.String("food", "", "Food fed, e.g. goats (required)")
+	quantity := fs.Float64("quantity", 0, "Kilograms fed (required)")
+	var fedAt time.Time
+	fs.Func("at", "RFC 3339 time of the feeding, now by default", timeFlag(&fedAt))
+
+	return func(ctx context.Context, e *env, _ []string) error {
+		feeding, err := e.client.Feedings.Record(ctx, &app.Feeding{
+			CageID:     *cageID,
+			DinosaurID: *dinosaurID,
+			Diet:       *food,
+			Quantity:   *quantity,
+			FedAt:      fedAt,
+		})
+		if err != nil {
+			return err
+		}
+		if feeding.Unsafe {
+			e.message("Warning: carnivores fed in a powered down cage")
+		}
+
+		return e.print(feeding, feedingHeader, [][]string{feedingRow(*feeding)})
+	}
+}
+
+func feedingsOverdue(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	var at time.Time
+	fs.Func("at", "RFC 3339 time to check the schedules at, now by default", timeFlag(&at))
+
+	return func(ctx context.Context, e *env, _ []string) error {
+		overdue, err := e.client.Feedings.Overdue(ctx, at)
+		if err != nil {
+			return err
+		}
+		if overdue == nil {
+			overdue = []app.OverdueFeeding{}
+		}
+
+		rows := make([][]string, len(overdue))
+		for i, o := range overdue {
+			rows[i] = overdueRow(o)
+		}
+
+		return e.print(overdue, overdueHeader, rows)
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"strconv"
+	"time"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+// timeRangeFlags registers --<prefix>-after and --<prefix>-before RFC 3339 flags.
+func timeRangeFlags(fs *flag.FlagSet, prefix string) *app.TimeRange {
+	var tr app.TimeRange
+	fs.Func(prefix+"-after", "Only "+prefix+" after the RFC 3339 time", timeFlag(&tr.After))
+	fs.Func(prefix+"-before", "Only "+prefix+" before the RFC 3339 time", timeFlag(&tr.Before))
+
+	return &tr
+}
+
+func timeFlag(t *time.Time) func(string) error {
+	return func(value string) error {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return errors.New("must be an RFC 3339 time, e.g. 2006-01-02T15:04:05Z")
+		}
+		*t = parsed
+
+		return nil
+	}
+}
+
+// boolFilterFlag registers a flag that filters by a boolean value when set.
+func boolFilterFlag(fs *flag.FlagSet, name, usage string) **bool {
+	var b *bool
+	fs.Func(name, usage+": true or false", func(value string) error {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return errors.New("must be true or false")
+		}
+		b = &parsed
+
+		return nil
+	})
+
+	return &b
+}
+
+// sortFlag registers a --sort flag limited to the allowed fields.
+func sortFlag(fs *flag.FlagSet, allowed []string) *[]app.Sort {
+	var sort []app.Sort
+	fs.Func("sort", "Comma separated fields to sort by, prefix with - for descending order", func(value string) error {
+		var err error
+		sort, err = app.ParseSort(value, allowed)
+		return err
+	})
+
+	return &sort
+}
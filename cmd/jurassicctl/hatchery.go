@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"time"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+var hatcheryCommands = []command{
+	{name: "list", summary: "List the eggs in the hatchery, the earliest to hatch first", setup: hatcheryList},
+	{name: "lay", summary: "Lay a new egg into the hatchery", setup: hatcheryLay},
+	{name: "hatch", args: []string{"id"}, summary: "Hatch an egg into a cage", setup: hatcheryHatch},
+}
+
+var eggHeader = []string{"ID", "NAME", "SPECIES", "HATCH", "CREATED"}
+
+func eggRow(d app.Dinosaur) []string {
+	var hatchDate string
+	if d.HatchDate != nil {
+		hatchDate = formatTime(*d.HatchDate)
+	}
+
+	return []string{
+		d.ID,
+		d.Name,
+		string(d.Species),
+		hatchDate,
+		formatTime(d.CreatedAt),
+	}
+}
+
+func hatcheryList(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	species := fs.String("species", "", "Filter by species")
+	hatch := timeRangeFlags(fs, "hatch")
+
+	return func(ctx context.Context, e *env, _ []string) error {
+		filter := app.DinosaurFilter{
+			Stages:    []app.LifecycleStage{app.LifecycleStageEgg},
+			HatchDate: *hatch,
+			Sort:      []app.Sort{{Field: "hatchDate"}},
+		}
+		if *species != "" {
+			filter.Species = []app.DinosaurSpecies{app.DinosaurSpecies(*species)}
+		}
+
+		eggs, err := e.client.Dinosaurs.List(ctx, filter)
+		if err != nil {
+			return err
+		}
+		if eggs == nil {
+			eggs = []app.Dinosaur{}
+		}
+
+		rows := make([][]string, len(eggs))
+		for i, d := range eggs {
+			rows[i] = eggRow(d)
+		}
+
+		return e.print(eggs, eggHeader, rows)
+	}
+}
+
+func hatcheryLay(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	name := fs.String("name", "", "Dinosaur name (required)")
+	species := fs.String("species", "", "Dinosaur species (required)")
+	var hatchDate time.Time
+	fs.Func("hatch", "RFC 3339 expected hatch date (required)", timeFlag(&hatchDate))
+
+	return func(ctx context.Context, e *env, _ []string) error {
+		egg, err := e.client.Dinosaurs.AddEgg(ctx, app.Egg{
+			Name:      *name,
+			Species:   app.DinosaurSpecies(*species),
+			HatchDate: hatchDate,
+		})
+		if err != nil {
+			return err
+		}
+
+		return e.print(egg, eggHeader, [][]string{eggRow(*egg)})
+	}
+}
+
+func hatcheryHatch(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	cageID := fs.String("to", "", "Destination cage ID (required)")
+
+	return func(ctx context.Context, e *env, args []string) error {
+		if *cageID == "" {
+			return errors.New("-to is required")
+		}
+
+		dinosaur, err := e.client.Dinosaurs.Hatch(ctx, args[0], *cageID)
+		if err != nil {
+			return err
+		}
+
+		return e.printDinosaur(dinosaur)
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"strings"
+	"time"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+var incidentCommands = []command{
+	{name: "list", summary: "List incidents, the most recently opened first", setup: incidentsList},
+	{name: "open", summary: "Open an incident in a cage", setup: incidentsOpen},
+	{name: "get", args: []string{"id"}, summary: "Get an incident along with its timeline", setup: incidentsGet},
+	{name: "note", args: []string{"id"}, summary: "Add an entry to the timeline of an open incident", setup: incidentsNote},
+	{name: "resolve", args: []string{"id"}, summary: "Resolve an open incident", setup: incidentsResolve},
+}
+
+var incidentHeader = []string{"ID", "TYPE", "SEVERITY", "CAGE", "DINOSAURS", "STATUS", "SUMMARY", "OPENED"}
+
+func incidentRow(i app.Incident) []string {
+	return []string{
+		i.ID,
+		string(i.Type),
+		string(i.Severity),
+		i.CageID,
+		strings.Join(i.DinosaurIDs, ","),
+		string(i.Status),
+		i.Summary,
+		formatTime(i.OpenedAt),
+	}
+}
+
+var incidentEntryHeader = []string{"ID", "INCIDENT", "NOTE", "OCCURRED"}
+
+func incidentEntryRow(e app.IncidentEntry) []string {
+	return []string{
+		e.ID,
+		e.IncidentID,
+		e.Note,
+		formatTime(e.OccurredAt),
+	}
+}
+
+func incidentsList(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	cageID := fs.String("cage", "", "Filter by cage ID")
+	state := fs.String("state", "", "Filter by state: open or resolved")
+	severity := fs.String("severity", "", "Filter by severity: low, medium, high or critical")
+
+	return func(ctx context.Context, e *env, _ []string) error {
+		list, err := e.client.Incidents.List(ctx, app.IncidentFilter{
+			CageID:   *cageID,
+			Status:   app.IncidentStatus(*state),
+			Severity: app.IncidentSeverity(*severity),
+		})
+		if err != nil {
+			return err
+		}
+		if list == nil {
+			list = []app.Incident{}
+		}
+
+		rows := make([][]string, len(list))
+		for i, incident := range list {
+			rows[i] = incidentRow(incident)
+		}
+
+		return e.print(list, incidentHeader, rows)
+	}
+}
+
+func incidentsOpen(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	cageID := fs.String("cage", "", "Cage ID (required)")
+	incidentType := fs.String("incident", "", "Incident type: escape, fence-failure, injury or other (required)")
+	severity := fs.String("severity", "", "Severity: low, medium, high or critical (required)")
+	summary := fs.String("summary", "", "Summary of the incident (required)")
+	var dinosaurIDs []string
+	fs.Func("dinos", "Comma separated IDs of the dinosaurs involved", func(value string) error {
+		dinosaurIDs = append(dinosaurIDs, strings.Split(value, ",")...)
+		return nil
+	})
+
+	return func(ctx context.Context, e *env, _ []string) error {
+		switch {
+		case *cageID == "":
+			return errors.New("-cage is required")
+		case *incidentType == "":
+			return errors.New("-incident is required")
+		case *severity == "":
+			return errors.New("-severity is required")
+		case *summary == "":
+			return errors.New("-summary is required")
+		}
+
+		incident, err := e.client.Incidents.Open(ctx, &app.Incident{
+			Type:        app.IncidentType(*incidentType),
+			Severity:    app.IncidentSeverity(*severity),
+			CageID:      *cageID,
+			DinosaurIDs: dinosaurIDs,
+			Summary:     *summary,
+		})
+		if err != nil {
+			return err
+		}
+		if incident.LocksDown() {
+			e.message("Warning: cage %s is locked down until the incident is resolved", incident.CageID)
+		}
+
+		return e.print(incident, incidentHeader, [][]string{incidentRow(*incident)})
+	}
+}
+
+func incidentsGet(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	return func(ctx context.Context, e *env, args []string) error {
+		incident, err := e.client.Incidents.Get(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		if err := e.print(incident, incidentHeader, [][]string{incidentRow(*incident)}); err != nil {
+			return err
+		}
+		for _, entry := range incident.Timeline {
+			e.message("%s  %s", formatTime(entry.OccurredAt), entry.Note)
+		}
+		if incident.Resolution != "" {
+			e.message("Resolution: %s", incident.Resolution)
+		}
+
+		return nil
+	}
+}
+
+func incidentsNote(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	note := fs.String("note", "", "Timeline note (required)")
+	var at time.Time
+	fs.Func("at", "RFC 3339 time of the entry, now by default", timeFlag(&at))
+
+	return func(ctx context.Context, e *env, args []string) error {
+		if *note == "" {
+			return errors.New("-note is required")
+		}
+
+		entry, err := e.client.Incidents.AddEntry(ctx, args[0], &app.IncidentEntry{Note: *note, OccurredAt: at})
+		if err != nil {
+			return err
+		}
+
+		return e.print(entry, incidentEntryHeader, [][]string{incidentEntryRow(*entry)})
+	}
+}
+
+func incidentsResolve(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	resolution := fs.String("resolution", "", "How the incident was resolved (required)")
+
+	return func(ctx context.Context, e *env, args []string) error {
+		if *resolution == "" {
+			return errors.New("-resolution is required")
+		}
+
+		incident, err := e.client.Incidents.Resolve(ctx, args[0], *resolution)
+		if err != nil {
+			return err
+		}
+
+		return e.print(incident, incidentHeader, [][]string{incidentRow(*incident)})
+	}
+}
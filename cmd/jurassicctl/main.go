@@ -0,0 +1,205 @@
+// Command jurassicctl is a command line client for the Jurassic Park API.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+)
+
+// command is a jurassicctl command.
+type command struct {
+	name    string
+	args    []string // Positional arguments.
+	summary string
+	// setup registers the command flags and returns the command function.
+	setup func(fs *flag.FlagSet) func(ctx context.Context, e *env, args []string) error
+}
+
+// group is a group of commands acting on a resource.
+type group struct {
+	name     string
+	aliases  []string
+	summary  string
+	commands []command
+}
+
+// groups is populated in init to let the completion command refer to it.
+var groups []group
+
+func init() {
+	groups = []group{
+		{name: "cages", aliases: []string{"cage"}, summary: "Manage cages", commands: cageCommands},
+		{name: "dinos", aliases: []string{"dino", "dinosaurs"}, summary: "Manage dinosaurs", commands: dinoCommands},
+		{name: "hatchery", summary: "Manage the eggs in the hatchery", commands: hatcheryCommands},
+		{name: "zones", aliases: []string{"zone"}, summary: "Manage zones and sectors", commands: zoneCommands},
+		{name: "feedings", aliases: []string{"feeding"}, summary: "Manage feeding schedules and the feeding log", commands: feedingCommands},
+		{name: "breeding", summary: "Manage the lineage of dinosaurs and breeding pairs", commands: breedingCommands},
+		{name: "staff", summary: "Manage the staff and their cage assignments", commands: staffCommands},
+		{name: "incidents", summary: "Report incidents and escapes in the cages", commands: incidentCommands},
+		{name: "profiles", aliases: []string{"profile"}, summary: "Manage connection profiles", commands: profileCommands},
+		{name: "completion", summary: "Print shell completion script", commands: completionCommands},
+	}
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	err := run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if errors.Is(err, errUsage) {
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// errUsage is returned after the usage message has been printed.
+var errUsage = errors.New("usage")
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	if len(args) == 0 || isHelp(args[0]) {
+		usage(stderr)
+		if len(args) == 0 {
+			return errUsage
+		}
+		return nil
+	}
+
+	g, ok := findGroup(args[0])
+	if !ok {
+		fmt.Fprintf(stderr, "Unknown command %q\n\n", args[0])
+		usage(stderr)
+		return errUsage
+	}
+
+	if len(args) < 2 || isHelp(args[1]) {
+		groupUsage(stderr, g)
+		if len(args) < 2 {
+			return errUsage
+		}
+		return nil
+	}
+
+	for _, cmd := range g.commands {
+		if cmd.name == args[1] {
+			return runCommand(ctx, g, cmd, args[2:], stdout, stderr)
+		}
+	}
+
+	fmt.Fprintf(stderr, "Unknown command %q\n\n", g.name+" "+args[1])
+	groupUsage(stderr, g)
+
+	return errUsage
+}
+
+func runCommand(ctx context.Context, g group, cmd command, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("jurassicctl "+g.name+" "+cmd.name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: jurassicctl %s %s", g.name, cmd.name)
+		for _, arg := range cmd.args {
+			fmt.Fprintf(stderr, " <%s>", arg)
+		}
+		fmt.Fprintf(stderr, " [flags]\n\n%s\n\nFlags:\n", cmd.summary)
+		fs.PrintDefaults()
+	}
+
+	var opts options
+	opts.register(fs)
+	fn := cmd.setup(fs)
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != len(cmd.args) {
+		fmt.Fprintf(stderr, "Expected %d argument(s) got %d\n\n", len(cmd.args), len(positional))
+		fs.Usage()
+		return errUsage
+	}
+
+	e, err := newEnv(opts, stdout)
+	if err != nil {
+		return err
+	}
+
+	return fn(ctx, e, positional)
+}
+
+// parseInterspersed parses the flags that may follow the positional
+// arguments, e.g. dinos move <id> --to <cage>, and returns the positional arguments.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+
+		rest := fs.Args()
+		// Everything after -- is positional.
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		if len(rest) == 0 {
+			return positional, nil
+		}
+
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+func findGroup(name string) (group, bool) {
+	for _, g := range groups {
+		if g.name == name {
+			return g, true
+		}
+		for _, alias := range g.aliases {
+			if alias == name {
+				return g, true
+			}
+		}
+	}
+
+	return group{}, false
+}
+
+func isHelp(arg string) bool {
+	switch arg {
+	case "help", "-h", "-help", "--help":
+		return true
+	default:
+		return false
+	}
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: jurassicctl <command> <subcommand> [flags]\n\nCommands:\n")
+	for _, g := range groups {
+		fmt.Fprintf(w, "  %-12s %s\n", g.name, g.summary)
+	}
+	fmt.Fprintf(w, "\nRun 'jurassicctl <command> help' for the subcommands.\n")
+}
+
+func groupUsage(w io.Writer, g group) {
+	fmt.Fprintf(w, "Usage: jurassicctl %s <subcommand> [flags]\n\nSubcommands:\n", g.name)
+	for _, cmd := range g.commands {
+		name := cmd.name
+		if len(cmd.args) > 0 {
+			name += " <" + strings.Join(cmd.args, "> <") + ">"
+		}
+		fmt.Fprintf(w, "  %-24s %s\n", name, cmd.summary)
+	}
+	fmt.Fprintf(w, "\nRun 'jurassicctl %s <subcommand> -h' for the subcommand flags.\n", g.name)
+}
@@ -0,0 +1,794 @@
+//go:build unit
+// +build unit
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+const (
+	testCageID     = "6e1a2c6e-2c5f-4c1c-9d3b-111111111111"
+	testOtherCage  = "6e1a2c6e-2c5f-4c1c-9d3b-222222222222"
+	testDinosaurID = "6e1a2c6e-2c5f-4c1c-9d3b-333333333333"
+	testZoneID     = "6e1a2c6e-2c5f-4c1c-9d3b-444444444444"
+	testSectorID   = "6e1a2c6e-2c5f-4c1c-9d3b-555555555555"
+	testFeedingID  = "6e1a2c6e-2c5f-4c1c-9d3b-666666666666"
+	testRecordID   = "6e1a2c6e-2c5f-4c1c-9d3b-777777777777"
+	testEggID      = "6e1a2c6e-2c5f-4c1c-9d3b-888888888888"
+	testEventID    = "6e1a2c6e-2c5f-4c1c-9d3b-999999999999"
+	testPairID     = "6e1a2c6e-2c5f-4c1c-9d3b-aaaaaaaaaaaa"
+	testStaffID    = "6e1a2c6e-2c5f-4c1c-9d3b-bbbbbbbbbbbb"
+	testShiftID    = "6e1a2c6e-2c5f-4c1c-9d3b-cccccccccccc"
+	testIncidentID = "6e1a2c6e-2c5f-4c1c-9d3b-dddddddddddd"
+)
+
+type recordedRequest struct {
+	method, path, query, auth, body string
+}
+
+// newTestAPI serves canned responses and records the requests.
+func newTestAPI(t *testing.T) (*httptest.Server, *[]recordedRequest) {
+	t.Helper()
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	cage := app.Cage{ID: testCageID, Status: app.CageStatusActive, Capacity: 10, Occupancy: 1, CreatedAt: now, UpdatedAt: now}
+	zone := app.Zone{ID: testZoneID, Name: "Lagoon", HeadCapacity: 10, HeadCount: 1, CreatedAt: now, UpdatedAt: now}
+	zone.Sectors = []app.Sector{{ID: testSectorID, ZoneID: testZoneID, Name: "East", HeadCapacity: 10, HeadCount: 1, CreatedAt: now, UpdatedAt: now}}
+	dinosaur := app.Dinosaur{ID: testDinosaurID, Name: "Sarah", Species: app.DinosaurSpeciesTriceratops, CageID: testCageID, Stage: app.LifecycleStageAdult, CreatedAt: now, UpdatedAt: now}
+	hatchDate := now.Add(7 * 24 * time.Hour)
+	egg := app.Dinosaur{ID: testEggID, Name: "Blue", Species: app.DinosaurSpeciesVelociraptor, Stage: app.LifecycleStageEgg, HatchDate: &hatchDate, CreatedAt: now, UpdatedAt: now}
+	lineage := app.Lineage{DinosaurID: testDinosaurID, ParentIDs: []string{testEggID}, Ancestors: []app.Relative{
+		{ID: testEggID, Name: "Blue", Species: app.DinosaurSpeciesTriceratops, Stage: app.LifecycleStageAdult, ParentIDs: []string{}, Generation: 1},
+	}, Descendants: []app.Relative{}}
+	pair := app.BreedingPair{ID: testPairID, SireID: testEggID, DamID: testDinosaurID, Species: app.DinosaurSpeciesTriceratops, InbreedingCoefficient: 0.25, Flagged: true, CreatedAt: now}
+	staff := app.Staff{ID: testStaffID, Name: "Muldoon", Role: app.StaffRoleKeeper, Certifications: []app.Certification{app.CertificationCarnivoreHandling}, CreatedAt: now, UpdatedAt: now}
+	shift := app.Assignment{ID: testShiftID, StaffID: testStaffID, CageID: testCageID, StartsAt: now, EndsAt: now.Add(8 * time.Hour), CreatedAt: now}
+	incident := app.Incident{ID: testIncidentID, Type: app.IncidentTypeEscape, Severity: app.IncidentSeverityCritical, CageID: testCageID, DinosaurIDs: []string{testDinosaurID}, Summary: "Fence down", Status: app.IncidentStatusOpen, OpenedAt: now, CreatedAt: now, UpdatedAt: now}
+	incident.Timeline = []app.IncidentEntry{{ID: testEventID, IncidentID: testIncidentID, Note: "Sarah recaptured", OccurredAt: now, CreatedAt: now}}
+	event := app.LifecycleEvent{ID: testEventID, DinosaurID: testDinosaurID, From: app.LifecycleStageAdult, To: app.LifecycleStageDeceased, CageID: testCageID, OccurredAt: now, CreatedAt: now}
+	feeding := app.Feeding{ID: testFeedingID, CageID: testCageID, Diet: "goats", Quantity: 50, Unsafe: true, FedAt: now, CreatedAt: now}
+	record := app.HealthRecord{ID: testRecordID, DinosaurID: testDinosaurID, Kind: app.HealthRecordKindWeight, Author: "Dr. Harding", WeightKg: 7200.5, RecordedAt: now, CreatedAt: now}
+	overdue := app.OverdueFeeding{DinosaurID: testDinosaurID, Name: "Sarah", Species: app.DinosaurSpeciesTriceratops, CageID: testCageID, DueAt: now}
+
+	var requests []recordedRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, recordedRequest{
+			method: r.Method,
+			path:   r.URL.Path,
+			query:  r.URL.RawQuery,
+			auth:   r.Header.Get("Authorization"),
+			body:   string(body),
+		})
+
+		var data any
+		switch {
+		case r.URL.Path == "/api/cages" && r.Method == http.MethodGet:
+			data = []app.Cage{cage}
+		case r.URL.Path == "/api/cages" && r.Method == http.MethodPost:
+			data = cage
+		case r.URL.Path == "/api/cages/"+testCageID && r.Method == http.MethodPut:
+			cage.Status = app.CageStatusDown
+			data = cage
+		case r.URL.Path == "/api/cages/"+testCageID+"/capacity" && r.Method == http.MethodPut:
+			cage.Capacity = 12
+			data = cage
+		case r.URL.Path == "/api/dinosaurs" && r.Method == http.MethodGet:
+			data = []app.Dinosaur{dinosaur}
+		case r.URL.Path == "/api/zones/"+testZoneID && r.Method == http.MethodGet:
+			data = zone
+		case r.URL.Path == "/api/zones/"+testZoneID+"/sectors" && r.Method == http.MethodPost:
+			data = zone.Sectors[0]
+		case r.URL.Path == "/api/dinosaurs/"+testDinosaurID && r.Method == http.MethodPut:
+			dinosaur.CageID = testOtherCage
+			data = dinosaur
+		case r.URL.Path == "/api/dinosaurs/"+testDinosaurID+"/health" && r.Method == http.MethodPost:
+			data = record
+		case r.URL.Path == "/api/dinosaurs/"+testDinosaurID+"/quarantine" && r.Method == http.MethodPost:
+			dinosaur.CageID = testOtherCage
+			dinosaur.Quarantined = true
+			dinosaur.QuarantineReason = "Fever"
+			dinosaur.QuarantineStart = &now
+			data = dinosaur
+		case r.URL.Path == "/api/dinosaurs/"+testDinosaurID+"/release" && r.Method == http.MethodPost:
+			data = dinosaur
+		case r.URL.Path == "/api/dinosaurs/"+testDinosaurID+"/lifecycle" && r.Method == http.MethodPost:
+			data = event
+		case r.URL.Path == "/api/hatchery" && r.Method == http.MethodPost:
+			data = egg
+		case r.URL.Path == "/api/hatchery/"+testEggID+"/hatch" && r.Method == http.MethodPost:
+			egg.CageID = testCageID
+			egg.Stage = app.LifecycleStageJuvenile
+			data = egg
+		case r.URL.Path == "/api/dinosaurs/"+testDinosaurID+"/lineage" && r.Method == http.MethodGet:
+			data = lineage
+		case r.URL.Path == "/api/dinosaurs/"+testDinosaurID+"/parents" && r.Method == http.MethodPut:
+			data = lineage
+		case r.URL.Path == "/api/breeding-pairs" && r.Method == http.MethodPost:
+			data = pair
+		case r.URL.Path == "/api/cages/"+testCageID+"/staff" && r.Method == http.MethodGet:
+			data = []app.Staff{staff}
+		case r.URL.Path == "/api/staff/"+testStaffID+"/cages" && r.Method == http.MethodGet:
+			data = []app.Cage{cage}
+		case r.URL.Path == "/api/assignments" && r.Method == http.MethodPost:
+			data = shift
+		case r.URL.Path == "/api/incidents" && r.Method == http.MethodPost:
+			data = incident
+		case r.URL.Path == "/api/incidents/"+testIncidentID && r.Method == http.MethodGet:
+			data = incident
+		case r.URL.Path == "/api/incidents/"+testIncidentID+"/resolve" && r.Method == http.MethodPost:
+			incident.Status = app.IncidentStatusResolved
+			incident.Resolution = "Fence repaired"
+			incident.ResolvedAt = &now
+			data = incident
+		case r.URL.Path == "/api/feedings" && r.Method == http.MethodPost:
+			data = feeding
+		case r.URL.Path == "/api/feedings/overdue" && r.Method == http.MethodGet:
+			data = []app.OverdueFeeding{overdue}
+		case r.URL.Path == "/api/dinosaurs/"+testOtherCage:
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		default:
+			http.Error(w, http.StatusText(http.StatusNotImplemented), http.StatusNotImplemented)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"data": data}) // nolint:errcheck
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, &requests
+}
+
+func runCtl(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	err := run(context.Background(), args, &stdout, &stderr)
+
+	return stdout.String(), err
+}
+
+func setUpConfig(t *testing.T) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	t.Setenv(envConfig, path)
+	t.Setenv(envProfile, "")
+	t.Setenv(envBaseURL, "")
+	t.Setenv(envAPIKey, "")
+
+	return path
+}
+
+func TestCagesList(t *testing.T) {
+	setUpConfig(t)
+	srv, requests := newTestAPI(t)
+
+	out, err := runCtl(t, "cages", "list", "--status", "active", "--base-url", srv.URL+"/api", "--api-key", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := "/api/cages", (*requests)[0].path; want != got {
+		t.Fatalf("Expected path %s got %s", want, got)
+	}
+	if want, got := "status=active", (*requests)[0].query; want != got {
+		t.Fatalf("Expected query %s got %s", want, got)
+	}
+	if want, got := "Bearer secret", (*requests)[0].auth; want != got {
+		t.Fatalf("Expected auth %s got %s", want, got)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if want, got := 2, len(lines); want != got {
+		t.Fatalf("Expected lines %d got %d:\n%s", want, got, out)
+	}
+	if !strings.HasPrefix(lines[0], "ID") || !strings.Contains(lines[1], testCageID) {
+		t.Fatalf("Unexpected table:\n%s", out)
+	}
+}
+
+func TestIncludeDeleted(t *testing.T) {
+	setUpConfig(t)
+	srv, requests := newTestAPI(t)
+
+	if _, err := runCtl(t, "dinos", "list", "--include-deleted", "--base-url", srv.URL+"/api"); err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := "includeDeleted=true", (*requests)[0].query; want != got {
+		t.Fatalf("Expected query %s got %s", want, got)
+	}
+}
+
+func TestListFilters(t *testing.T) {
+	setUpConfig(t)
+	srv, requests := newTestAPI(t)
+
+	_, err := runCtl(t, "dinos", "list", "--species", "triceratops,stegosaurus", "--diet", "herbivore", "--health", "sick",
+		"--quarantined", "true", "--name-prefix", "Sa", "--sort", "-createdAt", "--base-url", srv.URL+"/api")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	query, err := url.ParseQuery((*requests)[0].query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{
+		"species":      "triceratops,stegosaurus",
+		"diet":         "herbivore",
+		"healthStatus": "sick",
+		"quarantined":  "true",
+		"namePrefix":   "Sa",
+		"sort":         "-createdAt",
+	} {
+		if got := query.Get(name); want != got {
+			t.Fatalf("Expected %s %s got %s", name, want, got)
+		}
+	}
+
+	_, err = runCtl(t, "cages", "list", "--type", "aquatic", "--quarantine", "false", "--min-free-capacity", "2", "--occupancy", "empty",
+		"--created-after", "2023-01-02T03:04:05Z", "--base-url", srv.URL+"/api")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	query, err = url.ParseQuery((*requests)[1].query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{
+		"type":            "aquatic",
+		"quarantine":      "false",
+		"minFreeCapacity": "2",
+		"occupancy":       "empty",
+		"createdAfter":    "2023-01-02T03:04:05Z",
+	} {
+		if got := query.Get(name); want != got {
+			t.Fatalf("Expected %s %s got %s", name, want, got)
+		}
+	}
+
+	_, err = runCtl(t, "dinos", "list", "--zone", testZoneID, "--base-url", srv.URL+"/api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "zoneId="+testZoneID, (*requests)[2].query; want != got {
+		t.Fatalf("Expected query %s got %s", want, got)
+	}
+
+	if _, err := runCtl(t, "cages", "list", "--sort", "name", "--base-url", srv.URL+"/api"); err == nil {
+		t.Fatal("Expected error got nil")
+	}
+	if _, err := runCtl(t, "cages", "list", "--created-after", "yesterday", "--base-url", srv.URL+"/api"); err == nil {
+		t.Fatal("Expected error got nil")
+	}
+	if _, err := runCtl(t, "cages", "list", "--quarantine", "maybe", "--base-url", srv.URL+"/api"); err == nil {
+		t.Fatal("Expected error got nil")
+	}
+}
+
+func TestZones(t *testing.T) {
+	setUpConfig(t)
+	srv, requests := newTestAPI(t)
+
+	out, err := runCtl(t, "zones", "get", testZoneID, "--base-url", srv.URL+"/api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "Lagoon") || !strings.Contains(out, testSectorID) {
+		t.Fatalf("Unexpected output:\n%s", out)
+	}
+
+	if _, err := runCtl(t, "zones", "add-sector", testZoneID, "--name", "East", "--base-url", srv.URL+"/api"); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := `{"name":"East"}`, strings.TrimSpace((*requests)[1].body); want != got {
+		t.Fatalf("Expected body %s got %s", want, got)
+	}
+
+	if _, err := runCtl(t, "zones", "cages", testZoneID, "--sector", testSectorID, "--base-url", srv.URL+"/api"); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "/api/cages", (*requests)[2].path; want != got {
+		t.Fatalf("Expected path %s got %s", want, got)
+	}
+	if want, got := "sectorId="+testSectorID+"&zoneId="+testZoneID, (*requests)[2].query; want != got {
+		t.Fatalf("Expected query %s got %s", want, got)
+	}
+
+	if _, err := runCtl(t, "zones", "add", "--base-url", srv.URL+"/api"); err == nil {
+		t.Fatal("Expected error got nil")
+	}
+}
+
+func TestOutputFormats(t *testing.T) {
+	setUpConfig(t)
+	srv, _ := newTestAPI(t)
+
+	out, err := runCtl(t, "dinos", "list", "--species", "triceratops", "-o", "json", "--base-url", srv.URL+"/api")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var dinosaurs []app.Dinosaur
+	if err := json.Unmarshal([]byte(out), &dinosaurs); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "Sarah", dinosaurs[0].Name; want != got {
+		t.Fatalf("Expected name %s got %s", want, got)
+	}
+
+	out, err = runCtl(t, "dinos", "list", "-output", "yaml", "--base-url", srv.URL+"/api")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var items []map[string]any
+	if err := yaml.Unmarshal([]byte(out), &items); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := testCageID, items[0]["cageId"]; want != got {
+		t.Fatalf("Expected cageId %s got %s", want, got)
+	}
+
+	if _, err := runCtl(t, "dinos", "list", "-o", "xml", "--base-url", srv.URL+"/api"); err == nil {
+		t.Fatal("Expected error got nil")
+	}
+}
+
+func TestInterspersedFlags(t *testing.T) {
+	setUpConfig(t)
+	srv, requests := newTestAPI(t)
+
+	if _, err := runCtl(t, "dinos", "move", testDinosaurID, "--to", testOtherCage, "--base-url", srv.URL+"/api"); err != nil {
+		t.Fatal(err)
+	}
+
+	r := (*requests)[0]
+	if want, got := http.MethodPut, r.method; want != got {
+		t.Fatalf("Expected method %s got %s", want, got)
+	}
+	if want, got := "/api/dinosaurs/"+testDinosaurID, r.path; want != got {
+		t.Fatalf("Expected path %s got %s", want, got)
+	}
+	if !strings.Contains(r.body, testOtherCage) {
+		t.Fatalf("Expected body to contain the cage ID got %s", r.body)
+	}
+
+	if _, err := runCtl(t, "cages", "power-down", "--base-url", srv.URL+"/api", testCageID); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := `{"status":"down"}`, (*requests)[1].body; want != got {
+		t.Fatalf("Expected body %s got %s", want, got)
+	}
+}
+
+func TestCagesAdd(t *testing.T) {
+	setUpConfig(t)
+	srv, requests := newTestAPI(t)
+
+	_, err := runCtl(t, "cages", "add", "--capacity", "10", "--type", "paddock", "--capacity-unit", "space",
+		"--base-url", srv.URL+"/api")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"type":"paddock","capacity":10,"capacityUnit":"space","status":"active"}`
+	if got := (*requests)[0].body; want != got {
+		t.Fatalf("Expected body %s got %s", want, got)
+	}
+}
+
+func TestDinosQuarantine(t *testing.T) {
+	setUpConfig(t)
+	srv, requests := newTestAPI(t)
+
+	out, err := runCtl(t, "dinos", "quarantine", testDinosaurID, "--to", testOtherCage, "--reason", "Fever",
+		"--end", "2023-01-15T00:00:00Z", "--base-url", srv.URL+"/api")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"cageId":"` + testOtherCage + `","reason":"Fever","end":"2023-01-15T00:00:00Z"}`
+	if got := (*requests)[0].body; want != got {
+		t.Fatalf("Expected body %s got %s", want, got)
+	}
+	if !strings.Contains(out, "Fever") {
+		t.Fatalf("Expected the quarantine reason in the output got %s", out)
+	}
+
+	_, err = runCtl(t, "dinos", "release", testDinosaurID, "--to", testCageID, "--base-url", srv.URL+"/api")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want = `{"cageId":"` + testCageID + `"}`
+	if got := (*requests)[1].body; want != got {
+		t.Fatalf("Expected body %s got %s", want, got)
+	}
+
+	if _, err := runCtl(t, "dinos", "quarantine", testDinosaurID, "--reason", "Fever", "--base-url", srv.URL+"/api"); err == nil {
+		t.Fatal("Expected error got nil")
+	}
+}
+
+func TestDinosAddHealth(t *testing.T) {
+	setUpConfig(t)
+	srv, requests := newTestAPI(t)
+
+	out, err := runCtl(t, "dinos", "add-health", testDinosaurID, "--kind", "weight", "--weight", "7200.5",
+		"--author", "Dr. Harding", "--base-url", srv.URL+"/api")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"kind":"weight","author":"Dr. Harding","weightKg":7200.5}`
+	if got := (*requests)[0].body; want != got {
+		t.Fatalf("Expected body %s got %s", want, got)
+	}
+	if !strings.Contains(out, "7200.5kg") {
+		t.Fatalf("Expected the weight in the output got %s", out)
+	}
+}
+
+func TestHatchery(t *testing.T) {
+	setUpConfig(t)
+	srv, requests := newTestAPI(t)
+
+	out, err := runCtl(t, "hatchery", "lay", "--name", "Blue", "--species", "velociraptor", "--hatch", "2023-01-08T00:00:00Z",
+		"--base-url", srv.URL+"/api")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"name":"Blue","species":"velociraptor","hatchDate":"2023-01-08T00:00:00Z"}`
+	if got := (*requests)[0].body; want != got {
+		t.Fatalf("Expected body %s got %s", want, got)
+	}
+	if !strings.Contains(out, "2023-01-08") {
+		t.Fatalf("Expected the hatch date in the output got %s", out)
+	}
+
+	if _, err := runCtl(t, "hatchery", "list", "--hatch-before", "2023-02-01T00:00:00Z", "--base-url", srv.URL+"/api"); err != nil {
+		t.Fatal(err)
+	}
+
+	query, err := url.ParseQuery((*requests)[1].query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{
+		"stage":       "egg",
+		"hatchBefore": "2023-02-01T00:00:00Z",
+		"sort":        "hatchDate",
+	} {
+		if got := query.Get(name); want != got {
+			t.Fatalf("Expected %s %s got %s", name, want, got)
+		}
+	}
+
+	out, err = runCtl(t, "hatchery", "hatch", testEggID, "--to", testCageID, "--base-url", srv.URL+"/api")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want = `{"cageId":"` + testCageID + `"}`
+	if got := (*requests)[2].body; want != got {
+		t.Fatalf("Expected body %s got %s", want, got)
+	}
+	if !strings.Contains(out, "juvenile") {
+		t.Fatalf("Expected the juvenile stage in the output got %s", out)
+	}
+
+	if _, err := runCtl(t, "hatchery", "hatch", testEggID, "--base-url", srv.URL+"/api"); err == nil {
+		t.Fatal("Expected error got nil")
+	}
+}
+
+func TestDinosTransition(t *testing.T) {
+	setUpConfig(t)
+	srv, requests := newTestAPI(t)
+
+	out, err := runCtl(t, "dinos", "transition", testDinosaurID, "--stage", "deceased", "--at", "2023-01-01T00:00:00Z",
+		"--base-url", srv.URL+"/api")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"stage":"deceased","occurredAt":"2023-01-01T00:00:00Z"}`
+	if got := (*requests)[0].body; want != got {
+		t.Fatalf("Expected body %s got %s", want, got)
+	}
+	if !strings.Contains(out, "deceased") || !strings.Contains(out, testCageID) {
+		t.Fatalf("Expected the transition in the output got %s", out)
+	}
+}
+
+func TestBreeding(t *testing.T) {
+	setUpConfig(t)
+	srv, requests := newTestAPI(t)
+
+	out, err := runCtl(t, "breeding", "set-parents", testDinosaurID, "--parents", testEggID, "--base-url", srv.URL+"/api")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"parentIds":["` + testEggID + `"]}`
+	if got := (*requests)[0].body; want != got {
+		t.Fatalf("Expected body %s got %s", want, got)
+	}
+	if !strings.Contains(out, "ancestor") {
+		t.Fatalf("Expected the parent in the output got %s", out)
+	}
+
+	if _, err := runCtl(t, "breeding", "lineage", testDinosaurID, "--generations", "5", "--base-url", srv.URL+"/api"); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "generations=5", (*requests)[1].query; want != got {
+		t.Fatalf("Expected query %s got %s", want, got)
+	}
+
+	out, err = runCtl(t, "breeding", "propose", "--sire", testEggID, "--dam", testDinosaurID, "--base-url", srv.URL+"/api")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want = `{"sireId":"` + testEggID + `","damId":"` + testDinosaurID + `"}`
+	if got := (*requests)[2].body; want != got {
+		t.Fatalf("Expected body %s got %s", want, got)
+	}
+	if !strings.Contains(out, "Warning") {
+		t.Fatalf("Expected an inbreeding warning got %s", out)
+	}
+
+	if _, err := runCtl(t, "breeding", "set-parents", testDinosaurID, "--base-url", srv.URL+"/api"); err == nil {
+		t.Fatal("Expected error got nil")
+	}
+}
+
+func TestStaff(t *testing.T) {
+	setUpConfig(t)
+	srv, requests := newTestAPI(t)
+
+	if _, err := runCtl(t, "staff", "assign", testStaffID, "--cage", testCageID, "--start", "2023-01-01T08:00:00Z", "--hours", "4", "--base-url", srv.URL+"/api"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"staffId":"` + testStaffID + `","cageId":"` + testCageID + `","startsAt":"2023-01-01T08:00:00Z","endsAt":"2023-01-01T12:00:00Z"}`
+	if got := (*requests)[0].body; want != got {
+		t.Fatalf("Expected body %s got %s", want, got)
+	}
+
+	out, err := runCtl(t, "staff", "on-duty", testCageID, "--base-url", srv.URL+"/api")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := "", (*requests)[1].query; want != got {
+		t.Fatalf("Expected query %s got %s", want, got)
+	}
+	if !strings.Contains(out, "carnivore-handling") {
+		t.Fatalf("Expected the certifications in the output got %s", out)
+	}
+
+	out, err = runCtl(t, "staff", "cages", testStaffID, "--at", "2023-01-01T09:00:00Z", "--base-url", srv.URL+"/api")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := "at=2023-01-01T09%3A00%3A00Z", (*requests)[2].query; want != got {
+		t.Fatalf("Expected query %s got %s", want, got)
+	}
+	if !strings.Contains(out, testCageID) {
+		t.Fatalf("Expected the cage in the output got %s", out)
+	}
+
+	if _, err := runCtl(t, "staff", "assign", testStaffID, "--base-url", srv.URL+"/api"); err == nil {
+		t.Fatal("Expected error got nil")
+	}
+}
+
+func TestIncidents(t *testing.T) {
+	setUpConfig(t)
+	srv, requests := newTestAPI(t)
+
+	out, err := runCtl(t, "incidents", "open", "--cage", testCageID, "--incident", "escape", "--severity", "critical",
+		"--dinos", testDinosaurID, "--summary", "Fence down", "--base-url", srv.URL+"/api")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"type":"escape","severity":"critical","cageId":"` + testCageID + `","dinosaurIds":["` + testDinosaurID + `"],"summary":"Fence down"}`
+	if got := (*requests)[0].body; want != got {
+		t.Fatalf("Expected body %s got %s", want, got)
+	}
+	if !strings.Contains(out, "locked down") {
+		t.Fatalf("Expected a lockdown warning got %s", out)
+	}
+
+	out, err = runCtl(t, "incidents", "get", testIncidentID, "--base-url", srv.URL+"/api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "Sarah recaptured") {
+		t.Fatalf("Expected the timeline in the output got %s", out)
+	}
+
+	if _, err := runCtl(t, "incidents", "resolve", testIncidentID, "--resolution", "Fence repaired", "--base-url", srv.URL+"/api"); err != nil {
+		t.Fatal(err)
+	}
+
+	want = `{"resolution":"Fence repaired"}`
+	if got := (*requests)[2].body; want != got {
+		t.Fatalf("Expected body %s got %s", want, got)
+	}
+
+	if _, err := runCtl(t, "incidents", "open", "--cage", testCageID, "--base-url", srv.URL+"/api"); err == nil {
+		t.Fatal("Expected error got nil")
+	}
+}
+
+func TestFeedings(t *testing.T) {
+	setUpConfig(t)
+	srv, requests := newTestAPI(t)
+
+	out, err := runCtl(t, "feedings", "record", "--cage", testCageID, "--food", "goats", "--quantity", "50",
+		"--at", "2023-01-01T08:30:00Z", "--base-url", srv.URL+"/api")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"cageId":"` + testCageID + `","diet":"goats","quantity":50,"fedAt":"2023-01-01T08:30:00Z"}`
+	if got := (*requests)[0].body; want != got {
+		t.Fatalf("Expected body %s got %s", want, got)
+	}
+	if !strings.Contains(out, "Warning") {
+		t.Fatalf("Expected an unsafe feeding warning got %s", out)
+	}
+
+	out, err = runCtl(t, "feedings", "overdue", "--at", "2023-01-02T00:00:00Z", "--base-url", srv.URL+"/api")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := "at=2023-01-02T00%3A00%3A00Z", (*requests)[1].query; want != got {
+		t.Fatalf("Expected query %s got %s", want, got)
+	}
+	if !strings.Contains(out, "Sarah") {
+		t.Fatalf("Expected the overdue dinosaur in the output got %s", out)
+	}
+}
+
+func TestCagesResize(t *testing.T) {
+	setUpConfig(t)
+	srv, requests := newTestAPI(t)
+
+	out, err := runCtl(t, "cages", "resize", testCageID, "--capacity", "12", "--base-url", srv.URL+"/api")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := (*requests)[0]
+	if want, got := "/api/cages/"+testCageID+"/capacity", r.path; want != got {
+		t.Fatalf("Expected path %s got %s", want, got)
+	}
+	if want, got := `{"capacity":12}`, r.body; want != got {
+		t.Fatalf("Expected body %s got %s", want, got)
+	}
+	if !strings.Contains(out, "12") {
+		t.Fatalf("Expected the new capacity in the output got %s", out)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	setUpConfig(t)
+	srv, _ := newTestAPI(t)
+
+	_, err := runCtl(t, "dinos", "get", testOtherCage, "--base-url", srv.URL+"/api")
+	if !errors.Is(err, app.ErrNotFound) {
+		t.Fatalf("Expected %v got %v", app.ErrNotFound, err)
+	}
+
+	tests := [][]string{
+		{},
+		{"foo"},
+		{"cages"},
+		{"cages", "foo"},
+		{"cages", "get"},
+		{"dinos", "move", testDinosaurID, testOtherCage},
+	}
+	for _, args := range tests {
+		if _, err := runCtl(t, args...); !errors.Is(err, errUsage) {
+			t.Errorf("%v: expected %v got %v", args, errUsage, err)
+		}
+	}
+
+	if _, err := runCtl(t, "cages", "list", "-h"); !errors.Is(err, flag.ErrHelp) {
+		t.Errorf("Expected %v got %v", flag.ErrHelp, err)
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	path := setUpConfig(t)
+	srv, requests := newTestAPI(t)
+
+	if _, err := runCtl(t, "profiles", "set", "local", "--base-url", "http://localhost:1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runCtl(t, "profiles", "set", "test", "--base-url", srv.URL+"/api", "--api-key", "secret", "-o", "json"); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := os.FileMode(0o600), info.Mode().Perm(); want != got {
+		t.Fatalf("Expected mode %s got %s", want, got)
+	}
+
+	if _, err := runCtl(t, "profiles", "use", "test"); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runCtl(t, "cages", "list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "Bearer secret", (*requests)[0].auth; want != got {
+		t.Fatalf("Expected auth %s got %s", want, got)
+	}
+	if !strings.HasPrefix(out, "[") {
+		t.Fatalf("Expected JSON output got %s", out)
+	}
+
+	out, err = runCtl(t, "profiles", "list", "-o", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out, `"secret"`) {
+		t.Fatalf("Expected API key to be redacted got %s", out)
+	}
+
+	// The environment overrides the current profile.
+	t.Setenv(envProfile, "foo")
+	if _, err := runCtl(t, "cages", "list"); err == nil {
+		t.Fatal("Expected error got nil")
+	}
+}
+
+func TestCompletion(t *testing.T) {
+	setUpConfig(t)
+
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		out, err := runCtl(t, "completion", shell)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(out, "power-down") || !strings.Contains(out, "triceratops") {
+			t.Errorf("%s: unexpected completion script:\n%s", shell, out)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats.
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatYAML  = "yaml"
+)
+
+func validateFormat(format string) error {
+	switch format {
+	case formatTable, formatJSON, formatYAML:
+		return nil
+	default:
+		return fmt.Errorf("invalid output format %q", format)
+	}
+}
+
+// print writes v in the output format.
+// Tables are rendered from the header and the rows.
+func (e *env) print(v any, header []string, rows [][]string) error {
+	switch e.format {
+	case formatJSON:
+		enc := json.NewEncoder(e.out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case formatYAML:
+		return printYAML(e.out, v)
+	default:
+		return printTable(e.out, header, rows)
+	}
+}
+
+// message writes a confirmation in the table format only,
+// so that JSON and YAML output stays machine readable.
+func (e *env) message(format string, args ...any) {
+	if e.format == formatTable {
+		fmt.Fprintf(e.out, format+"\n", args...)
+	}
+}
+
+// printYAML writes v as YAML with the same field names as JSON.
+func printYAML(w io.Writer, v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	var generic any
+	if err := json.Unmarshal(b, &generic); err != nil {
+		return err
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(generic); err != nil {
+		return err
+	}
+
+	return enc.Close()
+}
+
+func printTable(w io.Writer, header []string, rows [][]string) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+
+	return tw.Flush()
+}
+
+func formatTime(t time.Time) string {
+	return t.Local().Format(time.DateTime)
+}
+
+// formatDeleted formats the soft deletion time, if any.
+func formatDeleted(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return formatTime(*t)
+}
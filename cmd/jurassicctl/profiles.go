@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sort"
+)
+
+var profileCommands = []command{
+	{name: "list", summary: "List profiles", setup: profilesList},
+	{name: "set", args: []string{"name"}, summary: "Create or update a profile", setup: profilesSet},
+	{name: "use", args: []string{"name"}, summary: "Set the current profile", setup: profilesUse},
+	{name: "delete", args: []string{"name"}, summary: "Delete a profile", setup: profilesDelete},
+}
+
+func profilesList(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	return func(_ context.Context, e *env, _ []string) error {
+		names := make([]string, 0, len(e.config.Profiles))
+		for name := range e.config.Profiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		type item struct {
+			Name    string `json:"name"`
+			Current bool   `json:"current"`
+			profile
+		}
+
+		items := make([]item, len(names))
+		rows := make([][]string, len(names))
+		for i, name := range names {
+			p := e.config.Profiles[name]
+			if p.APIKey != "" {
+				p.APIKey = "[redacted]"
+			}
+			items[i] = item{Name: name, Current: name == e.profileName, profile: p}
+
+			current := ""
+			if items[i].Current {
+				current = "*"
+			}
+			rows[i] = []string{current, name, p.BaseURL, p.Output}
+		}
+
+		return e.print(items, []string{"CURRENT", "NAME", "BASE-URL", "OUTPUT"}, rows)
+	}
+}
+
+func profilesSet(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	// The common -base-url, -api-key and -output flags set the profile settings.
+	return func(_ context.Context, e *env, args []string) error {
+		name := args[0]
+		p := e.config.Profiles[name]
+
+		fs.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "base-url":
+				p.BaseURL = f.Value.String()
+			case "api-key":
+				p.APIKey = f.Value.String()
+			case "o", "output":
+				p.Output = f.Value.String()
+			}
+		})
+
+		if e.config.Profiles == nil {
+			e.config.Profiles = make(map[string]profile)
+		}
+		e.config.Profiles[name] = p
+		if e.config.CurrentProfile == "" {
+			e.config.CurrentProfile = name
+		}
+
+		if err := saveConfig(e.configPath, e.config); err != nil {
+			return err
+		}
+		e.message("Profile %s saved to %s", name, e.configPath)
+
+		return nil
+	}
+}
+
+func profilesUse(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	return func(_ context.Context, e *env, args []string) error {
+		name := args[0]
+		if _, ok := e.config.Profiles[name]; !ok {
+			return fmt.Errorf("unknown profile %q", name)
+		}
+
+		e.config.CurrentProfile = name
+		if err := saveConfig(e.configPath, e.config); err != nil {
+			return err
+		}
+		e.message("Using profile %s", name)
+
+		return nil
+	}
+}
+
+func profilesDelete(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	return func(_ context.Context, e *env, args []string) error {
+		name := args[0]
+		if _, ok := e.config.Profiles[name]; !ok {
+			return fmt.Errorf("unknown profile %q", name)
+		}
+
+		delete(e.config.Profiles, name)
+		if e.config.CurrentProfile == name {
+			e.config.CurrentProfile = ""
+		}
+		if err := saveConfig(e.configPath, e.config); err != nil {
+			return err
+		}
+		e.message("Profile %s deleted", name)
+
+		return nil
+	}
+}
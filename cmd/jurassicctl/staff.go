@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"strings"
+	"time"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+var staffCommands = []command{
+	{name: "list", summary: "List staff members sorted by name", setup: staffList},
+	{name: "add", summary: "Add a new staff member", setup: staffAdd},
+	{name: "get", args: []string{"id"}, summary: "Get a staff member", setup: staffGet},
+	{name: "cages", args: []string{"id"}, summary: "List the cages a staff member covers", setup: staffCages},
+	{name: "on-duty", args: []string{"cage-id"}, summary: "List the staff on duty for a cage", setup: staffOnDuty},
+	{name: "assign", args: []string{"id"}, summary: "Assign a staff member to a shift covering a cage", setup: staffAssign},
+	{name: "assignments", summary: "List the assignments in the order the shifts start", setup: staffAssignments},
+	{name: "unassign", args: []string{"assignment-id"}, summary: "Delete an assignment", setup: staffUnassign},
+}
+
+var staffHeader = []string{"ID", "NAME", "ROLE", "CERTIFICATIONS", "CREATED"}
+
+func staffRow(s app.Staff) []string {
+	certifications := make([]string, len(s.Certifications))
+	for i, c := range s.Certifications {
+		certifications[i] = string(c)
+	}
+
+	return []string{
+		s.ID,
+		s.Name,
+		string(s.Role),
+		strings.Join(certifications, ","),
+		formatTime(s.CreatedAt),
+	}
+}
+
+func (e *env) printStaff(list []app.Staff) error {
+	if list == nil {
+		list = []app.Staff{}
+	}
+
+	rows := make([][]string, len(list))
+	for i, s := range list {
+		rows[i] = staffRow(s)
+	}
+
+	return e.print(list, staffHeader, rows)
+}
+
+var assignmentHeader = []string{"ID", "STAFF", "CAGE", "STARTS", "ENDS"}
+
+func assignmentRow(a app.Assignment) []string {
+	return []string{
+		a.ID,
+		a.StaffID,
+		a.CageID,
+		formatTime(a.StartsAt),
+		formatTime(a.EndsAt),
+	}
+}
+
+func staffList(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	role := fs.String("role", "", "Filter by role")
+
+	return func(ctx context.Context, e *env, _ []string) error {
+		list, err := e.client.Staff.List(ctx, app.StaffFilter{Role: app.StaffRole(*role)})
+		if err != nil {
+			return err
+		}
+
+		return e.printStaff(list)
+	}
+}
+
+func staffAdd(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	name := fs.String("name", "", "Staff member name (required)")
+	role := fs.String("role", string(app.StaffRoleKeeper), "Staff member role")
+	var certifications []app.Certification
+	fs.Func("certifications", "Comma separated certifications, e.g. carnivore-handling", func(value string) error {
+		for _, c := range strings.Split(value, ",") {
+			certifications = append(certifications, app.Certification(c))
+		}
+		return nil
+	})
+
+	return func(ctx context.Context, e *env, _ []string) error {
+		if *name == "" {
+			return errors.New("-name is required")
+		}
+
+		staff, err := e.client.Staff.Add(ctx, &app.Staff{
+			Name:           *name,
+			Role:           app.StaffRole(*role),
+			Certifications: certifications,
+		})
+		if err != nil {
+			return err
+		}
+
+		return e.print(staff, staffHeader, [][]string{staffRow(*staff)})
+	}
+}
+
+func staffGet(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	return func(ctx context.Context, e *env, args []string) error {
+		staff, err := e.client.Staff.Get(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		return e.print(staff, staffHeader, [][]string{staffRow(*staff)})
+	}
+}
+
+func staffCages(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	var at time.Time
+	fs.Func("at", "RFC 3339 time of the shifts, now by default", timeFlag(&at))
+
+	return func(ctx context.Context, e *env, args []string) error {
+		cages, err := e.client.Staff.Cages(ctx, args[0], at)
+		if err != nil {
+			return err
+		}
+		if cages == nil {
+			cages = []app.Cage{}
+		}
+
+		rows := make([][]string, len(cages))
+		for i, c := range cages {
+			rows[i] = cageRow(c)
+		}
+
+		return e.print(cages, cageHeader, rows)
+	}
+}
+
+func staffOnDuty(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	var at time.Time
+	fs.Func("at", "RFC 3339 time of the shifts, now by default", timeFlag(&at))
+
+	return func(ctx context.Context, e *env, args []string) error {
+		list, err := e.client.Staff.List(ctx, app.StaffFilter{CageID: args[0], OnDuty: at})
+		if err != nil {
+			return err
+		}
+
+		return e.printStaff(list)
+	}
+}
+
+func staffAssign(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	cageID := fs.String("cage", "", "Cage ID (required)")
+	var start time.Time
+	fs.Func("start", "RFC 3339 start of the shift, now by default", timeFlag(&start))
+	hours := fs.Int("hours", 8, "Length of the shift in hours")
+
+	return func(ctx context.Context, e *env, args []string) error {
+		if *cageID == "" {
+			return errors.New("-cage is required")
+		}
+		if start.IsZero() {
+			start = time.Now().Truncate(time.Second)
+		}
+
+		assignment, err := e.client.Staff.Assign(ctx, &app.Assignment{
+			StaffID:  args[0],
+			CageID:   *cageID,
+			StartsAt: start,
+			EndsAt:   start.Add(time.Duration(*hours) * time.Hour),
+		})
+		if err != nil {
+			return err
+		}
+
+		return e.print(assignment, assignmentHeader, [][]string{assignmentRow(*assignment)})
+	}
+}
+
+func staffAssignments(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	staffID := fs.String("staff", "", "Filter by staff member ID")
+	cageID := fs.String("cage", "", "Filter by cage ID")
+	var at time.Time
+	fs.Func("at", "Only the shifts on at the RFC 3339 time", timeFlag(&at))
+
+	return func(ctx context.Context, e *env, _ []string) error {
+		assignments, err := e.client.Staff.ListAssignments(ctx, app.AssignmentFilter{
+			StaffID: *staffID,
+			CageID:  *cageID,
+			At:      at,
+		})
+		if err != nil {
+			return err
+		}
+		if assignments == nil {
+			assignments = []app.Assignment{}
+		}
+
+		rows := make([][]string, len(assignments))
+		for i, a := range assignments {
+			rows[i] = assignmentRow(a)
+		}
+
+		return e.print(assignments, assignmentHeader, rows)
+	}
+}
+
+func staffUnassign(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	return func(ctx context.Context, e *env, args []string) error {
+		if err := e.client.Staff.DeleteAssignment(ctx, args[0]); err != nil {
+			return err
+		}
+		e.message("Assignment %s deleted", args[0])
+
+		return nil
+	}
+}
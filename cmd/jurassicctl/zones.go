@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"strconv"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+var zoneCommands = []command{
+	{name: "list", summary: "List zones", setup: zonesList},
+	{name: "get", args: []string{"id"}, summary: "Get a zone along with its sectors", setup: zonesGet},
+	{name: "add", summary: "Add a new zone", setup: zonesAdd},
+	{name: "add-sector", args: []string{"id"}, summary: "Add a new sector to a zone", setup: zonesAddSector},
+	{name: "cages", args: []string{"id"}, summary: "List the cages in a zone", setup: zonesCages},
+}
+
+var zoneHeader = []string{"ID", "NAME", "HEADS", "SPACE", "CREATED"}
+
+func zoneRow(z app.Zone) []string {
+	return []string{
+		z.ID,
+		z.Name,
+		rollup(z.HeadCount, z.HeadCapacity),
+		rollup(z.SpaceUsed, z.SpaceCapacity),
+		formatTime(z.CreatedAt),
+	}
+}
+
+var sectorHeader = []string{"ID", "ZONE", "NAME", "HEADS", "SPACE", "CREATED"}
+
+func sectorRow(s app.Sector) []string {
+	return []string{
+		s.ID,
+		s.ZoneID,
+		s.Name,
+		rollup(s.HeadCount, s.HeadCapacity),
+		rollup(s.SpaceUsed, s.SpaceCapacity),
+		formatTime(s.CreatedAt),
+	}
+}
+
+// rollup formats the rolled up occupancy out of the capacity, e.g. 3/10.
+func rollup(occupancy, capacity int) string {
+	return strconv.Itoa(occupancy) + "/" + strconv.Itoa(capacity)
+}
+
+func zonesList(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	return func(ctx context.Context, e *env, _ []string) error {
+		zones, err := e.client.Zones.List(ctx)
+		if err != nil {
+			return err
+		}
+		if zones == nil {
+			zones = []app.Zone{}
+		}
+
+		rows := make([][]string, len(zones))
+		for i, z := range zones {
+			rows[i] = zoneRow(z)
+		}
+
+		return e.print(zones, zoneHeader, rows)
+	}
+}
+
+// zonesGet prints the zone followed by its sectors in the table format.
+func zonesGet(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	return func(ctx context.Context, e *env, args []string) error {
+		zone, err := e.client.Zones.Get(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		if e.format != formatTable {
+			return e.print(zone, nil, nil)
+		}
+
+		if err := printTable(e.out, zoneHeader, [][]string{zoneRow(*zone)}); err != nil {
+			return err
+		}
+		e.message("")
+
+		rows := make([][]string, len(zone.Sectors))
+		for i, s := range zone.Sectors {
+			rows[i] = sectorRow(s)
+		}
+
+		return printTable(e.out, sectorHeader, rows)
+	}
+}
+
+func zonesAdd(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	name := fs.String("name", "", "Zone name (required)")
+
+	return func(ctx context.Context, e *env, _ []string) error {
+		if *name == "" {
+			return errors.New("-name is required")
+		}
+
+		zone, err := e.client.Zones.Add(ctx, &app.Zone{Name: *name})
+		if err != nil {
+			return err
+		}
+
+		return e.print(zone, zoneHeader, [][]string{zoneRow(*zone)})
+	}
+}
+
+func zonesAddSector(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	name := fs.String("name", "", "Sector name (required)")
+
+	return func(ctx context.Context, e *env, args []string) error {
+		if *name == "" {
+			return errors.New("-name is required")
+		}
+
+		sector, err := e.client.Zones.AddSector(ctx, &app.Sector{ZoneID: args[0], Name: *name})
+		if err != nil {
+			return err
+		}
+
+		return e.print(sector, sectorHeader, [][]string{sectorRow(*sector)})
+	}
+}
+
+func zonesCages(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+	sectorID := fs.String("sector", "", "Filter by sector ID")
+
+	return func(ctx context.Context, e *env, args []string) error {
+		cages, err := e.client.Cages.List(ctx, app.CageFilter{ZoneID: args[0], SectorID: *sectorID})
+		if err != nil {
+			return err
+		}
+		if cages == nil {
+			cages = []app.Cage{}
+		}
+
+		rows := make([][]string, len(cages))
+		for i, c := range cages {
+			rows[i] = cageRow(c)
+		}
+
+		return e.print(cages, cageHeader, rows)
+	}
+}
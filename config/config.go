@@ -0,0 +1,367 @@
+// Package config loads and validates the service configuration.
+//
+// Settings are resolved in the order of increasing precedence:
+// defaults, the configuration file (YAML or TOML), JURASSIC_* environment
+// variables and command line flags. Configuration file keys, flag names and
+// environment variable names map one to one, e.g. db-conn, -db-conn and
+// JURASSIC_DB_CONN.
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+// EnvPrefix is the prefix of the environment variables.
+const EnvPrefix = "JURASSIC_"
+
+// Redacted replaces secret values.
+const Redacted = "REDACTED"
+
+// Rate limit backends.
+const (
+	RateLimitBackendMemory   = "memory"
+	RateLimitBackendPostgres = "postgres"
+)
+
+// TLS client authentication modes.
+const (
+	// TLSClientAuthOptional verifies client certificates if they are presented.
+	TLSClientAuthOptional = "optional"
+	// TLSClientAuthRequire requires and verifies client certificates.
+	TLSClientAuthRequire = "require"
+)
+
+// Config represents the service configuration.
+type Config struct {
+	Addr            string
+	BaseURI         string
+	ShutdownTimeout time.Duration
+	DrainDelay      time.Duration
+	ReadyTimeout    time.Duration
+	LogLevel        string
+
+	DBConnString string
+	DBMigrations string
+	Migrate      bool
+
+	APIKeys []string
+
+	TLSCert           string
+	TLSKey            string
+	TLSClientCA       string
+	TLSClientAuth     string
+	TLSReloadInterval time.Duration
+
+	CORSAllowedOrigins []string
+	CORSAllowedMethods []string
+	CORSAllowedHeaders []string
+	CORSMaxAge         time.Duration
+
+	RateLimits       []string
+	RateLimitBackend string
+
+	DeletedRetention time.Duration
+
+	InbreedingThreshold float64
+
+	Docs              bool
+	ValidateRequests  bool
+	ValidateResponses bool
+}
+
+// Default returns the default configuration.
+func Default() Config {
+	return Config{
+		Addr:                ":9001",
+		ShutdownTimeout:     2 * time.Second,
+		ReadyTimeout:        time.Second,
+		LogLevel:            "info",
+		TLSClientAuth:       TLSClientAuthOptional,
+		TLSReloadInterval:   10 * time.Second,
+		CORSAllowedMethods:  []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
+		CORSAllowedHeaders:  []string{"Authorization", "Content-Type"},
+		CORSMaxAge:          10 * time.Minute,
+		RateLimitBackend:    RateLimitBackendMemory,
+		DeletedRetention:    30 * 24 * time.Hour,
+		InbreedingThreshold: app.DefaultInbreedingThreshold,
+		ValidateRequests:    true,
+	}
+}
+
+// RegisterFlags binds all configuration settings to the flag set.
+func (c *Config) RegisterFlags(fs *flag.FlagSet) {
+	c.RegisterServerFlags(fs)
+	c.RegisterDBFlags(fs)
+}
+
+// RegisterServerFlags binds the API server settings to the flag set.
+func (c *Config) RegisterServerFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.Addr, "addr", c.Addr, "Address to listen on")
+	fs.StringVar(&c.BaseURI, "base-uri", c.BaseURI, "Base URI")
+	fs.DurationVar(&c.ShutdownTimeout, "shutdown-timeout", c.ShutdownTimeout, "Shutdown timeout")
+	fs.DurationVar(&c.DrainDelay, "drain-delay", c.DrainDelay, "Delay between failing readiness and shutting down")
+	fs.DurationVar(&c.ReadyTimeout, "ready-timeout", c.ReadyTimeout, "Readiness checks timeout")
+	fs.StringVar(&c.LogLevel, "log-level", c.LogLevel, "Log level: debug, info, warn or error (reloadable)")
+	fs.BoolVar(&c.Migrate, "migrate", c.Migrate, "Run DB migrations before serving")
+	fs.Var((*listValue)(&c.APIKeys), "api-key", "Comma separated list of API keys (reloadable)")
+	fs.StringVar(&c.TLSCert, "tls-cert", c.TLSCert, "TLS certificate file, enables TLS")
+	fs.StringVar(&c.TLSKey, "tls-key", c.TLSKey, "TLS private key file")
+	fs.StringVar(&c.TLSClientCA, "tls-client-ca", c.TLSClientCA, "CA bundle to verify TLS client certificates against, enables mutual TLS")
+	fs.StringVar(&c.TLSClientAuth, "tls-client-auth", c.TLSClientAuth, "TLS client certificates: optional or require")
+	fs.DurationVar(&c.TLSReloadInterval, "tls-reload-interval", c.TLSReloadInterval, "Interval to check TLS files for changes")
+	fs.Var((*listValue)(&c.CORSAllowedOrigins), "cors-allowed-origins", "Comma separated list of CORS allowed origins (reloadable)")
+	fs.Var((*listValue)(&c.CORSAllowedMethods), "cors-allowed-methods", "Comma separated list of CORS allowed methods (reloadable)")
+	fs.Var((*listValue)(&c.CORSAllowedHeaders), "cors-allowed-headers", "Comma separated list of CORS allowed headers (reloadable)")
+	fs.DurationVar(&c.CORSMaxAge, "cors-max-age", c.CORSMaxAge, "CORS preflight max age (reloadable)")
+	fs.Var((*listValue)(&c.RateLimits), "rate-limits", "Comma separated list of per client rate limits per route group, e.g. default=300/1m,dinosaurs=60/1m (reloadable)")
+	fs.StringVar(&c.RateLimitBackend, "rate-limit-backend", c.RateLimitBackend, "Rate limit backend: memory or postgres")
+	fs.DurationVar(&c.DeletedRetention, "deleted-retention", c.DeletedRetention, "How long soft deleted cages and dinosaurs are kept before they are purged, 0 keeps them forever")
+	fs.Float64Var(&c.InbreedingThreshold, "inbreeding-threshold", c.InbreedingThreshold, "Inbreeding coefficient over which proposed breeding pairs are flagged")
+	fs.BoolVar(&c.Docs, "docs", c.Docs, "Serve the API documentation UI at /docs")
+	fs.BoolVar(&c.ValidateRequests, "validate-requests", c.ValidateRequests, "Reject requests that don't match the OpenAPI spec")
+	fs.BoolVar(&c.ValidateResponses, "validate-responses", c.ValidateResponses, "Log responses that don't match the OpenAPI spec")
+}
+
+// RegisterDBFlags binds the DB settings to the flag set.
+func (c *Config) RegisterDBFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.DBConnString, "db-conn", c.DBConnString, "DB connection string")
+	fs.StringVar(&c.DBMigrations, "db-migrations", c.DBMigrations, "DB migrations path (defaults to the embedded migrations)")
+}
+
+// Validate validates the configuration.
+func (c Config) Validate() error {
+	var errs []error
+
+	if c.Addr == "" {
+		errs = append(errs, errors.New("addr is required"))
+	}
+	if c.BaseURI != "" && (!strings.HasPrefix(c.BaseURI, "/") || strings.HasSuffix(c.BaseURI, "/")) {
+		errs = append(errs, errors.New("base-uri must start and must not end with /"))
+	}
+	if c.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("shutdown-timeout must be positive"))
+	}
+	if c.DrainDelay < 0 {
+		errs = append(errs, errors.New("drain-delay must not be negative"))
+	}
+	if c.DeletedRetention < 0 {
+		errs = append(errs, errors.New("deleted-retention must not be negative"))
+	}
+	if c.InbreedingThreshold < 0 || c.InbreedingThreshold > 1 {
+		errs = append(errs, errors.New("inbreeding-threshold must be between 0 and 1"))
+	}
+	if c.ReadyTimeout <= 0 {
+		errs = append(errs, errors.New("ready-timeout must be positive"))
+	}
+	if _, err := ParseLogLevel(c.LogLevel); err != nil {
+		errs = append(errs, err)
+	}
+	if c.DBConnString == "" {
+		errs = append(errs, errors.New("db-conn is required"))
+	}
+	for _, key := range c.APIKeys {
+		if strings.ContainsAny(key, " \t\r\n") {
+			errs = append(errs, errors.New("api-key must not contain whitespace"))
+			break
+		}
+	}
+	if (c.TLSCert == "") != (c.TLSKey == "") {
+		errs = append(errs, errors.New("tls-cert and tls-key must be set together"))
+	}
+	if c.TLSClientCA != "" && c.TLSCert == "" {
+		errs = append(errs, errors.New("tls-client-ca requires tls-cert and tls-key"))
+	}
+	switch c.TLSClientAuth {
+	case TLSClientAuthOptional, TLSClientAuthRequire:
+	default:
+		errs = append(errs, fmt.Errorf("invalid tls-client-auth %q", c.TLSClientAuth))
+	}
+	if c.TLSReloadInterval <= 0 {
+		errs = append(errs, errors.New("tls-reload-interval must be positive"))
+	}
+	for _, origin := range c.CORSAllowedOrigins {
+		if origin == "*" {
+			continue
+		}
+		if u, err := url.Parse(origin); err != nil || u.Scheme == "" || u.Host == "" {
+			errs = append(errs, fmt.Errorf("invalid cors-allowed-origins value %q", origin))
+		}
+	}
+	if c.CORSMaxAge < 0 {
+		errs = append(errs, errors.New("cors-max-age must not be negative"))
+	}
+	if _, err := ParseRateLimits(c.RateLimits); err != nil {
+		errs = append(errs, err)
+	}
+	switch c.RateLimitBackend {
+	case RateLimitBackendMemory, RateLimitBackendPostgres:
+	default:
+		errs = append(errs, fmt.Errorf("invalid rate-limit-backend %q", c.RateLimitBackend))
+	}
+
+	return errors.Join(errs...)
+}
+
+// ValidateDB validates only the DB settings.
+func (c Config) ValidateDB() error {
+	if c.DBConnString == "" {
+		return errors.New("db-conn is required")
+	}
+
+	return nil
+}
+
+// RateLimit is a number of requests allowed per period.
+type RateLimit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// ParseRateLimits parses rate limits in the form of group=requests/period,
+// e.g. dinosaurs=60/1m. Zero requests turn the rate limiting off for the group.
+func ParseRateLimits(items []string) (map[string]RateLimit, error) {
+	limits := make(map[string]RateLimit, len(items))
+	for _, item := range items {
+		group, spec, ok := strings.Cut(item, "=")
+		if !ok || group == "" {
+			return nil, fmt.Errorf("invalid rate-limits value %q", item)
+		}
+
+		requests, period, ok := strings.Cut(spec, "/")
+		if !ok {
+			return nil, fmt.Errorf("invalid rate-limits value %q", item)
+		}
+
+		var (
+			limit RateLimit
+			err   error
+		)
+		if limit.Requests, err = strconv.Atoi(requests); err != nil || limit.Requests < 0 {
+			return nil, fmt.Errorf("invalid rate-limits requests %q", item)
+		}
+		if limit.Period, err = time.ParseDuration(period); err != nil || limit.Period <= 0 {
+			return nil, fmt.Errorf("invalid rate-limits period %q", item)
+		}
+		if limit.Requests > 0 && limit.Period/time.Duration(limit.Requests) == 0 {
+			return nil, fmt.Errorf("invalid rate-limits value %q: rate is too high", item)
+		}
+		if _, ok := limits[group]; ok {
+			return nil, fmt.Errorf("duplicate rate-limits group %q", group)
+		}
+
+		limits[group] = limit
+	}
+
+	return limits, nil
+}
+
+// ParseLogLevel parses a log level name.
+func ParseLogLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return level, fmt.Errorf("invalid log-level %q", s)
+	}
+
+	return level, nil
+}
+
+// Redact returns a copy of the configuration with secrets redacted.
+func (c Config) Redact() Config {
+	if len(c.APIKeys) > 0 {
+		keys := make([]string, len(c.APIKeys))
+		for i := range keys {
+			keys[i] = Redacted
+		}
+		c.APIKeys = keys
+	}
+	c.DBConnString = redactConnString(c.DBConnString)
+
+	return c
+}
+
+var connStringPasswordRe = regexp.MustCompile(`(password\s*=\s*)('(?:[^'\\]|\\.)*'|\S+)`)
+
+// redactConnString redacts the password in a URL or a keyword/value connection string.
+func redactConnString(s string) string {
+	if u, err := url.Parse(s); err == nil && u.Scheme != "" {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), Redacted)
+		}
+		q := u.Query()
+		if q.Has("password") {
+			q.Set("password", Redacted)
+			u.RawQuery = q.Encode()
+		}
+
+		return u.String()
+	}
+
+	return connStringPasswordRe.ReplaceAllString(s, "${1}"+Redacted)
+}
+
+// listValue is a flag.Value for comma separated lists.
+// Setting a value replaces the whole list.
+type listValue []string
+
+func (v *listValue) String() string {
+	if v == nil {
+		return ""
+	}
+
+	return strings.Join(*v, ",")
+}
+
+func (v *listValue) Set(s string) error {
+	var list []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	*v = list
+
+	return nil
+}
+
+// reloadable is the set of settings that can be changed without a restart.
+var reloadable = map[string]bool{
+	"log-level":            true,
+	"api-key":              true,
+	"cors-allowed-origins": true,
+	"cors-allowed-methods": true,
+	"cors-allowed-headers": true,
+	"cors-max-age":         true,
+	"rate-limits":          true,
+}
+
+// IsReloadable returns true if the setting can be changed without a restart.
+func IsReloadable(name string) bool {
+	return reloadable[name]
+}
+
+// Changed returns the names of the settings that differ between the configurations.
+func Changed(a, b Config) []string {
+	afs := flag.NewFlagSet("", flag.ContinueOnError)
+	a.RegisterFlags(afs)
+	bfs := flag.NewFlagSet("", flag.ContinueOnError)
+	b.RegisterFlags(bfs)
+
+	var changed []string
+	afs.VisitAll(func(f *flag.Flag) {
+		if f.Value.String() != bfs.Lookup(f.Name).Value.String() {
+			changed = append(changed, f.Name)
+		}
+	})
+
+	return changed
+}
@@ -0,0 +1,182 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Loader loads the configuration of a command.
+type Loader struct {
+	// Name is the command name used in the usage message.
+	Name string
+	// Usage optionally replaces the default usage line.
+	Usage string
+	// Args are the command line arguments without the command name.
+	Args []string
+	// Flags registers the command flags binding them to the configuration
+	// or to the command's own variables.
+	Flags func(cfg *Config, fs *flag.FlagSet)
+
+	rest []string
+}
+
+// Load resolves the configuration from defaults, the configuration file,
+// the environment and the flags, in the order of increasing precedence.
+// Load can be called repeatedly to pick up changes, e.g. on SIGHUP.
+func (l *Loader) Load() (Config, error) {
+	cfg := Default()
+	fs := flag.NewFlagSet("jurassic "+l.Name, flag.ContinueOnError)
+	fs.Usage = func() {
+		if l.Usage != "" {
+			fmt.Fprint(fs.Output(), l.Usage)
+		} else {
+			fmt.Fprintf(fs.Output(), "Usage: jurassic %s [flags]\n", l.Name)
+		}
+		fmt.Fprintf(fs.Output(), "\nFlags (can also be set via %s<FLAG> environment variables):\n", EnvPrefix)
+		fs.PrintDefaults()
+	}
+	if l.Flags != nil {
+		l.Flags(&cfg, fs)
+	}
+	var path string
+	fs.StringVar(&path, "config", "", "Configuration file (YAML or TOML)")
+
+	if err := fs.Parse(l.Args); err != nil {
+		return cfg, err
+	}
+	l.rest = fs.Args()
+
+	// Remember explicitly set flags and start over from the defaults
+	// to apply the settings in the order of precedence.
+	explicit := make(map[string]string)
+	fs.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = f.Value.String()
+	})
+	cfg = Default()
+
+	if path == "" {
+		path = os.Getenv(EnvName("config"))
+	}
+	if path != "" {
+		if err := loadFile(fs, path); err != nil {
+			return cfg, err
+		}
+	}
+
+	var err error
+	fs.VisitAll(func(f *flag.Flag) {
+		if err != nil || f.Name == "config" {
+			return
+		}
+
+		if s, ok := os.LookupEnv(EnvName(f.Name)); ok && s != "" {
+			if setErr := fs.Set(f.Name, s); setErr != nil {
+				err = fmt.Errorf("invalid value %q for %s: %w", s, EnvName(f.Name), setErr)
+			}
+		}
+	})
+	if err != nil {
+		return cfg, err
+	}
+
+	for name, value := range explicit {
+		if err := fs.Set(name, value); err != nil {
+			return cfg, err
+		}
+	}
+
+	return cfg, nil
+}
+
+// Rest returns the non-flag arguments remaining after the last Load.
+func (l *Loader) Rest() []string {
+	return l.rest
+}
+
+// EnvName returns the name of the environment variable for a setting.
+func EnvName(name string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// loadFile applies the settings from a YAML or TOML file.
+// Settings that are not used by the command are ignored,
+// unknown settings are rejected.
+func loadFile(fs *flag.FlagSet, path string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	values := make(map[string]any)
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(b, &values)
+	case ".toml":
+		err = toml.Unmarshal(b, &values)
+	default:
+		return fmt.Errorf("unsupported config file format %q", ext)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	known := flag.NewFlagSet("", flag.ContinueOnError)
+	all := Default()
+	all.RegisterFlags(known)
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if known.Lookup(key) == nil {
+			return fmt.Errorf("unknown config file setting %q", key)
+		}
+		if fs.Lookup(key) == nil {
+			continue
+		}
+
+		s, err := fileValue(values[key])
+		if err != nil {
+			return fmt.Errorf("invalid config file setting %q: %w", key, err)
+		}
+
+		if err := fs.Set(key, s); err != nil {
+			return fmt.Errorf("invalid config file setting %q: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// fileValue converts a configuration file value to its flag representation.
+func fileValue(v any) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			s, err := fileValue(item)
+			if err != nil {
+				return "", err
+			}
+			items[i] = s
+		}
+
+		return strings.Join(items, ","), nil
+	case map[string]any:
+		return "", fmt.Errorf("unexpected nested value")
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Print writes the configuration in the configuration file format.
+func Print(w io.Writer, cfg Config, format string) error {
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	cfg.RegisterFlags(fs)
+
+	values := make(map[string]any)
+	fs.VisitAll(func(f *flag.Flag) {
+		switch v := f.Value.(type) {
+		case *listValue:
+			values[f.Name] = append([]string{}, *v...)
+		case interface{ IsBoolFlag() bool }:
+			values[f.Name] = f.Value.String() == "true"
+		default:
+			values[f.Name] = v.String()
+		}
+	})
+
+	switch format {
+	case "yaml":
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(values); err != nil {
+			return err
+		}
+
+		return enc.Close()
+	case "toml":
+		return toml.NewEncoder(w).Encode(values)
+	default:
+		return fmt.Errorf("unsupported format %q", format)
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/pmatseykanets/jurassic/config"
+)
+
+func runConfig(_ *slog.Logger, args []string) error {
+	if len(args) == 0 || args[0] != "print" {
+		fmt.Fprintln(os.Stderr, "Usage: jurassic config print [flags]")
+		return errors.New("config subcommand is required")
+	}
+
+	var format string
+	loader := config.Loader{
+		Name: "config print",
+		Args: args[1:],
+		Flags: func(cfg *config.Config, fs *flag.FlagSet) {
+			cfg.RegisterFlags(fs)
+			fs.StringVar(&format, "format", "yaml", "Output format: yaml or toml")
+		},
+	}
+	cfg, err := loader.Load()
+	if err != nil {
+		return err
+	}
+
+	if err := config.Print(os.Stdout, cfg.Redact(), format); err != nil {
+		return err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("Invalid configuration: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,22 @@
+// Package db provides the DB schema migrations.
+package db
+
+import (
+	"embed"
+
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+// Migrations contains the SQL migrations embedded into the binary.
+//
+//go:embed migrations/*.sql
+var Migrations embed.FS
+
+// MigrationsPath is the path of the migrations within Migrations.
+const MigrationsPath = "migrations"
+
+// Source returns a golang-migrate source driver for the embedded migrations.
+func Source() (source.Driver, error) {
+	return iofs.New(Migrations, MigrationsPath)
+}
@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS rate_limits (
+    key TEXT PRIMARY KEY,
+    full_at TIMESTAMPTZ NOT NULL,
+    allowed BOOLEAN NOT NULL
+);
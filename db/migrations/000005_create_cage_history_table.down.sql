@@ -0,0 +1 @@
+DROP TABLE IF EXISTS cage_history;
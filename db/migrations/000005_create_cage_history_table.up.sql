@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS cage_history (
+    id BIGSERIAL PRIMARY KEY,
+    cage_id UUID NOT NULL,
+    change TEXT NOT NULL,
+    old_value TEXT NOT NULL,
+    new_value TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (cage_id) REFERENCES cages (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS cage_history_cage_id_idx ON cage_history (cage_id, created_at);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS dinosaurs_deleted_at_idx;
+DROP INDEX IF EXISTS cages_deleted_at_idx;
+
+ALTER TABLE dinosaurs DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE cages DROP COLUMN IF EXISTS deleted_at;
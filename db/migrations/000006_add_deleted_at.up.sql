@@ -0,0 +1,5 @@
+ALTER TABLE cages ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE dinosaurs ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS cages_deleted_at_idx ON cages (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS dinosaurs_deleted_at_idx ON dinosaurs (deleted_at) WHERE deleted_at IS NOT NULL;
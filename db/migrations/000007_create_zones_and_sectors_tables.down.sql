@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS cages_sector_id_idx;
+
+ALTER TABLE cages DROP COLUMN IF EXISTS sector_id;
+
+DROP TABLE IF EXISTS sectors;
+DROP TABLE IF EXISTS zones;
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS zones (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v1mc(),
+    name TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS sectors (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v1mc(),
+    zone_id UUID NOT NULL,
+    name TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (zone_id) REFERENCES zones (id),
+    UNIQUE (zone_id, name)
+);
+
+ALTER TABLE cages ADD COLUMN IF NOT EXISTS sector_id UUID REFERENCES sectors (id);
+
+CREATE INDEX IF NOT EXISTS cages_sector_id_idx ON cages (sector_id);
@@ -0,0 +1 @@
+ALTER TABLE cages DROP COLUMN IF EXISTS type;
@@ -0,0 +1,2 @@
+-- Existing cages become general purpose cages that accept any species.
+ALTER TABLE cages ADD COLUMN IF NOT EXISTS type TEXT NOT NULL DEFAULT 'general';
@@ -0,0 +1,3 @@
+-- Existing cages keep counting heads, their capacity is not rescaled.
+-- Cages count space units only if they are explicitly switched to them.
+ALTER TABLE cages ADD COLUMN IF NOT EXISTS capacity_unit TEXT NOT NULL DEFAULT 'heads';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS feedings;
+DROP TABLE IF EXISTS feeding_schedules;
@@ -0,0 +1,33 @@
+-- A schedule is either for a cage or for a species.
+CREATE TABLE IF NOT EXISTS feeding_schedules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v1mc(),
+    cage_id UUID,
+    species TEXT,
+    diet TEXT NOT NULL,
+    quantity DOUBLE PRECISION NOT NULL,
+    interval_hours INTEGER NOT NULL,
+    window_start TIME,
+    window_end TIME,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (cage_id) REFERENCES cages (id) ON DELETE CASCADE,
+    CHECK ((cage_id IS NULL) <> (species IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS feeding_schedules_cage_id_idx ON feeding_schedules (cage_id);
+
+CREATE TABLE IF NOT EXISTS feedings (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v1mc(),
+    cage_id UUID NOT NULL,
+    dinosaur_id UUID,
+    diet TEXT NOT NULL,
+    quantity DOUBLE PRECISION NOT NULL,
+    unsafe BOOLEAN NOT NULL DEFAULT FALSE,
+    fed_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (cage_id) REFERENCES cages (id) ON DELETE CASCADE,
+    FOREIGN KEY (dinosaur_id) REFERENCES dinosaurs (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS feedings_cage_id_idx ON feedings (cage_id, fed_at);
+CREATE INDEX IF NOT EXISTS feedings_dinosaur_id_idx ON feedings (dinosaur_id, fed_at);
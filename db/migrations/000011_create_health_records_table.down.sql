@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS health_records;
+
+DROP INDEX IF EXISTS dinosaurs_health_status_idx;
+
+ALTER TABLE dinosaurs DROP COLUMN IF EXISTS health_status;
@@ -0,0 +1,19 @@
+-- Dinosaurs without health records are healthy.
+ALTER TABLE dinosaurs ADD COLUMN IF NOT EXISTS health_status TEXT NOT NULL DEFAULT 'healthy';
+
+CREATE INDEX IF NOT EXISTS dinosaurs_health_status_idx ON dinosaurs (health_status);
+
+CREATE TABLE IF NOT EXISTS health_records (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v1mc(),
+    dinosaur_id UUID NOT NULL,
+    kind TEXT NOT NULL,
+    author TEXT NOT NULL,
+    weight_kg DOUBLE PRECISION,
+    notes TEXT NOT NULL DEFAULT '',
+    status TEXT,
+    recorded_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (dinosaur_id) REFERENCES dinosaurs (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS health_records_dinosaur_id_idx ON health_records (dinosaur_id, recorded_at);
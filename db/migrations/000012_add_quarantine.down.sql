@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS dinosaurs_quarantined_idx;
+
+ALTER TABLE dinosaurs DROP COLUMN IF EXISTS quarantine_end;
+ALTER TABLE dinosaurs DROP COLUMN IF EXISTS quarantine_start;
+ALTER TABLE dinosaurs DROP COLUMN IF EXISTS quarantine_reason;
+ALTER TABLE dinosaurs DROP COLUMN IF EXISTS quarantined;
+
+ALTER TABLE cages DROP COLUMN IF EXISTS quarantine;
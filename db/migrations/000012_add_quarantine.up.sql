@@ -0,0 +1,9 @@
+-- Existing cages are regular and existing dinosaurs aren't quarantined.
+ALTER TABLE cages ADD COLUMN IF NOT EXISTS quarantine BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE dinosaurs ADD COLUMN IF NOT EXISTS quarantined BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE dinosaurs ADD COLUMN IF NOT EXISTS quarantine_reason TEXT NOT NULL DEFAULT '';
+ALTER TABLE dinosaurs ADD COLUMN IF NOT EXISTS quarantine_start TIMESTAMPTZ;
+ALTER TABLE dinosaurs ADD COLUMN IF NOT EXISTS quarantine_end TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS dinosaurs_quarantined_idx ON dinosaurs (quarantined) WHERE quarantined;
@@ -0,0 +1,19 @@
+-- Eggs, deceased and transferred out dinosaurs don't live in a cage and can't
+-- be kept once cage_id is required again. Refuse to migrate down rather than
+-- lose their records; they have to be dealt with manually first.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM dinosaurs WHERE cage_id IS NULL) THEN
+        RAISE EXCEPTION 'dinosaurs without a cage exist, they have to be removed or placed in a cage before migrating down';
+    END IF;
+END
+$$;
+
+DROP TABLE IF EXISTS lifecycle_events;
+
+DROP INDEX IF EXISTS dinosaurs_stage_idx;
+
+ALTER TABLE dinosaurs DROP COLUMN IF EXISTS hatch_date;
+ALTER TABLE dinosaurs DROP COLUMN IF EXISTS stage;
+
+ALTER TABLE dinosaurs ALTER COLUMN cage_id SET NOT NULL;
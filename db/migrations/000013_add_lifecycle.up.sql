@@ -0,0 +1,22 @@
+-- Eggs, deceased and transferred out dinosaurs don't live in a cage.
+ALTER TABLE dinosaurs ALTER COLUMN cage_id DROP NOT NULL;
+-- Dinosaurs admitted before the lifecycle was tracked are adults.
+ALTER TABLE dinosaurs ADD COLUMN IF NOT EXISTS stage TEXT NOT NULL DEFAULT 'adult';
+ALTER TABLE dinosaurs ADD COLUMN IF NOT EXISTS hatch_date TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS dinosaurs_stage_idx ON dinosaurs (stage);
+
+CREATE TABLE IF NOT EXISTS lifecycle_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v1mc(),
+    dinosaur_id UUID NOT NULL,
+    from_stage TEXT NOT NULL,
+    to_stage TEXT NOT NULL,
+    cage_id UUID,
+    notes TEXT NOT NULL DEFAULT '',
+    occurred_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (dinosaur_id) REFERENCES dinosaurs (id) ON DELETE CASCADE,
+    FOREIGN KEY (cage_id) REFERENCES cages (id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS lifecycle_events_dinosaur_id_idx ON lifecycle_events (dinosaur_id, occurred_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS breeding_pairs;
+DROP TABLE IF EXISTS dinosaur_parents;
@@ -0,0 +1,31 @@
+-- The parents and the breeding pairs are kept for the record, the dinosaurs
+-- they reference as parents, sires or dams can't be purged.
+CREATE TABLE IF NOT EXISTS dinosaur_parents (
+    dinosaur_id UUID NOT NULL,
+    parent_id UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (dinosaur_id, parent_id),
+    FOREIGN KEY (dinosaur_id) REFERENCES dinosaurs (id) ON DELETE CASCADE,
+    FOREIGN KEY (parent_id) REFERENCES dinosaurs (id) ON DELETE RESTRICT,
+    CHECK (dinosaur_id <> parent_id)
+);
+
+-- Descendants are walked down from the parents.
+CREATE INDEX IF NOT EXISTS dinosaur_parents_parent_id_idx ON dinosaur_parents (parent_id);
+
+CREATE TABLE IF NOT EXISTS breeding_pairs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v1mc(),
+    sire_id UUID NOT NULL,
+    dam_id UUID NOT NULL,
+    species TEXT NOT NULL,
+    inbreeding_coefficient DOUBLE PRECISION NOT NULL,
+    flagged BOOLEAN NOT NULL DEFAULT FALSE,
+    notes TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (sire_id) REFERENCES dinosaurs (id) ON DELETE RESTRICT,
+    FOREIGN KEY (dam_id) REFERENCES dinosaurs (id) ON DELETE RESTRICT,
+    CHECK (sire_id <> dam_id)
+);
+
+CREATE INDEX IF NOT EXISTS breeding_pairs_sire_id_idx ON breeding_pairs (sire_id);
+CREATE INDEX IF NOT EXISTS breeding_pairs_dam_id_idx ON breeding_pairs (dam_id);
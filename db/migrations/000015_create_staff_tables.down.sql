@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS staff_assignments;
+DROP TABLE IF EXISTS staff_certifications;
+DROP TABLE IF EXISTS staff;
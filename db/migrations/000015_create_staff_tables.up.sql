@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS staff (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v1mc(),
+    name TEXT NOT NULL,
+    role TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS staff_certifications (
+    staff_id UUID NOT NULL,
+    certification TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (staff_id, certification),
+    FOREIGN KEY (staff_id) REFERENCES staff (id) ON DELETE CASCADE
+);
+
+-- A shift starts at starts_at inclusive and ends at ends_at exclusive.
+CREATE TABLE IF NOT EXISTS staff_assignments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v1mc(),
+    staff_id UUID NOT NULL,
+    cage_id UUID NOT NULL,
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (staff_id) REFERENCES staff (id) ON DELETE CASCADE,
+    FOREIGN KEY (cage_id) REFERENCES cages (id) ON DELETE CASCADE,
+    CHECK (starts_at < ends_at)
+);
+
+CREATE INDEX IF NOT EXISTS staff_assignments_staff_id_idx ON staff_assignments (staff_id, starts_at);
+CREATE INDEX IF NOT EXISTS staff_assignments_cage_id_idx ON staff_assignments (cage_id, starts_at);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS incident_entries;
+DROP TABLE IF EXISTS incident_dinosaurs;
+DROP TABLE IF EXISTS incidents;
@@ -0,0 +1,40 @@
+-- Incidents are kept for the record, the cages and the dinosaurs
+-- they reference can't be purged.
+CREATE TABLE IF NOT EXISTS incidents (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v1mc(),
+    type TEXT NOT NULL,
+    severity TEXT NOT NULL,
+    cage_id UUID NOT NULL,
+    summary TEXT NOT NULL,
+    resolution TEXT NOT NULL DEFAULT '',
+    opened_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (cage_id) REFERENCES cages (id) ON DELETE RESTRICT
+);
+
+CREATE INDEX IF NOT EXISTS incidents_cage_id_idx ON incidents (cage_id, opened_at);
+-- Admissions look up the open incidents of the cage.
+CREATE INDEX IF NOT EXISTS incidents_open_cage_id_idx ON incidents (cage_id) WHERE resolved_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS incident_dinosaurs (
+    incident_id UUID NOT NULL,
+    dinosaur_id UUID NOT NULL,
+    PRIMARY KEY (incident_id, dinosaur_id),
+    FOREIGN KEY (incident_id) REFERENCES incidents (id) ON DELETE CASCADE,
+    FOREIGN KEY (dinosaur_id) REFERENCES dinosaurs (id) ON DELETE RESTRICT
+);
+
+CREATE INDEX IF NOT EXISTS incident_dinosaurs_dinosaur_id_idx ON incident_dinosaurs (dinosaur_id);
+
+CREATE TABLE IF NOT EXISTS incident_entries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v1mc(),
+    incident_id UUID NOT NULL,
+    note TEXT NOT NULL,
+    occurred_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (incident_id) REFERENCES incidents (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS incident_entries_incident_id_idx ON incident_entries (incident_id, occurred_at);
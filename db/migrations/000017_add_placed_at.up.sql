@@ -0,0 +1,5 @@
+-- When a dinosaur was placed in its current cage, only the cage feedings
+-- after that feed it. Existing dinosaurs are assumed to be placed when added.
+ALTER TABLE dinosaurs ADD COLUMN IF NOT EXISTS placed_at TIMESTAMPTZ;
+
+UPDATE dinosaurs SET placed_at = created_at WHERE cage_id IS NOT NULL;
@@ -0,0 +1,56 @@
+//go:build unit
+// +build unit
+
+package db
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"testing"
+)
+
+func TestEmbeddedMigrationsMatchDisk(t *testing.T) {
+	disk := os.DirFS(MigrationsPath)
+
+	diskFiles, err := fs.Glob(disk, "*.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	embeddedFiles, err := fs.Glob(Migrations, MigrationsPath+"/*.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := len(diskFiles), len(embeddedFiles); want != got {
+		t.Fatalf("Expected embedded migrations %d got %d", want, got)
+	}
+
+	for _, name := range diskFiles {
+		want, err := fs.ReadFile(disk, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := fs.ReadFile(Migrations, MigrationsPath+"/"+name)
+		if err != nil {
+			t.Fatalf("Expected embedded migration %s: %s", name, err)
+		}
+
+		if !bytes.Equal(want, got) {
+			t.Errorf("Expected embedded migration %s to match the file on disk", name)
+		}
+	}
+}
+
+func TestSource(t *testing.T) {
+	src, err := Source()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+
+	if _, err := src.First(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/pmatseykanets/jurassic/app"
+	"github.com/pmatseykanets/jurassic/config"
+	"github.com/pmatseykanets/jurassic/store"
+)
+
+// export is the format of the exported data.
+type export struct {
+	Cages     []app.Cage     `json:"cages"`
+	Dinosaurs []app.Dinosaur `json:"dinosaurs"`
+}
+
+func runExport(logger *slog.Logger, args []string) error {
+	var output string
+	loader := config.Loader{
+		Name: "export",
+		Args: args,
+		Flags: func(cfg *config.Config, fs *flag.FlagSet) {
+			cfg.RegisterDBFlags(fs)
+			fs.StringVar(&output, "output", "", "Output file (defaults to stdout)")
+		},
+	}
+	cfg, err := loader.Load()
+	if err != nil {
+		return err
+	}
+	if err := cfg.ValidateDB(); err != nil {
+		return err
+	}
+
+	db, err := openDB(logger, cfg.DBConnString)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+
+	var data export
+	data.Cages, err = (&store.CageStore{DB: db}).List(ctx, app.CageFilter{})
+	if err != nil {
+		return fmt.Errorf("Failed to list cages: %w", err)
+	}
+	data.Dinosaurs, err = (&store.DinosaurStore{DB: db}).List(ctx, app.DinosaurFilter{})
+	if err != nil {
+		return fmt.Errorf("Failed to list dinosaurs: %w", err)
+	}
+	if data.Cages == nil {
+		data.Cages = []app.Cage{}
+	}
+	if data.Dinosaurs == nil {
+		data.Dinosaurs = []app.Dinosaur{}
+	}
+
+	var w io.Writer = os.Stdout
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(data); err != nil {
+		return err
+	}
+
+	logger.Info("Exported data", "cages", len(data.Cages), "dinosaurs", len(data.Dinosaurs))
+
+	return nil
+}
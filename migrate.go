@@ -0,0 +1,230 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+
+	"github.com/pmatseykanets/jurassic/config"
+	"github.com/pmatseykanets/jurassic/db"
+)
+
+const migrateUsage = `Usage: jurassic migrate [flags] <subcommand>
+
+Subcommands:
+  up [N]      Apply all or N up migrations
+  down [N]    Apply N down migrations (1 by default), use -all to revert all
+  goto V      Migrate to version V
+  version     Print the current migration version
+  force V     Set the version V without running migrations and clear the dirty state,
+              -1 resets to no version, e.g. after a failed first migration
+`
+
+func runMigrate(logger *slog.Logger, args []string) error {
+	var all bool
+	loader := config.Loader{
+		Name:  "migrate",
+		Usage: migrateUsage,
+		Args:  args,
+		Flags: func(cfg *config.Config, fs *flag.FlagSet) {
+			cfg.RegisterDBFlags(fs)
+			fs.BoolVar(&all, "all", false, "Revert all migrations with down")
+		},
+	}
+	cfg, err := loader.Load()
+	if err != nil {
+		return err
+	}
+	if err := cfg.ValidateDB(); err != nil {
+		return err
+	}
+
+	args = loader.Rest()
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, migrateUsage)
+		return errors.New("migrate subcommand is required")
+	}
+
+	m, err := newMigrate(cfg.DBMigrations, cfg.DBConnString)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	subcommand, args := args[0], args[1:]
+	switch subcommand {
+	case "up":
+		n, err := optionalIntArg(args)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			err = m.Steps(n)
+		} else {
+			err = m.Up()
+		}
+
+		return migrateResult(logger, err)
+	case "down":
+		n, err := optionalIntArg(args)
+		if err != nil {
+			return err
+		}
+		switch {
+		case all:
+			err = m.Down()
+		case n > 0:
+			err = m.Steps(-n)
+		default:
+			err = m.Steps(-1)
+		}
+
+		return migrateResult(logger, err)
+	case "goto":
+		v, err := requiredIntArg(args, 0)
+		if err != nil {
+			return err
+		}
+
+		return migrateResult(logger, m.Migrate(uint(v)))
+	case "force":
+		v, err := requiredIntArg(args, database.NilVersion)
+		if err != nil {
+			return err
+		}
+
+		return migrateResult(logger, m.Force(v))
+	case "version":
+		v, dirty, err := m.Version()
+		if err != nil {
+			if errors.Is(err, migrate.ErrNilVersion) {
+				fmt.Println("none")
+				return nil
+			}
+
+			return err
+		}
+
+		if dirty {
+			fmt.Printf("%d (dirty)\n", v)
+			return nil
+		}
+
+		fmt.Println(v)
+
+		return nil
+	default:
+		fmt.Fprint(os.Stderr, migrateUsage)
+		return fmt.Errorf("unknown migrate subcommand %q", subcommand)
+	}
+}
+
+// migrateUp applies all up migrations.
+func migrateUp(logger *slog.Logger, path, connString string) error {
+	logger.Info("Running DB migrations")
+	m, err := newMigrate(path, connString)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	return migrateResult(logger, m.Up())
+}
+
+func newMigrate(path, connString string) (*migrate.Migrate, error) {
+	if connString == "" {
+		return nil, errors.New("DB connection string is required")
+	}
+
+	src, err := migrationSource(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read DB migrations: %w", err)
+	}
+
+	m, err := migrate.NewWithSourceInstance("migrations", src, connString)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to initialize DB migrations: %w", err)
+	}
+
+	return m, nil
+}
+
+// migrationSource returns the migrations from the path on disk
+// or the migrations embedded into the binary if the path is empty.
+func migrationSource(path string) (source.Driver, error) {
+	if path == "" {
+		return db.Source()
+	}
+
+	return source.Open("file://" + path)
+}
+
+func migrateResult(logger *slog.Logger, err error) error {
+	switch err {
+	case nil:
+		logger.Info("DB migrations applied")
+	case migrate.ErrNoChange:
+		logger.Info("No DB schema changes")
+	default:
+		return fmt.Errorf("Failed to run DB migrations: %w", err)
+	}
+
+	return nil
+}
+
+func optionalIntArg(args []string) (int, error) {
+	if len(args) == 0 {
+		return 0, nil
+	}
+
+	return requiredIntArg(args, 0)
+}
+
+// requiredIntArg returns the single numeric argument that is at least min.
+func requiredIntArg(args []string, min int) (int, error) {
+	if len(args) != 1 {
+		return 0, errors.New("expected a single numeric argument")
+	}
+
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n < min {
+		return 0, fmt.Errorf("invalid argument %q", args[0])
+	}
+
+	return n, nil
+}
+
+// latestMigrationVersion returns the version of the last migration.
+func latestMigrationVersion(path string) (uint, error) {
+	src, err := migrationSource(path)
+	if err != nil {
+		return 0, err
+	}
+	defer src.Close()
+
+	version, err := src.First()
+	if err != nil {
+		return 0, err
+	}
+
+	for {
+		next, err := src.Next(version)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return version, nil
+			}
+
+			return 0, err
+		}
+		version = next
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+
+	"github.com/pmatseykanets/jurassic/app"
+	"github.com/pmatseykanets/jurassic/config"
+	"github.com/pmatseykanets/jurassic/store"
+)
+
+// seedCage is a cage with its occupants to seed the DB with.
+type seedCage struct {
+	cage      app.Cage
+	dinosaurs []app.Dinosaur
+}
+
+var seedData = []seedCage{
+	{
+		cage: app.Cage{Capacity: 4, Status: app.CageStatusActive},
+		dinosaurs: []app.Dinosaur{
+			{Name: "Rexy", Species: app.DinosaurSpeciesTyrannosaurus},
+			{Name: "Roberta", Species: app.DinosaurSpeciesTyrannosaurus},
+		},
+	},
+	{
+		cage: app.Cage{Capacity: 6, Status: app.CageStatusActive},
+		dinosaurs: []app.Dinosaur{
+			{Name: "Blue", Species: app.DinosaurSpeciesVelociraptor},
+			{Name: "Charlie", Species: app.DinosaurSpeciesVelociraptor},
+			{Name: "Delta", Species: app.DinosaurSpeciesVelociraptor},
+			{Name: "Echo", Species: app.DinosaurSpeciesVelociraptor},
+		},
+	},
+	{
+		cage: app.Cage{Capacity: 10, Status: app.CageStatusActive},
+		dinosaurs: []app.Dinosaur{
+			{Name: "Sarah", Species: app.DinosaurSpeciesTriceratops},
+			{Name: "Bumpy", Species: app.DinosaurSpeciesAnkylosaurus},
+			{Name: "Spike", Species: app.DinosaurSpeciesStegosaurus},
+			{Name: "Brachy", Species: app.DinosaurSpeciesBrachiosaurus},
+		},
+	},
+	{
+		cage: app.Cage{Capacity: 2, Status: app.CageStatusDown},
+	},
+}
+
+func runSeed(logger *slog.Logger, args []string) error {
+	loader := config.Loader{
+		Name: "seed",
+		Args: args,
+		Flags: func(cfg *config.Config, fs *flag.FlagSet) {
+			cfg.RegisterDBFlags(fs)
+		},
+	}
+	cfg, err := loader.Load()
+	if err != nil {
+		return err
+	}
+	if err := cfg.ValidateDB(); err != nil {
+		return err
+	}
+
+	db, err := openDB(logger, cfg.DBConnString)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	cageStore := &store.CageStore{DB: db}
+	dinosaurStore := &store.DinosaurStore{DB: db}
+
+	for _, seed := range seedData {
+		cage, err := cageStore.Add(ctx, &seed.cage)
+		if err != nil {
+			return fmt.Errorf("Failed to add cage: %w", err)
+		}
+
+		for _, dinosaur := range seed.dinosaurs {
+			dinosaur.CageID = cage.ID
+			if _, err := dinosaurStore.Add(ctx, &dinosaur); err != nil {
+				return fmt.Errorf("Failed to add dinosaur %s: %w", dinosaur.Name, err)
+			}
+		}
+
+		logger.Info("Seeded cage", "id", cage.ID, "dinosaurs", len(seed.dinosaurs))
+	}
+
+	return nil
+}
@@ -0,0 +1,365 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/pmatseykanets/jurassic/api"
+	"github.com/pmatseykanets/jurassic/config"
+	"github.com/pmatseykanets/jurassic/store"
+)
+
+func runServe(logger *slog.Logger, args []string) error {
+	loader := &config.Loader{
+		Name: "serve",
+		Args: args,
+		Flags: func(cfg *config.Config, fs *flag.FlagSet) {
+			cfg.RegisterFlags(fs)
+		},
+	}
+	cfg, err := loader.Load()
+	if err != nil {
+		return err
+	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("Invalid configuration: %w", err)
+	}
+
+	return serve(logger, loader, cfg)
+}
+
+// liveSettings are the settings that can be reloaded without a restart.
+type liveSettings struct {
+	apiKeys    *api.APIKeys
+	cors       *api.CORSPolicy
+	rateLimits *api.RateLimitPolicy
+}
+
+func newLiveSettings(cfg config.Config) *liveSettings {
+	live := &liveSettings{
+		apiKeys:    api.NewAPIKeys(),
+		cors:       api.NewCORSPolicy(api.CORSOptions{}),
+		rateLimits: api.NewRateLimitPolicy(nil),
+	}
+	live.apply(cfg)
+
+	return live
+}
+
+func (l *liveSettings) apply(cfg config.Config) {
+	level, _ := config.ParseLogLevel(cfg.LogLevel) // Already validated.
+	logLevel.Set(level)
+	l.apiKeys.Set(cfg.APIKeys...)
+	l.cors.Set(api.CORSOptions{
+		AllowedOrigins: cfg.CORSAllowedOrigins,
+		AllowedMethods: cfg.CORSAllowedMethods,
+		AllowedHeaders: cfg.CORSAllowedHeaders,
+		MaxAge:         cfg.CORSMaxAge,
+	})
+
+	limits, _ := config.ParseRateLimits(cfg.RateLimits) // Already validated.
+	rateLimits := make(map[string]api.RateLimit, len(limits))
+	for group, limit := range limits {
+		rateLimits[group] = api.RateLimit{Requests: limit.Requests, Period: limit.Period}
+	}
+	l.rateLimits.Set(rateLimits)
+}
+
+// reload loads the configuration again and applies the reloadable settings.
+// It returns the configuration in effect after the reload.
+func (l *liveSettings) reload(logger *slog.Logger, loader *config.Loader, current config.Config) config.Config {
+	next, err := loader.Load()
+	if err == nil {
+		err = next.Validate()
+	}
+	if err != nil {
+		logger.Error("Failed to reload configuration", "error", err)
+		return current
+	}
+
+	// Authentication can't be turned on or off without a restart.
+	if (len(current.APIKeys) == 0) != (len(next.APIKeys) == 0) {
+		logger.Warn("Enabling or disabling API key authentication requires a restart")
+		next.APIKeys = current.APIKeys
+	}
+
+	var applied, ignored []string
+	for _, name := range config.Changed(current, next) {
+		if config.IsReloadable(name) {
+			applied = append(applied, name)
+		} else {
+			ignored = append(ignored, name)
+		}
+	}
+	if len(ignored) > 0 {
+		logger.Warn("Configuration changes require a restart", "settings", ignored)
+	}
+
+	// Keep the settings that can't be changed live.
+	effective := current
+	effective.LogLevel = next.LogLevel
+	effective.APIKeys = next.APIKeys
+	effective.CORSAllowedOrigins = next.CORSAllowedOrigins
+	effective.CORSAllowedMethods = next.CORSAllowedMethods
+	effective.CORSAllowedHeaders = next.CORSAllowedHeaders
+	effective.CORSMaxAge = next.CORSMaxAge
+	effective.RateLimits = next.RateLimits
+
+	l.apply(effective)
+	logger.Info("Configuration reloaded", "changed", applied)
+
+	return effective
+}
+
+func serve(logger *slog.Logger, loader *config.Loader, cfg config.Config) error {
+	live := newLiveSettings(cfg)
+
+	if cfg.Migrate {
+		if err := migrateUp(logger, cfg.DBMigrations, cfg.DBConnString); err != nil {
+			return err
+		}
+	}
+
+	expectedVersion, err := latestMigrationVersion(cfg.DBMigrations)
+	if err != nil {
+		return fmt.Errorf("Failed to read DB migrations: %w", err)
+	}
+
+	db, err := openDB(logger, cfg.DBConnString)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var limiter api.RateLimiter
+	switch cfg.RateLimitBackend {
+	case config.RateLimitBackendPostgres:
+		dbLimiter := &store.RateLimiter{DB: db}
+		go purgeRateLimits(ctx, logger, dbLimiter)
+		limiter = dbLimiter
+	default:
+		limiter = api.NewMemoryRateLimiter()
+	}
+	rateLimit := func(group string) func(http.Handler) http.Handler {
+		return api.RateLimitMiddleware(logger, limiter, live.rateLimits, group)
+	}
+
+	middlewares := []func(http.Handler) http.Handler{
+		api.RequestID,
+		middleware.RealIP,
+		api.Logger(logger),
+		middleware.Recoverer,
+		api.CORS(live.cors),
+	}
+
+	cageStore := &store.CageStore{DB: db}
+	dinosaurStore := &store.DinosaurStore{DB: db}
+	if cfg.DeletedRetention > 0 {
+		go purgeDeleted(ctx, logger, cageStore, dinosaurStore, cfg.DeletedRetention)
+	}
+
+	svc := &api.Server{
+		Addr:          cfg.Addr,
+		Logger:        logger,
+		CageStore:     cageStore,
+		DinosaurStore: dinosaurStore,
+		ZoneStore:     &store.ZoneStore{DB: db},
+		FeedingStore:  &store.FeedingStore{DB: db},
+		BreedingStore: &store.BreedingStore{DB: db, InbreedingThreshold: cfg.InbreedingThreshold},
+		StaffStore:    &store.StaffStore{DB: db},
+		IncidentStore: &store.IncidentStore{DB: db},
+		ReadinessChecks: []api.HealthCheck{
+			{Name: "db", Check: db.PingContext},
+			{Name: "migrations", Check: func(ctx context.Context) error {
+				return store.CheckMigrationVersion(ctx, db, expectedVersion)
+			}},
+		},
+		ReadinessTimeout: cfg.ReadyTimeout,
+	}
+
+	rtr := chi.NewRouter()
+	rtr.Use(middlewares...)
+
+	spec, err := api.LoadOpenAPI()
+	if err != nil {
+		return err
+	}
+	if cfg.ValidateResponses {
+		rtr.Use(api.ValidateResponses(spec, cfg.BaseURI, func(r *http.Request, err error) {
+			logger.Warn("Response doesn't match the spec",
+				"requestId", middleware.GetReqID(r.Context()),
+				"method", r.Method,
+				"path", r.URL.Path,
+				"error", err,
+			)
+		}))
+	}
+
+	// Health endpoints are not authenticated so that the orchestrator can probe them.
+	rtr.Get("/healthz", svc.Liveness())
+	rtr.Get("/readyz", svc.Readiness())
+	// So is the API specification.
+	rtr.Get("/openapi.yaml", svc.ServeSpec())
+	if cfg.Docs {
+		rtr.Get("/docs", svc.ServeDocs("/openapi.yaml"))
+	}
+
+	rtr.Group(func(rtr chi.Router) {
+		clientCerts := cfg.TLSClientCA != ""
+		if len(cfg.APIKeys) > 0 || clientCerts {
+			logger.Info("Using authentication", "apiKeys", len(cfg.APIKeys) > 0, "clientCerts", clientCerts)
+			rtr.Use(api.Authenticate(live.apiKeys, clientCerts))
+		}
+		if cfg.ValidateRequests {
+			rtr.Use(api.ValidateRequests(spec, cfg.BaseURI))
+		}
+
+		svc.Routes(rtr, cfg.BaseURI, rateLimit)
+	})
+
+	// Configure HTTP server.
+	// Timeouts can/should be individually fine tuned.
+	// Here we'll use the same value.
+	httpTimeout := 30 * time.Second
+	srv := &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           rtr,
+		IdleTimeout:       httpTimeout,
+		ReadHeaderTimeout: httpTimeout,
+		ReadTimeout:       httpTimeout,
+		WriteTimeout:      httpTimeout,
+	}
+
+	if cfg.TLSCert != "" {
+		clientAuth := tls.VerifyClientCertIfGiven
+		if cfg.TLSClientAuth == config.TLSClientAuthRequire {
+			clientAuth = tls.RequireAndVerifyClientCert
+		}
+
+		reloader, err := api.NewTLSReloader(logger, cfg.TLSCert, cfg.TLSKey, cfg.TLSClientCA, clientAuth)
+		if err != nil {
+			return err
+		}
+		srv.TLSConfig = reloader.TLSConfig()
+		go reloader.Watch(ctx, cfg.TLSReloadInterval)
+	}
+
+	// Reload the configuration on SIGHUP.
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	defer signal.Stop(sighup)
+	go func() {
+		current := cfg
+		for range sighup {
+			logger.Info("Reloading configuration")
+			current = live.reload(logger, loader, current)
+		}
+	}()
+
+	idleConnsClosed := make(chan struct{})
+	go func() {
+		sigint := make(chan os.Signal, 1)
+		signal.Notify(sigint, os.Interrupt, syscall.SIGTERM)
+		<-sigint
+
+		// Received an interrupt signal, fail readiness first
+		// to let the load balancer stop sending requests.
+		logger.Info("Draining service", "delay", cfg.DrainDelay)
+		svc.Drain()
+		time.Sleep(cfg.DrainDelay)
+
+		logger.Info("Shutting down service")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+		defer cancel()
+
+		// Drain and close http connections.
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			logger.Error("Shutdown error", "error", err)
+		}
+		close(idleConnsClosed)
+	}()
+
+	logger.Info("Starting service", "version", version, "baseURI", cfg.BaseURI, "addr", cfg.Addr, "tls", srv.TLSConfig != nil)
+	// ListenAndServe[TLS] always return a non-nil error.
+	if srv.TLSConfig != nil {
+		err = srv.ListenAndServeTLS("", "")
+	} else {
+		err = srv.ListenAndServe()
+	}
+	if err != http.ErrServerClosed {
+		// Don't wait, just return with the error.
+		return err
+	}
+
+	// Wait until we shut down the server.
+	<-idleConnsClosed
+	logger.Info("Service stopped")
+
+	return nil
+}
+
+// purgeRateLimits periodically removes the rate limit buckets that are full again.
+func purgeRateLimits(ctx context.Context, logger *slog.Logger, limiter *store.RateLimiter) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		n, err := limiter.Purge(ctx)
+		if err != nil {
+			logger.Error("Failed to purge rate limits", "error", err)
+			continue
+		}
+		logger.Debug("Purged rate limits", "count", n)
+	}
+}
+
+// purgeDeleted periodically removes the cages and dinosaurs
+// that were soft deleted longer than the retention period ago.
+func purgeDeleted(ctx context.Context, logger *slog.Logger, cages *store.CageStore, dinosaurs *store.DinosaurStore, retention time.Duration) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		before := time.Now().Add(-retention)
+
+		n, err := dinosaurs.Purge(ctx, before)
+		if err != nil {
+			logger.Error("Failed to purge deleted dinosaurs", "error", err)
+			continue
+		}
+		logger.Debug("Purged deleted dinosaurs", "count", n)
+
+		n, err = cages.Purge(ctx, before)
+		if err != nil {
+			logger.Error("Failed to purge deleted cages", "error", err)
+			continue
+		}
+		logger.Debug("Purged deleted cages", "count", n)
+	}
+}
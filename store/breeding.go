@@ -0,0 +1,344 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+// BreedingStore is a DB implementation of api.BreedingStore.
+type BreedingStore struct {
+	DB *sql.DB
+	// InbreedingThreshold is the inbreeding coefficient over which breeding pairs are flagged.
+	InbreedingThreshold float64
+}
+
+// SetParents replaces the parents of a dinosaur and returns its lineage one generation deep.
+// Parents are of the same species and none of them is a descendant of the dinosaur.
+// app.ErrNotFound is returned if the dinosaur or a parent doesn't exist,
+// app.ErrSpeciesMismatch if a parent is of a different species
+// and app.ErrLineageCycle if a parent is a descendant of the dinosaur.
+func (s *BreedingStore) SetParents(ctx context.Context, id string, parentIDs []string) (*app.Lineage, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() // nolint:errcheck
+
+	// Serialize the changes of the parent links, otherwise concurrent
+	// changes could link two dinosaurs as the ancestors of each other.
+	if _, err = tx.ExecContext(ctx, "LOCK TABLE dinosaur_parents IN SHARE ROW EXCLUSIVE MODE"); err != nil {
+		return nil, err
+	}
+
+	fields := []string{"id", "species"}
+	dinosaur, err := getDinosaur(ctx, tx, id, app.GetOptions{Fields: fields})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, parentID := range parentIDs {
+		parent, err := getDinosaur(ctx, tx, parentID, app.GetOptions{Fields: fields})
+		if err != nil {
+			return nil, err
+		}
+		if parent.Species != dinosaur.Species {
+			return nil, app.ErrSpeciesMismatch
+		}
+	}
+
+	for _, parentID := range parentIDs {
+		cycle, err := isDescendant(ctx, tx, parentID, id)
+		if err != nil {
+			return nil, err
+		}
+		if cycle {
+			return nil, app.ErrLineageCycle
+		}
+	}
+
+	if _, err = tx.ExecContext(ctx, "DELETE FROM dinosaur_parents WHERE dinosaur_id = $1", id); err != nil {
+		return nil, err
+	}
+	for _, parentID := range parentIDs {
+		_, err = tx.ExecContext(ctx, "INSERT INTO dinosaur_parents (dinosaur_id, parent_id) VALUES ($1, $2)", id, parentID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	lineage, err := getLineage(ctx, tx, id, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return lineage, nil
+}
+
+// Lineage returns the ancestors and the descendants of a dinosaur up to the number of generations.
+// Deleted relatives are left out, but the lineage is walked through them.
+// app.ErrNotFound is returned if the dinosaur doesn't exist.
+func (s *BreedingStore) Lineage(ctx context.Context, id string, generations int) (*app.Lineage, error) {
+	if _, err := getDinosaur(ctx, s.DB, id, app.GetOptions{Fields: []string{"id"}}); err != nil {
+		return nil, err
+	}
+
+	return getLineage(ctx, s.DB, id, generations)
+}
+
+// isDescendant returns true if the dinosaur is found down the lineage of the ancestor.
+func isDescendant(ctx context.Context, q queryable, id, ancestorID string) (bool, error) {
+	query := `
+	WITH RECURSIVE descendants (id) AS (
+		SELECT dinosaur_id FROM dinosaur_parents WHERE parent_id = $1
+		UNION
+		SELECT p.dinosaur_id
+		  FROM descendants d
+		  JOIN dinosaur_parents p ON p.parent_id = d.id
+	)
+	SELECT EXISTS (SELECT 1 FROM descendants WHERE id = $2)`
+
+	var found bool
+	if err := q.QueryRowContext(ctx, query, ancestorID, id).Scan(&found); err != nil {
+		return false, err
+	}
+
+	return found, nil
+}
+
+func getLineage(ctx context.Context, q queryable, id string, generations int) (*app.Lineage, error) {
+	lineage := app.Lineage{DinosaurID: id}
+
+	var parentIDs string
+	if err := q.QueryRowContext(ctx, "SELECT "+parentIDsColumn("$1"), id).Scan(&parentIDs); err != nil {
+		return nil, err
+	}
+	lineage.ParentIDs = splitIDs(parentIDs)
+
+	var err error
+	if lineage.Ancestors, err = relatives(ctx, q, id, generations, true); err != nil {
+		return nil, err
+	}
+	if lineage.Descendants, err = relatives(ctx, q, id, generations, false); err != nil {
+		return nil, err
+	}
+
+	return &lineage, nil
+}
+
+// relatives walks the parent links of a dinosaur up to its ancestors or down to its descendants.
+func relatives(ctx context.Context, q queryable, id string, generations int, ancestors bool) ([]app.Relative, error) {
+	from, to := "parent_id", "dinosaur_id"
+	if ancestors {
+		from, to = "dinosaur_id", "parent_id"
+	}
+
+	query := `
+	WITH RECURSIVE relatives (id, generation) AS (
+		SELECT ` + to + `, 1 FROM dinosaur_parents WHERE ` + from + ` = $1
+		UNION
+		SELECT p.` + to + `, r.generation + 1
+		  FROM relatives r
+		  JOIN dinosaur_parents p ON p.` + from + ` = r.id
+		 WHERE r.generation < $2
+	)
+	SELECT d.id, d.name, d.species, d.stage, ` + parentIDsColumn("d.id") + `, r.generation
+	  FROM (SELECT id, MIN(generation) AS generation FROM relatives GROUP BY id) r
+	  JOIN dinosaurs d ON d.id = r.id
+	 WHERE d.deleted_at IS NULL
+	 ORDER BY r.generation, d.name, d.id`
+
+	rows, err := q.QueryContext(ctx, query, id, generations)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []app.Relative{}
+	for rows.Next() {
+		var (
+			relative  app.Relative
+			parentIDs string
+		)
+		err := rows.Scan(
+			&relative.ID,
+			&relative.Name,
+			&relative.Species,
+			&relative.Stage,
+			&parentIDs,
+			&relative.Generation,
+		)
+		if err != nil {
+			return nil, err
+		}
+		relative.ParentIDs = splitIDs(parentIDs)
+
+		list = append(list, relative)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// parentIDsColumn returns the comma separated parent IDs of the dinosaur.
+func parentIDsColumn(id string) string {
+	return `COALESCE((SELECT string_agg(parent_id::text, ',' ORDER BY parent_id)
+	  FROM dinosaur_parents WHERE dinosaur_id = ` + id + `), '')`
+}
+
+func splitIDs(ids string) []string {
+	if ids == "" {
+		return []string{}
+	}
+
+	return strings.Split(ids, ",")
+}
+
+// breedingPairColumns are the selected columns of a breeding pair.
+const breedingPairColumns = `
+	id, sire_id, dam_id, species, inbreeding_coefficient, flagged, notes, created_at`
+
+func breedingPairFieldPointers(pair *app.BreedingPair) []any {
+	return []any{
+		&pair.ID,
+		&pair.SireID,
+		&pair.DamID,
+		&pair.Species,
+		&pair.InbreedingCoefficient,
+		&pair.Flagged,
+		&pair.Notes,
+		&pair.CreatedAt,
+	}
+}
+
+// AddPair records a proposed breeding pair of adults of the same species.
+// The pair is recorded along with the inbreeding coefficient of its offspring,
+// computed over app.MaxLineageGenerations of ancestors, but flagged
+// if the coefficient goes over the inbreeding threshold.
+// app.ErrNotFound is returned if the sire or the dam doesn't exist,
+// app.ErrNotAdult if either of them isn't an adult
+// and app.ErrSpeciesMismatch if they are of different species.
+func (s *BreedingStore) AddPair(ctx context.Context, pair *app.BreedingPair) (*app.BreedingPair, error) {
+	fields := []string{"id", "species", "stage"}
+	sire, err := getDinosaur(ctx, s.DB, pair.SireID, app.GetOptions{Fields: fields})
+	if err != nil {
+		return nil, err
+	}
+	dam, err := getDinosaur(ctx, s.DB, pair.DamID, app.GetOptions{Fields: fields})
+	if err != nil {
+		return nil, err
+	}
+
+	if sire.Stage != app.LifecycleStageAdult || dam.Stage != app.LifecycleStageAdult {
+		return nil, app.ErrNotAdult
+	}
+	if sire.Species != dam.Species {
+		return nil, app.ErrSpeciesMismatch
+	}
+
+	pedigree, err := getPedigree(ctx, s.DB, sire.ID, dam.ID, app.MaxLineageGenerations)
+	if err != nil {
+		return nil, err
+	}
+	coefficient := pedigree.Kinship(sire.ID, dam.ID)
+
+	var added app.BreedingPair
+	query := `
+	INSERT INTO breeding_pairs (sire_id, dam_id, species, inbreeding_coefficient, flagged, notes)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	RETURNING` + breedingPairColumns
+	err = s.DB.QueryRowContext(ctx, query,
+		sire.ID,
+		dam.ID,
+		sire.Species,
+		coefficient,
+		coefficient > s.InbreedingThreshold,
+		pair.Notes,
+	).Scan(breedingPairFieldPointers(&added)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &added, nil
+}
+
+// getPedigree returns the parent links of a pair and of their ancestors up to the number of generations.
+func getPedigree(ctx context.Context, q queryable, firstID, secondID string, generations int) (app.Pedigree, error) {
+	query := `
+	WITH RECURSIVE pedigree (dinosaur_id, parent_id, generation) AS (
+		SELECT dinosaur_id, parent_id, 1 FROM dinosaur_parents WHERE dinosaur_id IN ($1, $2)
+		UNION
+		SELECT p.dinosaur_id, p.parent_id, pd.generation + 1
+		  FROM pedigree pd
+		  JOIN dinosaur_parents p ON p.dinosaur_id = pd.parent_id
+		 WHERE pd.generation < $3
+	)
+	SELECT DISTINCT dinosaur_id, parent_id FROM pedigree ORDER BY dinosaur_id, parent_id`
+
+	rows, err := q.QueryContext(ctx, query, firstID, secondID, generations)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pedigree := make(app.Pedigree)
+	for rows.Next() {
+		var id, parentID string
+		if err := rows.Scan(&id, &parentID); err != nil {
+			return nil, err
+		}
+
+		pedigree[id] = append(pedigree[id], parentID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return pedigree, nil
+}
+
+// ListPairs lists breeding pairs, the most recent first.
+func (s *BreedingStore) ListPairs(ctx context.Context, filter app.BreedingPairFilter) ([]app.BreedingPair, error) {
+	var (
+		where []string
+		args  []any
+	)
+	if filter.DinosaurID != "" {
+		where = append(where, "(sire_id = ? OR dam_id = ?)")
+		args = append(args, filter.DinosaurID, filter.DinosaurID)
+	}
+	if filter.FlaggedOnly {
+		where = append(where, "flagged")
+	}
+
+	query := "SELECT" + breedingPairColumns + " FROM breeding_pairs" + whereClause(where) + " ORDER BY created_at DESC, id"
+
+	rows, err := s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pairs []app.BreedingPair
+	for rows.Next() {
+		var pair app.BreedingPair
+		if err := rows.Scan(breedingPairFieldPointers(&pair)...); err != nil {
+			return nil, err
+		}
+
+		pairs = append(pairs, pair)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return pairs, nil
+}
@@ -0,0 +1,214 @@
+//go:build integration
+// +build integration
+
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+func TestBreedingStore(t *testing.T) {
+	setUpTestDB(t)
+	t.Cleanup(func() {
+		testDB.Exec("TRUNCATE TABLE cages CASCADE")
+	})
+
+	ctx := context.Background()
+	cageStore := CageStore{DB: testDB}
+	dinosaurStore := DinosaurStore{DB: testDB}
+	breedingStore := BreedingStore{DB: testDB, InbreedingThreshold: app.DefaultInbreedingThreshold}
+
+	cage, err := cageStore.Add(ctx, &app.Cage{Capacity: 10, Status: app.CageStatusActive})
+	if err != nil {
+		t.Fatal(err)
+	}
+	add := func(name string) *app.Dinosaur {
+		t.Helper()
+		dinosaur, err := dinosaurStore.Add(ctx, &app.Dinosaur{Name: name, Species: app.DinosaurSpeciesTriceratops, CageID: cage.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return dinosaur
+	}
+	// Full siblings Cera and Tria of Bess and Bull, Cody is the child of Cera.
+	bess, bull, cera, tria, cody := add("Bess"), add("Bull"), add("Cera"), add("Tria"), add("Cody")
+
+	lineage, err := breedingStore.SetParents(ctx, cera.ID, []string{bess.ID, bull.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 2, len(lineage.ParentIDs); want != got {
+		t.Fatalf("Expected ParentIDs %d got %d", want, got)
+	}
+	if want, got := 2, len(lineage.Ancestors); want != got {
+		t.Fatalf("Expected Ancestors %d got %d", want, got)
+	}
+	if _, err := breedingStore.SetParents(ctx, tria.ID, []string{bess.ID, bull.ID}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := breedingStore.SetParents(ctx, cody.ID, []string{cera.ID}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A dinosaur can't become an ancestor of its ancestor.
+	if _, err := breedingStore.SetParents(ctx, bess.ID, []string{cody.ID}); err != app.ErrLineageCycle {
+		t.Fatalf("Expected error %v got %v", app.ErrLineageCycle, err)
+	}
+	if _, err := breedingStore.SetParents(ctx, bess.ID, []string{uuid.NewString()}); err != app.ErrNotFound {
+		t.Fatalf("Expected error %v got %v", app.ErrNotFound, err)
+	}
+
+	lineage, err = breedingStore.Lineage(ctx, bess.ID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 0, len(lineage.Ancestors); want != got {
+		t.Fatalf("Expected Ancestors %d got %d", want, got)
+	}
+	if want, got := 2, len(lineage.Descendants); want != got {
+		t.Fatalf("Expected Descendants %d got %d", want, got)
+	}
+	lineage, err = breedingStore.Lineage(ctx, bess.ID, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 3, len(lineage.Descendants); want != got {
+		t.Fatalf("Expected Descendants %d got %d", want, got)
+	}
+	if want, got := cody.ID, lineage.Descendants[2].ID; want != got {
+		t.Fatalf("Expected Descendant %s got %s", want, got)
+	}
+	if want, got := 2, lineage.Descendants[2].Generation; want != got {
+		t.Fatalf("Expected Generation %d got %d", want, got)
+	}
+	if _, err := breedingStore.Lineage(ctx, uuid.NewString(), 1); err != app.ErrNotFound {
+		t.Fatalf("Expected error %v got %v", app.ErrNotFound, err)
+	}
+
+	siblings, err := breedingStore.AddPair(ctx, &app.BreedingPair{SireID: tria.ID, DamID: cera.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 0.25, siblings.InbreedingCoefficient; want != got {
+		t.Fatalf("Expected InbreedingCoefficient %v got %v", want, got)
+	}
+	if !siblings.Flagged {
+		t.Fatal("Expected the pair to be flagged")
+	}
+	founders, err := breedingStore.AddPair(ctx, &app.BreedingPair{SireID: bull.ID, DamID: bess.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if founders.Flagged {
+		t.Fatal("Expected the pair not to be flagged")
+	}
+
+	egg, err := dinosaurStore.AddEgg(ctx, app.Egg{Name: "Tiny", Species: app.DinosaurSpeciesTriceratops, HatchDate: founders.CreatedAt})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := breedingStore.AddPair(ctx, &app.BreedingPair{SireID: bull.ID, DamID: egg.ID}); err != app.ErrNotAdult {
+		t.Fatalf("Expected error %v got %v", app.ErrNotAdult, err)
+	}
+
+	pairs, err := breedingStore.ListPairs(ctx, app.BreedingPairFilter{FlaggedOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 1, len(pairs); want != got {
+		t.Fatalf("Expected pairs %d got %d", want, got)
+	}
+	pairs, err = breedingStore.ListPairs(ctx, app.BreedingPairFilter{DinosaurID: bess.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := founders.ID, pairs[0].ID; want != got {
+		t.Fatalf("Expected pair %s got %s", want, got)
+	}
+}
+
+func TestBreedingStoreKeepsLineage(t *testing.T) {
+	setUpTestDB(t)
+	t.Cleanup(func() {
+		testDB.Exec("TRUNCATE TABLE cages CASCADE")
+	})
+
+	ctx := context.Background()
+	cageStore := CageStore{DB: testDB}
+	dinosaurStore := DinosaurStore{DB: testDB}
+	breedingStore := BreedingStore{DB: testDB, InbreedingThreshold: app.DefaultInbreedingThreshold}
+
+	cage, err := cageStore.Add(ctx, &app.Cage{Capacity: 10, Status: app.CageStatusActive})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parentCage, err := cageStore.Add(ctx, &app.Cage{Capacity: 10, Status: app.CageStatusActive})
+	if err != nil {
+		t.Fatal(err)
+	}
+	add := func(name, cageID string) *app.Dinosaur {
+		t.Helper()
+		dinosaur, err := dinosaurStore.Add(ctx, &app.Dinosaur{Name: name, Species: app.DinosaurSpeciesTriceratops, CageID: cageID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return dinosaur
+	}
+	bess, bull, cera, stray := add("Bess", parentCage.ID), add("Bull", parentCage.ID), add("Cera", cage.ID), add("Stray", cage.ID)
+
+	if _, err := breedingStore.SetParents(ctx, cera.ID, []string{bess.ID}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := breedingStore.AddPair(ctx, &app.BreedingPair{SireID: bull.ID, DamID: cera.ID}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The parent and the sire are deleted along with their cage, the stray is not on record.
+	for _, dinosaur := range []*app.Dinosaur{bess, bull, stray} {
+		if err := dinosaurStore.Delete(ctx, dinosaur.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := cageStore.Delete(ctx, parentCage.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := cageStore.Purge(ctx, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := int64(0), n; want != got {
+		t.Fatalf("Expected purged cages %d got %d", want, got)
+	}
+	n, err = dinosaurStore.Purge(ctx, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := int64(1), n; want != got {
+		t.Fatalf("Expected purged dinosaurs %d got %d", want, got)
+	}
+
+	lineage, err := breedingStore.Lineage(ctx, cera.ID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 1, len(lineage.ParentIDs); want != got {
+		t.Fatalf("Expected ParentIDs %d got %d", want, got)
+	}
+	if want, got := bess.ID, lineage.ParentIDs[0]; want != got {
+		t.Fatalf("Expected parent %s got %s", want, got)
+	}
+	pairs, err := breedingStore.ListPairs(ctx, app.BreedingPairFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 1, len(pairs); want != got {
+		t.Fatalf("Expected pairs %d got %d", want, got)
+	}
+}
@@ -3,6 +3,9 @@ package store
 import (
 	"context"
 	"database/sql"
+	"slices"
+	"strconv"
+	"time"
 
 	"github.com/pmatseykanets/jurassic/app"
 )
@@ -13,15 +16,36 @@ type CageStore struct {
 }
 
 // Add a new cage.
+// Cages are general purpose and count heads unless the type and the capacity unit are set.
+// Cages are regular unless designated as quarantine ones.
 func (s *CageStore) Add(ctx context.Context, cage *app.Cage) (*app.Cage, error) {
+	if cage.SectorID != "" {
+		if err := checkSector(ctx, s.DB, cage.SectorID); err != nil {
+			return nil, err
+		}
+	}
+
+	cageType := cage.Type
+	if cageType == app.CageTypeUnspecified {
+		cageType = app.CageTypeGeneral
+	}
+	unit := cage.CapacityUnit
+	if unit == app.CapacityUnitUnspecified {
+		unit = app.CapacityUnitHeads
+	}
+
 	var c app.Cage
 	query := `
-	INSERT INTO cages (capacity, status) VALUES ($1, $2) 
-	RETURNING id, capacity, status, created_at, updated_at`
-	err := s.DB.QueryRowContext(ctx, query, cage.Capacity, cage.Status).Scan(
+	INSERT INTO cages (capacity, status, sector_id, type, capacity_unit, quarantine) VALUES ($1, $2, NULLIF($3, '')::uuid, $4, $5, $6) 
+	RETURNING id, type, quarantine, capacity, capacity_unit, status, COALESCE(sector_id::text, ''), created_at, updated_at`
+	err := s.DB.QueryRowContext(ctx, query, cage.Capacity, cage.Status, cage.SectorID, cageType, unit, cage.Quarantine).Scan(
 		&c.ID,
+		&c.Type,
+		&c.Quarantine,
 		&c.Capacity,
+		&c.CapacityUnit,
 		&c.Status,
+		&c.SectorID,
 		&c.CreatedAt,
 		&c.UpdatedAt,
 	)
@@ -33,25 +57,179 @@ func (s *CageStore) Add(ctx context.Context, cage *app.Cage) (*app.Cage, error)
 }
 
 // Get a cage by id.
-func (s *CageStore) Get(ctx context.Context, id string) (*app.Cage, error) {
-	return getCage(ctx, s.DB, id)
+func (s *CageStore) Get(ctx context.Context, id string, opts app.GetOptions) (*app.Cage, error) {
+	return getCage(ctx, s.DB, id, opts)
+}
+
+// cageColumns maps app.CageFields to the SQL expressions of the cage queries.
+var cageColumns = map[string]string{
+	"id":           "c.id",
+	"type":         "c.type",
+	"quarantine":   "c.quarantine",
+	"status":       "c.status",
+	"capacity":     "c.capacity",
+	"capacityUnit": "c.capacity_unit",
+	"occupancy":    cageOccupancy,
+	"headCount":    "COALESCE(o.n, 0)",
+	"spaceUsed":    "COALESCE(o.w, 0)",
+	"sectorId":     "COALESCE(c.sector_id::text, '')",
+	"createdAt":    "c.created_at",
+	"updatedAt":    "c.updated_at",
+	"deletedAt":    "c.deleted_at",
+}
+
+// cageOccupancyFields are the cage fields that need cageOccupancyJoin.
+var cageOccupancyFields = []string{"occupancy", "headCount", "spaceUsed"}
+
+// cageOccupancy is the occupancy of a cage in its capacity unit.
+const cageOccupancy = "CASE c.capacity_unit WHEN 'space' THEN COALESCE(o.w, 0) ELSE COALESCE(o.n, 0) END"
+
+// cageOccupancyJoin joins the number of the cage occupants as o.n
+// and the space they take as o.w.
+var cageOccupancyJoin = `
+	  LEFT JOIN (
+		SELECT cage_id, COUNT(*) AS n, SUM(` + speciesWeight + `) AS w
+		  FROM dinosaurs
+		 WHERE deleted_at IS NULL
+		 GROUP BY cage_id
+	  ) o ON o.cage_id = c.id`
+
+// speciesWeight is the SQL expression of the space weight of a dinosaur
+// in a query where the dinosaurs species column is unambiguous.
+// It's built from app.DinosaurSpecies.Weight so the weights are kept in one place.
+var speciesWeight = func() string {
+	expr := "CASE species"
+	for _, species := range app.AllDinosaurSpecies {
+		expr += " WHEN '" + string(species) + "' THEN " + strconv.Itoa(species.Weight())
+	}
+
+	return expr + " END"
+}()
+
+// needsOccupancy returns true if any of the fields is counted from the occupants.
+func needsOccupancy(fields ...string) bool {
+	return slices.ContainsFunc(fields, func(field string) bool {
+		return slices.Contains(cageOccupancyFields, field)
+	})
+}
+
+// cageFieldPointers returns the scan destinations of the cage fields.
+func cageFieldPointers(cage *app.Cage, fields []string) []any {
+	dest := make([]any, len(fields))
+	for i, field := range fields {
+		switch field {
+		case "id":
+			dest[i] = &cage.ID
+		case "type":
+			dest[i] = &cage.Type
+		case "quarantine":
+			dest[i] = &cage.Quarantine
+		case "status":
+			dest[i] = &cage.Status
+		case "capacity":
+			dest[i] = &cage.Capacity
+		case "capacityUnit":
+			dest[i] = &cage.CapacityUnit
+		case "occupancy":
+			dest[i] = &cage.Occupancy
+		case "headCount":
+			dest[i] = &cage.HeadCount
+		case "spaceUsed":
+			dest[i] = &cage.SpaceUsed
+		case "sectorId":
+			dest[i] = &cage.SectorID
+		case "createdAt":
+			dest[i] = &cage.CreatedAt
+		case "updatedAt":
+			dest[i] = &cage.UpdatedAt
+		case "deletedAt":
+			dest[i] = &cage.DeletedAt
+		}
+	}
+
+	return dest
 }
 
 // List cages.
-func (s *CageStore) List(ctx context.Context, status app.CageStatus) ([]app.Cage, error) {
+// Cages are sorted by creation time unless the filter sets the sort order.
+// Occupancy is counted only if it's selected, filtered or sorted by.
+func (s *CageStore) List(ctx context.Context, filter app.CageFilter) ([]app.Cage, error) {
 	var cages []app.Cage
-	query := `
-	SELECT c.id, c.capacity, c.status, c.created_at, c.updated_at, COUNT(d.id)
-	  FROM cages c 
-	  LEFT JOIN dinosaurs d ON d.cage_id = c.id`
 
-	var args []any
-	if !status.IsUnspecified() {
-		query += " WHERE status = $1"
-		args = append(args, status)
+	fields := filter.Fields
+	if len(fields) == 0 {
+		fields = app.CageFields
+	}
+	columns, err := selectList(fields, cageColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	query := "SELECT " + columns + " FROM cages c"
+	if needsOccupancy(fields...) ||
+		filter.MinFreeCapacity > 0 ||
+		filter.Occupancy != app.CageOccupancyUnspecified ||
+		slices.ContainsFunc(filter.Sort, func(s app.Sort) bool { return needsOccupancy(s.Field) }) {
+		query += cageOccupancyJoin
+	}
+
+	var (
+		where []string
+		args  []any
+	)
+	if len(filter.IDs) > 0 {
+		where = append(where, "c.id IN ("+placeholders(len(filter.IDs))+")")
+		for _, id := range filter.IDs {
+			args = append(args, id)
+		}
+	}
+	if filter.Type != app.CageTypeUnspecified {
+		where = append(where, "c.type = ?")
+		args = append(args, filter.Type)
+	}
+	if !filter.Status.IsUnspecified() {
+		where = append(where, "c.status = ?")
+		args = append(args, filter.Status)
+	}
+	if filter.Quarantine != nil {
+		where = append(where, "c.quarantine = ?")
+		args = append(args, *filter.Quarantine)
+	}
+	if filter.ZoneID != "" {
+		where = append(where, "c.sector_id IN (SELECT id FROM sectors WHERE zone_id = ?)")
+		args = append(args, filter.ZoneID)
+	}
+	if filter.SectorID != "" {
+		where = append(where, "c.sector_id = ?")
+		args = append(args, filter.SectorID)
+	}
+	if filter.MinFreeCapacity > 0 {
+		where = append(where, "c.capacity - "+cageOccupancy+" >= ?")
+		args = append(args, filter.MinFreeCapacity)
+	}
+	switch filter.Occupancy {
+	case app.CageOccupancyEmpty:
+		where = append(where, "COALESCE(o.n, 0) = 0")
+	case app.CageOccupancyFull:
+		where = append(where, cageOccupancy+" >= c.capacity")
 	}
+	where, args = timeRangePredicates(where, args, "c.created_at", filter.Created)
+	where, args = timeRangePredicates(where, args, "c.updated_at", filter.Updated)
+	if !filter.IncludeDeleted {
+		where = append(where, "c.deleted_at IS NULL")
+	}
+
+	query += whereClause(where)
 
-	query += " GROUP BY c.id, c.capacity, c.status, c.created_at, c.updated_at"
+	sort := filter.Sort
+	if len(sort) == 0 {
+		sort = []app.Sort{{Field: "createdAt"}}
+	}
+	orderBy, err := orderByClause(sort, cageColumns)
+	if err != nil {
+		return nil, err
+	}
+	query += orderBy
 
 	rows, err := s.DB.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -61,14 +239,7 @@ func (s *CageStore) List(ctx context.Context, status app.CageStatus) ([]app.Cage
 
 	for rows.Next() {
 		var cage app.Cage
-		if err := rows.Scan(
-			&cage.ID,
-			&cage.Capacity,
-			&cage.Status,
-			&cage.CreatedAt,
-			&cage.UpdatedAt,
-			&cage.Occupancy,
-		); err != nil {
+		if err := rows.Scan(cageFieldPointers(&cage, fields)...); err != nil {
 			return nil, err
 		}
 
@@ -82,6 +253,7 @@ func (s *CageStore) List(ctx context.Context, status app.CageStatus) ([]app.Cage
 }
 
 // Change status of a cage.
+// An occupied cage can be powered down only if it holds carnivores.
 func (s *CageStore) ChangeStatus(ctx context.Context, id string, status app.CageStatus) (*app.Cage, error) {
 	tx, err := s.DB.BeginTx(ctx, nil)
 	if err != nil {
@@ -89,7 +261,11 @@ func (s *CageStore) ChangeStatus(ctx context.Context, id string, status app.Cage
 	}
 	defer tx.Rollback() // nolint:errcheck
 
-	cage, err := getCage(ctx, tx, id)
+	if err := lockCage(ctx, tx, id); err != nil {
+		return nil, err
+	}
+
+	cage, err := getCage(ctx, tx, id, app.GetOptions{})
 	if err != nil {
 		return nil, err
 	}
@@ -98,8 +274,10 @@ func (s *CageStore) ChangeStatus(ctx context.Context, id string, status app.Cage
 		return cage, nil // Nothing to do.
 	}
 
-	if status == app.CageStatusDown && cage.Occupancy > 0 {
-		return nil, app.ErrConflict
+	if status == app.CageStatusDown {
+		if err := checkCagePowerDown(ctx, tx, id, cage.Occupancy); err != nil {
+			return nil, err
+		}
 	}
 
 	query := `
@@ -108,6 +286,7 @@ func (s *CageStore) ChangeStatus(ctx context.Context, id string, status app.Cage
 	 WHERE id = $2
 	RETURNING status, updated_at`
 
+	from := cage.Status
 	err = tx.QueryRowContext(ctx, query, status, id).Scan(
 		&cage.Status,
 		&cage.UpdatedAt,
@@ -116,6 +295,197 @@ func (s *CageStore) ChangeStatus(ctx context.Context, id string, status app.Cage
 		return nil, err
 	}
 
+	err = recordCageChange(ctx, tx, id, app.CageChangeStatus, string(from), string(cage.Status))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return cage, nil
+}
+
+// Update applies a patch to a cage.
+// All changes are applied together or not at all.
+func (s *CageStore) Update(ctx context.Context, id string, patch app.CagePatch) (*app.Cage, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() // nolint:errcheck
+
+	if err := lockCage(ctx, tx, id); err != nil {
+		return nil, err
+	}
+
+	cage, err := getCage(ctx, tx, id, app.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if patch.Status != nil && *patch.Status == app.CageStatusDown && cage.Status != app.CageStatusDown {
+		if err := checkCagePowerDown(ctx, tx, id, cage.Occupancy); err != nil {
+			return nil, err
+		}
+	}
+
+	// Switching the capacity unit changes the occupancy, the capacity
+	// in the new unit has to be big enough for the current occupants.
+	if patch.Capacity != nil || patch.CapacityUnit != nil {
+		capacity, unit := cage.Capacity, cage.CapacityUnit
+		if patch.Capacity != nil {
+			capacity = *patch.Capacity
+		}
+		if patch.CapacityUnit != nil {
+			unit = *patch.CapacityUnit
+		}
+		if occupancy := unit.Occupancy(cage.HeadCount, cage.SpaceUsed); capacity < occupancy {
+			return nil, &app.OccupancyError{Occupancy: occupancy}
+		}
+	}
+
+	if patch.SectorID != nil && *patch.SectorID != "" {
+		if err := checkSector(ctx, tx, *patch.SectorID); err != nil {
+			return nil, err
+		}
+	}
+
+	if patch.Type != nil && *patch.Type != cage.Type {
+		if err := checkCageHabitat(ctx, tx, id, *patch.Type); err != nil {
+			return nil, err
+		}
+	}
+
+	if patch.Quarantine != nil && *patch.Quarantine != cage.Quarantine {
+		if err := checkCageQuarantine(ctx, tx, id, *patch.Quarantine); err != nil {
+			return nil, err
+		}
+	}
+
+	from := *cage
+
+	query := `
+	UPDATE cages
+	   SET type = COALESCE($1, type),
+	       status = COALESCE($2, status),
+	       capacity = COALESCE($3, capacity),
+	       capacity_unit = COALESCE($4, capacity_unit),
+	       sector_id = CASE WHEN $5::text IS NULL THEN sector_id ELSE NULLIF($5::text, '')::uuid END,
+	       quarantine = COALESCE($6, quarantine),
+	       updated_at = NOW()
+	 WHERE id = $7
+	RETURNING type, quarantine, status, capacity, capacity_unit, COALESCE(sector_id::text, ''), updated_at`
+
+	err = tx.QueryRowContext(ctx, query,
+		patch.Type, patch.Status, patch.Capacity, patch.CapacityUnit, patch.SectorID, patch.Quarantine, id,
+	).Scan(
+		&cage.Type,
+		&cage.Quarantine,
+		&cage.Status,
+		&cage.Capacity,
+		&cage.CapacityUnit,
+		&cage.SectorID,
+		&cage.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	cage.Occupancy = cage.CapacityUnit.Occupancy(cage.HeadCount, cage.SpaceUsed)
+
+	if cage.Type != from.Type {
+		err = recordCageChange(ctx, tx, id, app.CageChangeType, string(from.Type), string(cage.Type))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if cage.Quarantine != from.Quarantine {
+		err = recordCageChange(ctx, tx, id, app.CageChangeQuarantine,
+			strconv.FormatBool(from.Quarantine), strconv.FormatBool(cage.Quarantine))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if cage.Status != from.Status {
+		err = recordCageChange(ctx, tx, id, app.CageChangeStatus, string(from.Status), string(cage.Status))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if cage.Capacity != from.Capacity {
+		err = recordCageChange(ctx, tx, id, app.CageChangeCapacity, strconv.Itoa(from.Capacity), strconv.Itoa(cage.Capacity))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if cage.CapacityUnit != from.CapacityUnit {
+		err = recordCageChange(ctx, tx, id, app.CageChangeCapacityUnit, string(from.CapacityUnit), string(cage.CapacityUnit))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if cage.SectorID != from.SectorID {
+		err = recordCageChange(ctx, tx, id, app.CageChangeSector, from.SectorID, cage.SectorID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return cage, nil
+}
+
+// Resize changes the capacity of a cage.
+// The cage is locked for the duration of the change so that concurrent
+// admissions can't push the occupancy above the new capacity.
+func (s *CageStore) Resize(ctx context.Context, id string, capacity int) (*app.Cage, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() // nolint:errcheck
+
+	if err := lockCage(ctx, tx, id); err != nil {
+		return nil, err
+	}
+
+	cage, err := getCage(ctx, tx, id, app.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if capacity == cage.Capacity {
+		return cage, nil // Nothing to do.
+	}
+
+	if capacity < cage.Occupancy {
+		return nil, &app.OccupancyError{Occupancy: cage.Occupancy}
+	}
+
+	query := `
+	UPDATE cages
+	   SET capacity = $1, updated_at = NOW()
+	 WHERE id = $2
+	RETURNING capacity, updated_at`
+
+	from := cage.Capacity
+	err = tx.QueryRowContext(ctx, query, capacity, id).Scan(
+		&cage.Capacity,
+		&cage.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = recordCageChange(ctx, tx, id, app.CageChangeCapacity, strconv.Itoa(from), strconv.Itoa(cage.Capacity))
+	if err != nil {
+		return nil, err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
@@ -123,7 +493,50 @@ func (s *CageStore) ChangeStatus(ctx context.Context, id string, status app.Cage
 	return cage, nil
 }
 
-// Delete a cage.
+// History lists the recorded changes of a cage, oldest first.
+func (s *CageStore) History(ctx context.Context, id string) ([]app.CageHistoryEntry, error) {
+	if _, err := getCage(ctx, s.DB, id, app.GetOptions{Fields: []string{"id"}}); err != nil {
+		return nil, err
+	}
+
+	query := `
+	SELECT id, cage_id, change, old_value, new_value, created_at
+	  FROM cage_history
+	 WHERE cage_id = $1
+	 ORDER BY created_at, id`
+
+	rows, err := s.DB.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []app.CageHistoryEntry
+	for rows.Next() {
+		var entry app.CageHistoryEntry
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.CageID,
+			&entry.Change,
+			&entry.From,
+			&entry.To,
+			&entry.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		history = append(history, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// Delete soft deletes a cage.
+// The cage can be restored until it's purged.
+// app.ErrConflict is returned if the cage is occupied or has an open incident.
 func (s *CageStore) Delete(ctx context.Context, id string) error {
 	tx, err := s.DB.BeginTx(ctx, nil)
 	if err != nil {
@@ -131,7 +544,11 @@ func (s *CageStore) Delete(ctx context.Context, id string) error {
 	}
 	defer tx.Rollback() // nolint:errcheck
 
-	cage, err := getCage(ctx, tx, id)
+	if err := lockCage(ctx, tx, id); err != nil {
+		return err
+	}
+
+	cage, err := getCage(ctx, tx, id, app.GetOptions{})
 	if err != nil {
 		return err
 	}
@@ -140,9 +557,19 @@ func (s *CageStore) Delete(ctx context.Context, id string) error {
 		return app.ErrConflict
 	}
 
+	open, err := hasOpenIncident(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	if open {
+		return app.ErrConflict
+	}
+
 	query := `
-	DELETE FROM cages
-	 WHERE id = $1`
+	UPDATE cages
+	   SET deleted_at = NOW(), updated_at = NOW()
+	 WHERE id = $1
+	   AND deleted_at IS NULL`
 	res, err := tx.ExecContext(ctx, query, id)
 	if err != nil {
 		return err
@@ -164,24 +591,63 @@ func (s *CageStore) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
-// getCage returns a cage by id including its occupancy.
-func getCage(ctx context.Context, q queryable, id string) (*app.Cage, error) {
-	var cage app.Cage
+// Restore brings back a soft deleted cage.
+// Restoring a cage that is not deleted is a no-op.
+func (s *CageStore) Restore(ctx context.Context, id string) (*app.Cage, error) {
 	query := `
-	SELECT c.id, c.capacity, c.status, c.created_at, c.updated_at, COUNT(d.id)
-	  FROM cages c 
-	  LEFT JOIN dinosaurs d ON d.cage_id = c.id
-	 WHERE c.id = $1
-	 GROUP BY c.id, c.capacity, c.status, c.created_at, c.updated_at`
+	UPDATE cages
+	   SET deleted_at = NULL, updated_at = NOW()
+	 WHERE id = $1
+	   AND deleted_at IS NOT NULL`
+	_, err := s.DB.ExecContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
 
-	err := q.QueryRowContext(ctx, query, id).Scan(
-		&cage.ID,
-		&cage.Capacity,
-		&cage.Status,
-		&cage.CreatedAt,
-		&cage.UpdatedAt,
-		&cage.Occupancy,
-	)
+	return getCage(ctx, s.DB, id, app.GetOptions{})
+}
+
+// Purge permanently deletes the cages soft deleted before the given time
+// along with their dinosaurs and history.
+// The cages referenced by incidents, directly or via their dinosaurs, and the cages
+// of the dinosaurs in the lineage of others are kept for the record.
+func (s *CageStore) Purge(ctx context.Context, before time.Time) (int64, error) {
+	query := `
+	DELETE FROM cages c
+	 WHERE c.deleted_at < $1
+	   AND NOT EXISTS (SELECT 1 FROM incidents i WHERE i.cage_id = c.id)
+	   AND NOT EXISTS (SELECT 1 FROM dinosaurs d WHERE d.cage_id = c.id AND (` + dinosaurOnRecord + `))`
+	res, err := s.DB.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+// getCage returns a cage by id.
+// Only the fields in opts are selected, all of them including the occupancy by default.
+func getCage(ctx context.Context, q queryable, id string, opts app.GetOptions) (*app.Cage, error) {
+	fields := opts.Fields
+	if len(fields) == 0 {
+		fields = app.CageFields
+	}
+	columns, err := selectList(fields, cageColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	query := "SELECT " + columns + " FROM cages c"
+	if needsOccupancy(fields...) {
+		query += cageOccupancyJoin
+	}
+	query += " WHERE c.id = $1"
+	if !opts.IncludeDeleted {
+		query += " AND c.deleted_at IS NULL"
+	}
+
+	var cage app.Cage
+	err = q.QueryRowContext(ctx, query, id).Scan(cageFieldPointers(&cage, fields)...)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, app.ErrNotFound
@@ -193,32 +659,157 @@ func getCage(ctx context.Context, q queryable, id string) (*app.Cage, error) {
 	return &cage, nil
 }
 
+// lockCage locks a cage row until the end of the transaction.
+// Changes of the cage and admissions into it are serialized this way.
+// The lock doesn't conflict with the foreign key checks.
+func lockCage(ctx context.Context, q queryable, id string) error {
+	query := `
+	SELECT id
+	  FROM cages
+	 WHERE id = $1
+	   AND deleted_at IS NULL
+	   FOR NO KEY UPDATE`
+
+	err := q.QueryRowContext(ctx, query, id).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return app.ErrNotFound
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// recordCageChange adds an entry to the cage history.
+func recordCageChange(ctx context.Context, q queryable, id string, change app.CageChange, from, to string) error {
+	query := `
+	INSERT INTO cage_history (cage_id, change, old_value, new_value)
+	VALUES ($1, $2, $3, $4)
+	RETURNING id`
+
+	var entryID int64
+	return q.QueryRowContext(ctx, query, id, change, from, to).Scan(&entryID)
+}
+
+// checkCagePowerDown checks if a cage can be powered down.
+// An occupied cage can't be powered down unless it holds carnivores,
+// their feedings in the powered down cage are flagged as unsafe.
+func checkCagePowerDown(ctx context.Context, q queryable, id string, occupancy int) error {
+	if occupancy == 0 {
+		return nil
+	}
+
+	carnivores, err := holdsCarnivores(ctx, q, id)
+	if err != nil {
+		return err
+	}
+	if !carnivores {
+		return app.ErrConflict
+	}
+
+	return nil
+}
+
+// checkCageHabitat checks if all occupants of a cage can live in a cage of the type.
+func checkCageHabitat(ctx context.Context, q queryable, id string, cageType app.CageType) error {
+	var species []app.DinosaurSpecies
+	for _, s := range app.AllDinosaurSpecies {
+		if !cageType.Allows(s) {
+			species = append(species, s)
+		}
+	}
+	if len(species) == 0 {
+		return nil
+	}
+
+	where := []string{"cage_id = ?", "deleted_at IS NULL", "species IN (" + placeholders(len(species)) + ")"}
+	args := []any{id}
+	for _, s := range species {
+		args = append(args, s)
+	}
+
+	var misplaced int
+	err := q.QueryRowContext(ctx, "SELECT COUNT(*) FROM dinosaurs"+whereClause(where), args...).Scan(&misplaced)
+	if err != nil {
+		return err
+	}
+	if misplaced > 0 {
+		return app.ErrHabitatMismatch
+	}
+
+	return nil
+}
+
+// checkCageQuarantine checks if the occupants of a cage can stay in it
+// when it's designated as a quarantine or a regular cage.
+// A quarantine cage takes only quarantined dinosaurs of a single species
+// and a regular one only those that aren't quarantined.
+func checkCageQuarantine(ctx context.Context, q queryable, id string, quarantine bool) error {
+	query := `
+	SELECT COUNT(*) FILTER (WHERE quarantined <> $2), COUNT(DISTINCT species)
+	  FROM dinosaurs
+	 WHERE cage_id = $1
+	   AND deleted_at IS NULL`
+
+	var misplaced, species int
+	err := q.QueryRowContext(ctx, query, id, quarantine).Scan(&misplaced, &species)
+	if err != nil {
+		return err
+	}
+	if misplaced > 0 {
+		return app.ErrQuarantineMismatch
+	}
+	if quarantine && species > 1 {
+		return app.ErrSpeciesMismatch
+	}
+
+	return nil
+}
+
 // checkCageCompatibility checks if a dinosaur can be added or moved to a cage.
+// Quarantined dinosaurs go only to quarantine cages and the others only to regular ones.
 func checkCageCompatibility(
 	ctx context.Context,
 	q queryable,
 	id string,
 	species app.DinosaurSpecies,
+	quarantined bool,
 ) error {
+	// Lock the cage so that concurrent admissions and resizes
+	// see the occupancy one at a time.
+	if err := lockCage(ctx, q, id); err != nil {
+		return err
+	}
+
 	// To satisfy the species compatibility requirements we just need to know
 	// the species of any of the occupying dinosaurs- thus the use of MIN(d.species).
 	query := `
-	SELECT c.capacity, c.status, COUNT(d.id), COALESCE(MIN(d.species), '')
+	SELECT c.type, c.quarantine, c.capacity, c.capacity_unit, c.status, COUNT(d.id), COALESCE(SUM(` + speciesWeight + `), 0), COALESCE(MIN(d.species), '')
 	  FROM cages c
-	  LEFT JOIN dinosaurs d ON d.cage_id = c.id
+	  LEFT JOIN dinosaurs d ON d.cage_id = c.id AND d.deleted_at IS NULL
 	 WHERE c.id = $1
-	 GROUP BY c.capacity, c.status`
+	 GROUP BY c.type, c.quarantine, c.capacity, c.capacity_unit, c.status`
 
 	var (
+		cageType    app.CageType
+		quarantine  bool
 		capacity    int
+		unit        app.CapacityUnit
 		status      app.CageStatus
-		occupancy   int
+		headCount   int
+		spaceUsed   int
 		cageSpecies app.DinosaurSpecies
 	)
 	err := q.QueryRowContext(ctx, query, id).Scan(
+		&cageType,
+		&quarantine,
 		&capacity,
+		&unit,
 		&status,
-		&occupancy,
+		&headCount,
+		&spaceUsed,
 		&cageSpecies,
 	)
 	if err != nil {
@@ -232,23 +823,54 @@ func checkCageCompatibility(
 		return app.ErrCagePoweredDown
 	}
 
-	if occupancy >= capacity {
+	lockedDown, err := cageLockedDown(ctx, q, id)
+	if err != nil {
+		return err
+	}
+	if lockedDown {
+		return app.ErrCageLockedDown
+	}
+
+	if quarantine != quarantined {
+		return app.ErrQuarantineMismatch
+	}
+
+	if !cageType.Allows(species) {
+		return app.ErrHabitatMismatch
+	}
+
+	// The dinosaur has to fit in whole, in space units a big one may not fit
+	// into a cage that still has room for a smaller one.
+	if unit.Occupancy(headCount, spaceUsed)+unit.Size(species) > capacity {
 		return app.ErrCapacityExceeded
 	}
 
 	// If a cage is occupied we need to make sure species are compatible.
-	if occupancy > 0 {
+	if headCount > 0 {
 		speciesType := species.Type()
 		// All dinosaurs in the cage must be of the same species type.
 		if speciesType != cageSpecies.Type() {
 			return app.ErrSpeciesMismatch
 		}
 
-		// Carnivores can only be in a cage with the same species.
-		if speciesType == app.DinosaurTypeCarnivore && species != cageSpecies {
+		// Carnivores can only be in a cage with the same species
+		// and so can the quarantined dinosaurs.
+		if (speciesType == app.DinosaurTypeCarnivore || quarantine) && species != cageSpecies {
 			return app.ErrSpeciesMismatch
 		}
 	}
 
+	// Carnivores can only be admitted into a cage covered by the staff
+	// certified for carnivore handling, now and in the upcoming shifts.
+	if species.Type() == app.DinosaurTypeCarnivore {
+		uncertified, err := coveredByUncertified(ctx, q, id)
+		if err != nil {
+			return err
+		}
+		if uncertified {
+			return app.ErrNotCertified
+		}
+	}
+
 	return nil
 }
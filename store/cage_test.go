@@ -5,7 +5,11 @@ package store
 
 import (
 	"context"
+	"errors"
+	"reflect"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -21,7 +25,7 @@ func TestCageStore(t *testing.T) {
 	ctx := context.Background()
 	store := CageStore{DB: testDB}
 
-	list, err := store.List(ctx, app.CageStatusUnspecified)
+	list, err := store.List(ctx, app.CageFilter{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -71,7 +75,7 @@ func TestCageStore(t *testing.T) {
 		t.Fatalf("Expected Status %s got %s", want, got)
 	}
 
-	list, err = store.List(ctx, app.CageStatusUnspecified)
+	list, err = store.List(ctx, app.CageFilter{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -81,7 +85,7 @@ func TestCageStore(t *testing.T) {
 	}
 
 	// List only active cages.
-	list, err = store.List(ctx, app.CageStatusActive)
+	list, err = store.List(ctx, app.CageFilter{Status: app.CageStatusActive})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -94,7 +98,7 @@ func TestCageStore(t *testing.T) {
 	}
 
 	// List only powered down cages.
-	list, err = store.List(ctx, app.CageStatusDown)
+	list, err = store.List(ctx, app.CageFilter{Status: app.CageStatusDown})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -128,19 +132,19 @@ func TestCageStore(t *testing.T) {
 
 	// Add a dinosaur.
 	query := "INSERT INTO dinosaurs (name, species, cage_id) VALUES ($1, $2, $3)"
-	_, err = testDB.Exec(query, "foo", app.DinosaurSpeciesTyrannosaurus, cage2.ID)
+	_, err = testDB.Exec(query, "foo", app.DinosaurSpeciesTriceratops, cage2.ID)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	// And make sure we can't power down an occupied cage.
+	// And make sure we can't power down a cage occupied by herbivores.
 	_, err = store.ChangeStatus(ctx, cage2.ID, app.CageStatusDown)
 	if want, got := app.ErrConflict, err; want != got {
 		t.Fatalf("Expected error %v got %v", want, got)
 	}
 
 	// Get the cage and see that occupancy is correctly reflected.
-	cage2, err = store.Get(ctx, cage2.ID)
+	cage2, err = store.Get(ctx, cage2.ID, app.GetOptions{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -149,8 +153,45 @@ func TestCageStore(t *testing.T) {
 		t.Fatalf("Expected Occupancy %d got %d", want, got)
 	}
 
+	// Patch the capacity and the status together.
+	capacity, status := 3, app.CageStatusActive
+	cage2, err = store.Update(ctx, cage2.ID, app.CagePatch{Status: &status, Capacity: &capacity})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := 3, cage2.Capacity; want != got {
+		t.Fatalf("Expected Capacity %d got %d", want, got)
+	}
+	if want, got := 1, cage2.Occupancy; want != got {
+		t.Fatalf("Expected Occupancy %d got %d", want, got)
+	}
+
+	// Make sure the capacity can't go below the occupancy.
+	capacity = 0
+	_, err = store.Update(ctx, cage2.ID, app.CagePatch{Capacity: &capacity})
+	if want, got := app.ErrCapacityBelowOccupancy, err; want != got {
+		t.Fatalf("Expected error %v got %v", want, got)
+	}
+
+	// And that it can't be powered down with a patch either.
+	capacity, status = 1, app.CageStatusDown
+	_, err = store.Update(ctx, cage2.ID, app.CagePatch{Status: &status, Capacity: &capacity})
+	if want, got := app.ErrConflict, err; want != got {
+		t.Fatalf("Expected error %v got %v", want, got)
+	}
+
+	cage2, err = store.Get(ctx, cage2.ID, app.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := 3, cage2.Capacity; want != got {
+		t.Fatalf("Expected Capacity %d got %d", want, got)
+	}
+
 	// Getting a non-existent cage should fail.
-	_, err = store.Get(ctx, uuid.NewString())
+	_, err = store.Get(ctx, uuid.NewString(), app.GetOptions{})
 	if want, got := app.ErrNotFound, err; want != got {
 		t.Fatalf("Expected error %v got %v", want, got)
 	}
@@ -168,7 +209,7 @@ func TestCageStore(t *testing.T) {
 	}
 
 	// Make sure we no longer see the deleted cage.
-	list, err = store.List(ctx, app.CageStatusUnspecified)
+	list, err = store.List(ctx, app.CageFilter{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -183,3 +224,534 @@ func TestCageStore(t *testing.T) {
 		t.Fatalf("Expected error %v got %v", want, got)
 	}
 }
+
+func TestCageStoreResize(t *testing.T) {
+	setUpTestDB(t)
+	t.Cleanup(func() {
+		testDB.Exec("TRUNCATE TABLE cages CASCADE")
+	})
+
+	ctx := context.Background()
+	cageStore := CageStore{DB: testDB}
+	dinosaurStore := DinosaurStore{DB: testDB}
+
+	cage, err := cageStore.Add(ctx, &app.Cage{
+		Capacity: 2,
+		Status:   app.CageStatusActive,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"Cera", "Tops"} {
+		_, err = dinosaurStore.Add(ctx, &app.Dinosaur{
+			Name:    name,
+			Species: app.DinosaurSpeciesTriceratops,
+			CageID:  cage.ID,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Make the cage bigger.
+	cage, err = cageStore.Resize(ctx, cage.ID, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := 4, cage.Capacity; want != got {
+		t.Fatalf("Expected Capacity %d got %d", want, got)
+	}
+	if want, got := 2, cage.Occupancy; want != got {
+		t.Fatalf("Expected Occupancy %d got %d", want, got)
+	}
+
+	// Make sure the capacity can't go below the occupancy
+	// and that the current occupancy is reported.
+	_, err = cageStore.Resize(ctx, cage.ID, 1)
+
+	var occupancyErr *app.OccupancyError
+	if !errors.As(err, &occupancyErr) {
+		t.Fatalf("Expected *app.OccupancyError got %v", err)
+	}
+	if want, got := 2, occupancyErr.Occupancy; want != got {
+		t.Fatalf("Expected Occupancy %d got %d", want, got)
+	}
+
+	// Resizing a non-existent cage should fail.
+	_, err = cageStore.Resize(ctx, uuid.NewString(), 1)
+	if want, got := app.ErrNotFound, err; want != got {
+		t.Fatalf("Expected error %v got %v", want, got)
+	}
+
+	// Shrink it back, both changes should be recorded in the history.
+	_, err = cageStore.Resize(ctx, cage.ID, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	history, err := cageStore.History(ctx, cage.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := 2, len(history); want != got {
+		t.Fatalf("Expected history entries %d got %d", want, got)
+	}
+	if want, got := app.CageChangeCapacity, history[0].Change; want != got {
+		t.Fatalf("Expected Change %s got %s", want, got)
+	}
+	if want, got := "2", history[0].From; want != got {
+		t.Fatalf("Expected From %s got %s", want, got)
+	}
+	if want, got := "4", history[0].To; want != got {
+		t.Fatalf("Expected To %s got %s", want, got)
+	}
+	if want, got := "2", history[1].To; want != got {
+		t.Fatalf("Expected To %s got %s", want, got)
+	}
+
+	// Getting the history of a non-existent cage should fail.
+	_, err = cageStore.History(ctx, uuid.NewString())
+	if want, got := app.ErrNotFound, err; want != got {
+		t.Fatalf("Expected error %v got %v", want, got)
+	}
+}
+
+func TestCageStoreResizeConcurrentAdmissions(t *testing.T) {
+	setUpTestDB(t)
+	t.Cleanup(func() {
+		testDB.Exec("TRUNCATE TABLE cages CASCADE")
+	})
+
+	ctx := context.Background()
+	cageStore := CageStore{DB: testDB}
+	dinosaurStore := DinosaurStore{DB: testDB}
+
+	cage, err := cageStore.Add(ctx, &app.Cage{
+		Capacity: 10,
+		Status:   app.CageStatusActive,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Race admissions against shrinking the cage.
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			dinosaurStore.Add(ctx, &app.Dinosaur{ // nolint:errcheck
+				Name:    "Brachiosaurus",
+				Species: app.DinosaurSpeciesBrachiosaurus,
+				CageID:  cage.ID,
+			})
+		}()
+		go func() {
+			defer wg.Done()
+			cageStore.Resize(ctx, cage.ID, 3) // nolint:errcheck
+		}()
+	}
+	wg.Wait()
+
+	// Whatever the order, the occupancy never exceeds the capacity.
+	cage, err = cageStore.Get(ctx, cage.ID, app.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cage.Occupancy > cage.Capacity {
+		t.Fatalf("Expected Occupancy %d to be at most Capacity %d", cage.Occupancy, cage.Capacity)
+	}
+}
+
+func TestCageStoreSoftDelete(t *testing.T) {
+	setUpTestDB(t)
+	t.Cleanup(func() {
+		testDB.Exec("TRUNCATE TABLE cages CASCADE")
+	})
+
+	ctx := context.Background()
+	store := CageStore{DB: testDB}
+
+	cage, err := store.Add(ctx, &app.Cage{
+		Capacity: 1,
+		Status:   app.CageStatusActive,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = store.Delete(ctx, cage.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The deleted cage can't be changed.
+	_, err = store.ChangeStatus(ctx, cage.ID, app.CageStatusDown)
+	if want, got := app.ErrNotFound, err; want != got {
+		t.Fatalf("Expected error %v got %v", want, got)
+	}
+
+	// But can still be seen when asked for.
+	list, err := store.List(ctx, app.CageFilter{IncludeDeleted: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 1, len(list); want != got {
+		t.Fatalf("Expected cages %d got %d", want, got)
+	}
+	if list[0].DeletedAt == nil {
+		t.Fatal("Expected DeletedAt got empty")
+	}
+
+	cage, err = store.Restore(ctx, cage.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cage.DeletedAt != nil {
+		t.Fatalf("Expected DeletedAt to be empty got %v", cage.DeletedAt)
+	}
+
+	// Restoring a non-existent cage should fail.
+	_, err = store.Restore(ctx, uuid.NewString())
+	if want, got := app.ErrNotFound, err; want != got {
+		t.Fatalf("Expected error %v got %v", want, got)
+	}
+
+	// Only the cages deleted before the retention period are purged.
+	err = store.Delete(ctx, cage.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := store.Purge(ctx, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := int64(0), n; want != got {
+		t.Fatalf("Expected purged %d got %d", want, got)
+	}
+
+	n, err = store.Purge(ctx, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := int64(1), n; want != got {
+		t.Fatalf("Expected purged %d got %d", want, got)
+	}
+}
+
+func TestCageStoreListFilters(t *testing.T) {
+	setUpTestDB(t)
+	t.Cleanup(func() {
+		testDB.Exec("TRUNCATE TABLE cages CASCADE")
+	})
+
+	ctx := context.Background()
+	cageStore := CageStore{DB: testDB}
+	dinosaurStore := DinosaurStore{DB: testDB}
+
+	var cages []*app.Cage
+	for _, c := range []app.Cage{
+		{Capacity: 1, Status: app.CageStatusActive},
+		{Capacity: 3, Status: app.CageStatusActive},
+		{Capacity: 5, Status: app.CageStatusActive},
+		{Capacity: 2, Status: app.CageStatusDown},
+	} {
+		cage, err := cageStore.Add(ctx, &c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cages = append(cages, cage)
+	}
+
+	// The first cage is full, the second one has one free spot left.
+	for _, cage := range []*app.Cage{cages[0], cages[1], cages[1]} {
+		_, err := dinosaurStore.Add(ctx, &app.Dinosaur{Name: "Bumpy", Species: app.DinosaurSpeciesAnkylosaurus, CageID: cage.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	capacities := func(cages []app.Cage) []int {
+		var capacities []int
+		for _, c := range cages {
+			capacities = append(capacities, c.Capacity)
+		}
+		return capacities
+	}
+
+	tests := []struct {
+		name       string
+		filter     app.CageFilter
+		capacities []int
+	}{
+		{"default order", app.CageFilter{}, []int{1, 3, 5, 2}},
+		{"status", app.CageFilter{Status: app.CageStatusActive}, []int{1, 3, 5}},
+		{"ids", app.CageFilter{IDs: []string{cages[3].ID, cages[1].ID}}, []int{3, 2}},
+		{"min free capacity", app.CageFilter{MinFreeCapacity: 2}, []int{5, 2}},
+		{"empty", app.CageFilter{Occupancy: app.CageOccupancyEmpty}, []int{5, 2}},
+		{"full", app.CageFilter{Occupancy: app.CageOccupancyFull}, []int{1}},
+		{"created after", app.CageFilter{Created: app.TimeRange{After: cages[1].CreatedAt}}, []int{5, 2}},
+		{"created range", app.CageFilter{Created: app.TimeRange{After: cages[0].CreatedAt, Before: cages[3].CreatedAt}}, []int{3, 5}},
+		{"sort by capacity", app.CageFilter{Sort: []app.Sort{{Field: "capacity"}}}, []int{1, 2, 3, 5}},
+		{"sort by occupancy descending", app.CageFilter{
+			Sort: []app.Sort{{Field: "occupancy", Desc: true}, {Field: "capacity", Desc: true}},
+		}, []int{3, 1, 5, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := cageStore.List(ctx, tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want, got := tt.capacities, capacities(list); !reflect.DeepEqual(want, got) {
+				t.Fatalf("Expected capacities %v got %v", want, got)
+			}
+		})
+	}
+
+	_, err := cageStore.List(ctx, app.CageFilter{Sort: []app.Sort{{Field: "name"}}})
+	if err == nil {
+		t.Fatal("Expected error got nil")
+	}
+}
+
+func TestCageStoreFields(t *testing.T) {
+	setUpTestDB(t)
+	t.Cleanup(func() {
+		testDB.Exec("TRUNCATE TABLE cages CASCADE")
+	})
+
+	ctx := context.Background()
+	cageStore := CageStore{DB: testDB}
+	dinosaurStore := DinosaurStore{DB: testDB}
+
+	cage, err := cageStore.Add(ctx, &app.Cage{Capacity: 2, Status: app.CageStatusActive})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = dinosaurStore.Add(ctx, &app.Dinosaur{Name: "Sarah", Species: app.DinosaurSpeciesTriceratops, CageID: cage.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Occupancy is not counted unless it's asked for.
+	list, err := cageStore.List(ctx, app.CageFilter{Fields: []string{"capacity", "id"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := []app.Cage{{ID: cage.ID, Capacity: 2}}, list; !reflect.DeepEqual(want, got) {
+		t.Fatalf("Expected %+v got %+v", want, got)
+	}
+
+	// Unless it's filtered by.
+	list, err = cageStore.List(ctx, app.CageFilter{Fields: []string{"id"}, Occupancy: app.CageOccupancyEmpty})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 0, len(list); want != got {
+		t.Fatalf("Expected cages %d got %d", want, got)
+	}
+
+	got, err := cageStore.Get(ctx, cage.ID, app.GetOptions{Fields: []string{"occupancy", "status"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (app.Cage{Status: app.CageStatusActive, Occupancy: 1}); !reflect.DeepEqual(want, *got) {
+		t.Fatalf("Expected %+v got %+v", want, *got)
+	}
+
+	if _, err := cageStore.Get(ctx, uuid.NewString(), app.GetOptions{Fields: []string{"id"}}); err != app.ErrNotFound {
+		t.Fatalf("Expected %v got %v", app.ErrNotFound, err)
+	}
+	if _, err := cageStore.List(ctx, app.CageFilter{Fields: []string{"name"}}); err == nil {
+		t.Fatal("Expected error got nil")
+	}
+}
+
+func TestCageStoreTypes(t *testing.T) {
+	setUpTestDB(t)
+	t.Cleanup(func() {
+		testDB.Exec("TRUNCATE TABLE cages CASCADE")
+	})
+
+	ctx := context.Background()
+	cageStore := CageStore{DB: testDB}
+	dinosaurStore := DinosaurStore{DB: testDB}
+
+	// Untyped cages keep accepting any species.
+	general, err := cageStore.Add(ctx, &app.Cage{Capacity: 2, Status: app.CageStatusActive})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := app.CageTypeGeneral, general.Type; want != got {
+		t.Fatalf("Expected Type %s got %s", want, got)
+	}
+
+	paddock, err := cageStore.Add(ctx, &app.Cage{Type: app.CageTypePaddock, Capacity: 2, Status: app.CageStatusActive})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := app.CageTypePaddock, paddock.Type; want != got {
+		t.Fatalf("Expected Type %s got %s", want, got)
+	}
+
+	_, err = dinosaurStore.Add(ctx, &app.Dinosaur{Name: "Rex", Species: app.DinosaurSpeciesTyrannosaurus, CageID: paddock.ID})
+	if want, got := app.ErrHabitatMismatch, err; want != got {
+		t.Fatalf("Expected error %v got %v", want, got)
+	}
+
+	rex, err := dinosaurStore.Add(ctx, &app.Dinosaur{Name: "Rex", Species: app.DinosaurSpeciesTyrannosaurus, CageID: general.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = dinosaurStore.Update(ctx, rex.ID, app.DinosaurPatch{CageID: &paddock.ID})
+	if want, got := app.ErrHabitatMismatch, err; want != got {
+		t.Fatalf("Expected error %v got %v", want, got)
+	}
+
+	// The type of an occupied cage can only change to one its occupants can live in.
+	aquatic := app.CageTypeAquatic
+	_, err = cageStore.Update(ctx, general.ID, app.CagePatch{Type: &aquatic})
+	if want, got := app.ErrHabitatMismatch, err; want != got {
+		t.Fatalf("Expected error %v got %v", want, got)
+	}
+
+	highSecurity := app.CageTypeHighSecurity
+	cage, err := cageStore.Update(ctx, general.ID, app.CagePatch{Type: &highSecurity})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := app.CageTypeHighSecurity, cage.Type; want != got {
+		t.Fatalf("Expected Type %s got %s", want, got)
+	}
+
+	history, err := cageStore.History(ctx, cage.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 1, len(history); want != got {
+		t.Fatalf("Expected history entries %d got %d", want, got)
+	}
+	if want, got := app.CageChangeType, history[0].Change; want != got {
+		t.Fatalf("Expected Change %s got %s", want, got)
+	}
+
+	cages, err := cageStore.List(ctx, app.CageFilter{Type: app.CageTypePaddock})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 1, len(cages); want != got {
+		t.Fatalf("Expected cages %d got %d", want, got)
+	}
+	if want, got := paddock.ID, cages[0].ID; want != got {
+		t.Fatalf("Expected cage %s got %s", want, got)
+	}
+}
+
+func TestCageStoreCapacityUnits(t *testing.T) {
+	setUpTestDB(t)
+	t.Cleanup(func() {
+		testDB.Exec("TRUNCATE TABLE cages CASCADE")
+	})
+
+	ctx := context.Background()
+	cageStore := CageStore{DB: testDB}
+	dinosaurStore := DinosaurStore{DB: testDB}
+
+	// Cages count heads unless told otherwise.
+	heads, err := cageStore.Add(ctx, &app.Cage{Capacity: 2, Status: app.CageStatusActive})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := app.CapacityUnitHeads, heads.CapacityUnit; want != got {
+		t.Fatalf("Expected CapacityUnit %s got %s", want, got)
+	}
+
+	space, err := cageStore.Add(ctx, &app.Cage{Capacity: 10, CapacityUnit: app.CapacityUnitSpace, Status: app.CageStatusActive})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, cage := range []*app.Cage{heads, space} {
+		_, err := dinosaurStore.Add(ctx, &app.Dinosaur{Name: "Brachi", Species: app.DinosaurSpeciesBrachiosaurus, CageID: cage.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A triceratops still fits as a head but not into the space left.
+	if _, err := dinosaurStore.Add(ctx, &app.Dinosaur{Name: "Cera", Species: app.DinosaurSpeciesTriceratops, CageID: heads.ID}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = dinosaurStore.Add(ctx, &app.Dinosaur{Name: "Tops", Species: app.DinosaurSpeciesTriceratops, CageID: space.ID})
+	if want, got := app.ErrCapacityExceeded, err; want != got {
+		t.Fatalf("Expected error %v got %v", want, got)
+	}
+
+	cage, err := cageStore.Get(ctx, space.ID, app.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 8, cage.Occupancy; want != got {
+		t.Fatalf("Expected Occupancy %d got %d", want, got)
+	}
+	if want, got := 1, cage.HeadCount; want != got {
+		t.Fatalf("Expected HeadCount %d got %d", want, got)
+	}
+	if want, got := 8, cage.SpaceUsed; want != got {
+		t.Fatalf("Expected SpaceUsed %d got %d", want, got)
+	}
+
+	cages, err := cageStore.List(ctx, app.CageFilter{MinFreeCapacity: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 1, len(cages); want != got {
+		t.Fatalf("Expected cages %d got %d", want, got)
+	}
+	if want, got := space.ID, cages[0].ID; want != got {
+		t.Fatalf("Expected cage %s got %s", want, got)
+	}
+
+	// Switching to space units has to leave room for the current occupants.
+	unit := app.CapacityUnitSpace
+	_, err = cageStore.Update(ctx, heads.ID, app.CagePatch{CapacityUnit: &unit})
+
+	var occupancyErr *app.OccupancyError
+	if !errors.As(err, &occupancyErr) {
+		t.Fatalf("Expected *app.OccupancyError got %v", err)
+	}
+	if want, got := 12, occupancyErr.Occupancy; want != got {
+		t.Fatalf("Expected Occupancy %d got %d", want, got)
+	}
+
+	capacity := 12
+	cage, err = cageStore.Update(ctx, heads.ID, app.CagePatch{Capacity: &capacity, CapacityUnit: &unit})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 12, cage.Occupancy; want != got {
+		t.Fatalf("Expected Occupancy %d got %d", want, got)
+	}
+
+	history, err := cageStore.History(ctx, heads.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 2, len(history); want != got {
+		t.Fatalf("Expected history entries %d got %d", want, got)
+	}
+	if want, got := app.CageChangeCapacity, history[0].Change; want != got {
+		t.Fatalf("Expected Change %s got %s", want, got)
+	}
+	if want, got := app.CageChangeCapacityUnit, history[1].Change; want != got {
+		t.Fatalf("Expected Change %s got %s", want, got)
+	}
+}
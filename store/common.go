@@ -3,9 +3,102 @@ package store
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pmatseykanets/jurassic/app"
 )
 
 // queryable allows to pass *sql.DB or *sql.Tx interchangeably to the consuming methods.
 type queryable interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
 	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
 }
+
+// whereClause joins the predicates into a WHERE clause.
+// Each ? placeholder is replaced with a numbered one in the order of appearance.
+func whereClause(predicates []string) string {
+	var (
+		clause string
+		n      int
+	)
+	for i, predicate := range predicates {
+		if i == 0 {
+			clause += " WHERE "
+		} else {
+			clause += " AND "
+		}
+
+		for strings.Contains(predicate, "?") {
+			n++
+			predicate = strings.Replace(predicate, "?", "$"+strconv.Itoa(n), 1)
+		}
+		clause += predicate
+	}
+
+	return clause
+}
+
+// placeholders returns n comma separated ? placeholders, e.g. for an IN list.
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// likeEscaper escapes the LIKE pattern special characters.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// likePrefix returns a LIKE pattern that matches values starting with the prefix.
+func likePrefix(prefix string) string {
+	return likeEscaper.Replace(prefix) + "%"
+}
+
+// timeRangePredicates appends the predicates and the arguments for the time range on the column.
+func timeRangePredicates(where []string, args []any, column string, tr app.TimeRange) ([]string, []any) {
+	if !tr.After.IsZero() {
+		where = append(where, column+" > ?")
+		args = append(args, tr.After)
+	}
+	if !tr.Before.IsZero() {
+		where = append(where, column+" < ?")
+		args = append(args, tr.Before)
+	}
+
+	return where, args
+}
+
+// selectList returns the comma separated SQL expressions of the fields.
+// Only the fields whitelisted in columns are accepted.
+func selectList(fields []string, columns map[string]string) (string, error) {
+	list := make([]string, len(fields))
+	for i, field := range fields {
+		column, ok := columns[field]
+		if !ok {
+			return "", fmt.Errorf("invalid field %q", field)
+		}
+		list[i] = column
+	}
+
+	return strings.Join(list, ", "), nil
+}
+
+// orderByClause builds an ORDER BY clause for the sort order.
+// Only the fields whitelisted in columns are accepted and mapped to their SQL expressions.
+// The id column is always added last to make the order stable.
+func orderByClause(sort []app.Sort, columns map[string]string) (string, error) {
+	terms := make([]string, 0, len(sort)+1)
+	for _, s := range sort {
+		column, ok := columns[s.Field]
+		if !ok {
+			return "", fmt.Errorf("invalid sort field %q", s.Field)
+		}
+		if s.Desc {
+			column += " DESC"
+		}
+
+		terms = append(terms, column)
+	}
+	terms = append(terms, columns["id"])
+
+	return " ORDER BY " + strings.Join(terms, ", "), nil
+}
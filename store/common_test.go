@@ -10,8 +10,9 @@ import (
 
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
 	_ "github.com/lib/pq"
+
+	"github.com/pmatseykanets/jurassic/db"
 )
 
 var (
@@ -28,7 +29,12 @@ func setUpTestDB(t *testing.T) {
 		testDBConnString = s
 	}
 
-	migrations, err := migrate.New("file://../db/migrations", testDBConnString)
+	src, err := db.Source()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	migrations, err := migrate.NewWithSourceInstance("migrations", src, testDBConnString)
 	if err != nil {
 		t.Fatal(err)
 	}
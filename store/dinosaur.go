@@ -3,8 +3,7 @@ package store
 import (
 	"context"
 	"database/sql"
-	"strconv"
-	"strings"
+	"time"
 
 	"github.com/pmatseykanets/jurassic/app"
 )
@@ -14,7 +13,9 @@ type DinosaurStore struct {
 	DB *sql.DB
 }
 
-// Add a dinosaur to a cage.
+// Add an adult dinosaur to a cage.
+// A quarantined dinosaur is admitted only into a quarantine cage,
+// its quarantine starts now unless the start is set.
 func (s *DinosaurStore) Add(ctx context.Context, dinosaur *app.Dinosaur) (*app.Dinosaur, error) {
 	tx, err := s.DB.BeginTx(ctx, nil)
 	if err != nil {
@@ -22,21 +23,41 @@ func (s *DinosaurStore) Add(ctx context.Context, dinosaur *app.Dinosaur) (*app.D
 	}
 	defer tx.Rollback() // nolint:errcheck
 
-	err = checkCageCompatibility(ctx, tx, dinosaur.CageID, dinosaur.Species)
+	err = checkCageCompatibility(ctx, tx, dinosaur.CageID, dinosaur.Species, dinosaur.Quarantined)
 	if err != nil {
 		return nil, err
 	}
 
+	var (
+		reason     string
+		start, end *time.Time
+	)
+	if dinosaur.Quarantined {
+		reason, start, end = dinosaur.QuarantineReason, dinosaur.QuarantineStart, dinosaur.QuarantineEnd
+		if start == nil {
+			now := time.Now()
+			start = &now
+		}
+	}
+
 	var added app.Dinosaur
 	query := `
-	INSERT INTO dinosaurs (name, species, cage_id)
-	VALUES ($1, $2, $3) 
-	RETURNING id, name, species, cage_id, created_at, updated_at`
-	err = s.DB.QueryRowContext(ctx, query, dinosaur.Name, dinosaur.Species, dinosaur.CageID).Scan(
+	INSERT INTO dinosaurs (name, species, cage_id, placed_at, quarantined, quarantine_reason, quarantine_start, quarantine_end)
+	VALUES ($1, $2, $3, NOW(), $4, $5, $6, $7) 
+	RETURNING id, name, species, cage_id, stage, health_status, ` + quarantineColumns + `, created_at, updated_at`
+	err = tx.QueryRowContext(ctx, query,
+		dinosaur.Name, dinosaur.Species, dinosaur.CageID, dinosaur.Quarantined, reason, start, end,
+	).Scan(
 		&added.ID,
 		&added.Name,
 		&added.Species,
 		&added.CageID,
+		&added.Stage,
+		&added.HealthStatus,
+		&added.Quarantined,
+		&added.QuarantineReason,
+		&added.QuarantineStart,
+		&added.QuarantineEnd,
 		&added.CreatedAt,
 		&added.UpdatedAt,
 	)
@@ -51,35 +72,153 @@ func (s *DinosaurStore) Add(ctx context.Context, dinosaur *app.Dinosaur) (*app.D
 	return &added, nil
 }
 
+// dinosaurColumns maps app.DinosaurFields to the dinosaur columns.
+var dinosaurColumns = map[string]string{
+	"id":               "id",
+	"name":             "name",
+	"species":          "species",
+	"cageId":           "COALESCE(cage_id::text, '')",
+	"stage":            "stage",
+	"hatchDate":        "hatch_date",
+	"healthStatus":     "health_status",
+	"quarantined":      "quarantined",
+	"quarantineReason": "quarantine_reason",
+	"quarantineStart":  "quarantine_start",
+	"quarantineEnd":    "quarantine_end",
+	"createdAt":        "created_at",
+	"updatedAt":        "updated_at",
+	"deletedAt":        "deleted_at",
+}
+
+// quarantineColumns are the quarantine columns in the order of the app.Dinosaur fields.
+const quarantineColumns = "quarantined, quarantine_reason, quarantine_start, quarantine_end"
+
+// dinosaurFieldPointers returns the scan destinations of the dinosaur fields.
+func dinosaurFieldPointers(dinosaur *app.Dinosaur, fields []string) []any {
+	dest := make([]any, len(fields))
+	for i, field := range fields {
+		switch field {
+		case "id":
+			dest[i] = &dinosaur.ID
+		case "name":
+			dest[i] = &dinosaur.Name
+		case "species":
+			dest[i] = &dinosaur.Species
+		case "cageId":
+			dest[i] = &dinosaur.CageID
+		case "stage":
+			dest[i] = &dinosaur.Stage
+		case "hatchDate":
+			dest[i] = &dinosaur.HatchDate
+		case "healthStatus":
+			dest[i] = &dinosaur.HealthStatus
+		case "quarantined":
+			dest[i] = &dinosaur.Quarantined
+		case "quarantineReason":
+			dest[i] = &dinosaur.QuarantineReason
+		case "quarantineStart":
+			dest[i] = &dinosaur.QuarantineStart
+		case "quarantineEnd":
+			dest[i] = &dinosaur.QuarantineEnd
+		case "createdAt":
+			dest[i] = &dinosaur.CreatedAt
+		case "updatedAt":
+			dest[i] = &dinosaur.UpdatedAt
+		case "deletedAt":
+			dest[i] = &dinosaur.DeletedAt
+		}
+	}
+
+	return dest
+}
+
 // List dinosaurs.
-func (s *DinosaurStore) List(ctx context.Context, cageID string, species app.DinosaurSpecies) ([]app.Dinosaur, error) {
+// Dinosaurs are sorted by creation time unless the filter sets the sort order.
+func (s *DinosaurStore) List(ctx context.Context, filter app.DinosaurFilter) ([]app.Dinosaur, error) {
 	var dinosaurs []app.Dinosaur
-	query := `
-	SELECT id, name, species, cage_id, created_at, updated_at
-	  FROM dinosaurs`
+
+	fields := filter.Fields
+	if len(fields) == 0 {
+		fields = app.DinosaurFields
+	}
+	columns, err := selectList(fields, dinosaurColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	query := "SELECT " + columns + " FROM dinosaurs"
 
 	var (
 		where []string
 		args  []any
 	)
-	if cageID != "" {
+	if filter.CageID != "" {
 		where = append(where, "cage_id = ?")
-		args = append(args, cageID)
+		args = append(args, filter.CageID)
 	}
-	if species != "" {
-		where = append(where, "species = ?")
-		args = append(args, species)
+	if len(filter.CageIDs) > 0 {
+		where = append(where, "cage_id IN ("+placeholders(len(filter.CageIDs))+")")
+		for _, id := range filter.CageIDs {
+			args = append(args, id)
+		}
 	}
-
-	for i, predicate := range where {
-		if i == 0 {
-			query += " WHERE "
-		} else {
-			query += " AND "
+	if filter.ZoneID != "" {
+		where = append(where, `cage_id IN (
+			SELECT c.id
+			  FROM cages c
+			  JOIN sectors s ON s.id = c.sector_id
+			 WHERE s.zone_id = ?)`)
+		args = append(args, filter.ZoneID)
+	}
+	if len(filter.Species) > 0 {
+		where = append(where, "species IN ("+placeholders(len(filter.Species))+")")
+		for _, species := range filter.Species {
+			args = append(args, species)
 		}
+	}
+	if filter.Diet != "" {
+		species := filter.Diet.Species()
+		where = append(where, "species IN ("+placeholders(len(species))+")")
+		for _, species := range species {
+			args = append(args, species)
+		}
+	}
+	if len(filter.Stages) > 0 {
+		where = append(where, "stage IN ("+placeholders(len(filter.Stages))+")")
+		for _, stage := range filter.Stages {
+			args = append(args, stage)
+		}
+	}
+	if filter.HealthStatus != "" {
+		where = append(where, "health_status = ?")
+		args = append(args, filter.HealthStatus)
+	}
+	if filter.Quarantined != nil {
+		where = append(where, "quarantined = ?")
+		args = append(args, *filter.Quarantined)
+	}
+	if filter.NamePrefix != "" {
+		where = append(where, `name LIKE ? ESCAPE '\'`)
+		args = append(args, likePrefix(filter.NamePrefix))
+	}
+	where, args = timeRangePredicates(where, args, "hatch_date", filter.HatchDate)
+	where, args = timeRangePredicates(where, args, "created_at", filter.Created)
+	where, args = timeRangePredicates(where, args, "updated_at", filter.Updated)
+	if !filter.IncludeDeleted {
+		where = append(where, "deleted_at IS NULL")
+	}
 
-		query += strings.Replace(predicate, "?", "$"+strconv.Itoa(i+1), 1)
+	query += whereClause(where)
+
+	sort := filter.Sort
+	if len(sort) == 0 {
+		sort = []app.Sort{{Field: "createdAt"}}
+	}
+	orderBy, err := orderByClause(sort, dinosaurColumns)
+	if err != nil {
+		return nil, err
 	}
+	query += orderBy
 
 	rows, err := s.DB.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -89,14 +228,7 @@ func (s *DinosaurStore) List(ctx context.Context, cageID string, species app.Din
 
 	for rows.Next() {
 		var dinosaur app.Dinosaur
-		if err := rows.Scan(
-			&dinosaur.ID,
-			&dinosaur.Name,
-			&dinosaur.Species,
-			&dinosaur.CageID,
-			&dinosaur.CreatedAt,
-			&dinosaur.UpdatedAt,
-		); err != nil {
+		if err := rows.Scan(dinosaurFieldPointers(&dinosaur, fields)...); err != nil {
 			return nil, err
 		}
 
@@ -110,11 +242,12 @@ func (s *DinosaurStore) List(ctx context.Context, cageID string, species app.Din
 }
 
 // Get a dinosaur by id.
-func (s *DinosaurStore) Get(ctx context.Context, id string) (*app.Dinosaur, error) {
-	return getDinosaur(ctx, s.DB, id)
+func (s *DinosaurStore) Get(ctx context.Context, id string, opts app.GetOptions) (*app.Dinosaur, error) {
+	return getDinosaur(ctx, s.DB, id, opts)
 }
 
 // Move a dinosaur to a different cage.
+// app.ErrNotInCage is returned if the dinosaur doesn't live in a cage.
 func (s *DinosaurStore) Move(ctx context.Context, id string, cageID string) (*app.Dinosaur, error) {
 	tx, err := s.DB.BeginTx(ctx, nil)
 	if err != nil {
@@ -122,22 +255,78 @@ func (s *DinosaurStore) Move(ctx context.Context, id string, cageID string) (*ap
 	}
 	defer tx.Rollback() // nolint:errcheck
 
-	dinosaur, err := getDinosaur(ctx, tx, id)
+	dinosaur, err := getDinosaur(ctx, tx, id, app.GetOptions{})
 	if err != nil {
 		return nil, err
 	}
 
-	err = checkCageCompatibility(ctx, tx, cageID, dinosaur.Species)
+	if !dinosaur.Stage.InCage() {
+		return nil, app.ErrNotInCage
+	}
+
+	err = checkCageCompatibility(ctx, tx, cageID, dinosaur.Species, dinosaur.Quarantined)
 	if err != nil {
 		return nil, err
 	}
 
 	query := `
 	UPDATE dinosaurs
-	   SET cage_id = $1, updated_at = NOW()
+	   SET cage_id = $1,
+	       placed_at = CASE WHEN cage_id IS DISTINCT FROM $1 THEN NOW() ELSE placed_at END,
+	       updated_at = NOW()
 	 WHERE id = $2
 	RETURNING cage_id, updated_at`
-	err = s.DB.QueryRowContext(ctx, query, cageID, id).Scan(
+	err = tx.QueryRowContext(ctx, query, cageID, id).Scan(
+		&dinosaur.CageID,
+		&dinosaur.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return dinosaur, nil
+}
+
+// Update applies a patch to a dinosaur.
+// All changes are applied together or not at all.
+func (s *DinosaurStore) Update(ctx context.Context, id string, patch app.DinosaurPatch) (*app.Dinosaur, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() // nolint:errcheck
+
+	dinosaur, err := getDinosaur(ctx, tx, id, app.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if patch.CageID != nil && *patch.CageID != dinosaur.CageID {
+		if !dinosaur.Stage.InCage() {
+			return nil, app.ErrNotInCage
+		}
+
+		err = checkCageCompatibility(ctx, tx, *patch.CageID, dinosaur.Species, dinosaur.Quarantined)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	query := `
+	UPDATE dinosaurs
+	   SET name = COALESCE($1, name),
+	       cage_id = COALESCE($2, cage_id),
+	       placed_at = CASE WHEN cage_id IS DISTINCT FROM COALESCE($2, cage_id) THEN NOW() ELSE placed_at END,
+	       updated_at = NOW()
+	 WHERE id = $3
+	RETURNING name, COALESCE(cage_id::text, ''), updated_at`
+
+	err = tx.QueryRowContext(ctx, query, patch.Name, patch.CageID, id).Scan(
+		&dinosaur.Name,
 		&dinosaur.CageID,
 		&dinosaur.UpdatedAt,
 	)
@@ -152,11 +341,14 @@ func (s *DinosaurStore) Move(ctx context.Context, id string, cageID string) (*ap
 	return dinosaur, nil
 }
 
-// Delete a dinosaur.
+// Delete soft deletes a dinosaur.
+// The dinosaur no longer occupies its cage and can be restored until it's purged.
 func (s *DinosaurStore) Delete(ctx context.Context, id string) error {
 	query := `
-	DELETE FROM dinosaurs
-	 WHERE id = $1`
+	UPDATE dinosaurs
+	   SET deleted_at = NOW(), updated_at = NOW()
+	 WHERE id = $1
+	   AND deleted_at IS NULL`
 	res, err := s.DB.ExecContext(ctx, query, id)
 	if err != nil {
 		return err
@@ -174,22 +366,573 @@ func (s *DinosaurStore) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
-// getDinosaur gets a dinosaur by id.
-func getDinosaur(ctx context.Context, q queryable, id string) (*app.Dinosaur, error) {
-	var dinosaur app.Dinosaur
+// Restore brings back a soft deleted dinosaur into its cage.
+// The cage has to accept the dinosaur as if it was added anew,
+// and if the cage itself is deleted the dinosaur can't be restored.
+// A dinosaur that doesn't live in a cage is restored as is.
+// Restoring a dinosaur that is not deleted is a no-op.
+func (s *DinosaurStore) Restore(ctx context.Context, id string) (*app.Dinosaur, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() // nolint:errcheck
+
+	dinosaur, err := getDinosaur(ctx, tx, id, app.GetOptions{IncludeDeleted: true})
+	if err != nil {
+		return nil, err
+	}
+
+	if dinosaur.DeletedAt == nil {
+		return dinosaur, nil // Nothing to do.
+	}
+
+	if dinosaur.CageID != "" {
+		err = checkCageCompatibility(ctx, tx, dinosaur.CageID, dinosaur.Species, dinosaur.Quarantined)
+		if err != nil {
+			if err == app.ErrNotFound {
+				return nil, app.ErrConflict // The cage is deleted.
+			}
+
+			return nil, err
+		}
+	}
+
 	query := `
-	SELECT id, name, species, cage_id, created_at, updated_at
-	  FROM dinosaurs
-	 WHERE id = $1`
+	UPDATE dinosaurs
+	   SET deleted_at = NULL,
+	       placed_at = CASE WHEN cage_id IS NOT NULL THEN NOW() END,
+	       updated_at = NOW()
+	 WHERE id = $1
+	RETURNING deleted_at, updated_at`
+	err = tx.QueryRowContext(ctx, query, id).Scan(
+		&dinosaur.DeletedAt,
+		&dinosaur.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
 
-	err := q.QueryRowContext(ctx, query, id).Scan(
-		&dinosaur.ID,
-		&dinosaur.Name,
-		&dinosaur.Species,
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return dinosaur, nil
+}
+
+// Quarantine moves a dinosaur to a quarantine cage and flags it as quarantined.
+// The cage has to accept the dinosaur as a quarantined one,
+// the quarantine starts now unless the start is set.
+// app.ErrConflict is returned if the dinosaur is quarantined already
+// and app.ErrNotInCage if it doesn't live in a cage.
+func (s *DinosaurStore) Quarantine(ctx context.Context, id string, quarantine app.Quarantine) (*app.Dinosaur, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() // nolint:errcheck
+
+	dinosaur, err := getDinosaur(ctx, tx, id, app.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if dinosaur.Quarantined {
+		return nil, app.ErrConflict
+	}
+	if !dinosaur.Stage.InCage() {
+		return nil, app.ErrNotInCage
+	}
+
+	err = checkCageCompatibility(ctx, tx, quarantine.CageID, dinosaur.Species, true)
+	if err != nil {
+		return nil, err
+	}
+
+	start := quarantine.Start
+	if start.IsZero() {
+		start = time.Now()
+	}
+	var end *time.Time
+	if !quarantine.End.IsZero() {
+		end = &quarantine.End
+	}
+
+	query := `
+	UPDATE dinosaurs
+	   SET cage_id = $1,
+	       placed_at = NOW(),
+	       quarantined = TRUE,
+	       quarantine_reason = $2,
+	       quarantine_start = $3,
+	       quarantine_end = $4,
+	       updated_at = NOW()
+	 WHERE id = $5
+	RETURNING cage_id, ` + quarantineColumns + `, updated_at`
+	err = tx.QueryRowContext(ctx, query, quarantine.CageID, quarantine.Reason, start, end, id).Scan(
+		&dinosaur.CageID,
+		&dinosaur.Quarantined,
+		&dinosaur.QuarantineReason,
+		&dinosaur.QuarantineStart,
+		&dinosaur.QuarantineEnd,
+		&dinosaur.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return dinosaur, nil
+}
+
+// Release ends the quarantine of a dinosaur and moves it to a regular cage.
+// app.ErrConflict is returned if the dinosaur isn't quarantined.
+func (s *DinosaurStore) Release(ctx context.Context, id string, cageID string) (*app.Dinosaur, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() // nolint:errcheck
+
+	dinosaur, err := getDinosaur(ctx, tx, id, app.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if !dinosaur.Quarantined {
+		return nil, app.ErrConflict
+	}
+
+	err = checkCageCompatibility(ctx, tx, cageID, dinosaur.Species, false)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+	UPDATE dinosaurs
+	   SET cage_id = $1,
+	       placed_at = NOW(),
+	       quarantined = FALSE,
+	       quarantine_reason = '',
+	       quarantine_start = NULL,
+	       quarantine_end = NULL,
+	       updated_at = NOW()
+	 WHERE id = $2
+	RETURNING cage_id, ` + quarantineColumns + `, updated_at`
+	err = tx.QueryRowContext(ctx, query, cageID, id).Scan(
+		&dinosaur.CageID,
+		&dinosaur.Quarantined,
+		&dinosaur.QuarantineReason,
+		&dinosaur.QuarantineStart,
+		&dinosaur.QuarantineEnd,
+		&dinosaur.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return dinosaur, nil
+}
+
+// AddEgg lays an egg into the hatchery.
+// The egg doesn't live in a cage until it hatches.
+func (s *DinosaurStore) AddEgg(ctx context.Context, egg app.Egg) (*app.Dinosaur, error) {
+	var added app.Dinosaur
+	query := `
+	INSERT INTO dinosaurs (name, species, stage, hatch_date)
+	VALUES ($1, $2, $3, $4)
+	RETURNING id, name, species, stage, hatch_date, health_status, ` + quarantineColumns + `, created_at, updated_at`
+	err := s.DB.QueryRowContext(ctx, query, egg.Name, egg.Species, app.LifecycleStageEgg, egg.HatchDate).Scan(
+		&added.ID,
+		&added.Name,
+		&added.Species,
+		&added.Stage,
+		&added.HatchDate,
+		&added.HealthStatus,
+		&added.Quarantined,
+		&added.QuarantineReason,
+		&added.QuarantineStart,
+		&added.QuarantineEnd,
+		&added.CreatedAt,
+		&added.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &added, nil
+}
+
+// Hatch hatches an egg into a cage as a juvenile.
+// The cage has to accept the hatchling as if it was added anew,
+// the hatch date is set to now.
+// app.ErrIllegalTransition is returned if the dinosaur is not an egg.
+func (s *DinosaurStore) Hatch(ctx context.Context, id string, cageID string) (*app.Dinosaur, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() // nolint:errcheck
+
+	dinosaur, err := getDinosaur(ctx, tx, id, app.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if dinosaur.Stage != app.LifecycleStageEgg {
+		return nil, app.ErrIllegalTransition
+	}
+
+	err = checkCageCompatibility(ctx, tx, cageID, dinosaur.Species, false)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+	UPDATE dinosaurs
+	   SET cage_id = $1, placed_at = NOW(), stage = $2, hatch_date = NOW(), updated_at = NOW()
+	 WHERE id = $3
+	RETURNING cage_id, stage, hatch_date, updated_at`
+	err = tx.QueryRowContext(ctx, query, cageID, app.LifecycleStageJuvenile, id).Scan(
 		&dinosaur.CageID,
-		&dinosaur.CreatedAt,
+		&dinosaur.Stage,
+		&dinosaur.HatchDate,
 		&dinosaur.UpdatedAt,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = recordLifecycleEvent(ctx, tx, &app.LifecycleEvent{
+		DinosaurID: id,
+		From:       app.LifecycleStageEgg,
+		To:         app.LifecycleStageJuvenile,
+		CageID:     cageID,
+		OccurredAt: *dinosaur.HatchDate,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return dinosaur, nil
+}
+
+// Transition moves a dinosaur to the next stage of its lifecycle.
+// A deceased or transferred out dinosaur leaves its cage and its quarantine
+// ends, but it stays on the records. An egg hatches only into a cage, see Hatch.
+// app.ErrIllegalTransition is returned if the dinosaur can't transition to the stage.
+func (s *DinosaurStore) Transition(ctx context.Context, id string, transition app.LifecycleTransition) (*app.LifecycleEvent, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() // nolint:errcheck
+
+	dinosaur, err := getDinosaur(ctx, tx, id, app.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if !dinosaur.Stage.CanBecome(transition.Stage) || transition.Stage.InCage() && !dinosaur.Stage.InCage() {
+		return nil, app.ErrIllegalTransition
+	}
+
+	query := `
+	UPDATE dinosaurs
+	   SET stage = $1, updated_at = NOW()
+	 WHERE id = $2`
+	if !transition.Stage.InCage() {
+		query = `
+		UPDATE dinosaurs
+		   SET stage = $1,
+		       cage_id = NULL,
+		       placed_at = NULL,
+		       quarantined = FALSE,
+		       quarantine_reason = '',
+		       quarantine_start = NULL,
+		       quarantine_end = NULL,
+		       updated_at = NOW()
+		 WHERE id = $2`
+	}
+	if _, err := tx.ExecContext(ctx, query, transition.Stage, id); err != nil {
+		return nil, err
+	}
+
+	occurredAt := transition.OccurredAt
+	if occurredAt.IsZero() {
+		occurredAt = time.Now()
+	}
+
+	event, err := recordLifecycleEvent(ctx, tx, &app.LifecycleEvent{
+		DinosaurID: id,
+		From:       dinosaur.Stage,
+		To:         transition.Stage,
+		CageID:     dinosaur.CageID,
+		Notes:      transition.Notes,
+		OccurredAt: occurredAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// LifecycleEvents lists the lifecycle events of a dinosaur in chronological order.
+// Events of a deleted dinosaur can still be listed.
+// app.ErrNotFound is returned if the dinosaur doesn't exist.
+func (s *DinosaurStore) LifecycleEvents(ctx context.Context, id string) ([]app.LifecycleEvent, error) {
+	if _, err := getDinosaur(ctx, s.DB, id, app.GetOptions{Fields: []string{"id"}, IncludeDeleted: true}); err != nil {
+		return nil, err
+	}
+
+	query := "SELECT" + lifecycleEventColumns + " FROM lifecycle_events WHERE dinosaur_id = $1 ORDER BY occurred_at, created_at"
+
+	rows, err := s.DB.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []app.LifecycleEvent
+	for rows.Next() {
+		var event app.LifecycleEvent
+		if err := rows.Scan(lifecycleEventFieldPointers(&event)...); err != nil {
+			return nil, err
+		}
+
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// lifecycleEventColumns are the selected columns of a lifecycle event.
+const lifecycleEventColumns = `
+	id, dinosaur_id, from_stage, to_stage, COALESCE(cage_id::text, ''), notes, occurred_at, created_at`
+
+func lifecycleEventFieldPointers(event *app.LifecycleEvent) []any {
+	return []any{
+		&event.ID,
+		&event.DinosaurID,
+		&event.From,
+		&event.To,
+		&event.CageID,
+		&event.Notes,
+		&event.OccurredAt,
+		&event.CreatedAt,
+	}
+}
+
+// recordLifecycleEvent adds an event to the lifecycle of a dinosaur.
+func recordLifecycleEvent(ctx context.Context, q queryable, event *app.LifecycleEvent) (*app.LifecycleEvent, error) {
+	query := `
+	INSERT INTO lifecycle_events (dinosaur_id, from_stage, to_stage, cage_id, notes, occurred_at)
+	VALUES ($1, $2, $3, NULLIF($4, '')::uuid, $5, $6)
+	RETURNING` + lifecycleEventColumns
+
+	var recorded app.LifecycleEvent
+	err := q.QueryRowContext(ctx, query,
+		event.DinosaurID,
+		event.From,
+		event.To,
+		event.CageID,
+		event.Notes,
+		event.OccurredAt,
+	).Scan(lifecycleEventFieldPointers(&recorded)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &recorded, nil
+}
+
+// AddHealthRecord adds a health record of a dinosaur.
+// A record that sets a status changes the health status of the dinosaur
+// unless a more recent record set one already, so records can be added
+// after the fact. The record time defaults to now.
+// app.ErrNotFound is returned if the dinosaur doesn't exist.
+func (s *DinosaurStore) AddHealthRecord(ctx context.Context, record *app.HealthRecord) (*app.HealthRecord, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() // nolint:errcheck
+
+	// Lock the dinosaur, so concurrent records don't race on the status.
+	query := "SELECT id FROM dinosaurs WHERE id = $1 AND deleted_at IS NULL FOR UPDATE"
+	if err := tx.QueryRowContext(ctx, query, record.DinosaurID).Scan(new(string)); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, app.ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	recordedAt := record.RecordedAt
+	if recordedAt.IsZero() {
+		recordedAt = time.Now()
+	}
+
+	var added app.HealthRecord
+	query = `
+	INSERT INTO health_records (dinosaur_id, kind, author, weight_kg, notes, status, recorded_at)
+	VALUES ($1, $2, $3, NULLIF($4::double precision, 0), $5, NULLIF($6, ''), $7)
+	RETURNING` + healthRecordColumns
+	err = tx.QueryRowContext(ctx, query,
+		record.DinosaurID,
+		record.Kind,
+		record.Author,
+		record.WeightKg,
+		record.Notes,
+		record.Status,
+		recordedAt,
+	).Scan(healthRecordFieldPointers(&added)...)
+	if err != nil {
+		return nil, err
+	}
+
+	if !added.Status.IsUnspecified() {
+		query = `
+		UPDATE dinosaurs
+		   SET health_status = (
+		       SELECT status
+		         FROM health_records
+		        WHERE dinosaur_id = $1
+		          AND status IS NOT NULL
+		        ORDER BY recorded_at DESC, created_at DESC
+		        LIMIT 1),
+		       updated_at = NOW()
+		 WHERE id = $1`
+		if _, err := tx.ExecContext(ctx, query, added.DinosaurID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &added, nil
+}
+
+// HealthRecords lists the health records of a dinosaur in chronological order.
+// Records of a deleted dinosaur can still be listed.
+// app.ErrNotFound is returned if the dinosaur doesn't exist.
+func (s *DinosaurStore) HealthRecords(ctx context.Context, id string, filter app.HealthRecordFilter) ([]app.HealthRecord, error) {
+	if _, err := getDinosaur(ctx, s.DB, id, app.GetOptions{Fields: []string{"id"}, IncludeDeleted: true}); err != nil {
+		return nil, err
+	}
+
+	where := []string{"dinosaur_id = ?"}
+	args := []any{id}
+	if filter.Kind != "" {
+		where = append(where, "kind = ?")
+		args = append(args, filter.Kind)
+	}
+	where, args = timeRangePredicates(where, args, "recorded_at", filter.Recorded)
+
+	query := "SELECT" + healthRecordColumns + " FROM health_records" + whereClause(where) + " ORDER BY recorded_at, id"
+
+	rows, err := s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []app.HealthRecord
+	for rows.Next() {
+		var record app.HealthRecord
+		if err := rows.Scan(healthRecordFieldPointers(&record)...); err != nil {
+			return nil, err
+		}
+
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// healthRecordColumns are the selected columns of a health record.
+const healthRecordColumns = `
+	id, dinosaur_id, kind, author, COALESCE(weight_kg, 0), notes, COALESCE(status, ''), recorded_at, created_at`
+
+func healthRecordFieldPointers(record *app.HealthRecord) []any {
+	return []any{
+		&record.ID,
+		&record.DinosaurID,
+		&record.Kind,
+		&record.Author,
+		&record.WeightKg,
+		&record.Notes,
+		&record.Status,
+		&record.RecordedAt,
+		&record.CreatedAt,
+	}
+}
+
+// dinosaurOnRecord is a predicate of a dinosaur d that has to be kept for the record,
+// i.e. involved in incidents, a parent or a member of a breeding pair.
+const dinosaurOnRecord = `
+	EXISTS (SELECT 1 FROM incident_dinosaurs i WHERE i.dinosaur_id = d.id)
+	OR EXISTS (SELECT 1 FROM dinosaur_parents p WHERE p.parent_id = d.id)
+	OR EXISTS (SELECT 1 FROM breeding_pairs b WHERE d.id IN (b.sire_id, b.dam_id))`
+
+// Purge permanently deletes the dinosaurs soft deleted before the given time.
+// The dinosaurs involved in incidents or in the lineage of others,
+// as parents or breeding pairs, are kept for the record.
+func (s *DinosaurStore) Purge(ctx context.Context, before time.Time) (int64, error) {
+	query := `
+	DELETE FROM dinosaurs d
+	 WHERE d.deleted_at < $1
+	   AND NOT (` + dinosaurOnRecord + `)`
+	res, err := s.DB.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+// getDinosaur gets a dinosaur by id.
+func getDinosaur(ctx context.Context, q queryable, id string, opts app.GetOptions) (*app.Dinosaur, error) {
+	fields := opts.Fields
+	if len(fields) == 0 {
+		fields = app.DinosaurFields
+	}
+	columns, err := selectList(fields, dinosaurColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	query := "SELECT " + columns + " FROM dinosaurs WHERE id = $1"
+	if !opts.IncludeDeleted {
+		query += " AND deleted_at IS NULL"
+	}
+
+	var dinosaur app.Dinosaur
+	err = q.QueryRowContext(ctx, query, id).Scan(dinosaurFieldPointers(&dinosaur, fields)...)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, app.ErrNotFound
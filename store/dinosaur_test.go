@@ -5,7 +5,9 @@ package store
 
 import (
 	"context"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/pmatseykanets/jurassic/app"
@@ -31,7 +33,7 @@ func TestDinosaurStore(t *testing.T) {
 	}
 
 	// Make sure listing cage dinosaurs comes back empty.
-	list, err := dinosaurStore.List(ctx, app.IDUnspecified, app.DinosaurSpeciesUnspecified)
+	list, err := dinosaurStore.List(ctx, app.DinosaurFilter{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -70,7 +72,7 @@ func TestDinosaurStore(t *testing.T) {
 	}
 
 	// Make sure listing cage dinosaurs comes back with one dinosaur.
-	list, err = dinosaurStore.List(ctx, cage1.ID, app.DinosaurSpeciesUnspecified)
+	list, err = dinosaurStore.List(ctx, app.DinosaurFilter{CageID: cage1.ID})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -124,8 +126,57 @@ func TestDinosaurStore(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	// Rename the dinosaur2 and move it back in one go.
+	name := "Tyrannosaurus Tex"
+	dinosaur2, err = dinosaurStore.Update(ctx, dinosaur2.ID, app.DinosaurPatch{Name: &name, CageID: &cage1.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := name, dinosaur2.Name; want != got {
+		t.Errorf("Expected Name %s got %s", want, got)
+	}
+	if want, got := cage1.ID, dinosaur2.CageID; want != got {
+		t.Errorf("Expected CageID %s got %s", want, got)
+	}
+
+	// Make sure nothing changes when the cage is not compatible.
+	brachiosaurus, err := dinosaurStore.Add(ctx, &app.Dinosaur{
+		Name:    "Brachiosaurus",
+		Species: app.DinosaurSpeciesBrachiosaurus,
+		CageID:  cage2.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	name = "Renamed"
+	_, err = dinosaurStore.Update(ctx, brachiosaurus.ID, app.DinosaurPatch{Name: &name, CageID: &cage1.ID})
+	if want, got := app.ErrCapacityExceeded, err; want != got {
+		t.Fatalf("Expected error %v got %v", want, got)
+	}
+
+	brachiosaurus, err = dinosaurStore.Get(ctx, brachiosaurus.ID, app.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "Brachiosaurus", brachiosaurus.Name; want != got {
+		t.Errorf("Expected Name %s got %s", want, got)
+	}
+
+	err = dinosaurStore.Delete(ctx, brachiosaurus.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// And move the dinosaur2 to the new cage again.
+	dinosaur2, err = dinosaurStore.Move(ctx, dinosaur2.ID, cage2.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	// List all dinosaurs.
-	list, err = dinosaurStore.List(ctx, app.IDUnspecified, app.DinosaurSpeciesUnspecified)
+	list, err = dinosaurStore.List(ctx, app.DinosaurFilter{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -166,7 +217,7 @@ func TestDinosaurStore(t *testing.T) {
 	}
 
 	// Make sure we no longer see the deleted dinosaur.
-	list, err = dinosaurStore.List(ctx, app.IDUnspecified, app.DinosaurSpeciesUnspecified)
+	list, err = dinosaurStore.List(ctx, app.DinosaurFilter{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -175,8 +226,640 @@ func TestDinosaurStore(t *testing.T) {
 		t.Fatalf("Expected dinosaurs %d got %d", want, got)
 	}
 
-	_, err = dinosaurStore.Get(ctx, dinosaur2.ID)
+	_, err = dinosaurStore.Get(ctx, dinosaur2.ID, app.GetOptions{})
 	if want, got := app.ErrNotFound, err; want != got {
 		t.Fatalf("Expected error %s got %s", want, got)
 	}
 }
+
+func TestDinosaurStoreSoftDelete(t *testing.T) {
+	setUpTestDB(t)
+	t.Cleanup(func() {
+		testDB.Exec("TRUNCATE TABLE cages CASCADE")
+	})
+
+	ctx := context.Background()
+	cageStore := CageStore{DB: testDB}
+	dinosaurStore := DinosaurStore{DB: testDB}
+
+	cage, err := cageStore.Add(ctx, &app.Cage{
+		Capacity: 1,
+		Status:   app.CageStatusActive,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rex, err := dinosaurStore.Add(ctx, &app.Dinosaur{
+		Name:    "Rex",
+		Species: app.DinosaurSpeciesTyrannosaurus,
+		CageID:  cage.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = dinosaurStore.Delete(ctx, rex.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Deleting it again should fail.
+	err = dinosaurStore.Delete(ctx, rex.ID)
+	if want, got := app.ErrNotFound, err; want != got {
+		t.Fatalf("Expected error %v got %v", want, got)
+	}
+
+	// The deleted dinosaur can still be seen when asked for.
+	rex, err = dinosaurStore.Get(ctx, rex.ID, app.GetOptions{IncludeDeleted: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rex.DeletedAt == nil {
+		t.Fatal("Expected DeletedAt got empty")
+	}
+
+	list, err := dinosaurStore.List(ctx, app.DinosaurFilter{IncludeDeleted: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 1, len(list); want != got {
+		t.Fatalf("Expected dinosaurs %d got %d", want, got)
+	}
+
+	// The deleted dinosaur doesn't occupy the cage anymore.
+	cage, err = cageStore.Get(ctx, cage.ID, app.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 0, cage.Occupancy; want != got {
+		t.Fatalf("Expected Occupancy %d got %d", want, got)
+	}
+
+	// Its spot is taken by another dinosaur, so it can't be restored.
+	pex, err := dinosaurStore.Add(ctx, &app.Dinosaur{
+		Name:    "Pex",
+		Species: app.DinosaurSpeciesTyrannosaurus,
+		CageID:  cage.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = dinosaurStore.Restore(ctx, rex.ID)
+	if want, got := app.ErrCapacityExceeded, err; want != got {
+		t.Fatalf("Expected error %v got %v", want, got)
+	}
+
+	// Once the spot is free again the dinosaur can be restored.
+	err = dinosaurStore.Delete(ctx, pex.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rex, err = dinosaurStore.Restore(ctx, rex.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rex.DeletedAt != nil {
+		t.Fatalf("Expected DeletedAt to be empty got %v", rex.DeletedAt)
+	}
+
+	// A dinosaur can't be restored into a deleted cage.
+	err = dinosaurStore.Delete(ctx, rex.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cageStore.Delete(ctx, cage.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = dinosaurStore.Restore(ctx, rex.ID)
+	if want, got := app.ErrConflict, err; want != got {
+		t.Fatalf("Expected error %v got %v", want, got)
+	}
+
+	// Purging removes the dinosaurs for good.
+	n, err := dinosaurStore.Purge(ctx, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := int64(2), n; want != got {
+		t.Fatalf("Expected purged %d got %d", want, got)
+	}
+
+	_, err = dinosaurStore.Get(ctx, rex.ID, app.GetOptions{IncludeDeleted: true})
+	if want, got := app.ErrNotFound, err; want != got {
+		t.Fatalf("Expected error %v got %v", want, got)
+	}
+}
+
+func TestDinosaurStoreListFilters(t *testing.T) {
+	setUpTestDB(t)
+	t.Cleanup(func() {
+		testDB.Exec("TRUNCATE TABLE cages CASCADE")
+	})
+
+	ctx := context.Background()
+	cageStore := CageStore{DB: testDB}
+	dinosaurStore := DinosaurStore{DB: testDB}
+
+	carnivores, err := cageStore.Add(ctx, &app.Cage{Capacity: 5, Status: app.CageStatusActive})
+	if err != nil {
+		t.Fatal(err)
+	}
+	herbivores, err := cageStore.Add(ctx, &app.Cage{Capacity: 5, Status: app.CageStatusActive})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var added []*app.Dinosaur
+	for _, d := range []app.Dinosaur{
+		{Name: "Blue", Species: app.DinosaurSpeciesVelociraptor, CageID: carnivores.ID},
+		{Name: "B_ta", Species: app.DinosaurSpeciesVelociraptor, CageID: carnivores.ID},
+		{Name: "Sarah", Species: app.DinosaurSpeciesTriceratops, CageID: herbivores.ID},
+		{Name: "Bumpy", Species: app.DinosaurSpeciesAnkylosaurus, CageID: herbivores.ID},
+		{Name: "Stella", Species: app.DinosaurSpeciesStegosaurus, CageID: herbivores.ID},
+	} {
+		dinosaur, err := dinosaurStore.Add(ctx, &d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		added = append(added, dinosaur)
+	}
+
+	names := func(dinosaurs []app.Dinosaur) string {
+		var names []string
+		for _, d := range dinosaurs {
+			names = append(names, d.Name)
+		}
+		return strings.Join(names, ",")
+	}
+
+	tests := []struct {
+		name   string
+		filter app.DinosaurFilter
+		names  string
+	}{
+		{"default order", app.DinosaurFilter{}, "Blue,B_ta,Sarah,Bumpy,Stella"},
+		{"several species", app.DinosaurFilter{
+			Species: []app.DinosaurSpecies{app.DinosaurSpeciesTriceratops, app.DinosaurSpeciesStegosaurus},
+		}, "Sarah,Stella"},
+		{"diet", app.DinosaurFilter{Diet: app.DinosaurTypeCarnivore}, "Blue,B_ta"},
+		{"cage ids", app.DinosaurFilter{CageIDs: []string{carnivores.ID, uuid.NewString()}}, "Blue,B_ta"},
+		{"name prefix", app.DinosaurFilter{NamePrefix: "B"}, "Blue,B_ta,Bumpy"},
+		{"name prefix is not a pattern", app.DinosaurFilter{NamePrefix: "B_"}, "B_ta"},
+		{"species and diet", app.DinosaurFilter{
+			Species: []app.DinosaurSpecies{app.DinosaurSpeciesTriceratops},
+			Diet:    app.DinosaurTypeCarnivore,
+		}, ""},
+		{"created after", app.DinosaurFilter{Created: app.TimeRange{After: added[2].CreatedAt}}, "Bumpy,Stella"},
+		{"created before", app.DinosaurFilter{Created: app.TimeRange{Before: added[1].CreatedAt}}, "Blue"},
+		{"updated range", app.DinosaurFilter{
+			Updated: app.TimeRange{After: added[0].UpdatedAt, Before: added[3].UpdatedAt},
+		}, "B_ta,Sarah"},
+		{"sort by name", app.DinosaurFilter{
+			CageID: herbivores.ID,
+			Sort:   []app.Sort{{Field: "name"}},
+		}, "Bumpy,Sarah,Stella"},
+		{"sort by species and name descending", app.DinosaurFilter{
+			CageID: herbivores.ID,
+			Sort:   []app.Sort{{Field: "species", Desc: true}, {Field: "name", Desc: true}},
+		}, "Sarah,Stella,Bumpy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := dinosaurStore.List(ctx, tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want, got := tt.names, names(list); want != got {
+				t.Fatalf("Expected %s got %s", want, got)
+			}
+		})
+	}
+
+	_, err = dinosaurStore.List(ctx, app.DinosaurFilter{Sort: []app.Sort{{Field: "capacity"}}})
+	if err == nil {
+		t.Fatal("Expected error got nil")
+	}
+}
+
+func TestDinosaurStoreFields(t *testing.T) {
+	setUpTestDB(t)
+	t.Cleanup(func() {
+		testDB.Exec("TRUNCATE TABLE cages CASCADE")
+	})
+
+	ctx := context.Background()
+	cageStore := CageStore{DB: testDB}
+	dinosaurStore := DinosaurStore{DB: testDB}
+
+	cage, err := cageStore.Add(ctx, &app.Cage{Capacity: 2, Status: app.CageStatusActive})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sarah, err := dinosaurStore.Add(ctx, &app.Dinosaur{Name: "Sarah", Species: app.DinosaurSpeciesTriceratops, CageID: cage.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := dinosaurStore.List(ctx, app.DinosaurFilter{Fields: []string{"name", "cageId"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 1, len(list); want != got {
+		t.Fatalf("Expected dinosaurs %d got %d", want, got)
+	}
+	if want, got := (app.Dinosaur{Name: "Sarah", CageID: cage.ID}), list[0]; want != got {
+		t.Fatalf("Expected %+v got %+v", want, got)
+	}
+
+	got, err := dinosaurStore.Get(ctx, sarah.ID, app.GetOptions{Fields: []string{"species"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (app.Dinosaur{Species: app.DinosaurSpeciesTriceratops}); want != *got {
+		t.Fatalf("Expected %+v got %+v", want, *got)
+	}
+
+	if _, err := dinosaurStore.List(ctx, app.DinosaurFilter{Fields: []string{"capacity"}}); err == nil {
+		t.Fatal("Expected error got nil")
+	}
+}
+
+func TestDinosaurStoreHealthRecords(t *testing.T) {
+	setUpTestDB(t)
+	t.Cleanup(func() {
+		testDB.Exec("TRUNCATE TABLE cages CASCADE")
+	})
+
+	ctx := context.Background()
+	cageStore := CageStore{DB: testDB}
+	dinosaurStore := DinosaurStore{DB: testDB}
+
+	cage, err := cageStore.Add(ctx, &app.Cage{Capacity: 2, Status: app.CageStatusActive})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rex, err := dinosaurStore.Add(ctx, &app.Dinosaur{Name: "Rexy", Species: app.DinosaurSpeciesTyrannosaurus, CageID: cage.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := app.HealthStatusHealthy, rex.HealthStatus; want != got {
+		t.Fatalf("Expected HealthStatus %s got %s", want, got)
+	}
+
+	now := time.Now()
+	diagnosis, err := dinosaurStore.AddHealthRecord(ctx, &app.HealthRecord{
+		DinosaurID: rex.ID,
+		Kind:       app.HealthRecordKindDiagnosis,
+		Author:     "Dr. Harding",
+		Notes:      "Stomach ache",
+		Status:     app.HealthStatusSick,
+		RecordedAt: now.Add(-time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := app.HealthStatusSick, diagnosis.Status; want != got {
+		t.Fatalf("Expected Status %s got %s", want, got)
+	}
+	// An older record added after the fact doesn't override the current status.
+	_, err = dinosaurStore.AddHealthRecord(ctx, &app.HealthRecord{
+		DinosaurID: rex.ID,
+		Kind:       app.HealthRecordKindCheckup,
+		Author:     "Dr. Harding",
+		Status:     app.HealthStatusUnderObservation,
+		RecordedAt: now.Add(-2 * time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	weight, err := dinosaurStore.AddHealthRecord(ctx, &app.HealthRecord{
+		DinosaurID: rex.ID,
+		Kind:       app.HealthRecordKindWeight,
+		Author:     "Dr. Harding",
+		WeightKg:   7200.5,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 7200.5, weight.WeightKg; want != got {
+		t.Fatalf("Expected WeightKg %v got %v", want, got)
+	}
+	if want, got := app.HealthStatusUnspecified, weight.Status; want != got {
+		t.Fatalf("Expected no Status got %s", got)
+	}
+	_, err = dinosaurStore.AddHealthRecord(ctx, &app.HealthRecord{DinosaurID: uuid.NewString(), Kind: app.HealthRecordKindNote, Author: "Dr. Harding"})
+	if err != app.ErrNotFound {
+		t.Fatalf("Expected error %v got %v", app.ErrNotFound, err)
+	}
+
+	got, err := dinosaurStore.Get(ctx, rex.ID, app.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := app.HealthStatusSick, got.HealthStatus; want != got {
+		t.Fatalf("Expected HealthStatus %s got %s", want, got)
+	}
+
+	records, err := dinosaurStore.HealthRecords(ctx, rex.ID, app.HealthRecordFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 3, len(records); want != got {
+		t.Fatalf("Expected records %d got %d", want, got)
+	}
+	if want, got := app.HealthRecordKindCheckup, records[0].Kind; want != got {
+		t.Fatalf("Expected the first record %s got %s", want, got)
+	}
+	records, err = dinosaurStore.HealthRecords(ctx, rex.ID, app.HealthRecordFilter{
+		Kind:     app.HealthRecordKindWeight,
+		Recorded: app.TimeRange{After: now.Add(-time.Minute)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 1, len(records); want != got {
+		t.Fatalf("Expected records %d got %d", want, got)
+	}
+	if _, err := dinosaurStore.HealthRecords(ctx, uuid.NewString(), app.HealthRecordFilter{}); err != app.ErrNotFound {
+		t.Fatalf("Expected error %v got %v", app.ErrNotFound, err)
+	}
+
+	sick, err := dinosaurStore.List(ctx, app.DinosaurFilter{HealthStatus: app.HealthStatusSick})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 1, len(sick); want != got {
+		t.Fatalf("Expected sick dinosaurs %d got %d", want, got)
+	}
+	healthy, err := dinosaurStore.List(ctx, app.DinosaurFilter{HealthStatus: app.HealthStatusHealthy})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 0, len(healthy); want != got {
+		t.Fatalf("Expected healthy dinosaurs %d got %d", want, got)
+	}
+}
+
+func TestDinosaurStoreQuarantine(t *testing.T) {
+	setUpTestDB(t)
+	t.Cleanup(func() {
+		testDB.Exec("TRUNCATE TABLE cages CASCADE")
+	})
+
+	ctx := context.Background()
+	cageStore := CageStore{DB: testDB}
+	dinosaurStore := DinosaurStore{DB: testDB}
+
+	paddock, err := cageStore.Add(ctx, &app.Cage{Type: app.CageTypePaddock, Capacity: 3, Status: app.CageStatusActive})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ward, err := cageStore.Add(ctx, &app.Cage{Capacity: 3, Status: app.CageStatusActive, Quarantine: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ward.Quarantine {
+		t.Fatal("Expected a quarantine cage")
+	}
+
+	// Dinosaurs that aren't quarantined never enter a quarantine cage.
+	_, err = dinosaurStore.Add(ctx, &app.Dinosaur{Name: "Cera", Species: app.DinosaurSpeciesTriceratops, CageID: ward.ID})
+	if want, got := app.ErrQuarantineMismatch, err; want != got {
+		t.Fatalf("Expected error %v got %v", want, got)
+	}
+
+	cera, err := dinosaurStore.Add(ctx, &app.Dinosaur{Name: "Cera", Species: app.DinosaurSpeciesTriceratops, CageID: paddock.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	steggy, err := dinosaurStore.Add(ctx, &app.Dinosaur{Name: "Steggy", Species: app.DinosaurSpeciesStegosaurus, CageID: paddock.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Quarantined dinosaurs go only to quarantine cages.
+	_, err = dinosaurStore.Quarantine(ctx, cera.ID, app.Quarantine{CageID: paddock.ID, Reason: "Fever"})
+	if want, got := app.ErrQuarantineMismatch, err; want != got {
+		t.Fatalf("Expected error %v got %v", want, got)
+	}
+	end := time.Now().Add(14 * 24 * time.Hour).Truncate(time.Second)
+	quarantined, err := dinosaurStore.Quarantine(ctx, cera.ID, app.Quarantine{CageID: ward.ID, Reason: "Fever", End: end})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !quarantined.Quarantined || quarantined.QuarantineStart == nil {
+		t.Fatalf("Expected the dinosaur to be quarantined got %+v", quarantined)
+	}
+	if quarantined.QuarantineEnd == nil || !quarantined.QuarantineEnd.Equal(end) {
+		t.Fatalf("Expected QuarantineEnd %v got %v", end, quarantined.QuarantineEnd)
+	}
+	if want, got := ward.ID, quarantined.CageID; want != got {
+		t.Fatalf("Expected CageID %s got %s", want, got)
+	}
+	_, err = dinosaurStore.Quarantine(ctx, cera.ID, app.Quarantine{CageID: ward.ID, Reason: "Fever"})
+	if want, got := app.ErrConflict, err; want != got {
+		t.Fatalf("Expected error %v got %v", want, got)
+	}
+
+	// Moving doesn't lift the quarantine.
+	_, err = dinosaurStore.Move(ctx, cera.ID, paddock.ID)
+	if want, got := app.ErrQuarantineMismatch, err; want != got {
+		t.Fatalf("Expected error %v got %v", want, got)
+	}
+
+	// Alone or with their own species.
+	_, err = dinosaurStore.Quarantine(ctx, steggy.ID, app.Quarantine{CageID: ward.ID, Reason: "Cough"})
+	if want, got := app.ErrSpeciesMismatch, err; want != got {
+		t.Fatalf("Expected error %v got %v", want, got)
+	}
+	_, err = dinosaurStore.Add(ctx, &app.Dinosaur{
+		Name:             "Sarah",
+		Species:          app.DinosaurSpeciesTriceratops,
+		CageID:           ward.ID,
+		Quarantined:      true,
+		QuarantineReason: "Fever",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The occupants have to comply with the cage designation.
+	regular := false
+	_, err = cageStore.Update(ctx, ward.ID, app.CagePatch{Quarantine: &regular})
+	if want, got := app.ErrQuarantineMismatch, err; want != got {
+		t.Fatalf("Expected error %v got %v", want, got)
+	}
+	quarantine := true
+	_, err = cageStore.Update(ctx, paddock.ID, app.CagePatch{Quarantine: &quarantine})
+	if want, got := app.ErrQuarantineMismatch, err; want != got {
+		t.Fatalf("Expected error %v got %v", want, got)
+	}
+
+	dinosaurs, err := dinosaurStore.List(ctx, app.DinosaurFilter{Quarantined: &quarantine})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 2, len(dinosaurs); want != got {
+		t.Fatalf("Expected dinosaurs %d got %d", want, got)
+	}
+	cages, err := cageStore.List(ctx, app.CageFilter{Quarantine: &quarantine})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 1, len(cages); want != got {
+		t.Fatalf("Expected cages %d got %d", want, got)
+	}
+
+	_, err = dinosaurStore.Release(ctx, steggy.ID, paddock.ID)
+	if want, got := app.ErrConflict, err; want != got {
+		t.Fatalf("Expected error %v got %v", want, got)
+	}
+	_, err = dinosaurStore.Release(ctx, cera.ID, ward.ID)
+	if want, got := app.ErrQuarantineMismatch, err; want != got {
+		t.Fatalf("Expected error %v got %v", want, got)
+	}
+	released, err := dinosaurStore.Release(ctx, cera.ID, paddock.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if released.Quarantined || released.QuarantineStart != nil || released.QuarantineReason != "" {
+		t.Fatalf("Expected the dinosaur to be released got %+v", released)
+	}
+	if want, got := paddock.ID, released.CageID; want != got {
+		t.Fatalf("Expected CageID %s got %s", want, got)
+	}
+}
+
+func TestDinosaurStoreLifecycle(t *testing.T) {
+	setUpTestDB(t)
+	t.Cleanup(func() {
+		testDB.Exec("TRUNCATE TABLE cages CASCADE")
+	})
+
+	ctx := context.Background()
+	cageStore := CageStore{DB: testDB}
+	dinosaurStore := DinosaurStore{DB: testDB}
+
+	cage, err := cageStore.Add(ctx, &app.Cage{Capacity: 1, Status: app.CageStatusActive})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hatchDate := time.Now().Add(7 * 24 * time.Hour).Truncate(time.Second)
+	egg, err := dinosaurStore.AddEgg(ctx, app.Egg{Name: "Blue", Species: app.DinosaurSpeciesVelociraptor, HatchDate: hatchDate})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := app.LifecycleStageEgg, egg.Stage; want != got {
+		t.Fatalf("Expected Stage %s got %s", want, got)
+	}
+	if want, got := "", egg.CageID; want != got {
+		t.Fatalf("Expected CageID %s got %s", want, got)
+	}
+	if egg.HatchDate == nil || !egg.HatchDate.Equal(hatchDate) {
+		t.Fatalf("Expected HatchDate %v got %v", hatchDate, egg.HatchDate)
+	}
+
+	// Eggs live in the hatchery, not in the cages.
+	_, err = dinosaurStore.Move(ctx, egg.ID, cage.ID)
+	if want, got := app.ErrNotInCage, err; want != got {
+		t.Fatalf("Expected error %v got %v", want, got)
+	}
+	_, err = dinosaurStore.Transition(ctx, egg.ID, app.LifecycleTransition{Stage: app.LifecycleStageJuvenile})
+	if want, got := app.ErrIllegalTransition, err; want != got {
+		t.Fatalf("Expected error %v got %v", want, got)
+	}
+	eggs, err := dinosaurStore.List(ctx, app.DinosaurFilter{
+		Stages:    []app.LifecycleStage{app.LifecycleStageEgg},
+		HatchDate: app.TimeRange{Before: hatchDate.Add(time.Hour)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 1, len(eggs); want != got {
+		t.Fatalf("Expected eggs %d got %d", want, got)
+	}
+
+	// A hatchling is admitted like any other dinosaur.
+	if _, err := dinosaurStore.Add(ctx, &app.Dinosaur{Name: "Charlie", Species: app.DinosaurSpeciesVelociraptor, CageID: cage.ID}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = dinosaurStore.Hatch(ctx, egg.ID, cage.ID)
+	if want, got := app.ErrCapacityExceeded, err; want != got {
+		t.Fatalf("Expected error %v got %v", want, got)
+	}
+	if _, err := cageStore.Resize(ctx, cage.ID, 2); err != nil {
+		t.Fatal(err)
+	}
+	hatched, err := dinosaurStore.Hatch(ctx, egg.ID, cage.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := app.LifecycleStageJuvenile, hatched.Stage; want != got {
+		t.Fatalf("Expected Stage %s got %s", want, got)
+	}
+	if want, got := cage.ID, hatched.CageID; want != got {
+		t.Fatalf("Expected CageID %s got %s", want, got)
+	}
+	_, err = dinosaurStore.Hatch(ctx, egg.ID, cage.ID)
+	if want, got := app.ErrIllegalTransition, err; want != got {
+		t.Fatalf("Expected error %v got %v", want, got)
+	}
+
+	if _, err := dinosaurStore.Transition(ctx, egg.ID, app.LifecycleTransition{Stage: app.LifecycleStageAdult}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = dinosaurStore.Transition(ctx, egg.ID, app.LifecycleTransition{Stage: app.LifecycleStageJuvenile})
+	if want, got := app.ErrIllegalTransition, err; want != got {
+		t.Fatalf("Expected error %v got %v", want, got)
+	}
+
+	// A deceased dinosaur leaves its cage but stays on the records.
+	event, err := dinosaurStore.Transition(ctx, egg.ID, app.LifecycleTransition{Stage: app.LifecycleStageDeceased, Notes: "Old age"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := cage.ID, event.CageID; want != got {
+		t.Fatalf("Expected CageID %s got %s", want, got)
+	}
+	deceased, err := dinosaurStore.Get(ctx, egg.ID, app.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := app.LifecycleStageDeceased, deceased.Stage; want != got {
+		t.Fatalf("Expected Stage %s got %s", want, got)
+	}
+	if want, got := "", deceased.CageID; want != got {
+		t.Fatalf("Expected CageID %s got %s", want, got)
+	}
+	occupants, err := dinosaurStore.List(ctx, app.DinosaurFilter{CageID: cage.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 1, len(occupants); want != got {
+		t.Fatalf("Expected occupants %d got %d", want, got)
+	}
+	_, err = dinosaurStore.Transition(ctx, egg.ID, app.LifecycleTransition{Stage: app.LifecycleStageTransferredOut})
+	if want, got := app.ErrIllegalTransition, err; want != got {
+		t.Fatalf("Expected error %v got %v", want, got)
+	}
+
+	events, err := dinosaurStore.LifecycleEvents(ctx, egg.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 3, len(events); want != got {
+		t.Fatalf("Expected events %d got %d", want, got)
+	}
+	for i, to := range []app.LifecycleStage{app.LifecycleStageJuvenile, app.LifecycleStageAdult, app.LifecycleStageDeceased} {
+		if want, got := to, events[i].To; want != got {
+			t.Fatalf("Expected event %d To %s got %s", i, want, got)
+		}
+	}
+}
@@ -0,0 +1,364 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+// FeedingStore is a DB implementation of api.FeedingStore.
+type FeedingStore struct {
+	DB *sql.DB
+}
+
+// feedingScheduleColumns are the selected columns of a feeding schedule.
+const feedingScheduleColumns = `
+	id, COALESCE(cage_id::text, ''), COALESCE(species, ''), diet, quantity, interval_hours,
+	COALESCE(to_char(window_start, 'HH24:MI'), ''), COALESCE(to_char(window_end, 'HH24:MI'), ''),
+	created_at, updated_at`
+
+func feedingScheduleFieldPointers(schedule *app.FeedingSchedule) []any {
+	return []any{
+		&schedule.ID,
+		&schedule.CageID,
+		&schedule.Species,
+		&schedule.Diet,
+		&schedule.Quantity,
+		&schedule.IntervalHours,
+		&schedule.WindowStart,
+		&schedule.WindowEnd,
+		&schedule.CreatedAt,
+		&schedule.UpdatedAt,
+	}
+}
+
+// AddSchedule adds a new feeding schedule of a cage or of a species.
+// app.ErrNotFound is returned if the cage doesn't exist.
+func (s *FeedingStore) AddSchedule(ctx context.Context, schedule *app.FeedingSchedule) (*app.FeedingSchedule, error) {
+	if schedule.CageID != "" {
+		if _, err := getCage(ctx, s.DB, schedule.CageID, app.GetOptions{Fields: []string{"id"}}); err != nil {
+			return nil, err
+		}
+	}
+
+	var added app.FeedingSchedule
+	query := `
+	INSERT INTO feeding_schedules (cage_id, species, diet, quantity, interval_hours, window_start, window_end)
+	VALUES (NULLIF($1, '')::uuid, NULLIF($2, ''), $3, $4, $5, NULLIF($6, '')::time, NULLIF($7, '')::time)
+	RETURNING` + feedingScheduleColumns
+	err := s.DB.QueryRowContext(ctx, query,
+		schedule.CageID,
+		schedule.Species,
+		schedule.Diet,
+		schedule.Quantity,
+		schedule.IntervalHours,
+		schedule.WindowStart,
+		schedule.WindowEnd,
+	).Scan(feedingScheduleFieldPointers(&added)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &added, nil
+}
+
+// ListSchedules lists feeding schedules in the order they were added.
+func (s *FeedingStore) ListSchedules(ctx context.Context, filter app.FeedingScheduleFilter) ([]app.FeedingSchedule, error) {
+	var (
+		where []string
+		args  []any
+	)
+	if filter.CageID != "" {
+		where = append(where, "cage_id = ?")
+		args = append(args, filter.CageID)
+	}
+	if !filter.Species.IsUnspecified() {
+		where = append(where, "species = ?")
+		args = append(args, filter.Species)
+	}
+
+	query := "SELECT" + feedingScheduleColumns + " FROM feeding_schedules" + whereClause(where) + " ORDER BY created_at, id"
+
+	rows, err := s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var schedules []app.FeedingSchedule
+	for rows.Next() {
+		var schedule app.FeedingSchedule
+		if err := rows.Scan(feedingScheduleFieldPointers(&schedule)...); err != nil {
+			return nil, err
+		}
+
+		schedules = append(schedules, schedule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return schedules, nil
+}
+
+// DeleteSchedule deletes a feeding schedule.
+// The recorded feedings are kept.
+func (s *FeedingStore) DeleteSchedule(ctx context.Context, id string) error {
+	res, err := s.DB.ExecContext(ctx, "DELETE FROM feeding_schedules WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return app.ErrNotFound
+	}
+
+	return nil
+}
+
+// feedingColumns are the selected columns of a feeding.
+const feedingColumns = `
+	id, cage_id, COALESCE(dinosaur_id::text, ''), diet, quantity, unsafe, fed_at, created_at`
+
+func feedingFieldPointers(feeding *app.Feeding) []any {
+	return []any{
+		&feeding.ID,
+		&feeding.CageID,
+		&feeding.DinosaurID,
+		&feeding.Diet,
+		&feeding.Quantity,
+		&feeding.Unsafe,
+		&feeding.FedAt,
+		&feeding.CreatedAt,
+	}
+}
+
+// Record records a feeding of a dinosaur or of all occupants of a cage.
+// A dinosaur feeding is recorded against the current cage of the dinosaur.
+// The feeding is recorded as it happened, but flagged as unsafe if
+// carnivores were fed in a powered down cage.
+// app.ErrNotFound is returned if the cage or the dinosaur doesn't exist
+// and app.ErrNotInCage if the dinosaur doesn't live in a cage.
+func (s *FeedingStore) Record(ctx context.Context, feeding *app.Feeding) (*app.Feeding, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() // nolint:errcheck
+
+	cageID := feeding.CageID
+	if feeding.DinosaurID != "" {
+		var dinosaurCageID sql.NullString
+		query := "SELECT cage_id FROM dinosaurs WHERE id = $1 AND deleted_at IS NULL"
+		if err := tx.QueryRowContext(ctx, query, feeding.DinosaurID).Scan(&dinosaurCageID); err != nil {
+			if err == sql.ErrNoRows {
+				return nil, app.ErrNotFound
+			}
+
+			return nil, err
+		}
+		if !dinosaurCageID.Valid {
+			return nil, app.ErrNotInCage
+		}
+		cageID = dinosaurCageID.String
+	}
+
+	// Lock the cage so that the status and the occupants don't change while checked.
+	if err := lockCage(ctx, tx, cageID); err != nil {
+		return nil, err
+	}
+
+	unsafe, err := feedingUnsafe(ctx, tx, cageID, feeding.DinosaurID)
+	if err != nil {
+		return nil, err
+	}
+
+	fedAt := feeding.FedAt
+	if fedAt.IsZero() {
+		fedAt = time.Now()
+	}
+
+	var recorded app.Feeding
+	query := `
+	INSERT INTO feedings (cage_id, dinosaur_id, diet, quantity, unsafe, fed_at)
+	VALUES ($1, NULLIF($2, '')::uuid, $3, $4, $5, $6)
+	RETURNING` + feedingColumns
+	err = tx.QueryRowContext(ctx, query,
+		cageID,
+		feeding.DinosaurID,
+		feeding.Diet,
+		feeding.Quantity,
+		unsafe,
+		fedAt,
+	).Scan(feedingFieldPointers(&recorded)...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &recorded, nil
+}
+
+// List lists recorded feedings, the most recent first.
+func (s *FeedingStore) List(ctx context.Context, filter app.FeedingFilter) ([]app.Feeding, error) {
+	var (
+		where []string
+		args  []any
+	)
+	if filter.CageID != "" {
+		where = append(where, "cage_id = ?")
+		args = append(args, filter.CageID)
+	}
+	if filter.DinosaurID != "" {
+		where = append(where, "dinosaur_id = ?")
+		args = append(args, filter.DinosaurID)
+	}
+	if filter.UnsafeOnly {
+		where = append(where, "unsafe")
+	}
+	where, args = timeRangePredicates(where, args, "fed_at", filter.Fed)
+
+	query := "SELECT" + feedingColumns + " FROM feedings" + whereClause(where) + " ORDER BY fed_at DESC, id"
+
+	rows, err := s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var feedings []app.Feeding
+	for rows.Next() {
+		var feeding app.Feeding
+		if err := rows.Scan(feedingFieldPointers(&feeding)...); err != nil {
+			return nil, err
+		}
+
+		feedings = append(feedings, feeding)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return feedings, nil
+}
+
+// Overdue reports the dinosaurs that are overdue for a feeding at the given time.
+// The schedules are matched against the current placements: a cage schedule
+// applies to the current occupants of the cage and a species one to all
+// dinosaurs of the species that live in a cage. A feeding of a dinosaur counts
+// and so does a feeding of its current cage since it was placed there.
+// A dinosaur that was never fed is due an interval after the schedule or
+// the dinosaur was added, whatever is later. A feeding due outside of the
+// schedule window is due when the window opens next.
+// The most overdue feedings come first.
+func (s *FeedingStore) Overdue(ctx context.Context, at time.Time) ([]app.OverdueFeeding, error) {
+	query := `
+	SELECT schedule_id, dinosaur_id, name, species, cage_id, last_fed_at, w.due_at
+	  FROM (
+		SELECT s.id AS schedule_id, d.id AS dinosaur_id, d.name, d.species, d.cage_id, f.last_fed_at,
+		       s.window_start, s.window_end,
+		       COALESCE(f.last_fed_at, GREATEST(s.created_at, d.created_at)) + s.interval_hours * INTERVAL '1 hour' AS due_at
+		  FROM feeding_schedules s
+		  JOIN dinosaurs d ON (d.cage_id = s.cage_id OR d.species = s.species AND d.cage_id IS NOT NULL) AND d.deleted_at IS NULL
+		  LEFT JOIN LATERAL (
+			SELECT MAX(fed_at) AS last_fed_at
+			  FROM feedings
+			 WHERE (dinosaur_id = d.id OR (dinosaur_id IS NULL AND cage_id = d.cage_id AND fed_at >= d.placed_at))
+			   AND fed_at <= $1
+		  ) f ON TRUE
+	  ) o
+	  -- The windows are in UTC.
+	  CROSS JOIN LATERAL (
+		SELECT CASE
+		       WHEN o.window_start IS NULL
+		         OR (o.due_at AT TIME ZONE 'UTC')::TIME BETWEEN o.window_start AND o.window_end
+		       THEN o.due_at
+		       WHEN (o.due_at AT TIME ZONE 'UTC')::TIME < o.window_start
+		       THEN ((o.due_at AT TIME ZONE 'UTC')::DATE + o.window_start) AT TIME ZONE 'UTC'
+		       ELSE ((o.due_at AT TIME ZONE 'UTC')::DATE + 1 + o.window_start) AT TIME ZONE 'UTC'
+		       END AS due_at
+	  ) w
+	 WHERE w.due_at < $1
+	 ORDER BY w.due_at, dinosaur_id, schedule_id`
+
+	rows, err := s.DB.QueryContext(ctx, query, at)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var overdue []app.OverdueFeeding
+	for rows.Next() {
+		var (
+			feeding   app.OverdueFeeding
+			lastFedAt sql.NullTime
+		)
+		if err := rows.Scan(
+			&feeding.ScheduleID,
+			&feeding.DinosaurID,
+			&feeding.Name,
+			&feeding.Species,
+			&feeding.CageID,
+			&lastFedAt,
+			&feeding.DueAt,
+		); err != nil {
+			return nil, err
+		}
+		if lastFedAt.Valid {
+			feeding.LastFedAt = &lastFedAt.Time
+		}
+
+		overdue = append(overdue, feeding)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return overdue, nil
+}
+
+// feedingUnsafe checks if a feeding in a cage is unsafe, that is the cage
+// is powered down and carnivores are fed. A cage feeding feeds all occupants
+// of the cage, a dinosaur feeding only the dinosaur.
+// app.ErrNotFound is returned if the cage doesn't exist.
+func feedingUnsafe(ctx context.Context, q queryable, cageID, dinosaurID string) (bool, error) {
+	carnivores := app.DinosaurTypeCarnivore.Species()
+
+	where := []string{"d.cage_id = ?", "d.deleted_at IS NULL", "d.species IN (" + placeholders(len(carnivores)) + ")"}
+	args := []any{cageID}
+	for _, species := range carnivores {
+		args = append(args, species)
+	}
+	if dinosaurID != "" {
+		where = append(where, "d.id = ?")
+		args = append(args, dinosaurID)
+	}
+
+	// The cage id is the first argument of both the cage and the occupants predicates.
+	query := `
+	SELECT c.status = 'down' AND EXISTS (SELECT 1 FROM dinosaurs d` + whereClause(where) + `)
+	  FROM cages c
+	 WHERE c.id = $1
+	   AND c.deleted_at IS NULL`
+
+	var unsafe bool
+	if err := q.QueryRowContext(ctx, query, args...).Scan(&unsafe); err != nil {
+		if err == sql.ErrNoRows {
+			return false, app.ErrNotFound
+		}
+
+		return false, err
+	}
+
+	return unsafe, nil
+}
@@ -0,0 +1,304 @@
+//go:build integration
+// +build integration
+
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+func TestFeedingStore(t *testing.T) {
+	setUpTestDB(t)
+	t.Cleanup(func() {
+		testDB.Exec("TRUNCATE TABLE cages, feeding_schedules CASCADE")
+	})
+
+	ctx := context.Background()
+	cageStore := CageStore{DB: testDB}
+	dinosaurStore := DinosaurStore{DB: testDB}
+	feedingStore := FeedingStore{DB: testDB}
+
+	cage, err := cageStore.Add(ctx, &app.Cage{Capacity: 2, Status: app.CageStatusActive})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := cageStore.Add(ctx, &app.Cage{Capacity: 2, Status: app.CageStatusActive})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rex, err := dinosaurStore.Add(ctx, &app.Dinosaur{Name: "Rexy", Species: app.DinosaurSpeciesTyrannosaurus, CageID: cage.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cera, err := dinosaurStore.Add(ctx, &app.Dinosaur{Name: "Cera", Species: app.DinosaurSpeciesTriceratops, CageID: other.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cageSchedule, err := feedingStore.AddSchedule(ctx, &app.FeedingSchedule{
+		CageID:        cage.ID,
+		Diet:          "goats",
+		Quantity:      50,
+		IntervalHours: 24,
+		// The window spans the day so that the feedings are due regardless of the time of day.
+		WindowStart: "00:00",
+		WindowEnd:   "23:59",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "00:00", cageSchedule.WindowStart; want != got {
+		t.Fatalf("Expected WindowStart %s got %s", want, got)
+	}
+	speciesSchedule, err := feedingStore.AddSchedule(ctx, &app.FeedingSchedule{
+		Species:       app.DinosaurSpeciesTriceratops,
+		Diet:          "ferns",
+		Quantity:      120.5,
+		IntervalHours: 8,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "", speciesSchedule.WindowStart; want != got {
+		t.Fatalf("Expected no WindowStart got %s", got)
+	}
+	if _, err := feedingStore.AddSchedule(ctx, &app.FeedingSchedule{CageID: uuid.NewString(), Diet: "goats", Quantity: 1, IntervalHours: 1}); err != app.ErrNotFound {
+		t.Fatalf("Expected error %v got %v", app.ErrNotFound, err)
+	}
+
+	schedules, err := feedingStore.ListSchedules(ctx, app.FeedingScheduleFilter{Species: app.DinosaurSpeciesTriceratops})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 1, len(schedules); want != got {
+		t.Fatalf("Expected schedules %d got %d", want, got)
+	}
+	if want, got := speciesSchedule.ID, schedules[0].ID; want != got {
+		t.Fatalf("Expected schedule %s got %s", want, got)
+	}
+
+	// Never fed, both dinosaurs are due an interval after they were added.
+	now := time.Now()
+	overdue, err := feedingStore.Overdue(ctx, now.Add(9*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 1, len(overdue); want != got {
+		t.Fatalf("Expected overdue %d got %d", want, got)
+	}
+	if want, got := cera.ID, overdue[0].DinosaurID; want != got {
+		t.Fatalf("Expected dinosaur %s got %s", want, got)
+	}
+	overdue, err = feedingStore.Overdue(ctx, now.Add(25*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 2, len(overdue); want != got {
+		t.Fatalf("Expected overdue %d got %d", want, got)
+	}
+
+	// A cage feeding feeds all occupants of the cage.
+	fedAt := now.Add(20 * time.Hour)
+	feeding, err := feedingStore.Record(ctx, &app.Feeding{CageID: cage.ID, Diet: "goats", Quantity: 50, FedAt: fedAt})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if feeding.Unsafe {
+		t.Fatal("Expected feeding to be safe")
+	}
+	overdue, err = feedingStore.Overdue(ctx, now.Add(25*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 1, len(overdue); want != got {
+		t.Fatalf("Expected overdue %d got %d", want, got)
+	}
+	if want, got := cera.ID, overdue[0].DinosaurID; want != got {
+		t.Fatalf("Expected dinosaur %s got %s", want, got)
+	}
+
+	// A cage holding carnivores can be powered down,
+	// their feedings are recorded but flagged as unsafe.
+	if _, err := cageStore.ChangeStatus(ctx, cage.ID, app.CageStatusDown); err != nil {
+		t.Fatal(err)
+	}
+	feeding, err = feedingStore.Record(ctx, &app.Feeding{DinosaurID: rex.ID, Diet: "goats", Quantity: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := cage.ID, feeding.CageID; want != got {
+		t.Fatalf("Expected CageID %s got %s", want, got)
+	}
+	if !feeding.Unsafe {
+		t.Fatal("Expected feeding to be unsafe")
+	}
+	if _, err := feedingStore.Record(ctx, &app.Feeding{DinosaurID: uuid.NewString(), Diet: "goats", Quantity: 50}); err != app.ErrNotFound {
+		t.Fatalf("Expected error %v got %v", app.ErrNotFound, err)
+	}
+
+	feedings, err := feedingStore.List(ctx, app.FeedingFilter{CageID: cage.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 2, len(feedings); want != got {
+		t.Fatalf("Expected feedings %d got %d", want, got)
+	}
+	// The most recent first, the cage feeding was recorded ahead of time.
+	if want, got := feeding.ID, feedings[1].ID; want != got {
+		t.Fatalf("Expected feeding %s got %s", want, got)
+	}
+	feedings, err = feedingStore.List(ctx, app.FeedingFilter{UnsafeOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 1, len(feedings); want != got {
+		t.Fatalf("Expected unsafe feedings %d got %d", want, got)
+	}
+
+	if err := feedingStore.DeleteSchedule(ctx, cageSchedule.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := feedingStore.DeleteSchedule(ctx, cageSchedule.ID); err != app.ErrNotFound {
+		t.Fatalf("Expected error %v got %v", app.ErrNotFound, err)
+	}
+}
+
+func TestFeedingStoreOverdueWindow(t *testing.T) {
+	setUpTestDB(t)
+	t.Cleanup(func() {
+		testDB.Exec("TRUNCATE TABLE cages, feeding_schedules CASCADE")
+	})
+
+	ctx := context.Background()
+	cageStore := CageStore{DB: testDB}
+	dinosaurStore := DinosaurStore{DB: testDB}
+	feedingStore := FeedingStore{DB: testDB}
+
+	cage, err := cageStore.Add(ctx, &app.Cage{Capacity: 2, Status: app.CageStatusActive})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dinosaurStore.Add(ctx, &app.Dinosaur{Name: "Rexy", Species: app.DinosaurSpeciesTyrannosaurus, CageID: cage.ID}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := feedingStore.AddSchedule(ctx, &app.FeedingSchedule{
+		CageID:        cage.ID,
+		Diet:          "goats",
+		Quantity:      50,
+		IntervalHours: 6,
+		WindowStart:   "08:00",
+		WindowEnd:     "10:00",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Fed at 09:00, the next feeding would be due at 15:00 but the window opens next at 08:00.
+	fedAt := time.Now().UTC().Truncate(24 * time.Hour).Add(24*time.Hour + 9*time.Hour)
+	if _, err := feedingStore.Record(ctx, &app.Feeding{CageID: cage.ID, Diet: "goats", Quantity: 50, FedAt: fedAt}); err != nil {
+		t.Fatal(err)
+	}
+	overdue, err := feedingStore.Overdue(ctx, fedAt.Add(7*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 0, len(overdue); want != got {
+		t.Fatalf("Expected overdue %d got %d", want, got)
+	}
+	overdue, err = feedingStore.Overdue(ctx, fedAt.Add(24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 1, len(overdue); want != got {
+		t.Fatalf("Expected overdue %d got %d", want, got)
+	}
+	if want, got := fedAt.Add(23*time.Hour), overdue[0].DueAt; !want.Equal(got) {
+		t.Fatalf("Expected DueAt %v got %v", want, got)
+	}
+}
+
+func TestFeedingStoreOverduePlacement(t *testing.T) {
+	setUpTestDB(t)
+	t.Cleanup(func() {
+		testDB.Exec("TRUNCATE TABLE cages, feeding_schedules CASCADE")
+	})
+
+	ctx := context.Background()
+	cageStore := CageStore{DB: testDB}
+	dinosaurStore := DinosaurStore{DB: testDB}
+	feedingStore := FeedingStore{DB: testDB}
+
+	cage, err := cageStore.Add(ctx, &app.Cage{Capacity: 2, Status: app.CageStatusActive})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := cageStore.Add(ctx, &app.Cage{Capacity: 2, Status: app.CageStatusActive})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cera, err := dinosaurStore.Add(ctx, &app.Dinosaur{Name: "Cera", Species: app.DinosaurSpeciesTriceratops, CageID: cage.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sarah, err := dinosaurStore.Add(ctx, &app.Dinosaur{Name: "Sarah", Species: app.DinosaurSpeciesTriceratops, CageID: other.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := feedingStore.AddSchedule(ctx, &app.FeedingSchedule{
+		CageID:        cage.ID,
+		Diet:          "ferns",
+		Quantity:      120,
+		IntervalHours: 2,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The cage was fed before Sarah was moved in, it only fed Cera.
+	feeding, err := feedingStore.Record(ctx, &app.Feeding{CageID: cage.ID, Diet: "ferns", Quantity: 120})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dinosaurStore.Move(ctx, sarah.ID, cage.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	overdue, err := feedingStore.Overdue(ctx, now.Add(3*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 2, len(overdue); want != got {
+		t.Fatalf("Expected overdue %d got %d", want, got)
+	}
+	for _, o := range overdue {
+		switch o.DinosaurID {
+		case cera.ID:
+			if o.LastFedAt == nil || !o.LastFedAt.Equal(feeding.FedAt) {
+				t.Fatalf("Expected Cera LastFedAt %v got %v", feeding.FedAt, o.LastFedAt)
+			}
+		case sarah.ID:
+			if o.LastFedAt != nil {
+				t.Fatalf("Expected Sarah LastFedAt to be empty got %v", o.LastFedAt)
+			}
+		default:
+			t.Fatalf("Unexpected dinosaur %s", o.DinosaurID)
+		}
+	}
+
+	// A cage feeding after the move feeds both.
+	if _, err := feedingStore.Record(ctx, &app.Feeding{CageID: cage.ID, Diet: "ferns", Quantity: 240, FedAt: now.Add(2 * time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	overdue, err = feedingStore.Overdue(ctx, now.Add(3*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 0, len(overdue); want != got {
+		t.Fatalf("Expected overdue %d got %d", want, got)
+	}
+}
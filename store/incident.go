@@ -0,0 +1,337 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+// IncidentStore is a DB implementation of api.IncidentStore.
+type IncidentStore struct {
+	DB *sql.DB
+}
+
+// incidentColumns are the selected columns of an incident.
+const incidentColumns = `
+	i.id, i.type, i.severity, i.cage_id,
+	COALESCE((SELECT string_agg(dinosaur_id::TEXT, ',' ORDER BY dinosaur_id)
+	  FROM incident_dinosaurs WHERE incident_id = i.id), ''),
+	i.summary,
+	CASE WHEN i.resolved_at IS NULL THEN 'open' ELSE 'resolved' END,
+	i.resolution, i.opened_at, i.resolved_at, i.created_at, i.updated_at`
+
+func scanIncident(row interface{ Scan(...any) error }, incident *app.Incident) error {
+	var (
+		dinosaurIDs string
+		resolvedAt  sql.NullTime
+	)
+	err := row.Scan(
+		&incident.ID,
+		&incident.Type,
+		&incident.Severity,
+		&incident.CageID,
+		&dinosaurIDs,
+		&incident.Summary,
+		&incident.Status,
+		&incident.Resolution,
+		&incident.OpenedAt,
+		&resolvedAt,
+		&incident.CreatedAt,
+		&incident.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	incident.DinosaurIDs = []string{}
+	if dinosaurIDs != "" {
+		incident.DinosaurIDs = strings.Split(dinosaurIDs, ",")
+	}
+	if resolvedAt.Valid {
+		incident.ResolvedAt = &resolvedAt.Time
+	}
+
+	return nil
+}
+
+// Open a new incident in a cage.
+// An open critical containment incident locks the cage down until it's resolved.
+// app.ErrNotFound is returned if the cage or any of the dinosaurs doesn't exist.
+func (s *IncidentStore) Open(ctx context.Context, incident *app.Incident) (*app.Incident, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() // nolint:errcheck
+
+	// Lock the cage so that the lockdown is serialized with the admissions.
+	if err := lockCage(ctx, tx, incident.CageID); err != nil {
+		return nil, err
+	}
+
+	for _, dinosaurID := range incident.DinosaurIDs {
+		if _, err := getDinosaur(ctx, tx, dinosaurID, app.GetOptions{Fields: []string{"id"}}); err != nil {
+			return nil, err
+		}
+	}
+
+	var id string
+	query := "INSERT INTO incidents (type, severity, cage_id, summary) VALUES ($1, $2, $3, $4) RETURNING id"
+	err = tx.QueryRowContext(ctx, query,
+		incident.Type,
+		incident.Severity,
+		incident.CageID,
+		incident.Summary,
+	).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dinosaurID := range incident.DinosaurIDs {
+		query := "INSERT INTO incident_dinosaurs (incident_id, dinosaur_id) VALUES ($1, $2)"
+		if _, err := tx.ExecContext(ctx, query, id, dinosaurID); err != nil {
+			return nil, err
+		}
+	}
+
+	opened, err := getIncident(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return opened, nil
+}
+
+// Get an incident by id along with its timeline.
+func (s *IncidentStore) Get(ctx context.Context, id string) (*app.Incident, error) {
+	return getIncident(ctx, s.DB, id)
+}
+
+// List incidents, the most recently opened first.
+func (s *IncidentStore) List(ctx context.Context, filter app.IncidentFilter) ([]app.Incident, error) {
+	var (
+		where []string
+		args  []any
+	)
+	if filter.CageID != "" {
+		where = append(where, "i.cage_id = ?")
+		args = append(args, filter.CageID)
+	}
+	switch filter.Status {
+	case app.IncidentStatusOpen:
+		where = append(where, "i.resolved_at IS NULL")
+	case app.IncidentStatusResolved:
+		where = append(where, "i.resolved_at IS NOT NULL")
+	}
+	if !filter.Severity.IsUnspecified() {
+		where = append(where, "i.severity = ?")
+		args = append(args, filter.Severity)
+	}
+
+	query := "SELECT" + incidentColumns + " FROM incidents i" + whereClause(where) + " ORDER BY i.opened_at DESC, i.id"
+
+	rows, err := s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []app.Incident
+	for rows.Next() {
+		var incident app.Incident
+		if err := scanIncident(rows, &incident); err != nil {
+			return nil, err
+		}
+
+		list = append(list, incident)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// incidentEntryColumns are the selected columns of a timeline entry.
+const incidentEntryColumns = `
+	id, incident_id, note, occurred_at, created_at`
+
+func incidentEntryFieldPointers(entry *app.IncidentEntry) []any {
+	return []any{
+		&entry.ID,
+		&entry.IncidentID,
+		&entry.Note,
+		&entry.OccurredAt,
+		&entry.CreatedAt,
+	}
+}
+
+// AddEntry adds an entry to the timeline of an open incident.
+// The entry occurs now unless the time is given.
+// app.ErrIncidentResolved is returned if the incident is already resolved.
+func (s *IncidentStore) AddEntry(ctx context.Context, id string, entry *app.IncidentEntry) (*app.IncidentEntry, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() // nolint:errcheck
+
+	if err := lockOpenIncident(ctx, tx, id); err != nil {
+		return nil, err
+	}
+
+	occurredAt := entry.OccurredAt
+	if occurredAt.IsZero() {
+		occurredAt = time.Now()
+	}
+
+	var added app.IncidentEntry
+	query := `
+	INSERT INTO incident_entries (incident_id, note, occurred_at)
+	VALUES ($1, $2, $3)
+	RETURNING` + incidentEntryColumns
+	err = tx.QueryRowContext(ctx, query, id, entry.Note, occurredAt).Scan(incidentEntryFieldPointers(&added)...)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE incidents SET updated_at = NOW() WHERE id = $1", id); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &added, nil
+}
+
+// Resolve an open incident lifting the cage lockdown if any.
+// app.ErrIncidentResolved is returned if the incident is already resolved.
+func (s *IncidentStore) Resolve(ctx context.Context, id, resolution string) (*app.Incident, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() // nolint:errcheck
+
+	if err := lockOpenIncident(ctx, tx, id); err != nil {
+		return nil, err
+	}
+
+	query := `
+	UPDATE incidents
+	   SET resolution = $2,
+	       resolved_at = NOW(),
+	       updated_at = NOW()
+	 WHERE id = $1`
+	if _, err := tx.ExecContext(ctx, query, id, resolution); err != nil {
+		return nil, err
+	}
+
+	resolved, err := getIncident(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return resolved, nil
+}
+
+func getIncident(ctx context.Context, q queryable, id string) (*app.Incident, error) {
+	var incident app.Incident
+	query := "SELECT" + incidentColumns + " FROM incidents i WHERE i.id = $1"
+	if err := scanIncident(q.QueryRowContext(ctx, query, id), &incident); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, app.ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	query = "SELECT" + incidentEntryColumns + " FROM incident_entries WHERE incident_id = $1 ORDER BY occurred_at, id"
+	rows, err := q.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	incident.Timeline = []app.IncidentEntry{}
+	for rows.Next() {
+		var entry app.IncidentEntry
+		if err := rows.Scan(incidentEntryFieldPointers(&entry)...); err != nil {
+			return nil, err
+		}
+
+		incident.Timeline = append(incident.Timeline, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &incident, nil
+}
+
+// lockOpenIncident locks an incident for an update.
+// app.ErrNotFound is returned if the incident doesn't exist
+// and app.ErrIncidentResolved if it's already resolved.
+func lockOpenIncident(ctx context.Context, q queryable, id string) error {
+	var resolved bool
+	query := "SELECT resolved_at IS NOT NULL FROM incidents WHERE id = $1 FOR UPDATE"
+	if err := q.QueryRowContext(ctx, query, id).Scan(&resolved); err != nil {
+		if err == sql.ErrNoRows {
+			return app.ErrNotFound
+		}
+
+		return err
+	}
+
+	if resolved {
+		return app.ErrIncidentResolved
+	}
+
+	return nil
+}
+
+// hasOpenIncident returns true if the cage has any open incident.
+func hasOpenIncident(ctx context.Context, q queryable, cageID string) (bool, error) {
+	var open bool
+	query := "SELECT EXISTS (SELECT 1 FROM incidents WHERE cage_id = $1 AND resolved_at IS NULL)"
+	if err := q.QueryRowContext(ctx, query, cageID).Scan(&open); err != nil {
+		return false, err
+	}
+
+	return open, nil
+}
+
+// cageLockedDown returns true if the cage has an open critical containment incident.
+func cageLockedDown(ctx context.Context, q queryable, cageID string) (bool, error) {
+	where := []string{
+		"cage_id = ?",
+		"resolved_at IS NULL",
+		"severity = ?",
+		"type IN (" + placeholders(len(app.ContainmentIncidentTypes)) + ")",
+	}
+	args := []any{cageID, app.IncidentSeverityCritical}
+	for _, t := range app.ContainmentIncidentTypes {
+		args = append(args, t)
+	}
+
+	var lockedDown bool
+	query := "SELECT EXISTS (SELECT 1 FROM incidents" + whereClause(where) + ")"
+	if err := q.QueryRowContext(ctx, query, args...).Scan(&lockedDown); err != nil {
+		return false, err
+	}
+
+	return lockedDown, nil
+}
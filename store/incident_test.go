@@ -0,0 +1,249 @@
+//go:build integration
+// +build integration
+
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+func TestIncidentStore(t *testing.T) {
+	setUpTestDB(t)
+	t.Cleanup(func() {
+		testDB.Exec("TRUNCATE TABLE cages, dinosaurs CASCADE")
+	})
+
+	ctx := context.Background()
+	cageStore := CageStore{DB: testDB}
+	dinosaurStore := DinosaurStore{DB: testDB}
+	incidentStore := IncidentStore{DB: testDB}
+
+	rexCage, err := cageStore.Add(ctx, &app.Cage{Capacity: 3, Status: app.CageStatusActive})
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherCage, err := cageStore.Add(ctx, &app.Cage{Capacity: 3, Status: app.CageStatusActive})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rexy, err := dinosaurStore.Add(ctx, &app.Dinosaur{Name: "Rexy", Species: app.DinosaurSpeciesTyrannosaurus, CageID: rexCage.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	roberta, err := dinosaurStore.Add(ctx, &app.Dinosaur{Name: "Roberta", Species: app.DinosaurSpeciesTyrannosaurus, CageID: otherCage.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := incidentStore.Open(ctx, &app.Incident{
+		Type:     app.IncidentTypeEscape,
+		Severity: app.IncidentSeverityCritical,
+		CageID:   uuid.NewString(),
+		Summary:  "Fence down",
+	}); err != app.ErrNotFound {
+		t.Fatalf("Expected error %v got %v", app.ErrNotFound, err)
+	}
+
+	// A high severity incident doesn't lock the cage down.
+	minor, err := incidentStore.Open(ctx, &app.Incident{
+		Type:     app.IncidentTypeFenceFailure,
+		Severity: app.IncidentSeverityHigh,
+		CageID:   rexCage.ID,
+		Summary:  "Fence flickering",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := app.IncidentStatusOpen, minor.Status; want != got {
+		t.Fatalf("Expected Status %s got %s", want, got)
+	}
+	if _, err := dinosaurStore.Move(ctx, roberta.ID, rexCage.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dinosaurStore.Move(ctx, roberta.ID, otherCage.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	escape, err := incidentStore.Open(ctx, &app.Incident{
+		Type:        app.IncidentTypeEscape,
+		Severity:    app.IncidentSeverityCritical,
+		CageID:      rexCage.ID,
+		DinosaurIDs: []string{rexy.ID},
+		Summary:     "Rexy is out",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 1, len(escape.DinosaurIDs); want != got {
+		t.Fatalf("Expected DinosaurIDs %d got %d", want, got)
+	}
+	if want, got := rexy.ID, escape.DinosaurIDs[0]; want != got {
+		t.Fatalf("Expected DinosaurID %s got %s", want, got)
+	}
+
+	// The cage is locked down until the escape is resolved.
+	if _, err := dinosaurStore.Move(ctx, roberta.ID, rexCage.ID); err != app.ErrCageLockedDown {
+		t.Fatalf("Expected error %v got %v", app.ErrCageLockedDown, err)
+	}
+	if _, err := dinosaurStore.Add(ctx, &app.Dinosaur{Name: "Buck", Species: app.DinosaurSpeciesTyrannosaurus, CageID: rexCage.ID}); err != app.ErrCageLockedDown {
+		t.Fatalf("Expected error %v got %v", app.ErrCageLockedDown, err)
+	}
+
+	occurredAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	if _, err := incidentStore.AddEntry(ctx, escape.ID, &app.IncidentEntry{Note: "Rexy recaptured"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := incidentStore.AddEntry(ctx, escape.ID, &app.IncidentEntry{Note: "Fence down", OccurredAt: occurredAt}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := incidentStore.AddEntry(ctx, uuid.NewString(), &app.IncidentEntry{Note: "Nothing"}); err != app.ErrNotFound {
+		t.Fatalf("Expected error %v got %v", app.ErrNotFound, err)
+	}
+
+	incident, err := incidentStore.Get(ctx, escape.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 2, len(incident.Timeline); want != got {
+		t.Fatalf("Expected Timeline %d got %d", want, got)
+	}
+	if want, got := "Fence down", incident.Timeline[0].Note; want != got {
+		t.Fatalf("Expected Note %s got %s", want, got)
+	}
+
+	list, err := incidentStore.List(ctx, app.IncidentFilter{CageID: rexCage.ID, Severity: app.IncidentSeverityCritical})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 1, len(list); want != got {
+		t.Fatalf("Expected Incidents %d got %d", want, got)
+	}
+
+	resolved, err := incidentStore.Resolve(ctx, escape.ID, "Rexy is back, fence repaired")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := app.IncidentStatusResolved, resolved.Status; want != got {
+		t.Fatalf("Expected Status %s got %s", want, got)
+	}
+	if resolved.ResolvedAt == nil {
+		t.Fatal("Expected ResolvedAt")
+	}
+	if _, err := incidentStore.Resolve(ctx, escape.ID, "Again"); err != app.ErrIncidentResolved {
+		t.Fatalf("Expected error %v got %v", app.ErrIncidentResolved, err)
+	}
+	if _, err := incidentStore.AddEntry(ctx, escape.ID, &app.IncidentEntry{Note: "Late"}); err != app.ErrIncidentResolved {
+		t.Fatalf("Expected error %v got %v", app.ErrIncidentResolved, err)
+	}
+
+	// The lockdown is lifted.
+	if _, err := dinosaurStore.Move(ctx, roberta.ID, rexCage.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	list, err = incidentStore.List(ctx, app.IncidentFilter{Status: app.IncidentStatusOpen})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 1, len(list); want != got {
+		t.Fatalf("Expected Incidents %d got %d", want, got)
+	}
+	if want, got := minor.ID, list[0].ID; want != got {
+		t.Fatalf("Expected Incident %s got %s", want, got)
+	}
+}
+
+func TestIncidentStoreKeepsRecords(t *testing.T) {
+	setUpTestDB(t)
+	t.Cleanup(func() {
+		testDB.Exec("TRUNCATE TABLE cages, dinosaurs CASCADE")
+	})
+
+	ctx := context.Background()
+	cageStore := CageStore{DB: testDB}
+	dinosaurStore := DinosaurStore{DB: testDB}
+	incidentStore := IncidentStore{DB: testDB}
+
+	cage, err := cageStore.Add(ctx, &app.Cage{Capacity: 2, Status: app.CageStatusActive})
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherCage, err := cageStore.Add(ctx, &app.Cage{Capacity: 2, Status: app.CageStatusActive})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rexy, err := dinosaurStore.Add(ctx, &app.Dinosaur{Name: "Rexy", Species: app.DinosaurSpeciesTyrannosaurus, CageID: otherCage.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fence, err := incidentStore.Open(ctx, &app.Incident{
+		Type:     app.IncidentTypeFenceFailure,
+		Severity: app.IncidentSeverityCritical,
+		CageID:   cage.ID,
+		Summary:  "Fence down",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	escape, err := incidentStore.Open(ctx, &app.Incident{
+		Type:        app.IncidentTypeEscape,
+		Severity:    app.IncidentSeverityHigh,
+		CageID:      cage.ID,
+		DinosaurIDs: []string{rexy.ID},
+		Summary:     "Rexy is out",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A cage with an open incident can't be deleted.
+	if err := cageStore.Delete(ctx, cage.ID); err != app.ErrConflict {
+		t.Fatalf("Expected error %v got %v", app.ErrConflict, err)
+	}
+	for _, incident := range []*app.Incident{fence, escape} {
+		if _, err := incidentStore.Resolve(ctx, incident.ID, "Fence repaired"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := cageStore.Delete(ctx, cage.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// The deleted cage and dinosaur involved in incidents aren't purged.
+	if err := dinosaurStore.Delete(ctx, rexy.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := cageStore.Delete(ctx, otherCage.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := dinosaurStore.Purge(ctx, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := int64(0), n; want != got {
+		t.Fatalf("Expected purged %d got %d", want, got)
+	}
+	n, err = cageStore.Purge(ctx, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := int64(0), n; want != got {
+		t.Fatalf("Expected purged %d got %d", want, got)
+	}
+
+	incident, err := incidentStore.Get(ctx, escape.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := rexy.ID, incident.DinosaurIDs[0]; want != got {
+		t.Fatalf("Expected DinosaurID %s got %s", want, got)
+	}
+}
@@ -0,0 +1,52 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ErrNoMigrations is returned when the DB schema has not been migrated yet.
+var ErrNoMigrations = errors.New("no migrations applied")
+
+// MigrationVersion returns the current DB schema version
+// as recorded by golang-migrate.
+func MigrationVersion(ctx context.Context, q queryable) (uint, bool, error) {
+	var (
+		version int64
+		dirty   bool
+	)
+	query := `
+	SELECT version, dirty
+	  FROM schema_migrations
+	 LIMIT 1`
+	err := q.QueryRowContext(ctx, query).Scan(&version, &dirty)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, false, ErrNoMigrations
+		}
+
+		return 0, false, err
+	}
+
+	return uint(version), dirty, nil
+}
+
+// CheckMigrationVersion checks that the DB schema is at the expected version.
+func CheckMigrationVersion(ctx context.Context, q queryable, expected uint) error {
+	version, dirty, err := MigrationVersion(ctx, q)
+	if err != nil {
+		return err
+	}
+
+	if dirty {
+		return fmt.Errorf("schema version %d is dirty", version)
+	}
+
+	if version != expected {
+		return fmt.Errorf("schema version %d, expected %d", version, expected)
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+//go:build integration
+// +build integration
+
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCheckMigrationVersion(t *testing.T) {
+	setUpTestDB(t)
+
+	ctx := context.Background()
+
+	version, dirty, err := MigrationVersion(ctx, testDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dirty {
+		t.Fatal("Expected clean schema got dirty")
+	}
+
+	if err := CheckMigrationVersion(ctx, testDB, version); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := CheckMigrationVersion(ctx, testDB, version+1); err == nil {
+		t.Fatal("Expected error got nil")
+	}
+}
@@ -0,0 +1,60 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// RateLimiter is a DB implementation of api.RateLimiter
+// that shares the limits between multiple API instances.
+type RateLimiter struct {
+	DB *sql.DB
+}
+
+// Allow registers a request for the key.
+// It uses the generic cell rate algorithm, an equivalent of a token bucket
+// that only needs to track the time the bucket is full again, which allows
+// to check and update the bucket atomically with a single statement.
+func (s *RateLimiter) Allow(ctx context.Context, key string, requests int, period time.Duration) (bool, time.Duration, error) {
+	interval := period / time.Duration(requests)
+
+	query := `
+	INSERT INTO rate_limits AS r (key, full_at, allowed)
+	VALUES ($1, NOW() + make_interval(secs => $2), TRUE)
+	ON CONFLICT (key) DO UPDATE
+	   SET allowed = GREATEST(r.full_at, NOW()) + make_interval(secs => $2) - NOW() <= make_interval(secs => $3),
+	       full_at = CASE
+	                   WHEN GREATEST(r.full_at, NOW()) + make_interval(secs => $2) - NOW() <= make_interval(secs => $3)
+	                   THEN GREATEST(r.full_at, NOW()) + make_interval(secs => $2)
+	                   ELSE r.full_at
+	                 END
+	RETURNING allowed, EXTRACT(EPOCH FROM full_at - NOW())`
+
+	var (
+		allowed bool
+		reset   float64
+	)
+	err := s.DB.QueryRowContext(ctx, query, key, interval.Seconds(), period.Seconds()).Scan(
+		&allowed,
+		&reset,
+	)
+	if err != nil {
+		return false, 0, err
+	}
+
+	return allowed, time.Duration(reset * float64(time.Second)), nil
+}
+
+// Purge deletes the buckets that are full and returns their number.
+func (s *RateLimiter) Purge(ctx context.Context) (int64, error) {
+	query := `
+	DELETE FROM rate_limits
+	 WHERE full_at < NOW()`
+	res, err := s.DB.ExecContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
@@ -0,0 +1,60 @@
+//go:build integration
+// +build integration
+
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	setUpTestDB(t)
+	t.Cleanup(func() {
+		testDB.Exec("TRUNCATE TABLE rate_limits")
+	})
+
+	ctx := context.Background()
+	limiter := RateLimiter{DB: testDB}
+
+	// A burst of up to the number of requests is allowed.
+	for i := 0; i < 3; i++ {
+		allowed, reset, err := limiter.Allow(ctx, "test:ip:10.0.0.1", 3, time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !allowed {
+			t.Fatalf("Expected request %d to be allowed", i+1)
+		}
+		if reset <= 0 || reset > time.Hour {
+			t.Fatalf("Expected reset within an hour got %s", reset)
+		}
+	}
+
+	allowed, _, err := limiter.Allow(ctx, "test:ip:10.0.0.1", 3, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if allowed {
+		t.Fatal("Expected request to be rejected")
+	}
+
+	// Other clients have their own buckets.
+	allowed, _, err = limiter.Allow(ctx, "test:ip:10.0.0.2", 3, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !allowed {
+		t.Fatal("Expected request to be allowed")
+	}
+
+	// Nothing to purge, the buckets are not full yet.
+	n, err := limiter.Purge(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := int64(0), n; want != got {
+		t.Fatalf("Expected purged %d got %d", want, got)
+	}
+}
@@ -0,0 +1,320 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+// StaffStore is a DB implementation of api.StaffStore.
+type StaffStore struct {
+	DB *sql.DB
+}
+
+// staffColumns are the selected columns of a staff member.
+const staffColumns = `
+	s.id, s.name, s.role,
+	COALESCE((SELECT string_agg(certification, ',' ORDER BY certification)
+	  FROM staff_certifications WHERE staff_id = s.id), ''),
+	s.created_at, s.updated_at`
+
+func scanStaff(row interface{ Scan(...any) error }, staff *app.Staff) error {
+	var certifications string
+	err := row.Scan(
+		&staff.ID,
+		&staff.Name,
+		&staff.Role,
+		&certifications,
+		&staff.CreatedAt,
+		&staff.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	staff.Certifications = []app.Certification{}
+	if certifications != "" {
+		for _, c := range strings.Split(certifications, ",") {
+			staff.Certifications = append(staff.Certifications, app.Certification(c))
+		}
+	}
+
+	return nil
+}
+
+// Add a new staff member along with the certifications.
+func (s *StaffStore) Add(ctx context.Context, staff *app.Staff) (*app.Staff, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() // nolint:errcheck
+
+	var id string
+	query := "INSERT INTO staff (name, role) VALUES ($1, $2) RETURNING id"
+	if err := tx.QueryRowContext(ctx, query, staff.Name, staff.Role).Scan(&id); err != nil {
+		return nil, err
+	}
+
+	for _, c := range staff.Certifications {
+		query := "INSERT INTO staff_certifications (staff_id, certification) VALUES ($1, $2)"
+		if _, err := tx.ExecContext(ctx, query, id, c); err != nil {
+			return nil, err
+		}
+	}
+
+	added, err := getStaff(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return added, nil
+}
+
+// Get a staff member by id.
+func (s *StaffStore) Get(ctx context.Context, id string) (*app.Staff, error) {
+	return getStaff(ctx, s.DB, id)
+}
+
+// List staff members sorted by name.
+// With a cage in the filter only the staff on duty for the cage are listed.
+func (s *StaffStore) List(ctx context.Context, filter app.StaffFilter) ([]app.Staff, error) {
+	var (
+		where []string
+		args  []any
+	)
+	if !filter.Role.IsUnspecified() {
+		where = append(where, "s.role = ?")
+		args = append(args, filter.Role)
+	}
+	if filter.CageID != "" {
+		onDuty := filter.OnDuty
+		if onDuty.IsZero() {
+			onDuty = time.Now()
+		}
+		where = append(where, `EXISTS (
+		SELECT 1
+		  FROM staff_assignments a
+		 WHERE a.staff_id = s.id
+		   AND a.cage_id = ?
+		   AND a.starts_at <= ?
+		   AND a.ends_at > ?)`)
+		args = append(args, filter.CageID, onDuty, onDuty)
+	}
+
+	query := "SELECT" + staffColumns + " FROM staff s" + whereClause(where) + " ORDER BY s.name, s.id"
+
+	rows, err := s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []app.Staff
+	for rows.Next() {
+		var staff app.Staff
+		if err := scanStaff(rows, &staff); err != nil {
+			return nil, err
+		}
+
+		list = append(list, staff)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// assignmentColumns are the selected columns of an assignment.
+const assignmentColumns = `
+	id, staff_id, cage_id, starts_at, ends_at, created_at`
+
+func assignmentFieldPointers(assignment *app.Assignment) []any {
+	return []any{
+		&assignment.ID,
+		&assignment.StaffID,
+		&assignment.CageID,
+		&assignment.StartsAt,
+		&assignment.EndsAt,
+		&assignment.CreatedAt,
+	}
+}
+
+// Assign a staff member to a shift covering a cage.
+// Only the staff certified for carnivore handling can cover a cage holding carnivores.
+// app.ErrNotFound is returned if the staff member or the cage doesn't exist
+// and app.ErrNotCertified if the staff member isn't certified for the cage.
+func (s *StaffStore) Assign(ctx context.Context, assignment *app.Assignment) (*app.Assignment, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() // nolint:errcheck
+
+	// Lock the cage so that carnivores can't be admitted while the staff is checked,
+	// admissions in turn reject carnivores into a cage covered by uncertified staff.
+	if err := lockCage(ctx, tx, assignment.CageID); err != nil {
+		return nil, err
+	}
+
+	staff, err := getStaff(ctx, tx, assignment.StaffID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !staff.Certified(app.CertificationCarnivoreHandling) {
+		carnivores, err := holdsCarnivores(ctx, tx, assignment.CageID)
+		if err != nil {
+			return nil, err
+		}
+		if carnivores {
+			return nil, app.ErrNotCertified
+		}
+	}
+
+	var added app.Assignment
+	query := `
+	INSERT INTO staff_assignments (staff_id, cage_id, starts_at, ends_at)
+	VALUES ($1, $2, $3, $4)
+	RETURNING` + assignmentColumns
+	err = tx.QueryRowContext(ctx, query,
+		assignment.StaffID,
+		assignment.CageID,
+		assignment.StartsAt,
+		assignment.EndsAt,
+	).Scan(assignmentFieldPointers(&added)...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &added, nil
+}
+
+// ListAssignments lists assignments in the order the shifts start.
+func (s *StaffStore) ListAssignments(ctx context.Context, filter app.AssignmentFilter) ([]app.Assignment, error) {
+	var (
+		where []string
+		args  []any
+	)
+	if filter.StaffID != "" {
+		where = append(where, "staff_id = ?")
+		args = append(args, filter.StaffID)
+	}
+	if filter.CageID != "" {
+		where = append(where, "cage_id = ?")
+		args = append(args, filter.CageID)
+	}
+	if !filter.At.IsZero() {
+		where = append(where, "starts_at <= ?", "ends_at > ?")
+		args = append(args, filter.At, filter.At)
+	}
+
+	query := "SELECT" + assignmentColumns + " FROM staff_assignments" + whereClause(where) + " ORDER BY starts_at, id"
+
+	rows, err := s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var assignments []app.Assignment
+	for rows.Next() {
+		var assignment app.Assignment
+		if err := rows.Scan(assignmentFieldPointers(&assignment)...); err != nil {
+			return nil, err
+		}
+
+		assignments = append(assignments, assignment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return assignments, nil
+}
+
+// DeleteAssignment deletes an assignment.
+func (s *StaffStore) DeleteAssignment(ctx context.Context, id string) error {
+	res, err := s.DB.ExecContext(ctx, "DELETE FROM staff_assignments WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return app.ErrNotFound
+	}
+
+	return nil
+}
+
+func getStaff(ctx context.Context, q queryable, id string) (*app.Staff, error) {
+	var staff app.Staff
+	query := "SELECT" + staffColumns + " FROM staff s WHERE s.id = $1"
+	if err := scanStaff(q.QueryRowContext(ctx, query, id), &staff); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, app.ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return &staff, nil
+}
+
+// holdsCarnivores returns true if any of the cage occupants is a carnivore.
+func holdsCarnivores(ctx context.Context, q queryable, cageID string) (bool, error) {
+	carnivores := app.DinosaurTypeCarnivore.Species()
+
+	where := []string{"cage_id = ?", "deleted_at IS NULL", "species IN (" + placeholders(len(carnivores)) + ")"}
+	args := []any{cageID}
+	for _, species := range carnivores {
+		args = append(args, species)
+	}
+
+	var holds bool
+	query := "SELECT EXISTS (SELECT 1 FROM dinosaurs" + whereClause(where) + ")"
+	if err := q.QueryRowContext(ctx, query, args...).Scan(&holds); err != nil {
+		return false, err
+	}
+
+	return holds, nil
+}
+
+// coveredByUncertified returns true if any of the current or future shifts covering the cage
+// is assigned to a staff member not certified for carnivore handling.
+func coveredByUncertified(ctx context.Context, q queryable, cageID string) (bool, error) {
+	var uncertified bool
+	query := `
+	SELECT EXISTS (
+		SELECT 1
+		  FROM staff_assignments a
+		 WHERE a.cage_id = $1
+		   AND a.ends_at > NOW()
+		   AND NOT EXISTS (
+			SELECT 1
+			  FROM staff_certifications sc
+			 WHERE sc.staff_id = a.staff_id
+			   AND sc.certification = $2))`
+	if err := q.QueryRowContext(ctx, query, cageID, app.CertificationCarnivoreHandling).Scan(&uncertified); err != nil {
+		return false, err
+	}
+
+	return uncertified, nil
+}
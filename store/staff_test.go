@@ -0,0 +1,204 @@
+//go:build integration
+// +build integration
+
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+func TestStaffStore(t *testing.T) {
+	setUpTestDB(t)
+	t.Cleanup(func() {
+		testDB.Exec("TRUNCATE TABLE cages, staff CASCADE")
+	})
+
+	ctx := context.Background()
+	cageStore := CageStore{DB: testDB}
+	dinosaurStore := DinosaurStore{DB: testDB}
+	staffStore := StaffStore{DB: testDB}
+
+	rexCage, err := cageStore.Add(ctx, &app.Cage{Capacity: 2, Status: app.CageStatusActive})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ceraCage, err := cageStore.Add(ctx, &app.Cage{Capacity: 2, Status: app.CageStatusActive})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dinosaurStore.Add(ctx, &app.Dinosaur{Name: "Rexy", Species: app.DinosaurSpeciesTyrannosaurus, CageID: rexCage.ID}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dinosaurStore.Add(ctx, &app.Dinosaur{Name: "Cera", Species: app.DinosaurSpeciesTriceratops, CageID: ceraCage.ID}); err != nil {
+		t.Fatal(err)
+	}
+
+	muldoon, err := staffStore.Add(ctx, &app.Staff{
+		Name:           "Muldoon",
+		Role:           app.StaffRoleKeeper,
+		Certifications: []app.Certification{app.CertificationTranquilizer, app.CertificationCarnivoreHandling},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 2, len(muldoon.Certifications); want != got {
+		t.Fatalf("Expected Certifications %d got %d", want, got)
+	}
+	if want, got := app.CertificationCarnivoreHandling, muldoon.Certifications[0]; want != got {
+		t.Fatalf("Expected Certification %s got %s", want, got)
+	}
+	sattler, err := staffStore.Add(ctx, &app.Staff{Name: "Sattler", Role: app.StaffRoleVeterinarian})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vet, err := staffStore.Get(ctx, sattler.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 0, len(vet.Certifications); want != got {
+		t.Fatalf("Expected Certifications %d got %d", want, got)
+	}
+	if _, err := staffStore.Get(ctx, uuid.NewString()); err != app.ErrNotFound {
+		t.Fatalf("Expected error %v got %v", app.ErrNotFound, err)
+	}
+
+	start := time.Now().Truncate(time.Hour)
+	end := start.Add(8 * time.Hour)
+
+	// Only the certified staff can cover the carnivores.
+	if _, err := staffStore.Assign(ctx, &app.Assignment{StaffID: sattler.ID, CageID: rexCage.ID, StartsAt: start, EndsAt: end}); err != app.ErrNotCertified {
+		t.Fatalf("Expected error %v got %v", app.ErrNotCertified, err)
+	}
+	if _, err := staffStore.Assign(ctx, &app.Assignment{StaffID: muldoon.ID, CageID: rexCage.ID, StartsAt: start, EndsAt: end}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := staffStore.Assign(ctx, &app.Assignment{StaffID: sattler.ID, CageID: ceraCage.ID, StartsAt: start, EndsAt: end}); err != nil {
+		t.Fatal(err)
+	}
+	later, err := staffStore.Assign(ctx, &app.Assignment{StaffID: muldoon.ID, CageID: ceraCage.ID, StartsAt: end, EndsAt: end.Add(8 * time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := staffStore.Assign(ctx, &app.Assignment{StaffID: uuid.NewString(), CageID: ceraCage.ID, StartsAt: start, EndsAt: end}); err != app.ErrNotFound {
+		t.Fatalf("Expected error %v got %v", app.ErrNotFound, err)
+	}
+	if _, err := staffStore.Assign(ctx, &app.Assignment{StaffID: muldoon.ID, CageID: uuid.NewString(), StartsAt: start, EndsAt: end}); err != app.ErrNotFound {
+		t.Fatalf("Expected error %v got %v", app.ErrNotFound, err)
+	}
+
+	// The cages Muldoon covers now.
+	assignments, err := staffStore.ListAssignments(ctx, app.AssignmentFilter{StaffID: muldoon.ID, At: start})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 1, len(assignments); want != got {
+		t.Fatalf("Expected assignments %d got %d", want, got)
+	}
+	if want, got := rexCage.ID, assignments[0].CageID; want != got {
+		t.Fatalf("Expected cage %s got %s", want, got)
+	}
+	assignments, err = staffStore.ListAssignments(ctx, app.AssignmentFilter{StaffID: muldoon.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 2, len(assignments); want != got {
+		t.Fatalf("Expected assignments %d got %d", want, got)
+	}
+
+	// Who is on duty for the triceratops cage now and after the shift change.
+	onDuty, err := staffStore.List(ctx, app.StaffFilter{CageID: ceraCage.ID, OnDuty: start})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 1, len(onDuty); want != got {
+		t.Fatalf("Expected staff %d got %d", want, got)
+	}
+	if want, got := sattler.ID, onDuty[0].ID; want != got {
+		t.Fatalf("Expected staff %s got %s", want, got)
+	}
+	onDuty, err = staffStore.List(ctx, app.StaffFilter{CageID: ceraCage.ID, OnDuty: end})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := muldoon.ID, onDuty[0].ID; want != got {
+		t.Fatalf("Expected staff %s got %s", want, got)
+	}
+
+	keepers, err := staffStore.List(ctx, app.StaffFilter{Role: app.StaffRoleKeeper})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 1, len(keepers); want != got {
+		t.Fatalf("Expected staff %d got %d", want, got)
+	}
+
+	if err := staffStore.DeleteAssignment(ctx, later.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := staffStore.DeleteAssignment(ctx, later.ID); err != app.ErrNotFound {
+		t.Fatalf("Expected error %v got %v", app.ErrNotFound, err)
+	}
+}
+
+func TestStaffStoreUncertifiedCoverage(t *testing.T) {
+	setUpTestDB(t)
+	t.Cleanup(func() {
+		testDB.Exec("TRUNCATE TABLE cages, staff CASCADE")
+	})
+
+	ctx := context.Background()
+	cageStore := CageStore{DB: testDB}
+	dinosaurStore := DinosaurStore{DB: testDB}
+	staffStore := StaffStore{DB: testDB}
+
+	emptyCage, err := cageStore.Add(ctx, &app.Cage{Capacity: 2, Status: app.CageStatusActive})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rexCage, err := cageStore.Add(ctx, &app.Cage{Capacity: 2, Status: app.CageStatusActive})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rexy, err := dinosaurStore.Add(ctx, &app.Dinosaur{Name: "Rexy", Species: app.DinosaurSpeciesTyrannosaurus, CageID: rexCage.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sattler, err := staffStore.Add(ctx, &app.Staff{Name: "Sattler", Role: app.StaffRoleVeterinarian})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The empty cage is covered by the uncertified staff in the next shift.
+	start := time.Now().Add(time.Hour).Truncate(time.Hour)
+	shift, err := staffStore.Assign(ctx, &app.Assignment{StaffID: sattler.ID, CageID: emptyCage.ID, StartsAt: start, EndsAt: start.Add(8 * time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := dinosaurStore.Add(ctx, &app.Dinosaur{Name: "Buck", Species: app.DinosaurSpeciesTyrannosaurus, CageID: emptyCage.ID}); err != app.ErrNotCertified {
+		t.Fatalf("Expected error %v got %v", app.ErrNotCertified, err)
+	}
+	if _, err := dinosaurStore.Move(ctx, rexy.ID, emptyCage.ID); err != app.ErrNotCertified {
+		t.Fatalf("Expected error %v got %v", app.ErrNotCertified, err)
+	}
+
+	// Once the uncertified staff is off the cage the carnivore can be moved in.
+	if err := staffStore.DeleteAssignment(ctx, shift.ID); err != nil {
+		t.Fatal(err)
+	}
+	moved, err := dinosaurStore.Move(ctx, rexy.ID, emptyCage.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := emptyCage.ID, moved.CageID; want != got {
+		t.Fatalf("Expected cage %s got %s", want, got)
+	}
+}
@@ -0,0 +1,234 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+// ZoneStore is a DB implementation of api.ZoneStore.
+type ZoneStore struct {
+	DB *sql.DB
+}
+
+// Capacity and occupancy of zones and sectors are rolled up
+// from the cages in them separately per capacity unit:
+// the head capacity and count of the cages counting heads
+// and the space capacity and usage of the cages counting space.
+// Deleted cages are not counted.
+var (
+	rollupColumns = `
+	COALESCE(SUM(c.capacity) FILTER (WHERE c.capacity_unit = 'heads'), 0),
+	COALESCE(SUM(COALESCE(o.n, 0)) FILTER (WHERE c.capacity_unit = 'heads'), 0),
+	COALESCE(SUM(c.capacity) FILTER (WHERE c.capacity_unit = 'space'), 0),
+	COALESCE(SUM(COALESCE(o.w, 0)) FILTER (WHERE c.capacity_unit = 'space'), 0)`
+	zoneRollupQuery = `
+	SELECT z.id, z.name,` + rollupColumns + `, z.created_at, z.updated_at
+	  FROM zones z
+	  LEFT JOIN sectors s ON s.zone_id = z.id
+	  LEFT JOIN cages c ON c.sector_id = s.id AND c.deleted_at IS NULL` + cageOccupancyJoin
+	sectorRollupQuery = `
+	SELECT s.id, s.zone_id, s.name,` + rollupColumns + `, s.created_at, s.updated_at
+	  FROM sectors s
+	  LEFT JOIN cages c ON c.sector_id = s.id AND c.deleted_at IS NULL` + cageOccupancyJoin
+)
+
+// Add a new zone.
+// Zone names are unique, app.ErrConflict is returned for a taken name.
+func (s *ZoneStore) Add(ctx context.Context, zone *app.Zone) (*app.Zone, error) {
+	var z app.Zone
+	query := `
+	INSERT INTO zones (name) VALUES ($1)
+	ON CONFLICT (name) DO NOTHING
+	RETURNING id, name, created_at, updated_at`
+	err := s.DB.QueryRowContext(ctx, query, zone.Name).Scan(
+		&z.ID,
+		&z.Name,
+		&z.CreatedAt,
+		&z.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, app.ErrConflict
+		}
+
+		return nil, err
+	}
+
+	return &z, nil
+}
+
+// Get a zone by id along with its sectors.
+func (s *ZoneStore) Get(ctx context.Context, id string) (*app.Zone, error) {
+	query := zoneRollupQuery + `
+	 WHERE z.id = $1
+	 GROUP BY z.id`
+
+	var zone app.Zone
+	err := s.DB.QueryRowContext(ctx, query, id).Scan(
+		&zone.ID,
+		&zone.Name,
+		&zone.HeadCapacity,
+		&zone.HeadCount,
+		&zone.SpaceCapacity,
+		&zone.SpaceUsed,
+		&zone.CreatedAt,
+		&zone.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, app.ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	if zone.Sectors, err = s.listSectors(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return &zone, nil
+}
+
+// List zones sorted by name.
+func (s *ZoneStore) List(ctx context.Context) ([]app.Zone, error) {
+	query := zoneRollupQuery + `
+	 GROUP BY z.id
+	 ORDER BY z.name`
+
+	rows, err := s.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var zones []app.Zone
+	for rows.Next() {
+		var zone app.Zone
+		if err := rows.Scan(
+			&zone.ID,
+			&zone.Name,
+			&zone.HeadCapacity,
+			&zone.HeadCount,
+			&zone.SpaceCapacity,
+			&zone.SpaceUsed,
+			&zone.CreatedAt,
+			&zone.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		zones = append(zones, zone)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return zones, nil
+}
+
+// AddSector adds a new sector to a zone.
+// Sector names are unique within a zone, app.ErrConflict is returned for a taken name.
+func (s *ZoneStore) AddSector(ctx context.Context, sector *app.Sector) (*app.Sector, error) {
+	if err := checkZone(ctx, s.DB, sector.ZoneID); err != nil {
+		return nil, err
+	}
+
+	var sec app.Sector
+	query := `
+	INSERT INTO sectors (zone_id, name) VALUES ($1, $2)
+	ON CONFLICT (zone_id, name) DO NOTHING
+	RETURNING id, zone_id, name, created_at, updated_at`
+	err := s.DB.QueryRowContext(ctx, query, sector.ZoneID, sector.Name).Scan(
+		&sec.ID,
+		&sec.ZoneID,
+		&sec.Name,
+		&sec.CreatedAt,
+		&sec.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, app.ErrConflict
+		}
+
+		return nil, err
+	}
+
+	return &sec, nil
+}
+
+// ListSectors lists the sectors of a zone sorted by name.
+func (s *ZoneStore) ListSectors(ctx context.Context, zoneID string) ([]app.Sector, error) {
+	if err := checkZone(ctx, s.DB, zoneID); err != nil {
+		return nil, err
+	}
+
+	return s.listSectors(ctx, zoneID)
+}
+
+func (s *ZoneStore) listSectors(ctx context.Context, zoneID string) ([]app.Sector, error) {
+	query := sectorRollupQuery + `
+	 WHERE s.zone_id = $1
+	 GROUP BY s.id
+	 ORDER BY s.name`
+
+	rows, err := s.DB.QueryContext(ctx, query, zoneID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sectors []app.Sector
+	for rows.Next() {
+		var sector app.Sector
+		if err := rows.Scan(
+			&sector.ID,
+			&sector.ZoneID,
+			&sector.Name,
+			&sector.HeadCapacity,
+			&sector.HeadCount,
+			&sector.SpaceCapacity,
+			&sector.SpaceUsed,
+			&sector.CreatedAt,
+			&sector.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		sectors = append(sectors, sector)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sectors, nil
+}
+
+// checkZone checks if a zone exists.
+func checkZone(ctx context.Context, q queryable, id string) error {
+	err := q.QueryRowContext(ctx, "SELECT id FROM zones WHERE id = $1", id).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return app.ErrNotFound
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// checkSector checks if a sector a cage is placed into exists.
+func checkSector(ctx context.Context, q queryable, id string) error {
+	err := q.QueryRowContext(ctx, "SELECT id FROM sectors WHERE id = $1", id).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return app.ErrSectorNotFound
+		}
+
+		return err
+	}
+
+	return nil
+}
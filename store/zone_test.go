@@ -0,0 +1,265 @@
+//go:build integration
+// +build integration
+
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/pmatseykanets/jurassic/app"
+)
+
+func TestZoneStore(t *testing.T) {
+	setUpTestDB(t)
+	t.Cleanup(func() {
+		testDB.Exec("TRUNCATE TABLE zones CASCADE")
+	})
+
+	ctx := context.Background()
+	zoneStore := ZoneStore{DB: testDB}
+	cageStore := CageStore{DB: testDB}
+	dinosaurStore := DinosaurStore{DB: testDB}
+
+	zone, err := zoneStore.Add(ctx, &app.Zone{Name: "Paddock North"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := zoneStore.Add(ctx, &app.Zone{Name: "Paddock North"}); err != app.ErrConflict {
+		t.Fatalf("Expected error %v got %v", app.ErrConflict, err)
+	}
+	other, err := zoneStore.Add(ctx, &app.Zone{Name: "Lagoon"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	east, err := zoneStore.AddSector(ctx, &app.Sector{ZoneID: zone.ID, Name: "East"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	west, err := zoneStore.AddSector(ctx, &app.Sector{ZoneID: zone.ID, Name: "West"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := zoneStore.AddSector(ctx, &app.Sector{ZoneID: zone.ID, Name: "East"}); err != app.ErrConflict {
+		t.Fatalf("Expected error %v got %v", app.ErrConflict, err)
+	}
+	if _, err := zoneStore.AddSector(ctx, &app.Sector{ZoneID: uuid.NewString(), Name: "East"}); err != app.ErrNotFound {
+		t.Fatalf("Expected error %v got %v", app.ErrNotFound, err)
+	}
+
+	// Two cages in the east sector, one in the west one and one unplaced.
+	var cages []*app.Cage
+	for _, c := range []app.Cage{
+		{Capacity: 2, Status: app.CageStatusActive, SectorID: east.ID},
+		{Capacity: 3, Status: app.CageStatusActive, SectorID: east.ID},
+		{Capacity: 5, Status: app.CageStatusActive, SectorID: west.ID},
+		{Capacity: 7, Status: app.CageStatusActive},
+	} {
+		cage, err := cageStore.Add(ctx, &c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want, got := c.SectorID, cage.SectorID; want != got {
+			t.Fatalf("Expected SectorID %s got %s", want, got)
+		}
+		cages = append(cages, cage)
+	}
+	_, err = cageStore.Add(ctx, &app.Cage{Capacity: 1, Status: app.CageStatusActive, SectorID: uuid.NewString()})
+	if !errors.Is(err, app.ErrSectorNotFound) {
+		t.Fatalf("Expected error %v got %v", app.ErrSectorNotFound, err)
+	}
+
+	for _, cage := range []*app.Cage{cages[0], cages[2], cages[3]} {
+		_, err := dinosaurStore.Add(ctx, &app.Dinosaur{Name: "Bumpy", Species: app.DinosaurSpeciesAnkylosaurus, CageID: cage.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := zoneStore.Get(ctx, zone.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 10, got.HeadCapacity; want != got {
+		t.Fatalf("Expected HeadCapacity %d got %d", want, got)
+	}
+	if want, got := 2, got.HeadCount; want != got {
+		t.Fatalf("Expected HeadCount %d got %d", want, got)
+	}
+	if want, got := 2, len(got.Sectors); want != got {
+		t.Fatalf("Expected sectors %d got %d", want, got)
+	}
+	if want, got := east.ID, got.Sectors[0].ID; want != got {
+		t.Fatalf("Expected sector %s got %s", want, got)
+	}
+	if want, got := 5, got.Sectors[0].HeadCapacity; want != got {
+		t.Fatalf("Expected sector HeadCapacity %d got %d", want, got)
+	}
+	if want, got := 1, got.Sectors[0].HeadCount; want != got {
+		t.Fatalf("Expected sector HeadCount %d got %d", want, got)
+	}
+
+	if _, err := zoneStore.Get(ctx, uuid.NewString()); err != app.ErrNotFound {
+		t.Fatalf("Expected error %v got %v", app.ErrNotFound, err)
+	}
+
+	zones, err := zoneStore.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 2, len(zones); want != got {
+		t.Fatalf("Expected zones %d got %d", want, got)
+	}
+	// Sorted by name, the empty zone rolls up to zero.
+	if want, got := other.ID, zones[0].ID; want != got {
+		t.Fatalf("Expected zone %s got %s", want, got)
+	}
+	if want, got := 0, zones[0].HeadCapacity; want != got {
+		t.Fatalf("Expected HeadCapacity %d got %d", want, got)
+	}
+
+	list, err := cageStore.List(ctx, app.CageFilter{ZoneID: zone.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 3, len(list); want != got {
+		t.Fatalf("Expected cages %d got %d", want, got)
+	}
+
+	list, err = cageStore.List(ctx, app.CageFilter{SectorID: west.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 1, len(list); want != got {
+		t.Fatalf("Expected cages %d got %d", want, got)
+	}
+
+	dinosaurs, err := dinosaurStore.List(ctx, app.DinosaurFilter{ZoneID: zone.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 2, len(dinosaurs); want != got {
+		t.Fatalf("Expected dinosaurs %d got %d", want, got)
+	}
+
+	// Moving the unplaced cage into the zone is recorded and rolls up.
+	cage, err := cageStore.Update(ctx, cages[3].ID, app.CagePatch{SectorID: &west.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := west.ID, cage.SectorID; want != got {
+		t.Fatalf("Expected SectorID %s got %s", want, got)
+	}
+
+	history, err := cageStore.History(ctx, cage.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 1, len(history); want != got {
+		t.Fatalf("Expected history entries %d got %d", want, got)
+	}
+	if want, got := app.CageChangeSector, history[0].Change; want != got {
+		t.Fatalf("Expected change %s got %s", want, got)
+	}
+
+	sectors, err := zoneStore.ListSectors(ctx, zone.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 12, sectors[1].HeadCapacity; want != got {
+		t.Fatalf("Expected sector HeadCapacity %d got %d", want, got)
+	}
+
+	missing := uuid.NewString()
+	_, err = cageStore.Update(ctx, cages[0].ID, app.CagePatch{SectorID: &missing})
+	if !errors.Is(err, app.ErrSectorNotFound) {
+		t.Fatalf("Expected error %v got %v", app.ErrSectorNotFound, err)
+	}
+
+	// Removing the cage from its sector is recorded too.
+	none := ""
+	cage, err = cageStore.Update(ctx, cage.ID, app.CagePatch{SectorID: &none})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "", cage.SectorID; want != got {
+		t.Fatalf("Expected no SectorID got %s", got)
+	}
+	history, err = cageStore.History(ctx, cage.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 2, len(history); want != got {
+		t.Fatalf("Expected history entries %d got %d", want, got)
+	}
+}
+
+func TestZoneStoreMixedCapacityUnits(t *testing.T) {
+	setUpTestDB(t)
+	t.Cleanup(func() {
+		testDB.Exec("TRUNCATE TABLE zones, cages CASCADE")
+	})
+
+	ctx := context.Background()
+	zoneStore := ZoneStore{DB: testDB}
+	cageStore := CageStore{DB: testDB}
+	dinosaurStore := DinosaurStore{DB: testDB}
+
+	zone, err := zoneStore.Add(ctx, &app.Zone{Name: "Paddock South"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sector, err := zoneStore.AddSector(ctx, &app.Sector{ZoneID: zone.ID, Name: "Valley"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A cage counting heads and a cage counting space in the same sector.
+	heads, err := cageStore.Add(ctx, &app.Cage{Capacity: 10, CapacityUnit: app.CapacityUnitHeads, Status: app.CageStatusActive, SectorID: sector.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	space, err := cageStore.Add(ctx, &app.Cage{Capacity: 40, CapacityUnit: app.CapacityUnitSpace, Status: app.CageStatusActive, SectorID: sector.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"Blue", "Charlie", "Delta"} {
+		if _, err := dinosaurStore.Add(ctx, &app.Dinosaur{Name: name, Species: app.DinosaurSpeciesVelociraptor, CageID: heads.ID}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{"Bracky", "Ducky"} {
+		if _, err := dinosaurStore.Add(ctx, &app.Dinosaur{Name: name, Species: app.DinosaurSpeciesBrachiosaurus, CageID: space.ID}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := zoneStore.Get(ctx, zone.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rollup := range []struct {
+		name                                              string
+		headCapacity, headCount, spaceCapacity, spaceUsed int
+	}{
+		{"zone", got.HeadCapacity, got.HeadCount, got.SpaceCapacity, got.SpaceUsed},
+		{"sector", got.Sectors[0].HeadCapacity, got.Sectors[0].HeadCount, got.Sectors[0].SpaceCapacity, got.Sectors[0].SpaceUsed},
+	} {
+		if want, got := 10, rollup.headCapacity; want != got {
+			t.Fatalf("Expected %s HeadCapacity %d got %d", rollup.name, want, got)
+		}
+		if want, got := 3, rollup.headCount; want != got {
+			t.Fatalf("Expected %s HeadCount %d got %d", rollup.name, want, got)
+		}
+		if want, got := 40, rollup.spaceCapacity; want != got {
+			t.Fatalf("Expected %s SpaceCapacity %d got %d", rollup.name, want, got)
+		}
+		if want, got := 2*app.DinosaurSpeciesBrachiosaurus.Weight(), rollup.spaceUsed; want != got {
+			t.Fatalf("Expected %s SpaceUsed %d got %d", rollup.name, want, got)
+		}
+	}
+}